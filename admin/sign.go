@@ -1,8 +1,11 @@
 package admin
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"math/big"
 	"time"
 
@@ -31,19 +34,58 @@ var (
 	// admin tx lifetime
 	maxExpireSeconds int64 = 120
 	maxFutureSeconds int64 = 30
+
+	// MaxValiditySeconds max validity window of offline signed admin call
+	MaxValiditySeconds int64 = 7 * 24 * 3600
 )
 
-// CallArgs call args
+// CallArgs call args.
+// offline signed call has explicit validity window ended at 'ValidUntil'
+// (instead of the default 'maxExpireSeconds' after 'Timestamp'), and
+// a random 'Nonce' which swap server records to prevent replaying.
 type CallArgs struct {
-	Method    string   `json:"method"`
-	Params    []string `json:"params"`
-	Timestamp int64    `json:"timestamp"`
+	Method     string   `json:"method"`
+	Params     []string `json:"params"`
+	Timestamp  int64    `json:"timestamp"`
+	ValidUntil int64    `json:"validUntil,omitempty"`
+	Nonce      string   `json:"nonce,omitempty"`
 }
 
 // Sign sign
 func Sign(method string, params []string) (rawTx string, err error) {
 	log.Info("admin Sign", "method", method, "params", params)
-	payload, err := encodeCallArgs(method, params)
+	return SignCallArgs(NewCallArgs(method, params))
+}
+
+// NewCallArgs new call args with current timestamp
+func NewCallArgs(method string, params []string) *CallArgs {
+	return &CallArgs{
+		Method:    method,
+		Params:    params,
+		Timestamp: time.Now().Unix(),
+	}
+}
+
+// SetValidity set validity window (in seconds since timestamp) and random nonce
+func (args *CallArgs) SetValidity(validity int64) error {
+	if validity <= 0 || validity > MaxValiditySeconds {
+		return fmt.Errorf("validity %v seconds is out of range (0, %v]", validity, MaxValiditySeconds)
+	}
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return err
+	}
+	args.ValidUntil = args.Timestamp + validity
+	args.Nonce = hex.EncodeToString(nonce)
+	return nil
+}
+
+// SignCallArgs sign call args with loaded keystore
+func SignCallArgs(args *CallArgs) (rawTx string, err error) {
+	if keyWrapper == nil {
+		return "", errors.New("admin keystore is not loaded")
+	}
+	payload, err := json.Marshal(args)
 	if err != nil {
 		return "", err
 	}
@@ -70,6 +112,35 @@ func Sign(method string, params []string) (rawTx string, err error) {
 	return common.ToHex(txdata), nil
 }
 
+// GetSignerAddress get address of loaded keystore
+func GetSignerAddress() string {
+	if keyWrapper == nil {
+		return ""
+	}
+	return keyWrapper.Address.String()
+}
+
+// ExpireTime the time after which admin tx of the call args is rejected
+func (args *CallArgs) ExpireTime() int64 {
+	if args.ValidUntil != 0 {
+		return args.ValidUntil
+	}
+	return args.Timestamp + maxExpireSeconds
+}
+
+func (args *CallArgs) checkValidity() error {
+	if args.ValidUntil == 0 && args.Nonce == "" {
+		return nil
+	}
+	if args.Nonce == "" {
+		return errors.New("admin call with validity window must have nonce")
+	}
+	if args.ValidUntil <= args.Timestamp || args.ValidUntil-args.Timestamp > MaxValiditySeconds {
+		return fmt.Errorf("wrong admin call validity window [%v, %v]", args.Timestamp, args.ValidUntil)
+	}
+	return nil
+}
+
 // LoadKeyStore load keystore
 func LoadKeyStore(keyfile, passfile string) error {
 	key, err := tools.LoadKeyStore(keyfile, passfile)
@@ -81,15 +152,6 @@ func LoadKeyStore(keyfile, passfile string) error {
 	return nil
 }
 
-func encodeCallArgs(method string, params []string) ([]byte, error) {
-	args := CallArgs{
-		Method:    method,
-		Params:    params,
-		Timestamp: time.Now().Unix(),
-	}
-	return json.Marshal(args)
-}
-
 func decodeCallArgs(data []byte) (*CallArgs, error) {
 	var args CallArgs
 	err := json.Unmarshal(data, &args)
@@ -101,21 +163,42 @@ func decodeCallArgs(data []byte) (*CallArgs, error) {
 
 // VerifyTransaction get sender
 func VerifyTransaction(tx *types.Transaction) (*common.Address, *CallArgs, error) {
-	if tx.To() == nil || *tx.To() != adminToAddr {
-		return nil, nil, errors.New("wrong admin tx to address")
-	}
-	args, err := decodeCallArgs(tx.Data())
+	sender, args, err := verifySignature(tx)
 	if err != nil {
 		return nil, nil, err
 	}
+	if err = args.checkValidity(); err != nil {
+		return nil, nil, err
+	}
 	timestamp := args.Timestamp
 	now := time.Now().Unix()
-	if now-timestamp > maxExpireSeconds {
+	if now > args.ExpireTime() {
 		return nil, nil, errors.New("expired admin tx timestamp")
 	}
 	if now+maxFutureSeconds < timestamp {
 		return nil, nil, errors.New("future admin tx timestamp")
 	}
+	return sender, args, nil
+}
+
+// VerifyRecordedTransaction get sender of recorded admin tx (eg. stored with pair config),
+// only signature is verified as the recorded tx is expired.
+func VerifyRecordedTransaction(rawTx string) (*common.Address, *CallArgs, error) {
+	tx, err := DecodeTransaction(rawTx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return verifySignature(tx)
+}
+
+func verifySignature(tx *types.Transaction) (*common.Address, *CallArgs, error) {
+	if tx.To() == nil || *tx.To() != adminToAddr {
+		return nil, nil, errors.New("wrong admin tx to address")
+	}
+	args, err := decodeCallArgs(tx.Data())
+	if err != nil {
+		return nil, nil, err
+	}
 	sender, err := adminSigner.Sender(tx) // will verify signature
 	if err != nil {
 		return nil, nil, err
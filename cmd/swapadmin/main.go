@@ -32,6 +32,13 @@ func initApp() {
 		manualCommand,
 		setnonceCommand,
 		addpairCommand,
+		reloadpairsCommand,
+		pairsCommand,
+		withdrawfeeCommand,
+		statusHistoryCommand,
+		signCommand,
+		submitCommand,
+		inspectCommand,
 		utils.LicenseCommand,
 		utils.VersionCommand,
 	}
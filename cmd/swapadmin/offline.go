@@ -0,0 +1,238 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/admin"
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+const offlineKindAdminCall = "admincall"
+
+var (
+	validityFlag = &cli.DurationFlag{
+		Name:  "validity",
+		Usage: "validity window of offline signed admin call (max 168h)",
+		Value: 24 * time.Hour,
+	}
+
+	signCommand = &cli.Command{
+		Action:    signOffline,
+		Name:      "sign",
+		Usage:     "sign unsigned admin call file offline",
+		ArgsUsage: " ",
+		Description: `
+sign the unsigned admin call file built by admin commands with '--output' flag.
+this command does not connect to network, it can run on air-gapped machine.
+
+the admin call timestamp is set when signing, and the signed call is valid
+in the window specified by '--validity' flag (default 24h, max 168h),
+so it can be carried out of air-gapped machine and submitted later.
+the signed call has a random nonce, swap server records it and rejects
+submitting the same signed call again.
+
+Example:
+
+./swapadmin maintain close deposit all --output ./unsigned.json
+./swapadmin inspect --input ./unsigned.json
+./swapadmin sign --keystore ./UTC.json --password ./password.txt --input ./unsigned.json --output ./signed.json --validity 2h
+./swapadmin submit --swapserver http://1.2.3.4:5555/rpc --input ./signed.json
+`,
+		Flags: []cli.Flag{
+			utils.KeystoreFileFlag,
+			utils.PasswordFileFlag,
+			utils.InputFileFlag,
+			utils.OutputFileFlag,
+			validityFlag,
+		},
+	}
+
+	submitCommand = &cli.Command{
+		Action:    submitOffline,
+		Name:      "submit",
+		Usage:     "submit signed admin call file",
+		ArgsUsage: " ",
+		Description: `
+submit the signed admin call file to swap server.
+`,
+		Flags: []cli.Flag{
+			utils.SwapServerFlag,
+			utils.InputFileFlag,
+		},
+	}
+
+	inspectCommand = &cli.Command{
+		Action:    inspectOffline,
+		Name:      "inspect",
+		Usage:     "inspect offline admin call file",
+		ArgsUsage: " ",
+		Description: `
+decode and verify unsigned or signed admin call file.
+`,
+		Flags: []cli.Flag{
+			utils.InputFileFlag,
+		},
+	}
+
+	// admin methods supported by swap server
+	adminMethods = []string{
+		"maintain",
+		"bigvalue",
+		"blacklist",
+		"reverify",
+		"reswap",
+		"replaceswap",
+		"manual",
+		"setnonce",
+		"addpair",
+		"reloadpairs",
+		"pushpair",
+		"removepair",
+		"withdrawfee",
+	}
+)
+
+type offlineCall struct {
+	utils.OfflineHeader
+	CallArgs *admin.CallArgs `json:"callArgs"`
+	Signer   string          `json:"signer,omitempty"`
+	RawTx    string          `json:"rawTx,omitempty"`
+}
+
+func isAdminMethod(method string) bool {
+	for _, m := range adminMethods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func loadOfflineCall(ctx *cli.Context, stage string) (*offlineCall, error) {
+	file := ctx.String(utils.InputFileFlag.Name)
+	if file == "" {
+		return nil, errors.New("must specify input file")
+	}
+	call := &offlineCall{}
+	err := utils.LoadOfflineFile(file, call)
+	if err != nil {
+		return nil, err
+	}
+	err = call.CheckOffline(offlineKindAdminCall, stage)
+	if err != nil {
+		return nil, err
+	}
+	return call, call.verify()
+}
+
+// verify checks the call args, and the signature if signed
+func (call *offlineCall) verify() error {
+	args := call.CallArgs
+	if args == nil {
+		return errors.New("missing admin call args")
+	}
+	if !isAdminMethod(args.Method) {
+		return fmt.Errorf("unknown admin method '%v'", args.Method)
+	}
+	if call.Stage == utils.OfflineStageUnsigned {
+		return nil
+	}
+	if call.Stage != utils.OfflineStageSigned {
+		return fmt.Errorf("unknown offline stage '%v'", call.Stage)
+	}
+	// expiration is checked when submitting, expired call can still be inspected
+	sender, signedArgs, err := admin.VerifyRecordedTransaction(call.RawTx)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(sender.String(), call.Signer) {
+		return fmt.Errorf("signer mismatch, have %v want %v", sender.String(), call.Signer)
+	}
+	if !reflect.DeepEqual(signedArgs, args) {
+		return errors.New("signed call args mismatch")
+	}
+	return nil
+}
+
+func signOffline(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	output := ctx.String(utils.OutputFileFlag.Name)
+	if output == "" {
+		return errors.New("must specify output file")
+	}
+	call, err := loadOfflineCall(ctx, utils.OfflineStageUnsigned)
+	if err != nil {
+		return err
+	}
+	err = loadKeyStore(ctx)
+	if err != nil {
+		return err
+	}
+
+	call.CallArgs.Timestamp = time.Now().Unix()
+	err = call.CallArgs.SetValidity(int64(ctx.Duration(validityFlag.Name) / time.Second))
+	if err != nil {
+		return err
+	}
+	call.RawTx, err = admin.SignCallArgs(call.CallArgs)
+	if err != nil {
+		return err
+	}
+	call.Stage = utils.OfflineStageSigned
+	call.SignedAt = call.CallArgs.Timestamp
+	call.Signer = admin.GetSignerAddress()
+
+	err = utils.SaveOfflineFile(output, call)
+	if err != nil {
+		return err
+	}
+	log.Printf("signed admin call is saved to %v, submit it before %v", output, time.Unix(call.CallArgs.ExpireTime(), 0))
+	return nil
+}
+
+func submitOffline(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	call, err := loadOfflineCall(ctx, utils.OfflineStageSigned)
+	if err != nil {
+		return err
+	}
+	if expireAt := call.CallArgs.ExpireTime(); time.Now().Unix() > expireAt {
+		return fmt.Errorf("signed admin call is expired at %v", time.Unix(expireAt, 0))
+	}
+	err = initSwapServer(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("admin submit: %v %v signed by %v", call.CallArgs.Method, call.CallArgs.Params, call.Signer)
+	result, err := submitRawTx(call.RawTx)
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func inspectOffline(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	call, err := loadOfflineCall(ctx, "")
+	if err != nil {
+		return err
+	}
+	args := call.CallArgs
+	fmt.Printf("kind:      %v\n", call.Kind)
+	fmt.Printf("stage:     %v\n", call.Stage)
+	fmt.Printf("createdAt: %v\n", time.Unix(call.CreatedAt, 0))
+	fmt.Printf("method:    %v\n", args.Method)
+	fmt.Printf("params:    %q\n", args.Params)
+	if call.Stage == utils.OfflineStageSigned {
+		fmt.Printf("signer:    %v\n", call.Signer)
+		fmt.Printf("signedAt:  %v\n", time.Unix(call.SignedAt, 0))
+		fmt.Printf("expireAt:  %v\n", time.Unix(args.ExpireTime(), 0))
+		fmt.Printf("nonce:     %v\n", args.Nonce)
+	}
+	log.Info("inspect admin call file success")
+	return nil
+}
@@ -0,0 +1,286 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/internal/swapapi"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	nodeSliceFlag = &cli.StringSliceFlag{
+		Name:  "node",
+		Usage: "rpc address of other swap server or oracle to compare",
+	}
+
+	pairsCommand = &cli.Command{
+		Name:  "pairs",
+		Usage: "manage token pairs config stored in database",
+		Description: `
+token pairs config can be stored in database of swap server (with 'PairsSource = "db"'),
+every pushed config is a new version signed by admin, and is applied by 'reloadpairs'.
+the signed call binds pairID, next version and hash of the latest version, which are
+queried from '--swapserver' (also required when building offline admin call).
+`,
+		Subcommands: []*cli.Command{
+			{
+				Action:    pushPair,
+				Name:      "push",
+				Usage:     "push new version of token pair config",
+				ArgsUsage: "<configFile>",
+				Flags:     commonAdminFlags,
+			},
+			{
+				Action:    removePair,
+				Name:      "remove",
+				Usage:     "push removed version of token pair",
+				ArgsUsage: "<pairID>",
+				Flags:     commonAdminFlags,
+			},
+			{
+				Action:    pairHistory,
+				Name:      "history",
+				Usage:     "query versions of token pair config",
+				ArgsUsage: "<pairID>",
+				Flags: []cli.Flag{
+					utils.SwapServerFlag,
+				},
+			},
+			{
+				Action:    diffPairs,
+				Name:      "diff",
+				Usage:     "compare local token pairs config with nodes",
+				ArgsUsage: " ",
+				Description: `
+compare hash of local token pairs config in '--pairsdir' with swap server and other nodes,
+field differences of mismatched pairs are shown for swap servers.
+`,
+				Flags: []cli.Flag{
+					utils.TokenPairsDirFlag,
+					utils.SwapServerFlag,
+					nodeSliceFlag,
+				},
+			},
+		},
+	}
+)
+
+func pushPair(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	if ctx.NArg() != 1 {
+		_ = cli.ShowCommandHelp(ctx, "push")
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	configFile := ctx.Args().Get(0)
+	content, err := ioutil.ReadFile(configFile)
+	if err != nil {
+		return err
+	}
+	pairCfg, err := tokens.ParseTokenPairConfig(string(content))
+	if err != nil {
+		return err
+	}
+
+	err = prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	params, err := getPairVersionParams(ctx, pairCfg.PairID)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("admin pushpair: %v pairID %v version %v prevHash '%v' hash %v", configFile, pairCfg.PairID, params[1], params[2], pairCfg.Hash().String())
+
+	params = append(params, string(content))
+	result, err := adminCall("pushpair", params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+func removePair(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	if ctx.NArg() != 1 {
+		_ = cli.ShowCommandHelp(ctx, "remove")
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	pairID := ctx.Args().Get(0)
+	params, err := getPairVersionParams(ctx, pairID)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("admin removepair: %v version %v prevHash '%v'", pairID, params[1], params[2])
+
+	result, err := adminCall("removepair", params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
+
+// getPairVersionParams get signed params [pairID, version, prevHash] of
+// the next version of pair config, which follows the latest version in server.
+func getPairVersionParams(ctx *cli.Context, pairID string) ([]string, error) {
+	err := initSwapServer(ctx)
+	if err != nil {
+		return nil, err
+	}
+	args := map[string]interface{}{
+		"pairid": pairID,
+		"limit":  1,
+	}
+	var result []*swapapi.PairConfigVersion
+	err = client.RPCPost(&result, swapServer, "swap.GetPairConfigVersions", args)
+	if err != nil {
+		return nil, err
+	}
+	version, prevHash := uint64(1), ""
+	if len(result) > 0 {
+		version, prevHash = result[0].Version+1, result[0].Hash
+	}
+	return []string{pairID, fmt.Sprint(version), prevHash}, nil
+}
+
+func pairHistory(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	if ctx.NArg() != 1 {
+		_ = cli.ShowCommandHelp(ctx, "history")
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+	err := initSwapServer(ctx)
+	if err != nil {
+		return err
+	}
+
+	args := map[string]interface{}{
+		"pairid": ctx.Args().Get(0),
+	}
+	var result []*swapapi.PairConfigVersion
+	err = client.RPCPost(&result, swapServer, "swap.GetPairConfigVersions", args)
+	if err != nil {
+		return err
+	}
+	if len(result) == 0 {
+		fmt.Println("no versions")
+		return nil
+	}
+	for _, v := range result {
+		state := v.Hash
+		if v.Removed {
+			state = "removed"
+		}
+		fmt.Printf("%v version %v %v admin=%v\n",
+			time.Unix(v.Timestamp, 0).Format(time.RFC3339), v.Version, state, v.Admin)
+	}
+	return nil
+}
+
+func diffPairs(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	pairsDir := utils.GetTokenPairsDir(ctx)
+	if pairsDir == "" {
+		return errors.New("must specify pairsdir")
+	}
+	nodes := ctx.StringSlice(nodeSliceFlag.Name)
+	if server := ctx.String(utils.SwapServerFlag.Name); server != "" {
+		nodes = append([]string{server}, nodes...)
+	}
+	if len(nodes) == 0 {
+		return errors.New("must specify swapserver or node")
+	}
+
+	localPairs, err := tokens.LoadTokenPairsConfigInDir(pairsDir, false)
+	if err != nil {
+		return err
+	}
+	local := tokens.CalcPairsConfigHash(localPairs)
+	fmt.Printf("local %v pairs %v\n", local.Hash, len(local.Pairs))
+
+	mismatchCount := 0
+	for _, node := range nodes {
+		remote, isServer, errf := getNodePairsConfigHash(node)
+		if errf != nil {
+			fmt.Printf("node %v: error %v\n", node, errf)
+			mismatchCount++
+			continue
+		}
+		pairIDs := tokens.DiffPairsConfigHash(local, remote)
+		if len(pairIDs) == 0 {
+			fmt.Printf("node %v: %v match\n", node, remote.Hash)
+			continue
+		}
+		mismatchCount++
+		fmt.Printf("node %v: %v mismatch\n", node, remote.Hash)
+		for _, pairID := range pairIDs {
+			fmt.Printf("  %v\n", diffPairWithNode(node, isServer, pairID, localPairs[pairID], remote))
+		}
+	}
+	if mismatchCount > 0 {
+		return fmt.Errorf("%v of %v nodes mismatch", mismatchCount, len(nodes))
+	}
+	return nil
+}
+
+// getNodePairsConfigHash get pairs config hash from swap server or oracle
+func getNodePairsConfigHash(node string) (result *tokens.PairsConfigHash, isServer bool, err error) {
+	result = &tokens.PairsConfigHash{}
+	err = client.RPCPost(result, node, "swap.GetPairsConfigHash")
+	if err == nil {
+		return result, true, nil
+	}
+	errOracle := client.RPCPost(result, node, "oracle.GetPairsConfigHash")
+	if errOracle == nil {
+		return result, false, nil
+	}
+	return nil, false, err
+}
+
+func diffPairWithNode(node string, isServer bool, pairID string, localPair *tokens.TokenPairConfig, remote *tokens.PairsConfigHash) string {
+	remoteHash, exist := remote.Pairs[pairID]
+	switch {
+	case localPair == nil:
+		return fmt.Sprintf("%v: only on node", pairID)
+	case !exist:
+		return fmt.Sprintf("%v: only on local", pairID)
+	case !isServer:
+		return fmt.Sprintf("%v: local %v node %v", pairID, localPair.Hash().String(), remoteHash)
+	}
+	var remotePair tokens.TokenPairConfig
+	err := client.RPCPost(&remotePair, node, "swap.GetTokenPairInfo", pairID)
+	if err != nil {
+		return fmt.Sprintf("%v: local %v node %v (get pair info failed, %v)", pairID, localPair.Hash().String(), remoteHash, err)
+	}
+	if remotePair.SrcToken != nil {
+		remotePair.SrcToken.FeeSchedule = nil
+	}
+	if remotePair.DestToken != nil {
+		remotePair.DestToken.FeeSchedule = nil
+	}
+	changes := tokens.DiffPublicPairsConfig(
+		map[string]*tokens.TokenPairConfig{pairID: localPair},
+		map[string]*tokens.TokenPairConfig{pairID: &remotePair},
+	)
+	if len(changes) == 0 {
+		return fmt.Sprintf("%v: local %v node %v", pairID, localPair.Hash().String(), remoteHash)
+	}
+	return fmt.Sprintf("%v (local -> node)", changes[0].String())
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	reloadpairsCommand = &cli.Command{
+		Action:    reloadpairs,
+		Name:      "reloadpairs",
+		Usage:     "reload token pairs",
+		ArgsUsage: "[dryrun]",
+		Description: `
+reload token pairs config in token pairs dir of server,
+new pairs are added, changed pairs are updated in place, missing pairs are removed.
+use 'dryrun' to show the changes without applying them.
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func reloadpairs(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "reloadpairs"
+	if ctx.NArg() > 1 || (ctx.NArg() == 1 && ctx.Args().Get(0) != "dryrun") {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	params := ctx.Args().Slice()
+
+	log.Printf("admin reloadpairs: %v", params)
+
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/internal/swapapi"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	statusHistoryCommand = &cli.Command{
+		Action:    statusHistory,
+		Name:      "statushistory",
+		Usage:     "query swap status history",
+		ArgsUsage: "<swapin|swapout> <txid> <pairID> [bind]",
+		Description: `
+query status changes of swap and swap result in time order.
+this is a query command, no admin signing is needed.
+`,
+		Flags: []cli.Flag{
+			utils.SwapServerFlag,
+		},
+	}
+)
+
+func statusHistory(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "statushistory"
+	if !(ctx.NArg() == 3 || ctx.NArg() == 4) {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+	err := initSwapServer(ctx)
+	if err != nil {
+		return err
+	}
+
+	operation := ctx.Args().Get(0)
+	var rpcMethod string
+	switch operation {
+	case swapinOp:
+		rpcMethod = "swap.GetSwapinStatusHistory"
+	case swapoutOp:
+		rpcMethod = "swap.GetSwapoutStatusHistory"
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	args := map[string]string{
+		"txid":   ctx.Args().Get(1),
+		"pairid": ctx.Args().Get(2),
+		"bind":   ctx.Args().Get(3),
+	}
+
+	var result []*swapapi.SwapStatusChange
+	err = client.RPCPost(&result, swapServer, rpcMethod, args)
+	if err != nil {
+		return err
+	}
+	if len(result) == 0 {
+		fmt.Println("no status change")
+		return nil
+	}
+	for _, change := range result {
+		kind := "swap"
+		if change.IsResult {
+			kind = "result"
+		}
+		fmt.Printf("%v %-6v %v -> %v actor=%v reason=%q\n",
+			time.Unix(change.Timestamp, 0).Format(time.RFC3339), kind,
+			change.FromMsg, change.ToMsg, change.Actor, change.Reason)
+	}
+	return nil
+}
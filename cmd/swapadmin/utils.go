@@ -2,6 +2,8 @@ package main
 
 import (
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/admin"
 	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
@@ -12,22 +14,52 @@ import (
 var (
 	swapServer string
 
+	// build unsigned admin call to this file instead of signing and calling
+	offlineFile string
+
 	commonAdminFlags = []cli.Flag{
 		utils.SwapServerFlag,
 		utils.KeystoreFileFlag,
 		utils.PasswordFileFlag,
+		utils.OutputFileFlag,
 	}
 )
 
 func adminCall(method string, params []string) (result interface{}, err error) {
+	if offlineFile != "" {
+		err = buildOfflineCall(offlineFile, method, params)
+		if err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("unsigned admin call is saved to %v", offlineFile), nil
+	}
 	rawTx, err := admin.Sign(method, params)
 	if err != nil {
 		return "", err
 	}
+	return submitRawTx(rawTx)
+}
+
+func submitRawTx(rawTx string) (result interface{}, err error) {
 	err = client.RPCPost(&result, swapServer, "swap.AdminCall", rawTx)
 	return result, err
 }
 
+func buildOfflineCall(file, method string, params []string) error {
+	call := &offlineCall{
+		OfflineHeader: utils.OfflineHeader{
+			Kind:      offlineKindAdminCall,
+			Stage:     utils.OfflineStageUnsigned,
+			CreatedAt: time.Now().Unix(),
+		},
+		CallArgs: &admin.CallArgs{
+			Method: method,
+			Params: params,
+		},
+	}
+	return utils.SaveOfflineFile(file, call)
+}
+
 func loadKeyStore(ctx *cli.Context) error {
 	keyfile := ctx.String(utils.KeystoreFileFlag.Name)
 	passfile := ctx.String(utils.PasswordFileFlag.Name)
@@ -43,6 +75,12 @@ func initSwapServer(ctx *cli.Context) error {
 }
 
 func prepare(ctx *cli.Context) (err error) {
+	// offline build does not need keystore and swap server
+	offlineFile = ctx.String(utils.OutputFileFlag.Name)
+	if offlineFile != "" {
+		return nil
+	}
+
 	err = loadKeyStore(ctx)
 	if err != nil {
 		return err
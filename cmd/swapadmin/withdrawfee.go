@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	withdrawfeeCommand = &cli.Command{
+		Action:    withdrawfee,
+		Name:      "withdrawfee",
+		Usage:     "admin withdraw collected fee",
+		ArgsUsage: "<swapin|swapout> <pairID> [amount]",
+		Description: `
+admin withdraw collected fee to fee treasury of token config,
+swapin fee is collected on destination blockchain,
+swapout fee is collected on source blockchain,
+withdraw all withdrawable fee if amount is not specified.
+`,
+		Flags: commonAdminFlags,
+	}
+)
+
+func withdrawfee(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	method := "withdrawfee"
+	if !(ctx.NArg() == 2 || ctx.NArg() == 3) {
+		_ = cli.ShowCommandHelp(ctx, method)
+		fmt.Println()
+		return fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+
+	err := prepare(ctx)
+	if err != nil {
+		return err
+	}
+
+	operation := ctx.Args().Get(0)
+	pairID := ctx.Args().Get(1)
+
+	switch operation {
+	case swapinOp, swapoutOp:
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+
+	params := []string{operation, pairID}
+	if ctx.NArg() > 2 {
+		amount := ctx.Args().Get(2)
+		if _, err = common.GetBigIntFromStr(amount); err != nil {
+			return fmt.Errorf("wrong amount value '%v'", amount)
+		}
+		params = append(params, amount)
+	}
+
+	log.Printf("admin withdrawfee: %v", params)
+
+	result, err := adminCall(method, params)
+
+	log.Printf("result is '%v'", result)
+	return err
+}
@@ -8,7 +8,9 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/params"
+	rpcserver "github.com/anyswap/CrossChain-Bridge/rpc/server"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
 	"github.com/anyswap/CrossChain-Bridge/worker"
 	"github.com/urfave/cli/v2"
 )
@@ -58,12 +60,23 @@ func swaporacle(ctx *cli.Context) error {
 	}
 	exitCh := make(chan struct{})
 	configFile := utils.GetConfigFilePath(ctx)
-	params.LoadConfig(configFile, false)
+	config := params.LoadConfig(configFile, false)
 
 	tokens.SetTokenPairsDir(utils.GetTokenPairsDir(ctx))
 
+	if ledgerFile := config.Oracle.LedgerFile; ledgerFile != "" {
+		if err := tools.InitSwapLedger(ledgerFile); err != nil {
+			log.Fatalf("init swap ledger failed. %v", err)
+		}
+	}
+
 	worker.StartWork(false)
 
+	// serve swap attestation and pairs config hash
+	if config.APIServer != nil {
+		rpcserver.StartOracleAPIServer()
+	}
+
 	<-exitCh
 	return nil
 }
@@ -0,0 +1,300 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	checkpointFlag = &cli.StringFlag{
+		Name:  "checkpoint",
+		Usage: "checkpoint file to record scanned ranges and resume from on restart",
+	}
+
+	checkpointSaveInterval = 5 * time.Second
+)
+
+// heightRange is block heights range [From, To)
+type heightRange struct {
+	From uint64 `json:"from"`
+	To   uint64 `json:"to"`
+}
+
+type checkpointJob struct {
+	Scanned   []*heightRange `json:"scanned"`
+	UpdatedAt int64          `json:"updatedAt"`
+}
+
+type scanCheckpoint struct {
+	lock     sync.Mutex
+	file     string
+	lastSave time.Time
+
+	Jobs map[string]*checkpointJob `json:"jobs"`
+}
+
+// checkpoint is nil if checkpoint file is not specified
+var checkpoint *scanCheckpoint
+
+func initCheckpoint(ctx *cli.Context) {
+	file := ctx.String(checkpointFlag.Name)
+	if file == "" {
+		return
+	}
+	cp := &scanCheckpoint{
+		file: file,
+		Jobs: make(map[string]*checkpointJob),
+	}
+	if common.FileExist(file) {
+		data, err := ioutil.ReadFile(file)
+		if err != nil {
+			log.Fatalf("read checkpoint file failed, %v", err)
+		}
+		err = json.Unmarshal(data, cp)
+		if err != nil {
+			log.Fatalf("wrong checkpoint file '%v', %v", file, err)
+		}
+		if cp.Jobs == nil {
+			cp.Jobs = make(map[string]*checkpointJob)
+		}
+	}
+	checkpoint = cp
+	log.Info("init checkpoint success", "file", file, "jobs", len(cp.Jobs))
+}
+
+// getCheckpointJobKey identify scan job by its chain and scan targets
+func getCheckpointJobKey(chain, swapType string, targets ...string) string {
+	return fmt.Sprintf("%v:%v:%v", chain, swapType, targets)
+}
+
+func (cp *scanCheckpoint) getJob(key string) *checkpointJob {
+	job, exist := cp.Jobs[key]
+	if !exist {
+		job = &checkpointJob{}
+		cp.Jobs[key] = job
+	}
+	return job
+}
+
+// markScanned add range [from, to) to scanned ranges of job
+func (cp *scanCheckpoint) markScanned(key string, from, to uint64) {
+	if cp == nil || from >= to {
+		return
+	}
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+
+	job := cp.getJob(key)
+	job.Scanned = mergeRanges(append(job.Scanned, &heightRange{From: from, To: to}))
+	job.UpdatedAt = time.Now().Unix()
+
+	if time.Since(cp.lastSave) >= checkpointSaveInterval {
+		cp.save()
+	}
+}
+
+// flush save checkpoint file immediately
+func (cp *scanCheckpoint) flush() {
+	if cp == nil {
+		return
+	}
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+	cp.save()
+}
+
+// save must be called with lock held, write to temp file then rename
+// to prevent corrupting checkpoint file when crash in writing
+func (cp *scanCheckpoint) save() {
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err == nil {
+		tmpFile := cp.file + ".tmp"
+		err = ioutil.WriteFile(tmpFile, data, 0600)
+		if err == nil {
+			err = os.Rename(tmpFile, cp.file)
+		}
+	}
+	if err != nil {
+		log.Warn("save checkpoint failed", "file", cp.file, "err", err)
+		return
+	}
+	cp.lastSave = time.Now()
+}
+
+// getUnscanned get unscanned ranges of job in range [from, to)
+func (cp *scanCheckpoint) getUnscanned(key string, from, to uint64) []*heightRange {
+	if from >= to {
+		return nil
+	}
+	if cp == nil {
+		return []*heightRange{{From: from, To: to}}
+	}
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+
+	var result []*heightRange
+	next := from
+	for _, r := range cp.getJob(key).Scanned {
+		if r.To <= next {
+			continue
+		}
+		if r.From >= to {
+			break
+		}
+		if r.From > next {
+			result = append(result, &heightRange{From: next, To: r.From})
+		}
+		next = r.To
+		if next >= to {
+			break
+		}
+	}
+	if next < to {
+		result = append(result, &heightRange{From: next, To: to})
+	}
+	return result
+}
+
+// getResumeHeight get the end of the first scanned range which is below latest,
+// blocks failed to scan are gaps between scanned ranges, and are scanned again
+func (cp *scanCheckpoint) getResumeHeight(key string, latest uint64) uint64 {
+	if cp == nil {
+		return latest
+	}
+	cp.lock.Lock()
+	defer cp.lock.Unlock()
+
+	scanned := cp.getJob(key).Scanned
+	if len(scanned) > 0 && scanned[0].To <= latest {
+		return scanned[0].To
+	}
+	return latest
+}
+
+// advanceScanLoop mark stable blocks in (from, latest-stable] scanned in scan loop,
+// except the lowest failed block (if not zero) and above which are scanned again
+// in next loop. returns the height above which blocks are scanned in next loop.
+func advanceScanLoop(key string, from, latest, stable, failed uint64) uint64 {
+	if from+stable >= latest {
+		return from
+	}
+	next := latest - stable
+	if failed != 0 && failed <= next {
+		next = failed - 1
+	}
+	checkpoint.markScanned(key, from+1, next+1)
+	return next
+}
+
+// mergeRanges sort and merge overlapping or adjacent ranges
+func mergeRanges(ranges []*heightRange) []*heightRange {
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].From < ranges[j].From
+	})
+	result := make([]*heightRange, 0, len(ranges))
+	for _, r := range ranges {
+		last := len(result) - 1
+		if last >= 0 && r.From <= result[last].To {
+			if r.To > result[last].To {
+				result[last].To = r.To
+			}
+			continue
+		}
+		result = append(result, &heightRange{From: r.From, To: r.To})
+	}
+	return result
+}
+
+// splitRanges split ranges to parts of at most `step` blocks
+func splitRanges(ranges []*heightRange, step uint64) []*heightRange {
+	var result []*heightRange
+	for _, r := range ranges {
+		for from := r.From; from < r.To; from += step {
+			to := from + step
+			if to > r.To {
+				to = r.To
+			}
+			result = append(result, &heightRange{From: from, To: to})
+		}
+	}
+	return result
+}
+
+// scanBlocksFunc scan blocks in range [from, to), return error if any
+// block or swap in range failed, the range is not marked scanned then
+type scanBlocksFunc func(job, from, to uint64) error
+
+// doScanRangeJob scan unscanned blocks in range [start, end) with `jobs` routines,
+// every time scan at most `batch` blocks and record them in checkpoint.
+// wait for finishing if `wait` is true.
+func doScanRangeJob(key string, start, end, jobs, batch uint64, wait bool, scanBlocks scanBlocksFunc) {
+	pending := checkpoint.getUnscanned(key, start, end)
+	if len(pending) == 0 {
+		return
+	}
+	var count uint64
+	for _, r := range pending {
+		count += r.To - r.From
+	}
+	step := count / jobs
+	if step == 0 {
+		jobs = 1
+		step = count
+	}
+	parts := splitRanges(pending, step)
+	log.Info("start scan range job", "start", start, "end", end, "unscanned", count, "parts", len(parts), "jobs", jobs)
+
+	ch := make(chan *heightRange, len(parts))
+	for _, part := range parts {
+		ch <- part
+	}
+	close(ch)
+
+	wg := new(sync.WaitGroup)
+	for i := uint64(0); i < jobs; i++ {
+		wg.Add(1)
+		go func(job uint64) {
+			defer wg.Done()
+			for part := range ch {
+				scanRange(key, job, part.From, part.To, batch, scanBlocks)
+			}
+		}(i + 1)
+	}
+	done := func() {
+		wg.Wait()
+		checkpoint.flush()
+		log.Info("scan range job finish", "start", start, "end", end)
+	}
+	if wait {
+		done()
+	} else {
+		go done()
+	}
+}
+
+func scanRange(key string, job, from, to, batch uint64, scanBlocks scanBlocksFunc) {
+	log.Info(fmt.Sprintf("[%v] start scan range", job), "from", from, "to", to)
+
+	for h := from; h < to; h += batch {
+		end := h + batch
+		if end > to {
+			end = to
+		}
+		if err := scanBlocks(job, h, end); err != nil {
+			log.Warn(fmt.Sprintf("[%v] scan blocks failed, they will be scanned again on resume", job), "from", h, "to", end, "err", err)
+			continue
+		}
+		checkpoint.markScanned(key, h, end)
+	}
+
+	log.Info(fmt.Sprintf("[%v] scan range finish", job), "from", from, "to", to)
+}
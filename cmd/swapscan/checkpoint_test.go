@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestScanRangeSkipsFailedBatches(t *testing.T) {
+	dir, err := ioutil.TempDir("", "swapscan")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	checkpoint = &scanCheckpoint{
+		file: filepath.Join(dir, "checkpoint.json"),
+		Jobs: make(map[string]*checkpointJob),
+	}
+	defer func() { checkpoint = nil }()
+
+	key := "test"
+	failAt := uint64(120)
+	scanBlocks := func(job, from, to uint64) error {
+		if from <= failAt && failAt < to {
+			return errors.New("post swap failed")
+		}
+		return nil
+	}
+	doScanRangeJob(key, 100, 150, 1, 10, true, scanBlocks)
+
+	want := []*heightRange{{From: 100, To: 120}, {From: 130, To: 150}}
+	if have := checkpoint.getJob(key).Scanned; !reflect.DeepEqual(have, want) {
+		t.Errorf("scanned ranges mismatch, have %v want %v", have, want)
+	}
+	if have := checkpoint.getResumeHeight(key, 200); have != 120 {
+		t.Errorf("resume height mismatch, have %v want 120", have)
+	}
+	if have := checkpoint.getUnscanned(key, 120, 200); !reflect.DeepEqual(have, []*heightRange{{From: 120, To: 130}, {From: 150, To: 200}}) {
+		t.Errorf("unscanned ranges mismatch, have %v", have)
+	}
+
+	// scan loop keeps blocks from the lowest failed one to be scanned again
+	if next := advanceScanLoop(key, 149, 170, 5, 160); next != 159 {
+		t.Errorf("advance scan loop with failure, have %v want 159", next)
+	}
+	if next := advanceScanLoop(key, 159, 175, 5, 0); next != 170 {
+		t.Errorf("advance scan loop without failure, have %v want 170", next)
+	}
+	want = []*heightRange{{From: 100, To: 120}, {From: 130, To: 171}}
+	if have := checkpoint.getJob(key).Scanned; !reflect.DeepEqual(have, want) {
+		t.Errorf("scanned ranges after loop mismatch, have %v want %v", have, want)
+	}
+}
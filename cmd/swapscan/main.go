@@ -26,6 +26,9 @@ func initApp() {
 		scanEthCommand,
 		scanBtcCommand,
 		scanLtcCommand,
+		scanCosmosCommand,
+		scanBlockCommand,
+		replayCommand,
 		utils.LicenseCommand,
 		utils.VersionCommand,
 	}
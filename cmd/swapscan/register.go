@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+	"github.com/urfave/cli/v2"
+)
+
+// swapRecord is a discovered swap written to output file in JSONL format
+type swapRecord struct {
+	Method string            `json:"method"`
+	Args   map[string]string `json:"args"`
+	Time   int64             `json:"time"`
+}
+
+type swapRegister struct {
+	swapServer    string
+	rpcRetryCount int
+	rpcInterval   time.Duration
+
+	lock   sync.Mutex
+	output *os.File
+}
+
+var register = &swapRegister{
+	rpcRetryCount: 3,
+	rpcInterval:   time.Second,
+}
+
+// initRegister init swap register, write discovered swaps
+// to output file (if specified) instead of posting to swap server
+func initRegister(ctx *cli.Context) {
+	register.swapServer = ctx.String(utils.SwapServerFlag.Name)
+	outputFile := ctx.String(utils.OutputFileFlag.Name)
+	if outputFile == "" {
+		if register.swapServer == "" {
+			log.Fatal("must specify swap server address or output file")
+		}
+		return
+	}
+	file, err := os.OpenFile(outputFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Fatalf("open output file failed, %v", err)
+	}
+	register.output = file
+	log.Info("write discovered swaps to output file", "file", outputFile)
+}
+
+func isOutputMode() bool {
+	return register.output != nil
+}
+
+// postSwap post swap to swap server (or write to output file), return error if failed,
+// blocks of failed swaps are not marked scanned and will be scanned again
+func (r *swapRegister) postSwap(subject, method string, args map[string]string) error {
+	log.Info(subject, "method", method, "args", args)
+	if r.output != nil {
+		r.writeSwap(method, args)
+		return nil
+	}
+	if err := r.callSwapServer(method, args); err != nil {
+		log.Warn(subject+" failed", "args", args, "err", err)
+		return fmt.Errorf("%v failed, txid %v, %w", subject, args["txid"], err)
+	}
+	return nil
+}
+
+func (r *swapRegister) writeSwap(method string, args map[string]string) {
+	record := &swapRecord{
+		Method: method,
+		Args:   args,
+		Time:   time.Now().Unix(),
+	}
+	data, err := json.Marshal(record)
+	if err != nil {
+		log.Warn("marshal swap record failed", "args", args, "err", err)
+		return
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	_, err = r.output.Write(append(data, '\n'))
+	if err != nil {
+		log.Fatalf("write swap record failed, %v", err)
+	}
+}
+
+// callSwapServer post swap register with retrying,
+// swap already registered is regarded as success
+func (r *swapRegister) callSwapServer(method string, args map[string]string) (err error) {
+	var result interface{}
+	for i := 0; i < r.rpcRetryCount; i++ {
+		err = client.RPCPost(&result, r.swapServer, method, args)
+		if tokens.ShouldRegisterSwapForError(err) {
+			return nil
+		}
+		if tools.IsSwapAlreadyExistRegisterError(err) {
+			return nil
+		}
+		time.Sleep(r.rpcInterval)
+	}
+	return err
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	replayCommand = &cli.Command{
+		Action:    replay,
+		Name:      "replay",
+		Usage:     "replay discovered swaps in output file to swap server",
+		ArgsUsage: " ",
+		Description: `
+replay swaps written by scan commands with '--output' flag to swap server.
+swaps already registered are regarded as success.
+swaps failed to register are written to the file specified by '--output' flag.
+
+Example:
+
+./swapscan scaneth --output ./swaps.jsonl ...
+./swapscan replay --swapserver http://1.2.3.4:5555/rpc --input ./swaps.jsonl --output ./failed.jsonl
+`,
+		Flags: []cli.Flag{
+			utils.SwapServerFlag,
+			utils.InputFileFlag,
+			utils.OutputFileFlag,
+		},
+	}
+
+	replayMethods = map[string]bool{
+		"swap.Swapin":     true,
+		"swap.Swapout":    true,
+		"swap.P2shSwapin": true,
+	}
+)
+
+func replay(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	inputFile := ctx.String(utils.InputFileFlag.Name)
+	if inputFile == "" {
+		return errors.New("must specify input file")
+	}
+	register.swapServer = ctx.String(utils.SwapServerFlag.Name)
+	if register.swapServer == "" {
+		return errors.New("must specify swap server address")
+	}
+	if outputFile := ctx.String(utils.OutputFileFlag.Name); outputFile != "" {
+		if outputFile == inputFile {
+			return errors.New("output file must be different with input file")
+		}
+		file, err := os.OpenFile(outputFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		register.output = file
+	}
+
+	file, err := os.Open(inputFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	var total, success, failed int
+	scanner := bufio.NewScanner(file)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		record := &swapRecord{}
+		if err = json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return fmt.Errorf("wrong swap record at line %v: %v", line, err)
+		}
+		if !replayMethods[record.Method] {
+			return fmt.Errorf("unknown method '%v' at line %v", record.Method, line)
+		}
+		total++
+		err = register.callSwapServer(record.Method, record.Args)
+		if err == nil {
+			success++
+			log.Info("replay swap success", "line", line, "method", record.Method, "args", record.Args)
+			continue
+		}
+		failed++
+		log.Warn("replay swap failed", "line", line, "method", record.Method, "args", record.Args, "err", err)
+		if register.output != nil {
+			register.writeSwap(record.Method, record.Args)
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+	log.Info("replay swaps finished", "total", total, "success", success, "failed", failed)
+	return nil
+}
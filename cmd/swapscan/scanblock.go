@@ -0,0 +1,332 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/block"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+	"github.com/anyswap/CrossChain-Bridge/tokens/eth"
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	rpcUserFlag = &cli.StringFlag{
+		Name:  "rpcUser",
+		Usage: "blocknet core rpc user name",
+	}
+	rpcPassFlag = &cli.StringFlag{
+		Name:  "rpcPass",
+		Usage: "blocknet core rpc password",
+	}
+
+	scanBlockCommand = &cli.Command{
+		Action:    scanBlocknet,
+		Name:      "scanblock",
+		Usage:     "scan swap on block",
+		ArgsUsage: " ",
+		Description: `
+scan swap on blocknet, gateway is the blocknet core rpc address
+`,
+		Flags: []cli.Flag{
+			rpcUserFlag,
+			rpcPassFlag,
+			mongoURLFlag,
+			dbNameFlag,
+			dbUserFlag,
+			dbPassFlag,
+			utils.GatewayFlag,
+			utils.SwapServerFlag,
+			utils.DepositAddressFlag,
+			utils.PairIDFlag,
+			utils.StartHeightFlag,
+			utils.EndHeightFlag,
+			utils.StableHeightFlag,
+			utils.JobsFlag,
+			utils.OutputFileFlag,
+			checkpointFlag,
+		},
+	}
+)
+
+type blockSwapScanner struct {
+	rpcUser        string
+	rpcPassword    string
+	gateway        string
+	swapServer     string
+	depositAddress string
+	pairID         string
+	startHeight    uint64
+	endHeight      uint64
+	stableHeight   uint64
+	jobCount       uint64
+
+	rpcInterval   time.Duration
+	rpcRetryCount int
+
+	bridge *btc.Bridge
+}
+
+func scanBlocknet(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	scanner := &blockSwapScanner{
+		rpcInterval:   1 * time.Second,
+		rpcRetryCount: 3,
+	}
+	scanner.rpcUser = ctx.String(rpcUserFlag.Name)
+	scanner.rpcPassword = ctx.String(rpcPassFlag.Name)
+	scanner.gateway = ctx.String(utils.GatewayFlag.Name)
+	scanner.swapServer = ctx.String(utils.SwapServerFlag.Name)
+	scanner.depositAddress = ctx.String(utils.DepositAddressFlag.Name)
+	scanner.pairID = ctx.String(utils.PairIDFlag.Name)
+	if scanner.pairID == "" {
+		scanner.pairID = "block"
+	}
+	scanner.startHeight = ctx.Uint64(utils.StartHeightFlag.Name)
+	scanner.endHeight = ctx.Uint64(utils.EndHeightFlag.Name)
+	scanner.stableHeight = ctx.Uint64(utils.StableHeightFlag.Name)
+	scanner.jobCount = ctx.Uint64(utils.JobsFlag.Name)
+
+	log.Info("get argument success",
+		"gateway", scanner.gateway,
+		"swapServer", scanner.swapServer,
+		"depositAddress", scanner.depositAddress,
+		"pairID", scanner.pairID,
+		"start", scanner.startHeight,
+		"end", scanner.endHeight,
+		"stable", scanner.stableHeight,
+		"jobs", scanner.jobCount,
+	)
+
+	initRegister(ctx)
+	initCheckpoint(ctx)
+	scanner.initMongodb(ctx)
+	scanner.initBridge()
+	scanner.verifyOptions()
+	scanner.run()
+	return nil
+}
+
+func (scanner *blockSwapScanner) verifyOptions() {
+	if !scanner.bridge.IsValidAddress(scanner.depositAddress) {
+		log.Fatalf("invalid deposit address '%v'", scanner.depositAddress)
+	}
+	if scanner.gateway == "" {
+		log.Fatal("must specify gateway address")
+	}
+	if !isOutputMode() {
+		oracle := params.OracleConfig{
+			ServerAPIAddress: scanner.swapServer,
+		}
+		err := oracle.CheckConfig()
+		if err != nil {
+			log.Fatalf("check swap server failed. %v", err)
+		}
+	}
+
+	start := scanner.startHeight
+	end := scanner.endHeight
+	jobs := scanner.jobCount
+	if end != 0 && start >= end {
+		log.Fatalf("wrong scan range [%v, %v)", start, end)
+	}
+	if jobs == 0 {
+		log.Fatal("zero jobs specified")
+	}
+}
+
+func (scanner *blockSwapScanner) initMongodb(ctx *cli.Context) {
+	dbURL := ctx.String(mongoURLFlag.Name)
+	dbName := ctx.String(dbNameFlag.Name)
+	userName := ctx.String(dbUserFlag.Name)
+	passwd := ctx.String(dbPassFlag.Name)
+	if dbName != "" {
+		mongodb.MongoServerInit([]string{dbURL}, dbName, userName, passwd)
+	}
+}
+
+func (scanner *blockSwapScanner) initBridge() {
+	scanner.bridge = block.NewCrossChainBridge(true)
+	scanner.bridge.GatewayConfig = &tokens.GatewayConfig{
+		APIAddress: []string{scanner.gateway},
+		Extras: &tokens.GatewayExtras{
+			BlockExtra: &tokens.BlockExtraArgs{
+				CoreAPIs: []tokens.BlocknetCoreAPIArgs{
+					{
+						APIAddress:  scanner.gateway,
+						RPCUser:     scanner.rpcUser,
+						RPCPassword: scanner.rpcPassword,
+						DisableTLS:  true,
+					},
+				},
+			},
+		},
+	}
+	blockDecimals := uint8(8)
+	scanner.bridge.ChainConfig = &tokens.ChainConfig{
+		BlockChain:    "Block",
+		NetID:         "Mainnet",
+		Confirmations: &scanner.stableHeight,
+	}
+	pairConfig := &tokens.TokenPairConfig{
+		PairID: scanner.pairID,
+		SrcToken: &tokens.TokenConfig{
+			ID:             "BLOCK",
+			Name:           "BLOCK",
+			Symbol:         "BLOCK",
+			Decimals:       &blockDecimals,
+			DepositAddress: scanner.depositAddress,
+		},
+	}
+	pairsConfig := make(map[string]*tokens.TokenPairConfig)
+	pairsConfig[strings.ToLower(scanner.pairID)] = pairConfig
+	tokens.SetTokenPairsConfig(pairsConfig, false)
+	scanner.bridge.SetP2shPairID(scanner.pairID)
+	tokens.SrcBridge = scanner.bridge
+	tokens.DstBridge = eth.NewCrossChainBridge(false)
+}
+
+func (scanner *blockSwapScanner) run() {
+	start := scanner.startHeight
+	wend := scanner.endHeight
+	if wend == 0 {
+		wend = tools.LoopGetLatestBlockNumber(scanner.bridge)
+	}
+	if start == 0 {
+		start = checkpoint.getResumeHeight(scanner.getCheckpointJobKey(), wend)
+	}
+
+	scanner.doScanRangeJob(start, wend)
+
+	if scanner.endHeight == 0 {
+		go scanner.scanPool()
+		scanner.scanLoop(wend - 1) // range job scans blocks below wend
+	}
+}
+
+func (scanner *blockSwapScanner) getCheckpointJobKey() string {
+	return getCheckpointJobKey("block", "swapin", scanner.depositAddress)
+}
+
+func (scanner *blockSwapScanner) doScanRangeJob(start, end uint64) {
+	scanBlocks := func(job, from, to uint64) error {
+		return scanner.scanBlock(job, from, false)
+	}
+	doScanRangeJob(scanner.getCheckpointJobKey(), start, end, scanner.jobCount, 1, scanner.endHeight != 0, scanBlocks)
+}
+
+func (scanner *blockSwapScanner) scanPool() {
+	scanner.bridge.StartPoolTransactionScanJob()
+}
+
+func (scanner *blockSwapScanner) scanLoop(from uint64) {
+	stable := scanner.stableHeight
+	log.Info("start scan loop", "from", from, "stable", stable)
+	for {
+		latest := tools.LoopGetLatestBlockNumber(scanner.bridge)
+		var failed uint64
+		for h := latest; h > from; h-- {
+			if err := scanner.scanBlock(0, h, true); err != nil {
+				failed = h
+			}
+		}
+		from = advanceScanLoop(scanner.getCheckpointJobKey(), from, latest, stable, failed)
+		time.Sleep(5 * time.Second)
+	}
+}
+
+func (scanner *blockSwapScanner) loopGetBlockHash(height uint64) string {
+	for {
+		blockHash, err := scanner.bridge.GetBlockHash(height)
+		if err == nil {
+			return blockHash
+		}
+		log.Warn("get block hash failed", "height", height, "err", err)
+		time.Sleep(scanner.rpcInterval)
+	}
+}
+
+func (scanner *blockSwapScanner) scanBlock(job, height uint64, cache bool) error {
+	blockHash := scanner.loopGetBlockHash(height)
+	if cache && blockCachedBlocks.isScanned(blockHash) {
+		return nil
+	}
+	// blocknet core returns all txs of block in one call
+	var txs []*electrs.ElectTx
+	var err error
+	for i := 0; i < scanner.rpcRetryCount; i++ {
+		txs, err = scanner.bridge.GetBlockTransactions(blockHash, 0)
+		if err == nil {
+			break
+		}
+		log.Warn("get block txs failed", "height", height, "err", err)
+		time.Sleep(scanner.rpcInterval)
+	}
+	if err != nil {
+		return err
+	}
+	log.Info(fmt.Sprintf("[%v] scan block %v start", job, height), "hash", blockHash, "txs", len(txs))
+	for i, tx := range txs {
+		log.Trace(fmt.Sprintf("[%v] scan block %v process tx", job, height), "txid", *tx.Txid, "index", i)
+		if errp := scanner.processTx(tx); errp != nil {
+			err = errp
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	if cache {
+		blockCachedBlocks.addBlock(blockHash)
+	}
+	log.Info(fmt.Sprintf("[%v] scan block %v finish", job, height))
+	return nil
+}
+
+func (scanner *blockSwapScanner) processTx(tx *electrs.ElectTx) (err error) {
+	txid := *tx.Txid
+	p2shBindAddrs, errc := scanner.bridge.CheckSwapinTxType(tx, scanner.pairID)
+	if errc != nil {
+		return nil
+	}
+	if len(p2shBindAddrs) > 0 {
+		for _, p2shBindAddr := range p2shBindAddrs {
+			args := map[string]string{
+				"txid": txid,
+				"bind": p2shBindAddr,
+			}
+			if errp := register.postSwap("post p2sh swapin register", "swap.P2shSwapin", args); errp != nil {
+				err = errp
+			}
+		}
+		return err
+	} else {
+		value, memoScript, rightReceiver := scanner.bridge.GetReceivedValue(tx.Vout, scanner.depositAddress, "p2pkh")
+		if !rightReceiver || value == 0 {
+			return nil
+		}
+		_, bindOk := btc.GetBindAddressFromMemoScipt(memoScript)
+		if !bindOk {
+			return nil
+		}
+		args := map[string]string{
+			"txid":   txid,
+			"pairid": scanner.pairID,
+		}
+		return register.postSwap("post swapin register", "swap.Swapin", args)
+	}
+}
+
+var blockCachedBlocks = &cachedSacnnedBlocks{
+	capacity:  100,
+	nextIndex: 0,
+	hashes:    make([]string, 100),
+}
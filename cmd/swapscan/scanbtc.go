@@ -2,14 +2,13 @@ package main
 
 import (
 	"fmt"
-	"sync"
+	"strings"
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/mongodb"
 	"github.com/anyswap/CrossChain-Bridge/params"
-	"github.com/anyswap/CrossChain-Bridge/rpc/client"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
@@ -58,10 +57,13 @@ scan swap on btc
 			utils.GatewayFlag,
 			utils.SwapServerFlag,
 			utils.DepositAddressFlag,
+			utils.PairIDFlag,
 			utils.StartHeightFlag,
 			utils.EndHeightFlag,
 			utils.StableHeightFlag,
 			utils.JobsFlag,
+			utils.OutputFileFlag,
+			checkpointFlag,
 		},
 	}
 )
@@ -71,6 +73,7 @@ type btcSwapScanner struct {
 	gateway        string
 	swapServer     string
 	depositAddress string
+	pairID         string
 	startHeight    uint64
 	endHeight      uint64
 	stableHeight   uint64
@@ -92,6 +95,10 @@ func scanBtc(ctx *cli.Context) error {
 	scanner.gateway = ctx.String(utils.GatewayFlag.Name)
 	scanner.swapServer = ctx.String(utils.SwapServerFlag.Name)
 	scanner.depositAddress = ctx.String(utils.DepositAddressFlag.Name)
+	scanner.pairID = ctx.String(utils.PairIDFlag.Name)
+	if scanner.pairID == "" {
+		scanner.pairID = "btc"
+	}
 	scanner.startHeight = ctx.Uint64(utils.StartHeightFlag.Name)
 	scanner.endHeight = ctx.Uint64(utils.EndHeightFlag.Name)
 	scanner.stableHeight = ctx.Uint64(utils.StableHeightFlag.Name)
@@ -102,12 +109,15 @@ func scanBtc(ctx *cli.Context) error {
 		"gateway", scanner.gateway,
 		"swapServer", scanner.swapServer,
 		"depositAddress", scanner.depositAddress,
+		"pairID", scanner.pairID,
 		"start", scanner.startHeight,
 		"end", scanner.endHeight,
 		"stable", scanner.stableHeight,
 		"jobs", scanner.jobCount,
 	)
 
+	initRegister(ctx)
+	initCheckpoint(ctx)
 	scanner.initMongodb(ctx)
 	scanner.initBridge()
 	scanner.verifyOptions()
@@ -122,16 +132,14 @@ func (scanner *btcSwapScanner) verifyOptions() {
 	if scanner.gateway == "" {
 		log.Fatal("must specify gateway address")
 	}
-	if scanner.swapServer == "" {
-		log.Fatal("must specify swap server address")
-	}
-
-	oracle := params.OracleConfig{
-		ServerAPIAddress: scanner.swapServer,
-	}
-	err := oracle.CheckConfig()
-	if err != nil {
-		log.Fatalf("check swap server failed. %v", err)
+	if !isOutputMode() {
+		oracle := params.OracleConfig{
+			ServerAPIAddress: scanner.swapServer,
+		}
+		err := oracle.CheckConfig()
+		if err != nil {
+			log.Fatalf("check swap server failed. %v", err)
+		}
 	}
 
 	start := scanner.startHeight
@@ -171,7 +179,7 @@ func (scanner *btcSwapScanner) initBridge() {
 		Confirmations: &scanner.stableHeight,
 	}
 	pairConfig := &tokens.TokenPairConfig{
-		PairID: btc.PairID,
+		PairID: scanner.pairID,
 		SrcToken: &tokens.TokenConfig{
 			ID:             "BTC",
 			Name:           "BTC",
@@ -181,8 +189,9 @@ func (scanner *btcSwapScanner) initBridge() {
 		},
 	}
 	pairsConfig := make(map[string]*tokens.TokenPairConfig)
-	pairsConfig[btc.PairID] = pairConfig
+	pairsConfig[strings.ToLower(scanner.pairID)] = pairConfig
 	tokens.SetTokenPairsConfig(pairsConfig, false)
+	scanner.bridge.SetP2shPairID(scanner.pairID)
 	tokens.SrcBridge = scanner.bridge
 	tokens.DstBridge = eth.NewCrossChainBridge(false)
 }
@@ -194,53 +203,26 @@ func (scanner *btcSwapScanner) run() {
 		wend = tools.LoopGetLatestBlockNumber(scanner.bridge)
 	}
 	if start == 0 {
-		start = wend
+		start = checkpoint.getResumeHeight(scanner.getCheckpointJobKey(), wend)
 	}
 
 	scanner.doScanRangeJob(start, wend)
 
 	if scanner.endHeight == 0 {
 		go scanner.scanPool()
-		scanner.scanLoop(wend)
+		scanner.scanLoop(wend - 1) // range job scans blocks below wend
 	}
 }
 
-// nolint:dupl // in diff sub command
-func (scanner *btcSwapScanner) doScanRangeJob(start, end uint64) {
-	if start >= end {
-		return
-	}
-	jobs := scanner.jobCount
-	count := end - start
-	step := count / jobs
-	if step == 0 {
-		jobs = 1
-		step = count
-	}
-	wg := new(sync.WaitGroup)
-	for i := uint64(0); i < jobs; i++ {
-		from := start + i*step
-		to := start + (i+1)*step
-		if i+1 == jobs {
-			to = end
-		}
-		wg.Add(1)
-		go scanner.scanRange(i+1, from, to, wg)
-	}
-	if scanner.endHeight != 0 {
-		wg.Wait()
-	}
+func (scanner *btcSwapScanner) getCheckpointJobKey() string {
+	return getCheckpointJobKey("btc", "swapin", scanner.depositAddress)
 }
 
-func (scanner *btcSwapScanner) scanRange(job, from, to uint64, wg *sync.WaitGroup) {
-	defer wg.Done()
-	log.Info(fmt.Sprintf("[%v] start scan range", job), "from", from, "to", to)
-
-	for h := from; h < to; h++ {
-		scanner.scanBlock(job, h, false)
+func (scanner *btcSwapScanner) doScanRangeJob(start, end uint64) {
+	scanBlocks := func(job, from, to uint64) error {
+		return scanner.scanBlock(job, from, false)
 	}
-
-	log.Info(fmt.Sprintf("[%v] scan range finish", job), "from", from, "to", to)
+	doScanRangeJob(scanner.getCheckpointJobKey(), start, end, scanner.jobCount, 1, scanner.endHeight != 0, scanBlocks)
 }
 
 func (scanner *btcSwapScanner) scanPool() {
@@ -252,12 +234,13 @@ func (scanner *btcSwapScanner) scanLoop(from uint64) {
 	log.Info("start scan loop", "from", from, "stable", stable)
 	for {
 		latest := tools.LoopGetLatestBlockNumber(scanner.bridge)
+		var failed uint64
 		for h := latest; h > from; h-- {
-			scanner.scanBlock(0, h, true)
-		}
-		if from+stable < latest {
-			from = latest - stable
+			if err := scanner.scanBlock(0, h, true); err != nil {
+				failed = h
+			}
 		}
+		from = advanceScanLoop(scanner.getCheckpointJobKey(), from, latest, stable, failed)
 		time.Sleep(5 * time.Second)
 	}
 }
@@ -273,19 +256,20 @@ func (scanner *btcSwapScanner) loopGetBlockHash(height uint64) string {
 	}
 }
 
-func (scanner *btcSwapScanner) scanBlock(job, height uint64, cache bool) {
+func (scanner *btcSwapScanner) scanBlock(job, height uint64, cache bool) error {
 	blockHash := scanner.loopGetBlockHash(height)
 	if cache && btcCachedBlocks.isScanned(blockHash) {
-		return
+		return nil
 	}
 	block, err := scanner.bridge.GetBlock(blockHash)
 	if err != nil {
 		log.Warn("get block failed", "height", height, "hash", blockHash, "err", err)
-		return
+		return err
 	}
 	txCount := *block.TxCount
 	log.Info(fmt.Sprintf("[%v] scan block %v start", job, height), "hash", blockHash, "txs", txCount)
 
+	var postErr error
 	startIndex := uint32(0)
 	for startIndex < txCount {
 		var txs []*electrs.ElectTx
@@ -297,70 +281,60 @@ func (scanner *btcSwapScanner) scanBlock(job, height uint64, cache bool) {
 			log.Warn("get block txs failed", "height", height, "startIndex", startIndex, "err", err)
 			time.Sleep(scanner.rpcInterval)
 		}
+		if err != nil {
+			return err
+		}
 		for i, tx := range txs {
 			log.Trace(fmt.Sprintf("[%v] scan block %v process tx", job, height), "txid", *tx.Txid, "index", startIndex+uint32(i))
-			scanner.processTx(tx)
+			if errp := scanner.processTx(tx); errp != nil {
+				postErr = errp
+			}
 		}
 		log.Trace(fmt.Sprintf("[%v] scan block %v process txs", job, height), "startIndex", startIndex, "total", txCount)
 		startIndex += 25 // 25 is elctrs API defined
 	}
 
+	if postErr != nil {
+		return postErr
+	}
 	if cache {
 		btcCachedBlocks.addBlock(blockHash)
 	}
 	log.Info(fmt.Sprintf("[%v] scan block %v finish", job, height))
+	return nil
 }
 
-func (scanner *btcSwapScanner) processTx(tx *electrs.ElectTx) {
+func (scanner *btcSwapScanner) processTx(tx *electrs.ElectTx) (err error) {
 	txid := *tx.Txid
-	p2shBindAddrs, err := scanner.bridge.CheckSwapinTxType(tx)
-	if err != nil {
-		return
+	p2shBindAddrs, errc := scanner.bridge.CheckSwapinTxType(tx, scanner.pairID)
+	if errc != nil {
+		return nil
 	}
 	if len(p2shBindAddrs) > 0 {
 		for _, p2shBindAddr := range p2shBindAddrs {
-			log.Info("post p2sh swapin register", "txid", txid, "bind", p2shBindAddr)
-			args := map[string]interface{}{
+			args := map[string]string{
 				"txid": txid,
 				"bind": p2shBindAddr,
 			}
-			var result interface{}
-			for i := 0; i < scanner.rpcRetryCount; i++ {
-				err = client.RPCPost(&result, scanner.swapServer, "swap.P2shSwapin", args)
-				if tokens.ShouldRegisterSwapForError(err) {
-					break
-				}
-				if tools.IsSwapAlreadyExistRegisterError(err) {
-					break
-				}
-				log.Warn("post p2sh swapin register failed", "txid", txid, "bind", p2shBindAddr, "err", err)
+			if errp := register.postSwap("post p2sh swapin register", "swap.P2shSwapin", args); errp != nil {
+				err = errp
 			}
 		}
+		return err
 	} else {
 		value, memoScript, rightReceiver := scanner.bridge.GetReceivedValue(tx.Vout, scanner.depositAddress, "p2pkh")
 		if !rightReceiver || value == 0 {
-			return
+			return nil
 		}
-		bindAddress, bindOk := btc.GetBindAddressFromMemoScipt(memoScript)
+		_, bindOk := btc.GetBindAddressFromMemoScipt(memoScript)
 		if !bindOk {
-			return
+			return nil
 		}
-		log.Info("post swapin register", "txid", txid, "pairid", btc.PairID, "bind", bindAddress)
-		args := map[string]interface{}{
+		args := map[string]string{
 			"txid":   txid,
-			"pairid": btc.PairID,
-		}
-		var result interface{}
-		for i := 0; i < scanner.rpcRetryCount; i++ {
-			err = client.RPCPost(&result, scanner.swapServer, "swap.Swapin", args)
-			if tokens.ShouldRegisterSwapForError(err) {
-				break
-			}
-			if tools.IsSwapAlreadyExistRegisterError(err) {
-				break
-			}
-			log.Warn("post swapin register failed", "txid", txid, "bind", bindAddress, "err", err)
+			"pairid": scanner.pairID,
 		}
+		return register.postSwap("post swapin register", "swap.Swapin", args)
 	}
 }
 
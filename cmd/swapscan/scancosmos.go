@@ -0,0 +1,325 @@
+package main
+
+import (
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/cosmos"
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	denomSliceFlag = &cli.StringSliceFlag{
+		Name:  "denom",
+		Usage: "coin denom (or ibc denom trace like 'transfer/channel-0/uatom')",
+	}
+
+	protobufFlag = &cli.BoolFlag{
+		Name:  "protobuf",
+		Usage: "use grpc-gateway api of cosmos-sdk v0.40+",
+	}
+
+	scanCosmosCommand = &cli.Command{
+		Action:    scanCosmos,
+		Name:      "scancosmos",
+		Usage:     "scan swap on cosmos",
+		ArgsUsage: " ",
+		Description: `
+scan swap on cosmos (and cosmos-sdk based chains like terra)
+swapin and swapout are both deposits to the deposit address with memo of bind address.
+`,
+		Flags: []cli.Flag{
+			utils.GatewayFlag,
+			utils.SwapServerFlag,
+			utils.SwapTypeFlag,
+			utils.DepositAddressSliceFlag,
+			denomSliceFlag,
+			utils.PairIDSliceFlag,
+			utils.StartHeightFlag,
+			utils.EndHeightFlag,
+			utils.StableHeightFlag,
+			utils.JobsFlag,
+			utils.OutputFileFlag,
+			checkpointFlag,
+			protobufFlag,
+		},
+	}
+)
+
+// cosmos search txs in block range, scan at most so many blocks every time
+const cosmosScanBatch = 100
+
+type cosmosSwapScanner struct {
+	gateway          string
+	swapServer       string
+	swapType         string
+	depositAddresses []string
+	denoms           []string
+	pairIDs          []string
+	startHeight      uint64
+	endHeight        uint64
+	stableHeight     uint64
+	jobCount         uint64
+	useProtobuf      bool
+
+	rpcInterval time.Duration
+
+	isSwapin bool
+	bridge   *cosmos.Bridge
+}
+
+func scanCosmos(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	scanner := &cosmosSwapScanner{
+		rpcInterval: 3 * time.Second,
+	}
+	scanner.gateway = ctx.String(utils.GatewayFlag.Name)
+	scanner.swapServer = ctx.String(utils.SwapServerFlag.Name)
+	scanner.swapType = ctx.String(utils.SwapTypeFlag.Name)
+	scanner.depositAddresses = ctx.StringSlice(utils.DepositAddressSliceFlag.Name)
+	scanner.denoms = ctx.StringSlice(denomSliceFlag.Name)
+	scanner.pairIDs = ctx.StringSlice(utils.PairIDSliceFlag.Name)
+	scanner.startHeight = ctx.Uint64(utils.StartHeightFlag.Name)
+	scanner.endHeight = ctx.Uint64(utils.EndHeightFlag.Name)
+	scanner.stableHeight = ctx.Uint64(utils.StableHeightFlag.Name)
+	scanner.jobCount = ctx.Uint64(utils.JobsFlag.Name)
+	scanner.useProtobuf = ctx.Bool(protobufFlag.Name)
+
+	switch strings.ToLower(scanner.swapType) {
+	case "swapin":
+		scanner.isSwapin = true
+	case "swapout":
+		scanner.isSwapin = false
+	default:
+		log.Fatalf("unknown swap type: '%v'", scanner.swapType)
+	}
+
+	log.Info("get argument success",
+		"gateway", scanner.gateway,
+		"swapServer", scanner.swapServer,
+		"swapType", scanner.swapType,
+		"depositAddress", scanner.depositAddresses,
+		"denom", scanner.denoms,
+		"pairID", scanner.pairIDs,
+		"protobuf", scanner.useProtobuf,
+		"start", scanner.startHeight,
+		"end", scanner.endHeight,
+		"stable", scanner.stableHeight,
+		"jobs", scanner.jobCount,
+	)
+
+	initRegister(ctx)
+	initCheckpoint(ctx)
+	scanner.initBridge()
+	scanner.verifyOptions()
+	scanner.run()
+	return nil
+}
+
+func (scanner *cosmosSwapScanner) verifyOptions() {
+	if len(scanner.pairIDs) == 0 {
+		log.Fatal("must specify pairid")
+	}
+	if len(scanner.depositAddresses) != len(scanner.pairIDs) {
+		log.Fatalf("count of depositAddresses and pairIDs mismatch")
+	}
+	if len(scanner.denoms) != len(scanner.pairIDs) {
+		log.Fatalf("count of denoms and pairIDs mismatch")
+	}
+	for i, pairID := range scanner.pairIDs {
+		if pairID == "" {
+			log.Fatal("must specify pairid")
+		}
+		if !scanner.bridge.IsValidAddress(scanner.depositAddresses[i]) {
+			log.Fatalf("invalid deposit address '%v'", scanner.depositAddresses[i])
+		}
+		denom := scanner.denoms[i]
+		if strings.Contains(denom, "/") && !strings.HasPrefix(denom, cosmos.IBCDenomPrefix) {
+			scanner.denoms[i] = cosmos.IBCDenom(denom)
+		}
+	}
+	if scanner.gateway == "" {
+		log.Fatal("must specify gateway address")
+	}
+	if !isOutputMode() {
+		oracle := params.OracleConfig{
+			ServerAPIAddress: scanner.swapServer,
+		}
+		err := oracle.CheckConfig()
+		if err != nil {
+			log.Fatalf("check swap server failed. %v", err)
+		}
+	}
+
+	if scanner.endHeight != 0 && scanner.startHeight >= scanner.endHeight {
+		log.Fatalf("wrong scan range [%v, %v)", scanner.startHeight, scanner.endHeight)
+	}
+	if scanner.jobCount == 0 {
+		log.Fatal("zero jobs specified")
+	}
+}
+
+func (scanner *cosmosSwapScanner) initBridge() {
+	scanner.bridge = cosmos.NewCrossChainBridge(scanner.isSwapin)
+	scanner.bridge.ChainConfig = &tokens.ChainConfig{
+		BlockChain:    "Cosmos",
+		Confirmations: &scanner.stableHeight,
+	}
+	scanner.bridge.GatewayConfig = &tokens.GatewayConfig{
+		APIAddress: []string{scanner.gateway},
+		Extras: &tokens.GatewayExtras{
+			CosmosExtra: &tokens.CosmosExtraArgs{
+				EnableProtobufTx: scanner.useProtobuf,
+			},
+		},
+	}
+	scanner.bridge.BeforeConfig()
+}
+
+func (scanner *cosmosSwapScanner) getCheckpointJobKey() string {
+	targets := append([]string{}, scanner.depositAddresses...)
+	targets = append(targets, scanner.denoms...)
+	return getCheckpointJobKey("cosmos", scanner.swapType, strings.Join(targets, ","))
+}
+
+func (scanner *cosmosSwapScanner) run() {
+	start := scanner.startHeight
+	wend := scanner.endHeight
+	if wend == 0 {
+		wend = scanner.getStableHeight()
+	}
+	if start == 0 {
+		start = checkpoint.getResumeHeight(scanner.getCheckpointJobKey(), wend)
+	}
+
+	doScanRangeJob(scanner.getCheckpointJobKey(), start, wend, scanner.jobCount, cosmosScanBatch, scanner.endHeight != 0, scanner.scanBlocks)
+
+	if scanner.endHeight == 0 {
+		scanner.scanLoop(wend)
+	}
+}
+
+// getStableHeight get the next height of latest stable block
+func (scanner *cosmosSwapScanner) getStableHeight() uint64 {
+	latest := tools.LoopGetLatestBlockNumber(scanner.bridge)
+	if latest < scanner.stableHeight {
+		return 0
+	}
+	return latest - scanner.stableHeight + 1
+}
+
+// scanLoop scan stable blocks from height `from`,
+// cosmos blocks are final, so every block is scanned only once
+func (scanner *cosmosSwapScanner) scanLoop(from uint64) {
+	log.Info("start scan loop", "from", from, "stable", scanner.stableHeight)
+	key := scanner.getCheckpointJobKey()
+	for {
+		to := scanner.getStableHeight()
+		for from < to {
+			end := from + cosmosScanBatch
+			if end > to {
+				end = to
+			}
+			if err := scanner.scanBlocks(0, from, end); err != nil {
+				log.Warn("scan blocks failed, retry in next loop", "from", from, "to", end, "err", err)
+				break
+			}
+			checkpoint.markScanned(key, from, end)
+			from = end
+		}
+		time.Sleep(5 * time.Second)
+	}
+}
+
+// scanBlocks scan blocks in range [from, to)
+func (scanner *cosmosSwapScanner) scanBlocks(job, from, to uint64) error {
+	var txs []sdk.TxResponse
+	var err error
+	for {
+		// cosmos search txs api's height range is closed interval
+		txs, err = scanner.bridge.SearchTxs(new(big.Int).SetUint64(from), new(big.Int).SetUint64(to-1))
+		if err == nil {
+			break
+		}
+		log.Warn("search txs failed", "from", from, "to", to, "err", err)
+		time.Sleep(scanner.rpcInterval)
+	}
+	log.Info("scan blocks", "job", job, "from", from, "to", to, "txs", len(txs))
+	for i := range txs {
+		if errt := scanner.scanTransaction(&txs[i]); errt != nil {
+			err = errt
+		}
+	}
+	return err
+}
+
+func (scanner *cosmosSwapScanner) scanTransaction(txresp *sdk.TxResponse) (err error) {
+	if txresp.Code != 0 || txresp.Tx == nil {
+		return nil
+	}
+	var memo string
+	if stdtx, ok := txresp.Tx.(authtypes.StdTx); ok {
+		memo = strings.TrimSpace(stdtx.Memo)
+	}
+	posted := make(map[string]bool)
+	checkDeposit := func(to string, coins sdk.Coins, bind string) {
+		if bind == "" {
+			return
+		}
+		for i, pairID := range scanner.pairIDs {
+			if posted[pairID] || !strings.EqualFold(to, scanner.depositAddresses[i]) {
+				continue
+			}
+			for _, coin := range coins {
+				if strings.EqualFold(coin.Denom, scanner.denoms[i]) && coin.Amount.IsPositive() {
+					posted[pairID] = true
+					if errp := scanner.postSwap(txresp.TxHash, pairID); errp != nil {
+						err = errp
+					}
+					break
+				}
+			}
+		}
+	}
+	for _, msg := range txresp.Tx.GetMsgs() {
+		switch m := msg.(type) {
+		case cosmos.MsgSend:
+			checkDeposit(m.ToAddress.String(), m.Amount, memo)
+		case cosmos.MsgMultiSend:
+			for _, output := range m.Outputs {
+				checkDeposit(output.Address.String(), output.Coins, memo)
+			}
+		case cosmos.MsgRecvPacket:
+			data, coin, err := m.GetTransferData()
+			if err != nil {
+				continue
+			}
+			checkDeposit(data.Receiver, sdk.Coins{coin}, strings.TrimSpace(data.Memo))
+		}
+	}
+	return err
+}
+
+func (scanner *cosmosSwapScanner) postSwap(txid, pairID string) error {
+	var subject, rpcMethod string
+	if scanner.isSwapin {
+		subject = "post swapin register"
+		rpcMethod = "swap.Swapin"
+	} else {
+		subject = "post swapout register"
+		rpcMethod = "swap.Swapout"
+	}
+	args := map[string]string{
+		"txid":   strings.ToLower(txid),
+		"pairid": pairID,
+	}
+	return register.postSwap(subject, rpcMethod, args)
+}
@@ -6,7 +6,6 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
@@ -14,7 +13,6 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/rpc/client"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tokens/eth"
-	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
 	"github.com/fsn-dev/fsn-go-sdk/efsn/common"
 	"github.com/fsn-dev/fsn-go-sdk/efsn/core/types"
 	"github.com/fsn-dev/fsn-go-sdk/efsn/ethclient"
@@ -56,6 +54,8 @@ scan swap on eth
 			utils.EndHeightFlag,
 			utils.StableHeightFlag,
 			utils.JobsFlag,
+			utils.OutputFileFlag,
+			checkpointFlag,
 			isSwapoutType2Flag,
 			scanReceiptFlag,
 			isProxyFlag,
@@ -134,6 +134,8 @@ func scanEth(ctx *cli.Context) error {
 		"jobs", scanner.jobCount,
 	)
 
+	initRegister(ctx)
+	initCheckpoint(ctx)
 	scanner.verifyOptions()
 	scanner.init()
 	scanner.run()
@@ -168,9 +170,6 @@ func (scanner *ethSwapScanner) verifyOptions() {
 	if scanner.gateway == "" {
 		log.Fatal("must specify gateway address")
 	}
-	if scanner.swapServer == "" {
-		log.Fatal("must specify swap server address")
-	}
 	scanner.verifyJobsOption()
 }
 
@@ -190,18 +189,8 @@ func (scanner *ethSwapScanner) init() {
 	}
 	scanner.client = ethcli
 
-	var version string
-	for i := 0; i < scanner.rpcRetryCount; i++ {
-		err = client.RPCPost(&version, scanner.swapServer, "swap.GetVersionInfo")
-		if err == nil {
-			log.Info("get server version succeed", "version", version)
-			break
-		}
-		log.Warn("get server version failed", "swapServer", scanner.swapServer, "err", err)
-		time.Sleep(scanner.rpcInterval)
-	}
-	if version == "" {
-		log.Fatal("get server version failed", "swapServer", scanner.swapServer)
+	if !isOutputMode() {
+		scanner.checkSwapServer()
 	}
 
 	eth.InitExtCodePartsWithFlag(scanner.isSwapoutType2)
@@ -234,6 +223,31 @@ func (scanner *ethSwapScanner) init() {
 	}
 }
 
+func (scanner *ethSwapScanner) checkSwapServer() {
+	var version string
+	for i := 0; i < scanner.rpcRetryCount; i++ {
+		err := client.RPCPost(&version, scanner.swapServer, "swap.GetVersionInfo")
+		if err == nil {
+			log.Info("get server version succeed", "version", version)
+			break
+		}
+		log.Warn("get server version failed", "swapServer", scanner.swapServer, "err", err)
+		time.Sleep(scanner.rpcInterval)
+	}
+	if version == "" {
+		log.Fatal("get server version failed", "swapServer", scanner.swapServer)
+	}
+}
+
+func (scanner *ethSwapScanner) getCheckpointJobKey() string {
+	var targets []string
+	if scanner.isSwapin {
+		targets = append(targets, scanner.depositAddresses...)
+	}
+	targets = append(targets, scanner.tokenAddresses...)
+	return getCheckpointJobKey("eth", scanner.swapType, strings.ToLower(strings.Join(targets, ",")))
+}
+
 func (scanner *ethSwapScanner) run() {
 	start := scanner.startHeight
 	wend := scanner.endHeight
@@ -241,52 +255,21 @@ func (scanner *ethSwapScanner) run() {
 		wend = scanner.loopGetLatestBlockNumber()
 	}
 	if start == 0 {
-		start = wend
+		start = checkpoint.getResumeHeight(scanner.getCheckpointJobKey(), wend)
 	}
 
 	scanner.doScanRangeJob(start, wend)
 
 	if scanner.endHeight == 0 {
-		scanner.scanLoop(wend)
+		scanner.scanLoop(wend - 1) // range job scans blocks below wend
 	}
 }
 
-// nolint:dupl // in diff sub command
 func (scanner *ethSwapScanner) doScanRangeJob(start, end uint64) {
-	if start >= end {
-		return
-	}
-	jobs := scanner.jobCount
-	count := end - start
-	step := count / jobs
-	if step == 0 {
-		jobs = 1
-		step = count
-	}
-	wg := new(sync.WaitGroup)
-	for i := uint64(0); i < jobs; i++ {
-		from := start + i*step
-		to := start + (i+1)*step
-		if i+1 == jobs {
-			to = end
-		}
-		wg.Add(1)
-		go scanner.scanRange(i+1, from, to, wg)
-	}
-	if scanner.endHeight != 0 {
-		wg.Wait()
-	}
-}
-
-func (scanner *ethSwapScanner) scanRange(job, from, to uint64, wg *sync.WaitGroup) {
-	defer wg.Done()
-	log.Info(fmt.Sprintf("[%v] start scan range", job), "from", from, "to", to)
-
-	for h := from; h < to; h++ {
-		scanner.scanBlock(job, h, false)
+	scanBlocks := func(job, from, to uint64) error {
+		return scanner.scanBlock(job, from, false)
 	}
-
-	log.Info(fmt.Sprintf("[%v] scan range finish", job), "from", from, "to", to)
+	doScanRangeJob(scanner.getCheckpointJobKey(), start, end, scanner.jobCount, 1, scanner.endHeight != 0, scanBlocks)
 }
 
 func (scanner *ethSwapScanner) scanLoop(from uint64) {
@@ -294,12 +277,13 @@ func (scanner *ethSwapScanner) scanLoop(from uint64) {
 	log.Info("start scan loop", "from", from, "stable", stable)
 	for {
 		latest := scanner.loopGetLatestBlockNumber()
+		var failed uint64
 		for h := latest; h > from; h-- {
-			scanner.scanBlock(0, h, true)
-		}
-		if from+stable < latest {
-			from = latest - stable
+			if err := scanner.scanBlock(0, h, true); err != nil {
+				failed = h
+			}
 		}
+		from = advanceScanLoop(scanner.getCheckpointJobKey(), from, latest, stable, failed)
 		time.Sleep(5 * time.Second)
 	}
 }
@@ -332,22 +316,27 @@ func (scanner *ethSwapScanner) loopGetBlock(height uint64) *types.Block {
 	}
 }
 
-func (scanner *ethSwapScanner) scanBlock(job, height uint64, cache bool) {
+// scanBlock scan all txs of block, return the last error of posting swaps,
+// the block is not cached as scanned if error occurs
+func (scanner *ethSwapScanner) scanBlock(job, height uint64, cache bool) (err error) {
 	block := scanner.loopGetBlock(height)
 	blockHash := block.Hash().String()
 	if cache && cachedBlocks.isScanned(blockHash) {
-		return
+		return nil
 	}
 	log.Info(fmt.Sprintf("[%v] scan block %v", job, height), "hash", blockHash, "txs", len(block.Transactions()))
 	for _, tx := range block.Transactions() {
-		scanner.scanTransaction(tx)
+		if errt := scanner.scanTransaction(tx); errt != nil {
+			err = errt
+		}
 	}
-	if cache {
+	if cache && err == nil {
 		cachedBlocks.addBlock(blockHash)
 	}
+	return err
 }
 
-func (scanner *ethSwapScanner) scanTransaction(tx *types.Transaction) {
+func (scanner *ethSwapScanner) scanTransaction(tx *types.Transaction) error {
 	var err error
 	for i, pairID := range scanner.pairIDs {
 		tokenAddress := scanner.tokenAddresses[i]
@@ -365,12 +354,12 @@ func (scanner *ethSwapScanner) scanTransaction(tx *types.Transaction) {
 			continue
 		}
 		txid := tx.Hash().String()
-		scanner.postSwap(txid, pairID)
-		break
+		return scanner.postSwap(txid, pairID)
 	}
+	return nil
 }
 
-func (scanner *ethSwapScanner) postSwap(txid, pairID string) {
+func (scanner *ethSwapScanner) postSwap(txid, pairID string) error {
 	var subject, rpcMethod string
 	if scanner.isSwapin {
 		subject = "post swapin register"
@@ -379,23 +368,11 @@ func (scanner *ethSwapScanner) postSwap(txid, pairID string) {
 		subject = "post swapout register"
 		rpcMethod = "swap.Swapout"
 	}
-	log.Info(subject, "txid", txid, "pairID", pairID)
-
-	var result interface{}
-	args := map[string]interface{}{
+	args := map[string]string{
 		"txid":   txid,
 		"pairid": pairID,
 	}
-	for i := 0; i < scanner.rpcRetryCount; i++ {
-		err := client.RPCPost(&result, scanner.swapServer, rpcMethod, args)
-		if tokens.ShouldRegisterSwapForError(err) {
-			break
-		}
-		if tools.IsSwapAlreadyExistRegisterError(err) {
-			break
-		}
-		log.Warn(subject+" failed", "txid", txid, "pairID", pairID, "err", err)
-	}
+	return register.postSwap(subject, rpcMethod, args)
 }
 
 func (scanner *ethSwapScanner) verifyErc20SwapinTx(tx *types.Transaction, tokenAddress, depositAddress string) error {
@@ -2,15 +2,15 @@ package main
 
 import (
 	"fmt"
-	"sync"
+	"strings"
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/mongodb"
 	"github.com/anyswap/CrossChain-Bridge/params"
-	"github.com/anyswap/CrossChain-Bridge/rpc/client"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
 	"github.com/anyswap/CrossChain-Bridge/tokens/eth"
 	"github.com/anyswap/CrossChain-Bridge/tokens/ltc"
@@ -36,10 +36,13 @@ scan swap on ltc
 			utils.GatewayFlag,
 			utils.SwapServerFlag,
 			utils.DepositAddressFlag,
+			utils.PairIDFlag,
 			utils.StartHeightFlag,
 			utils.EndHeightFlag,
 			utils.StableHeightFlag,
 			utils.JobsFlag,
+			utils.OutputFileFlag,
+			checkpointFlag,
 		},
 	}
 )
@@ -49,6 +52,7 @@ type ltcSwapScanner struct {
 	gateway        string
 	swapServer     string
 	depositAddress string
+	pairID         string
 	startHeight    uint64
 	endHeight      uint64
 	stableHeight   uint64
@@ -57,7 +61,7 @@ type ltcSwapScanner struct {
 	rpcInterval   time.Duration
 	rpcRetryCount int
 
-	bridge *ltc.Bridge
+	bridge *btc.Bridge
 }
 
 func scanLtc(ctx *cli.Context) error {
@@ -70,6 +74,10 @@ func scanLtc(ctx *cli.Context) error {
 	scanner.gateway = ctx.String(utils.GatewayFlag.Name)
 	scanner.swapServer = ctx.String(utils.SwapServerFlag.Name)
 	scanner.depositAddress = ctx.String(utils.DepositAddressFlag.Name)
+	scanner.pairID = ctx.String(utils.PairIDFlag.Name)
+	if scanner.pairID == "" {
+		scanner.pairID = "ltc"
+	}
 	scanner.startHeight = ctx.Uint64(utils.StartHeightFlag.Name)
 	scanner.endHeight = ctx.Uint64(utils.EndHeightFlag.Name)
 	scanner.stableHeight = ctx.Uint64(utils.StableHeightFlag.Name)
@@ -80,12 +88,15 @@ func scanLtc(ctx *cli.Context) error {
 		"gateway", scanner.gateway,
 		"swapServer", scanner.swapServer,
 		"depositAddress", scanner.depositAddress,
+		"pairID", scanner.pairID,
 		"start", scanner.startHeight,
 		"end", scanner.endHeight,
 		"stable", scanner.stableHeight,
 		"jobs", scanner.jobCount,
 	)
 
+	initRegister(ctx)
+	initCheckpoint(ctx)
 	scanner.initMongodb(ctx)
 	scanner.initBridge()
 	scanner.verifyOptions()
@@ -100,16 +111,14 @@ func (scanner *ltcSwapScanner) verifyOptions() {
 	if scanner.gateway == "" {
 		log.Fatal("must specify gateway address")
 	}
-	if scanner.swapServer == "" {
-		log.Fatal("must specify swap server address")
-	}
-
-	oracle := params.OracleConfig{
-		ServerAPIAddress: scanner.swapServer,
-	}
-	err := oracle.CheckConfig()
-	if err != nil {
-		log.Fatalf("check swap server failed. %v", err)
+	if !isOutputMode() {
+		oracle := params.OracleConfig{
+			ServerAPIAddress: scanner.swapServer,
+		}
+		err := oracle.CheckConfig()
+		if err != nil {
+			log.Fatalf("check swap server failed. %v", err)
+		}
 	}
 
 	start := scanner.startHeight
@@ -149,7 +158,7 @@ func (scanner *ltcSwapScanner) initBridge() {
 		Confirmations: &scanner.stableHeight,
 	}
 	pairConfig := &tokens.TokenPairConfig{
-		PairID: ltc.PairID,
+		PairID: scanner.pairID,
 		SrcToken: &tokens.TokenConfig{
 			ID:             "LTC",
 			Name:           "LTC",
@@ -159,8 +168,9 @@ func (scanner *ltcSwapScanner) initBridge() {
 		},
 	}
 	pairsConfig := make(map[string]*tokens.TokenPairConfig)
-	pairsConfig[ltc.PairID] = pairConfig
+	pairsConfig[strings.ToLower(scanner.pairID)] = pairConfig
 	tokens.SetTokenPairsConfig(pairsConfig, false)
+	scanner.bridge.SetP2shPairID(scanner.pairID)
 	tokens.SrcBridge = scanner.bridge
 	tokens.DstBridge = eth.NewCrossChainBridge(false)
 }
@@ -172,53 +182,26 @@ func (scanner *ltcSwapScanner) run() {
 		wend = tools.LoopGetLatestBlockNumber(scanner.bridge)
 	}
 	if start == 0 {
-		start = wend
+		start = checkpoint.getResumeHeight(scanner.getCheckpointJobKey(), wend)
 	}
 
 	scanner.doScanRangeJob(start, wend)
 
 	if scanner.endHeight == 0 {
 		go scanner.scanPool()
-		scanner.scanLoop(wend)
+		scanner.scanLoop(wend - 1) // range job scans blocks below wend
 	}
 }
 
-// nolint:dupl // in diff sub command
-func (scanner *ltcSwapScanner) doScanRangeJob(start, end uint64) {
-	if start >= end {
-		return
-	}
-	jobs := scanner.jobCount
-	count := end - start
-	step := count / jobs
-	if step == 0 {
-		jobs = 1
-		step = count
-	}
-	wg := new(sync.WaitGroup)
-	for i := uint64(0); i < jobs; i++ {
-		from := start + i*step
-		to := start + (i+1)*step
-		if i+1 == jobs {
-			to = end
-		}
-		wg.Add(1)
-		go scanner.scanRange(i+1, from, to, wg)
-	}
-	if scanner.endHeight != 0 {
-		wg.Wait()
-	}
+func (scanner *ltcSwapScanner) getCheckpointJobKey() string {
+	return getCheckpointJobKey("ltc", "swapin", scanner.depositAddress)
 }
 
-func (scanner *ltcSwapScanner) scanRange(job, from, to uint64, wg *sync.WaitGroup) {
-	defer wg.Done()
-	log.Info(fmt.Sprintf("[%v] start scan range", job), "from", from, "to", to)
-
-	for h := from; h < to; h++ {
-		scanner.scanBlock(job, h, false)
+func (scanner *ltcSwapScanner) doScanRangeJob(start, end uint64) {
+	scanBlocks := func(job, from, to uint64) error {
+		return scanner.scanBlock(job, from, false)
 	}
-
-	log.Info(fmt.Sprintf("[%v] scan range finish", job), "from", from, "to", to)
+	doScanRangeJob(scanner.getCheckpointJobKey(), start, end, scanner.jobCount, 1, scanner.endHeight != 0, scanBlocks)
 }
 
 func (scanner *ltcSwapScanner) scanPool() {
@@ -230,12 +213,13 @@ func (scanner *ltcSwapScanner) scanLoop(from uint64) {
 	log.Info("start scan loop", "from", from, "stable", stable)
 	for {
 		latest := tools.LoopGetLatestBlockNumber(scanner.bridge)
+		var failed uint64
 		for h := latest; h > from; h-- {
-			scanner.scanBlock(0, h, true)
-		}
-		if from+stable < latest {
-			from = latest - stable
+			if err := scanner.scanBlock(0, h, true); err != nil {
+				failed = h
+			}
 		}
+		from = advanceScanLoop(scanner.getCheckpointJobKey(), from, latest, stable, failed)
 		time.Sleep(5 * time.Second)
 	}
 }
@@ -251,19 +235,20 @@ func (scanner *ltcSwapScanner) loopGetBlockHash(height uint64) string {
 	}
 }
 
-func (scanner *ltcSwapScanner) scanBlock(job, height uint64, cache bool) {
+func (scanner *ltcSwapScanner) scanBlock(job, height uint64, cache bool) error {
 	blockHash := scanner.loopGetBlockHash(height)
 	if cache && ltcCachedBlocks.isScanned(blockHash) {
-		return
+		return nil
 	}
 	block, err := scanner.bridge.GetBlock(blockHash)
 	if err != nil {
 		log.Warn("get block failed", "height", height, "hash", blockHash, "err", err)
-		return
+		return err
 	}
 	txCount := *block.TxCount
 	log.Info(fmt.Sprintf("[%v] scan block %v start", job, height), "hash", blockHash, "txs", txCount)
 
+	var postErr error
 	startIndex := uint32(0)
 	for startIndex < txCount {
 		var txs []*electrs.ElectTx
@@ -275,70 +260,60 @@ func (scanner *ltcSwapScanner) scanBlock(job, height uint64, cache bool) {
 			log.Warn("get block txs failed", "height", height, "startIndex", startIndex, "err", err)
 			time.Sleep(scanner.rpcInterval)
 		}
+		if err != nil {
+			return err
+		}
 		for i, tx := range txs {
 			log.Trace(fmt.Sprintf("[%v] scan block %v process tx", job, height), "txid", *tx.Txid, "index", startIndex+uint32(i))
-			scanner.processTx(tx)
+			if errp := scanner.processTx(tx); errp != nil {
+				postErr = errp
+			}
 		}
 		log.Trace(fmt.Sprintf("[%v] scan block %v process txs", job, height), "startIndex", startIndex, "total", txCount)
 		startIndex += 25 // 25 is elctrs API defined
 	}
 
+	if postErr != nil {
+		return postErr
+	}
 	if cache {
 		ltcCachedBlocks.addBlock(blockHash)
 	}
 	log.Info(fmt.Sprintf("[%v] scan block %v finish", job, height))
+	return nil
 }
 
-func (scanner *ltcSwapScanner) processTx(tx *electrs.ElectTx) {
+func (scanner *ltcSwapScanner) processTx(tx *electrs.ElectTx) (err error) {
 	txid := *tx.Txid
-	p2shBindAddrs, err := scanner.bridge.CheckSwapinTxType(tx)
-	if err != nil {
-		return
+	p2shBindAddrs, errc := scanner.bridge.CheckSwapinTxType(tx, scanner.pairID)
+	if errc != nil {
+		return nil
 	}
 	if len(p2shBindAddrs) > 0 {
 		for _, p2shBindAddr := range p2shBindAddrs {
-			log.Info("post p2sh swapin register", "txid", txid, "bind", p2shBindAddr)
-			args := map[string]interface{}{
+			args := map[string]string{
 				"txid": txid,
 				"bind": p2shBindAddr,
 			}
-			var result interface{}
-			for i := 0; i < scanner.rpcRetryCount; i++ {
-				err = client.RPCPost(&result, scanner.swapServer, "swap.P2shSwapin", args)
-				if tokens.ShouldRegisterSwapForError(err) {
-					break
-				}
-				if tools.IsSwapAlreadyExistRegisterError(err) {
-					break
-				}
-				log.Warn("post p2sh swapin register failed", "txid", txid, "bind", p2shBindAddr, "err", err)
+			if errp := register.postSwap("post p2sh swapin register", "swap.P2shSwapin", args); errp != nil {
+				err = errp
 			}
 		}
+		return err
 	} else {
 		value, memoScript, rightReceiver := scanner.bridge.GetReceivedValue(tx.Vout, scanner.depositAddress, "p2pkh")
 		if !rightReceiver || value == 0 {
-			return
+			return nil
 		}
-		bindAddress, bindOk := ltc.GetBindAddressFromMemoScipt(memoScript)
+		_, bindOk := btc.GetBindAddressFromMemoScipt(memoScript)
 		if !bindOk {
-			return
+			return nil
 		}
-		log.Info("post swapin register", "txid", txid, "pairid", ltc.PairID, "bind", bindAddress)
-		args := map[string]interface{}{
+		args := map[string]string{
 			"txid":   txid,
-			"pairid": ltc.PairID,
-		}
-		var result interface{}
-		for i := 0; i < scanner.rpcRetryCount; i++ {
-			err = client.RPCPost(&result, scanner.swapServer, "swap.Swapin", args)
-			if tokens.ShouldRegisterSwapForError(err) {
-				break
-			}
-			if tools.IsSwapAlreadyExistRegisterError(err) {
-				break
-			}
-			log.Warn("post swapin register failed", "txid", txid, "bind", bindAddress, "err", err)
+			"pairid": scanner.pairID,
 		}
+		return register.postSwap("post swapin register", "swap.Swapin", args)
 	}
 }
 
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/bridge"
+	"github.com/anyswap/CrossChain-Bridge/tools/secrets"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	oracleFlag = &cli.BoolFlag{
+		Name:  "oracle",
+		Usage: "check config as oracle (default as swap server)",
+	}
+	onlineFlag = &cli.BoolFlag{
+		Name:  "online",
+		Usage: "also run online checks through gateways and swap server",
+	}
+
+	// nolint:lll // allow long line of example
+	checkConfigCommand = &cli.Command{
+		Action:    checkConfig,
+		Name:      "checkconfig",
+		Usage:     "check config and token pairs config of deployment",
+		ArgsUsage: " ",
+		Description: `
+check server (or oracle) config and every token pair config in '--pairsdir',
+report all the problems at once instead of exiting at the first one when starting.
+with '--online' flag, chain id, token decimals, contract code hash are verified
+through gateways (gateway errors are reported, and then offline checks are used).
+
+the report is printed in json format, use '--log' to separate logs from report.
+exit with non-zero code if any problem is found.
+
+Example:
+
+./swaptools checkconfig --config ./config.toml --pairsdir ./tokenpairs --online --log ./checkconfig.log
+`,
+		Flags: []cli.Flag{
+			utils.ConfigFileFlag,
+			utils.TokenPairsDirFlag,
+			oracleFlag,
+			onlineFlag,
+			utils.OutputFileFlag,
+			utils.LogFileFlag,
+		},
+	}
+)
+
+type configIssue struct {
+	Section string `json:"section"`
+	File    string `json:"file,omitempty"`
+	PairID  string `json:"pairid,omitempty"`
+	Message string `json:"message"`
+}
+
+type checkConfigReport struct {
+	ConfigFile string         `json:"configfile"`
+	PairsDir   string         `json:"pairsdir,omitempty"`
+	IsServer   bool           `json:"isserver"`
+	Online     bool           `json:"online"`
+	PairsCount int            `json:"pairscount"`
+	Passed     bool           `json:"passed"`
+	Issues     []*configIssue `json:"issues"`
+}
+
+func (r *checkConfigReport) addIssue(section string, err error) {
+	r.Issues = append(r.Issues, &configIssue{Section: section, Message: err.Error()})
+}
+
+func checkConfig(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	report := &checkConfigReport{
+		ConfigFile: utils.GetConfigFilePath(ctx),
+		PairsDir:   utils.GetTokenPairsDir(ctx),
+		IsServer:   !ctx.Bool(oracleFlag.Name),
+		Online:     ctx.Bool(onlineFlag.Name),
+		Issues:     make([]*configIssue, 0),
+	}
+	if report.ConfigFile == "" {
+		return errors.New("must specify config file")
+	}
+
+	config, err := params.LoadConfigFile(report.ConfigFile)
+	if err != nil {
+		report.addIssue("Config", err)
+		return outputCheckConfigReport(ctx, report)
+	}
+	params.SetConfig(config)
+	for _, sectionErr := range params.CheckConfigSections(report.IsServer) {
+		report.addIssue(sectionErr.Section, sectionErr.Err)
+	}
+	if config.Secrets != nil && config.Secrets.CheckConfig() == nil {
+		if err = secrets.Init(config.Secrets); err != nil {
+			report.addIssue("Secrets", err)
+		}
+	}
+	if report.Online && !report.IsServer && config.Oracle != nil && config.Oracle.ServerAPIAddress != "" {
+		if err = config.Oracle.CheckServerConnection(); err != nil {
+			report.addIssue("Oracle", fmt.Errorf("connect server failed: %w", err))
+		}
+	}
+
+	pairsChecker := checkBridgesConfig(config, report)
+	if report.PairsDir != "" {
+		tokens.IsDcrmDisabled = config.Dcrm != nil && config.Dcrm.Disable
+		pairsConfig, issues := pairsChecker.CheckDir(report.PairsDir)
+		for _, issue := range issues {
+			report.Issues = append(report.Issues, &configIssue{
+				Section: "TokenPairs",
+				File:    issue.File,
+				PairID:  issue.PairID,
+				Message: issue.Message,
+			})
+		}
+		report.PairsCount = len(pairsConfig)
+	}
+	return outputCheckConfigReport(ctx, report)
+}
+
+// checkBridgesConfig check chain and gateway config by bridges, bridge which
+// fails offline checks is not used to check token config, and offline checks
+// are used if its gateway fails online checks
+func checkBridgesConfig(config *params.ServerConfig, report *checkConfigReport) *tokens.PairsConfigChecker {
+	var srcID, dstID string
+	if config.SrcChain != nil {
+		srcID = config.SrcChain.BlockChain
+	}
+	if config.DestChain != nil {
+		dstID = config.DestChain.BlockChain
+	}
+	srcChecker, dstChecker, srcErr, dstErr := bridge.NewConfigCheckers(srcID, dstID)
+
+	checkBridge := func(section string, checker tokens.ConfigChecker, err error,
+		chainCfg *tokens.ChainConfig, gatewayCfg *tokens.GatewayConfig) (tokens.ConfigChecker, bool) {
+		if chainCfg == nil || gatewayCfg == nil {
+			return nil, false // reported in config sections
+		}
+		if err == nil {
+			err = checker.CheckChainAndGateway(chainCfg, gatewayCfg, false)
+		}
+		if err != nil {
+			report.addIssue(section, err)
+			return nil, false
+		}
+		if !report.Online {
+			return checker, false
+		}
+		err = checker.CheckChainAndGateway(chainCfg, gatewayCfg, true)
+		if err != nil {
+			report.addIssue(section, fmt.Errorf("online check of gateway %v failed: %w", gatewayCfg.APIAddress, err))
+			return checker, false
+		}
+		return checker, true
+	}
+
+	pairsChecker := &tokens.PairsConfigChecker{}
+	pairsChecker.SrcChecker, pairsChecker.SrcOnline = checkBridge("SrcChain", srcChecker, srcErr, config.SrcChain, config.SrcGateway)
+	pairsChecker.DstChecker, pairsChecker.DstOnline = checkBridge("DestChain", dstChecker, dstErr, config.DestChain, config.DestGateway)
+	return pairsChecker
+}
+
+func outputCheckConfigReport(ctx *cli.Context, report *checkConfigReport) error {
+	report.Passed = len(report.Issues) == 0
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	if outputFile := ctx.String(utils.OutputFileFlag.Name); outputFile != "" {
+		err = ioutil.WriteFile(outputFile, data, 0600)
+		if err != nil {
+			return err
+		}
+	} else {
+		fmt.Println(string(data))
+	}
+	if !report.Passed {
+		return fmt.Errorf("check config found %v problems", len(report.Issues))
+	}
+	return nil
+}
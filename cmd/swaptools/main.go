@@ -26,6 +26,11 @@ func initApp() {
 		sendBtcCommand,
 		sendLtcCommand,
 		sendEthTxCommand,
+		signTxCommand,
+		sendTxCommand,
+		inspectCommand,
+		checkConfigCommand,
+		secretsCommand,
 		utils.LicenseCommand,
 		utils.VersionCommand,
 	}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/common/hexutil"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tools"
+	"github.com/anyswap/CrossChain-Bridge/tools/rlp"
+	"github.com/anyswap/CrossChain-Bridge/types"
+	"github.com/urfave/cli/v2"
+)
+
+// offline tx kinds
+const (
+	offlineKindEthTx = "ethtx"
+	offlineKindBtcTx = "btctx"
+	offlineKindLtcTx = "ltctx"
+)
+
+var (
+	// nolint:lll // allow long line of example
+	signTxCommand = &cli.Command{
+		Action:    signTx,
+		Name:      "signtx",
+		Usage:     "sign unsigned tx file offline",
+		ArgsUsage: " ",
+		Description: `
+sign the unsigned tx file built by 'sendethtx', 'sendbtc' or 'sendltc' with '--output' flag.
+eth tx is signed with keystore and password file, btc and ltc tx is signed with WIF or private key.
+this command does not connect to network, it can run on air-gapped machine.
+
+Example:
+
+./swaptools sendethtx --gateway http://1.2.3.4:5555 --from 0x1111111111111111111111111111111111111111 --to 0x2222222222222222222222222222222222222222 --value 1000000000000000000 --output ./unsigned.json
+./swaptools inspect --input ./unsigned.json
+./swaptools signtx --keystore ./UTC.json --password ./password.txt --input ./unsigned.json --output ./signed.json
+./swaptools sendtx --gateway http://1.2.3.4:5555 --input ./signed.json
+`,
+		Flags: []cli.Flag{
+			utils.InputFileFlag,
+			utils.OutputFileFlag,
+			utils.KeystoreFileFlag,
+			utils.PasswordFileFlag,
+			wifFileFlag,
+			priKeyFileFlag,
+		},
+	}
+
+	sendTxCommand = &cli.Command{
+		Action:    sendTx,
+		Name:      "sendtx",
+		Usage:     "broadcast signed tx file",
+		ArgsUsage: " ",
+		Description: `
+broadcast the signed tx file signed by 'signtx' command.
+`,
+		Flags: []cli.Flag{
+			utils.GatewayFlag,
+			utils.InputFileFlag,
+		},
+	}
+
+	inspectCommand = &cli.Command{
+		Action:    inspectTx,
+		Name:      "inspect",
+		Usage:     "inspect offline tx file",
+		ArgsUsage: " ",
+		Description: `
+decode and verify unsigned or signed tx file.
+verify the raw tx is consistent with the build args,
+and the signed tx is consistent with the raw tx and signed by the sender.
+`,
+		Flags: []cli.Flag{
+			utils.InputFileFlag,
+		},
+	}
+)
+
+// offlineTx portable file of offline signing
+type offlineTx struct {
+	utils.OfflineHeader
+	NetID     string              `json:"netID,omitempty"`
+	ChainID   string              `json:"chainID,omitempty"`
+	Args      *tokens.BuildTxArgs `json:"args"`
+	Receivers []string            `json:"receivers,omitempty"`
+	Amounts   []int64             `json:"amounts,omitempty"`
+	RawTx     string              `json:"rawTx"`
+	Utxo      *offlineUtxoInfo    `json:"utxo,omitempty"`
+	SignedTx  string              `json:"signedTx,omitempty"`
+	TxHash    string              `json:"txHash,omitempty"`
+}
+
+// offlineUtxoInfo inputs info of btc and ltc authored tx
+type offlineUtxoInfo struct {
+	PrevScripts     []string `json:"prevScripts"`
+	PrevInputValues []int64  `json:"prevInputValues"`
+	TotalInput      int64    `json:"totalInput"`
+	ChangeIndex     int      `json:"changeIndex"`
+}
+
+func newOfflineTx(kind string, args *tokens.BuildTxArgs) *offlineTx {
+	return &offlineTx{
+		OfflineHeader: utils.OfflineHeader{
+			Kind:      kind,
+			Stage:     utils.OfflineStageUnsigned,
+			CreatedAt: time.Now().Unix(),
+		},
+		Args: args,
+	}
+}
+
+func saveOfflineTx(file string, otx *offlineTx) {
+	err := utils.SaveOfflineFile(file, otx)
+	if err != nil {
+		log.Fatal("save offline tx file failed", "file", file, "err", err)
+	}
+	log.Info("save offline tx file success", "file", file, "kind", otx.Kind, "stage", otx.Stage)
+}
+
+func loadOfflineTx(ctx *cli.Context, stage string) (*offlineTx, error) {
+	file := ctx.String(utils.InputFileFlag.Name)
+	if file == "" {
+		return nil, errors.New("must specify input file")
+	}
+	otx := &offlineTx{}
+	err := utils.LoadOfflineFile(file, otx)
+	if err != nil {
+		return nil, err
+	}
+	if stage != "" && otx.Stage != stage {
+		return nil, fmt.Errorf("wrong offline file stage, have '%v' want '%v'", otx.Stage, stage)
+	}
+	if otx.Args == nil {
+		return nil, errors.New("missing build tx args")
+	}
+	return otx, otx.verify()
+}
+
+func (otx *offlineTx) verify() error {
+	switch otx.Stage {
+	case utils.OfflineStageUnsigned, utils.OfflineStageSigned:
+	default:
+		return fmt.Errorf("unknown offline stage '%v'", otx.Stage)
+	}
+	switch otx.Kind {
+	case offlineKindEthTx:
+		return otx.verifyEthTx()
+	case offlineKindBtcTx, offlineKindLtcTx:
+		return otx.verifyBtcTx()
+	default:
+		return fmt.Errorf("unknown offline file kind '%v'", otx.Kind)
+	}
+}
+
+func signTx(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	output := ctx.String(utils.OutputFileFlag.Name)
+	if output == "" {
+		return errors.New("must specify output file")
+	}
+	otx, err := loadOfflineTx(ctx, utils.OfflineStageUnsigned)
+	if err != nil {
+		return err
+	}
+	switch otx.Kind {
+	case offlineKindEthTx:
+		err = otx.signEthTx(ctx)
+	case offlineKindBtcTx, offlineKindLtcTx:
+		err = otx.signBtcTx(ctx)
+	}
+	if err != nil {
+		return err
+	}
+	otx.Stage = utils.OfflineStageSigned
+	otx.SignedAt = time.Now().Unix()
+	// verify again to ensure we have signed what we intended
+	err = otx.verify()
+	if err != nil {
+		return err
+	}
+	log.Info("SignTransaction success", "txHash", otx.TxHash)
+	saveOfflineTx(output, otx)
+	return nil
+}
+
+func sendTx(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	otx, err := loadOfflineTx(ctx, utils.OfflineStageSigned)
+	if err != nil {
+		return err
+	}
+	gateway := ctx.String(utils.GatewayFlag.Name)
+	switch otx.Kind {
+	case offlineKindEthTx:
+		err = otx.sendEthTx(gateway)
+	case offlineKindBtcTx, offlineKindLtcTx:
+		err = otx.sendBtcTx(gateway)
+	}
+	if err != nil {
+		log.Error("SendTransaction failed", "txHash", otx.TxHash, "err", err)
+		return err
+	}
+	log.Info("SendTransaction success", "txHash", otx.TxHash)
+	return nil
+}
+
+func inspectTx(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	otx, err := loadOfflineTx(ctx, "")
+	if err != nil {
+		return err
+	}
+	fmt.Printf("kind:      %v\n", otx.Kind)
+	fmt.Printf("stage:     %v\n", otx.Stage)
+	fmt.Printf("createdAt: %v\n", time.Unix(otx.CreatedAt, 0))
+	if otx.Stage == utils.OfflineStageSigned {
+		fmt.Printf("signedAt:  %v\n", time.Unix(otx.SignedAt, 0))
+		fmt.Printf("txHash:    %v\n", otx.TxHash)
+	}
+	fmt.Printf("args:      %v\n", common.ToJSONString(otx.Args, true))
+	switch otx.Kind {
+	case offlineKindEthTx:
+		otx.printEthTx()
+	case offlineKindBtcTx, offlineKindLtcTx:
+		otx.printBtcTx()
+	}
+	log.Info("inspect offline tx file success")
+	return nil
+}
+
+func buildOfflineEthTx(rawTx interface{}, args *tokens.BuildTxArgs, chainID *big.Int) (*offlineTx, error) {
+	tx, ok := rawTx.(*types.Transaction)
+	if !ok {
+		return nil, tokens.ErrWrongRawTx
+	}
+	txdata, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return nil, err
+	}
+	otx := newOfflineTx(offlineKindEthTx, args)
+	otx.ChainID = chainID.String()
+	otx.RawTx = hexutil.Encode(txdata)
+	return otx, nil
+}
+
+func decodeEthTx(txHex string) (*types.Transaction, error) {
+	data, err := hexutil.Decode(txHex)
+	if err != nil {
+		return nil, err
+	}
+	var tx types.Transaction
+	err = rlp.DecodeBytes(data, &tx)
+	if err != nil {
+		return nil, err
+	}
+	return &tx, nil
+}
+
+func (otx *offlineTx) getEthSigner() (types.Signer, error) {
+	chainID, err := common.GetBigIntFromStr(otx.ChainID)
+	if err != nil || chainID.Sign() <= 0 {
+		return nil, fmt.Errorf("wrong chain id '%v'", otx.ChainID)
+	}
+	return types.MakeSigner("EIP155", chainID), nil
+}
+
+func (otx *offlineTx) verifyEthTx() error {
+	signer, err := otx.getEthSigner()
+	if err != nil {
+		return err
+	}
+	tx, err := decodeEthTx(otx.RawTx)
+	if err != nil {
+		return err
+	}
+	err = checkEthTxWithArgs(tx, otx.Args)
+	if err != nil {
+		return err
+	}
+	if otx.Stage != utils.OfflineStageSigned {
+		return nil
+	}
+	signedTx, err := decodeEthTx(otx.SignedTx)
+	if err != nil {
+		return err
+	}
+	err = checkEthTxWithArgs(signedTx, otx.Args)
+	if err != nil {
+		return fmt.Errorf("signed tx mismatch, %v", err)
+	}
+	sender, err := types.Sender(signer, signedTx)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(sender.String(), otx.Args.From) {
+		return fmt.Errorf("signed tx sender mismatch, have %v want %v", sender.String(), otx.Args.From)
+	}
+	if signedTx.Hash().String() != otx.TxHash {
+		return fmt.Errorf("signed tx hash mismatch, have %v want %v", signedTx.Hash().String(), otx.TxHash)
+	}
+	return nil
+}
+
+func checkEthTxWithArgs(tx *types.Transaction, args *tokens.BuildTxArgs) error {
+	if args.Extra == nil || args.Extra.EthExtra == nil {
+		return errors.New("missing eth extra args")
+	}
+	extra := args.Extra.EthExtra
+	var input []byte
+	if args.Input != nil {
+		input = *args.Input
+	}
+	switch {
+	case extra.Nonce == nil || tx.Nonce() != *extra.Nonce:
+		return errors.New("nonce mismatch")
+	case extra.Gas == nil || tx.Gas() != *extra.Gas:
+		return errors.New("gas limit mismatch")
+	case extra.GasPrice == nil || tx.GasPrice().Cmp(extra.GasPrice) != 0:
+		return errors.New("gas price mismatch")
+	case tx.To() == nil || *tx.To() != common.HexToAddress(args.To):
+		return errors.New("receiver mismatch")
+	case args.Value == nil || tx.Value().Cmp(args.Value) != 0:
+		return errors.New("value mismatch")
+	case !bytes.Equal(tx.Data(), input):
+		return errors.New("input data mismatch")
+	}
+	return nil
+}
+
+func (otx *offlineTx) signEthTx(ctx *cli.Context) error {
+	keystoreFile := ctx.String(utils.KeystoreFileFlag.Name)
+	passwordFile := ctx.String(utils.PasswordFileFlag.Name)
+	if keystoreFile == "" || passwordFile == "" {
+		return errors.New("must specify '-keystore' and '-password' flag")
+	}
+	keyWrapper, err := tools.LoadKeyStore(keystoreFile, passwordFile)
+	if err != nil {
+		return err
+	}
+	keyAddr := keyWrapper.Address.String()
+	if !strings.EqualFold(keyAddr, otx.Args.From) {
+		return fmt.Errorf("sender mismatch, sender %v keyAddr %v", otx.Args.From, keyAddr)
+	}
+	signer, err := otx.getEthSigner()
+	if err != nil {
+		return err
+	}
+	tx, err := decodeEthTx(otx.RawTx)
+	if err != nil {
+		return err
+	}
+	signedTx, err := types.SignTx(tx, signer, keyWrapper.PrivateKey)
+	if err != nil {
+		return err
+	}
+	txdata, err := rlp.EncodeToBytes(signedTx)
+	if err != nil {
+		return err
+	}
+	otx.SignedTx = hexutil.Encode(txdata)
+	otx.TxHash = signedTx.Hash().String()
+	return nil
+}
+
+func (otx *offlineTx) sendEthTx(gateway string) error {
+	ethSender.gateway = gateway
+	ethSender.initBridge()
+	if ethBridge.SignerChainID.String() != otx.ChainID {
+		return fmt.Errorf("chain id mismatch, gateway %v file %v", ethBridge.SignerChainID, otx.ChainID)
+	}
+	signedTx, err := decodeEthTx(otx.SignedTx)
+	if err != nil {
+		return err
+	}
+	_, err = ethBridge.SendTransaction(signedTx)
+	return err
+}
+
+func (otx *offlineTx) printEthTx() {
+	fmt.Printf("chainID:   %v\n", otx.ChainID)
+	txHex := otx.RawTx
+	if otx.Stage == utils.OfflineStageSigned {
+		txHex = otx.SignedTx
+	}
+	tx, _ := decodeEthTx(txHex)
+	tx.PrintPretty()
+}
@@ -0,0 +1,304 @@
+package main
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcd/wire"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcwallet/wallet/txauthor"
+	"github.com/urfave/cli/v2"
+)
+
+func (bts *btcTxSender) buildOfflineTx(rawTx interface{}) (*offlineTx, error) {
+	authoredTx, ok := rawTx.(*txauthor.AuthoredTx)
+	if !ok || authoredTx.Tx == nil {
+		return nil, tokens.ErrWrongRawTx
+	}
+	txHex, err := encodeBtcTx(authoredTx.Tx)
+	if err != nil {
+		return nil, err
+	}
+	relayFeePerKb := bts.relayFeePerKb
+	args := &tokens.BuildTxArgs{
+		From: bts.sender,
+		Memo: bts.memo,
+		Extra: &tokens.AllExtras{
+			BtcExtra: &tokens.BtcExtraArgs{
+				RelayFeePerKb:     &relayFeePerKb,
+				PreviousOutPoints: make([]*tokens.BtcOutPoint, len(authoredTx.Tx.TxIn)),
+			},
+		},
+	}
+	for i, txin := range authoredTx.Tx.TxIn {
+		args.Extra.BtcExtra.PreviousOutPoints[i] = &tokens.BtcOutPoint{
+			Hash:  txin.PreviousOutPoint.Hash.String(),
+			Index: txin.PreviousOutPoint.Index,
+		}
+	}
+	otx := newOfflineTx(bts.kind, args)
+	otx.NetID = bts.netID
+	otx.Receivers = bts.receivers
+	otx.Amounts = bts.amounts
+	otx.RawTx = txHex
+	otx.Utxo = &offlineUtxoInfo{
+		PrevScripts:     make([]string, len(authoredTx.PrevScripts)),
+		PrevInputValues: make([]int64, len(authoredTx.PrevInputValues)),
+		TotalInput:      int64(authoredTx.TotalInput),
+		ChangeIndex:     authoredTx.ChangeIndex,
+	}
+	for i, script := range authoredTx.PrevScripts {
+		otx.Utxo.PrevScripts[i] = hex.EncodeToString(script)
+	}
+	for i, value := range authoredTx.PrevInputValues {
+		otx.Utxo.PrevInputValues[i] = int64(value)
+	}
+	return otx, nil
+}
+
+func encodeBtcTx(tx *wire.MsgTx) (string, error) {
+	buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
+	err := tx.Serialize(buf)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf.Bytes()), nil
+}
+
+func decodeBtcTx(txHex string) (*wire.MsgTx, error) {
+	data, err := hex.DecodeString(txHex)
+	if err != nil {
+		return nil, err
+	}
+	tx := wire.NewMsgTx(wire.TxVersion)
+	err = tx.Deserialize(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+func (otx *offlineTx) decodeBtcAuthoredTx() (*txauthor.AuthoredTx, error) {
+	if otx.Utxo == nil {
+		return nil, errors.New("missing utxo info")
+	}
+	tx, err := decodeBtcTx(otx.RawTx)
+	if err != nil {
+		return nil, err
+	}
+	authoredTx := &txauthor.AuthoredTx{
+		Tx:              tx,
+		PrevScripts:     make([][]byte, len(otx.Utxo.PrevScripts)),
+		PrevInputValues: make([]btcutil.Amount, len(otx.Utxo.PrevInputValues)),
+		TotalInput:      btcutil.Amount(otx.Utxo.TotalInput),
+		ChangeIndex:     otx.Utxo.ChangeIndex,
+	}
+	for i, script := range otx.Utxo.PrevScripts {
+		authoredTx.PrevScripts[i], err = hex.DecodeString(script)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for i, value := range otx.Utxo.PrevInputValues {
+		authoredTx.PrevInputValues[i] = btcutil.Amount(value)
+	}
+	return authoredTx, nil
+}
+
+func (otx *offlineTx) initBtcBridge(gateway string) *btcTxSender {
+	bts := utxoTxSenders[otx.Kind]
+	bts.gateway = gateway
+	bts.netID = otx.NetID
+	bts.initBridge()
+	return bts
+}
+
+func (otx *offlineTx) verifyBtcTx() error {
+	bts := otx.initBtcBridge("")
+	authoredTx, err := otx.decodeBtcAuthoredTx()
+	if err != nil {
+		return err
+	}
+	err = otx.checkBtcTxWithArgs(bts.bridge, authoredTx)
+	if err != nil {
+		return err
+	}
+	if otx.Stage != utils.OfflineStageSigned {
+		return nil
+	}
+	signedTx, err := decodeBtcTx(otx.SignedTx)
+	if err != nil {
+		return err
+	}
+	if signedTx.TxHash().String() != otx.TxHash {
+		return fmt.Errorf("signed tx hash mismatch, have %v want %v", signedTx.TxHash().String(), otx.TxHash)
+	}
+	if !isSameBtcTxContent(authoredTx.Tx, signedTx) {
+		return errors.New("signed tx mismatch with raw tx")
+	}
+	for i := range signedTx.TxIn {
+		vm, err := txscript.NewEngine(authoredTx.PrevScripts[i], signedTx, i,
+			txscript.StandardVerifyFlags, nil, nil, int64(authoredTx.PrevInputValues[i]))
+		if err != nil {
+			return err
+		}
+		if err = vm.Execute(); err != nil {
+			return fmt.Errorf("verify signature of input %v failed, %v", i, err)
+		}
+	}
+	return nil
+}
+
+func (otx *offlineTx) checkBtcTxWithArgs(bridge *btc.Bridge, authoredTx *txauthor.AuthoredTx) error {
+	tx := authoredTx.Tx
+	if len(tx.TxIn) == 0 ||
+		len(tx.TxIn) != len(authoredTx.PrevScripts) ||
+		len(tx.TxIn) != len(authoredTx.PrevInputValues) {
+		return errors.New("inputs count mismatch")
+	}
+	senderScript, err := bridge.GetPayToAddrScript(otx.Args.From)
+	if err != nil {
+		return err
+	}
+	var totalInput btcutil.Amount
+	for i, script := range authoredTx.PrevScripts {
+		if !bytes.Equal(script, senderScript) {
+			return fmt.Errorf("input %v is not owned by sender", i)
+		}
+		totalInput += authoredTx.PrevInputValues[i]
+	}
+	if totalInput != authoredTx.TotalInput {
+		return errors.New("total input mismatch")
+	}
+	if otx.Args.Extra != nil && otx.Args.Extra.BtcExtra != nil {
+		points := otx.Args.Extra.BtcExtra.PreviousOutPoints
+		if len(points) != len(tx.TxIn) {
+			return errors.New("previous outpoints count mismatch")
+		}
+		for i, txin := range tx.TxIn {
+			if points[i].Hash != txin.PreviousOutPoint.Hash.String() || points[i].Index != txin.PreviousOutPoint.Index {
+				return fmt.Errorf("previous outpoint %v mismatch", i)
+			}
+		}
+	}
+
+	// outputs are receivers, memo and change in order
+	if len(otx.Receivers) != len(otx.Amounts) {
+		return errors.New("count of receivers and values are not equal")
+	}
+	var txOuts []*wire.TxOut
+	for i, receiver := range otx.Receivers {
+		if otx.Amounts[i] <= 0 {
+			continue
+		}
+		pkscript, errf := bridge.GetPayToAddrScript(receiver)
+		if errf != nil {
+			return errf
+		}
+		txOuts = append(txOuts, wire.NewTxOut(otx.Amounts[i], pkscript))
+	}
+	if otx.Args.Memo != "" {
+		nullScript, errf := bridge.NullDataScript(otx.Args.Memo)
+		if errf != nil {
+			return errf
+		}
+		txOuts = append(txOuts, wire.NewTxOut(0, nullScript))
+	}
+	changeIndex := authoredTx.ChangeIndex
+	if changeIndex >= 0 {
+		if changeIndex != len(txOuts) || changeIndex >= len(tx.TxOut) {
+			return errors.New("wrong change index")
+		}
+		change := tx.TxOut[changeIndex]
+		if !bytes.Equal(change.PkScript, senderScript) {
+			return errors.New("change is not paid to sender")
+		}
+		txOuts = append(txOuts, change)
+	}
+	if !reflect.DeepEqual(txOuts, tx.TxOut) {
+		return errors.New("outputs mismatch with receivers and memo")
+	}
+	if getBtcTxFee(authoredTx) < 0 {
+		return errors.New("outputs exceed inputs")
+	}
+	return nil
+}
+
+func getBtcTxFee(authoredTx *txauthor.AuthoredTx) int64 {
+	fee := int64(authoredTx.TotalInput)
+	for _, txout := range authoredTx.Tx.TxOut {
+		fee -= txout.Value
+	}
+	return fee
+}
+
+// isSameBtcTxContent compare tx ignore signature scripts
+func isSameBtcTxContent(tx1, tx2 *wire.MsgTx) bool {
+	if tx1.Version != tx2.Version || tx1.LockTime != tx2.LockTime ||
+		len(tx1.TxIn) != len(tx2.TxIn) ||
+		!reflect.DeepEqual(tx1.TxOut, tx2.TxOut) {
+		return false
+	}
+	for i, txin := range tx1.TxIn {
+		if txin.PreviousOutPoint != tx2.TxIn[i].PreviousOutPoint ||
+			txin.Sequence != tx2.TxIn[i].Sequence {
+			return false
+		}
+	}
+	return true
+}
+
+func (otx *offlineTx) signBtcTx(ctx *cli.Context) error {
+	bts := otx.initBtcBridge("")
+	bts.wifFile = ctx.String(wifFileFlag.Name)
+	bts.priFile = ctx.String(priKeyFileFlag.Name)
+	bts.sender = otx.Args.From
+	if bts.wifFile == "" && bts.priFile == "" {
+		return errors.New("must specify '-wif' or '-pri' flag")
+	}
+	wifStr := bts.loadWIFForAddress()
+
+	authoredTx, err := otx.decodeBtcAuthoredTx()
+	if err != nil {
+		return err
+	}
+	signedTx, txHash, err := bts.bridge.SignTransactionWithWIF(authoredTx, wifStr)
+	if err != nil {
+		return err
+	}
+	signedAuthoredTx, ok := signedTx.(*txauthor.AuthoredTx)
+	if !ok {
+		return tokens.ErrWrongRawTx
+	}
+	otx.SignedTx, err = encodeBtcTx(signedAuthoredTx.Tx)
+	if err != nil {
+		return err
+	}
+	otx.TxHash = txHash
+	return nil
+}
+
+func (otx *offlineTx) sendBtcTx(gateway string) error {
+	bts := otx.initBtcBridge(gateway)
+	_, err := bts.bridge.PostTransaction(otx.SignedTx)
+	return err
+}
+
+func (otx *offlineTx) printBtcTx() {
+	authoredTx, _ := otx.decodeBtcAuthoredTx()
+	fmt.Printf("netID:     %v\n", otx.NetID)
+	fmt.Printf("receivers: %v\n", otx.Receivers)
+	fmt.Printf("amounts:   %v\n", otx.Amounts)
+	fmt.Printf("fee:       %v\n", getBtcTxFee(authoredTx))
+	if otx.Stage == utils.OfflineStageSigned {
+		authoredTx.Tx, _ = decodeBtcTx(otx.SignedTx)
+	}
+	fmt.Println(btc.AuthoredTxToString(authoredTx, true))
+}
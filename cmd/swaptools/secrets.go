@@ -0,0 +1,203 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/cmd/utils"
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tools/secrets"
+	"github.com/urfave/cli/v2"
+)
+
+var (
+	localVaultFlag = &cli.StringFlag{
+		Name:  "vault",
+		Usage: "encrypted local vault file",
+	}
+	localVaultPasswordFlag = &cli.StringFlag{
+		Name:  "vaultpassword",
+		Usage: "reference of local vault password (password file path, or 'env:NAME')",
+	}
+
+	// nolint:lll // allow long line of example
+	secretsCommand = &cli.Command{
+		Name:  "secrets",
+		Usage: "manage secrets in local vault and check secret references",
+		Description: `
+secrets (eg. keystore passwords, db credentials) can be configed by reference in form of 'scheme:name':
+
+env:NAME                 environment variable
+file:/path/to/file       file content (default of password file config items and flags)
+localvault:name          secret in encrypted local vault file ('LocalVaultFile' in '[Secrets]')
+vault:path#field         field of kv secret in hashicorp vault compatible http api ('VaultAddress' in '[Secrets]')
+
+Example:
+
+echo -n "$DB_PASSWORD" | ./swaptools secrets set --vault ./secrets.vault --vaultpassword env:VAULT_PASSWORD mongodb-password
+./swaptools secrets check --config ./config.toml localvault:mongodb-password env:DCRM_PASSWORD
+`,
+		Subcommands: []*cli.Command{
+			{
+				Action:    setSecret,
+				Name:      "set",
+				Usage:     "set secret read from stdin into local vault (create if not exist)",
+				ArgsUsage: "<name>",
+				Flags: []cli.Flag{
+					localVaultFlag,
+					localVaultPasswordFlag,
+				},
+			},
+			{
+				Action:    removeSecret,
+				Name:      "remove",
+				Usage:     "remove secret from local vault",
+				ArgsUsage: "<name>",
+				Flags: []cli.Flag{
+					localVaultFlag,
+					localVaultPasswordFlag,
+				},
+			},
+			{
+				Action:    listSecrets,
+				Name:      "list",
+				Usage:     "list names of secrets in local vault",
+				ArgsUsage: " ",
+				Flags: []cli.Flag{
+					localVaultFlag,
+					localVaultPasswordFlag,
+				},
+			},
+			{
+				Action:    checkSecrets,
+				Name:      "check",
+				Usage:     "check secret references can be resolved (without showing values)",
+				ArgsUsage: "<reference>...",
+				Flags: []cli.Flag{
+					utils.ConfigFileFlag,
+				},
+			},
+		},
+	}
+)
+
+func openLocalVault(ctx *cli.Context, create bool) (*secrets.LocalVault, error) {
+	vaultFile := ctx.String(localVaultFlag.Name)
+	if vaultFile == "" {
+		return nil, errors.New("must specify local vault file")
+	}
+	passwordRef := ctx.String(localVaultPasswordFlag.Name)
+	if passwordRef == "" {
+		return nil, errors.New("must specify local vault password")
+	}
+	password, err := secrets.GetPassword(passwordRef)
+	if err != nil {
+		return nil, err
+	}
+	if create && !common.FileExist(vaultFile) {
+		return secrets.NewLocalVault(vaultFile, password)
+	}
+	return secrets.LoadLocalVault(vaultFile, password)
+}
+
+func getSecretName(ctx *cli.Context, command string) (string, error) {
+	if ctx.NArg() != 1 {
+		_ = cli.ShowCommandHelp(ctx, command)
+		fmt.Println()
+		return "", fmt.Errorf("invalid arguments: %q", ctx.Args())
+	}
+	return ctx.Args().Get(0), nil
+}
+
+func setSecret(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	name, err := getSecretName(ctx, "set")
+	if err != nil {
+		return err
+	}
+	vault, err := openLocalVault(ctx, true)
+	if err != nil {
+		return err
+	}
+	value, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && value == "" {
+		return fmt.Errorf("read secret from stdin failed: %v", err)
+	}
+	value = strings.TrimRight(value, "\r\n")
+	if value == "" {
+		return errors.New("empty secret")
+	}
+	vault.SetSecret(name, value)
+	if err = vault.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("set secret '%v' success\n", name)
+	return nil
+}
+
+func removeSecret(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	name, err := getSecretName(ctx, "remove")
+	if err != nil {
+		return err
+	}
+	vault, err := openLocalVault(ctx, false)
+	if err != nil {
+		return err
+	}
+	if !vault.RemoveSecret(name) {
+		return fmt.Errorf("secret '%v' not found", name)
+	}
+	if err = vault.Save(); err != nil {
+		return err
+	}
+	fmt.Printf("remove secret '%v' success\n", name)
+	return nil
+}
+
+func listSecrets(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	vault, err := openLocalVault(ctx, false)
+	if err != nil {
+		return err
+	}
+	for _, name := range vault.Names() {
+		fmt.Println(name)
+	}
+	return nil
+}
+
+func checkSecrets(ctx *cli.Context) error {
+	utils.SetLogger(ctx)
+	if ctx.NArg() == 0 {
+		_ = cli.ShowCommandHelp(ctx, "check")
+		fmt.Println()
+		return errors.New("no secret reference is specified")
+	}
+	if configFile := utils.GetConfigFilePath(ctx); configFile != "" {
+		config, err := params.LoadConfigFile(configFile)
+		if err != nil {
+			return err
+		}
+		if err = secrets.Init(config.Secrets); err != nil {
+			return err
+		}
+	}
+	failedCount := 0
+	for _, ref := range ctx.Args().Slice() {
+		if _, err := secrets.GetSecret(ref); err != nil {
+			failedCount++
+			fmt.Printf("%v: error %v\n", ref, err)
+			continue
+		}
+		fmt.Printf("%v: ok\n", ref)
+	}
+	if failedCount > 0 {
+		return fmt.Errorf("%v of %v secret references failed", failedCount, ctx.NArg())
+	}
+	return nil
+}
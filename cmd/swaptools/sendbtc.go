@@ -31,6 +31,9 @@ send btc command, sign tx with WIF or private key.
 Example:
 
 ./swaptools sendbtc --gateway http://1.2.3.4:5555 --net testnet3 --wif ./wif.txt --from maiApsjjnceZ7Cx1UMj344JRU3R8A2Say6 --to mtc4xaZgJJZpN6BdoWk7pHFho1GTUnd5aP --value 10000 --to mfwanCuht2b4Lvb5XTds4Rvzy3jZ2ZWraL --value 20000 --memo "test send btc" --dryrun
+
+specify '--output' flag to build unsigned tx file for offline signing (see 'signtx' command),
+WIF or private key is not needed in this case.
 `,
 		Flags: []cli.Flag{
 			utils.GatewayFlag,
@@ -43,15 +46,23 @@ Example:
 			memoFlag,
 			relayFeePerKbFlag,
 			dryRunFlag,
+			utils.OutputFileFlag,
 		},
 	}
 )
 
+// btcTxSender sender of utxo chains built on btc code path
 type btcTxSender struct {
+	kind       string // offline tx kind
+	blockChain string
+	newBridge  func(isSrc bool) *btc.Bridge
+	bridge     *btc.Bridge
+
 	gateway       string
 	netID         string
 	wifFile       string
 	priFile       string
+	outputFile    string
 	sender        string
 	receivers     []string
 	amounts       []int64
@@ -61,8 +72,16 @@ type btcTxSender struct {
 }
 
 var (
-	btcBridge *btc.Bridge
-	btcSender = &btcTxSender{}
+	btcSender = &btcTxSender{
+		kind:       offlineKindBtcTx,
+		blockChain: "Bitcoin",
+		newBridge:  btc.NewCrossChainBridge,
+	}
+
+	utxoTxSenders = map[string]*btcTxSender{
+		offlineKindBtcTx: btcSender,
+		offlineKindLtcTx: ltcSender,
+	}
 )
 
 func (bts *btcTxSender) initArgs(ctx *cli.Context) {
@@ -76,11 +95,12 @@ func (bts *btcTxSender) initArgs(ctx *cli.Context) {
 	bts.memo = ctx.String(memoFlag.Name)
 	bts.relayFeePerKb = ctx.Int64(relayFeePerKbFlag.Name)
 	bts.dryRun = ctx.Bool(dryRunFlag.Name)
+	bts.outputFile = ctx.String(utils.OutputFileFlag.Name)
 
 	if bts.netID == "" {
 		log.Fatal("must specify '-net' flag")
 	}
-	if bts.wifFile == "" && bts.priFile == "" {
+	if bts.outputFile == "" && bts.wifFile == "" && bts.priFile == "" {
 		log.Fatal("must specify '-wif' or '-pri' flag")
 	}
 	if bts.sender == "" {
@@ -98,19 +118,32 @@ func (bts *btcTxSender) initArgs(ctx *cli.Context) {
 }
 
 func sendBtc(ctx *cli.Context) error {
-	utils.SetLogger(ctx)
-	btcSender.initArgs(ctx)
+	return sendUtxoTx(ctx, btcSender)
+}
 
-	btcSender.initBridge()
+func sendUtxoTx(ctx *cli.Context, bts *btcTxSender) error {
+	utils.SetLogger(ctx)
+	bts.initArgs(ctx)
 
-	wifStr := btcSender.loadWIFForAddress()
+	bts.initBridge()
 
-	rawTx, err := btcBridge.BuildTransaction(btcSender.sender, btcSender.receivers, btcSender.amounts, btcSender.memo, btcSender.relayFeePerKb)
+	rawTx, err := bts.bridge.BuildTransaction(bts.sender, bts.receivers, bts.amounts, bts.memo, bts.relayFeePerKb)
 	if err != nil {
 		log.Fatal("BuildRawTransaction error", "err", err)
 	}
 
-	signedTx, txHash, err := btcBridge.SignTransactionWithWIF(rawTx, wifStr)
+	if bts.outputFile != "" {
+		otx, errf := bts.buildOfflineTx(rawTx)
+		if errf != nil {
+			log.Fatal("build offline tx failed", "err", errf)
+		}
+		saveOfflineTx(bts.outputFile, otx)
+		return nil
+	}
+
+	wifStr := bts.loadWIFForAddress()
+
+	signedTx, txHash, err := bts.bridge.SignTransactionWithWIF(rawTx, wifStr)
 	if err != nil {
 		log.Fatal("SignTransaction failed", "err", err)
 	}
@@ -118,8 +151,8 @@ func sendBtc(ctx *cli.Context) error {
 
 	fmt.Println(btc.AuthoredTxToString(signedTx, true))
 
-	if !btcSender.dryRun {
-		_, err = btcBridge.SendTransaction(signedTx)
+	if !bts.dryRun {
+		_, err = bts.bridge.SendTransaction(signedTx)
 		if err != nil {
 			log.Error("SendTransaction failed", "err", err)
 		}
@@ -130,12 +163,12 @@ func sendBtc(ctx *cli.Context) error {
 }
 
 func (bts *btcTxSender) initBridge() {
-	btcBridge = btc.NewCrossChainBridge(true)
-	btcBridge.ChainConfig = &tokens.ChainConfig{
-		BlockChain: "Bitcoin",
+	bts.bridge = bts.newBridge(true)
+	bts.bridge.ChainConfig = &tokens.ChainConfig{
+		BlockChain: bts.blockChain,
 		NetID:      bts.netID,
 	}
-	btcBridge.GatewayConfig = &tokens.GatewayConfig{
+	bts.bridge.GatewayConfig = &tokens.GatewayConfig{
 		APIAddress: []string{bts.gateway},
 	}
 }
@@ -167,7 +200,7 @@ func (bts *btcTxSender) loadWIFForAddress() string {
 			}
 		}
 		pri, _ := btcec.PrivKeyFromBytes(btcec.S256(), pribs)
-		wif, err := btcutil.NewWIF(pri, btcBridge.GetChainParams(), true)
+		wif, err := btcutil.NewWIF(pri, bts.bridge.GetChainParams(), true)
 		if err != nil {
 			log.Fatal("failed to parse private key")
 		}
@@ -178,7 +211,7 @@ func (bts *btcTxSender) loadWIFForAddress() string {
 		log.Fatal("failed to decode WIF to verify")
 	}
 	pkdata := wif.SerializePubKey()
-	pkaddr, _ := btcBridge.NewAddressPubKeyHash(pkdata)
+	pkaddr, _ := bts.bridge.NewAddressPubKeyHash(pkdata)
 	if pkaddr.EncodeAddress() != bts.sender {
 		log.Fatal("address mismatch", "decoded", pkaddr.EncodeAddress(), "from", bts.sender)
 	}
@@ -28,6 +28,9 @@ send eth tx command, sign tx with keystore and password file.
 Example:
 
 ./swaptools sendethtx --gateway http://1.2.3.4:5555 --keystore ./UTC.json --password ./password.txt --from 0x1111111111111111111111111111111111111111 --to 0x2222222222222222222222222222222222222222 --value 1000000000000000000 --input 0x0123456789 --dryrun
+
+specify '--output' flag to build unsigned tx file for offline signing (see 'signtx' command),
+keystore and password is not needed in this case.
 `,
 		Flags: []cli.Flag{
 			utils.GatewayFlag,
@@ -41,6 +44,7 @@ Example:
 			gasPriceFlag,
 			accountNonceFlag,
 			dryRunFlag,
+			utils.OutputFileFlag,
 		},
 	}
 )
@@ -49,6 +53,7 @@ type ethTxSender struct {
 	gateway      string
 	keystoreFile string
 	passwordFile string
+	outputFile   string
 	sender       string
 	receiver     string
 	dryRun       bool
@@ -71,8 +76,9 @@ func (ets *ethTxSender) initArgs(ctx *cli.Context) {
 	ets.sender = ctx.String(senderFlag.Name)
 	ets.receiver = ctx.String(receiverFlag.Name)
 	ets.dryRun = ctx.Bool(dryRunFlag.Name)
+	ets.outputFile = ctx.String(utils.OutputFileFlag.Name)
 
-	if ets.keystoreFile == "" || ets.passwordFile == "" {
+	if ets.outputFile == "" && (ets.keystoreFile == "" || ets.passwordFile == "") {
 		log.Fatal("must specify '-keystore' and '-password' flag")
 	}
 	if ets.sender == "" {
@@ -115,6 +121,11 @@ func (ets *ethTxSender) initArgs(ctx *cli.Context) {
 }
 
 func (ets *ethTxSender) doInit() {
+	if ets.outputFile != "" {
+		ets.initBridge()
+		return
+	}
+
 	var err error
 	ets.keyWrapper, err = tools.LoadKeyStore(ets.keystoreFile, ets.passwordFile)
 	if err != nil {
@@ -142,8 +153,8 @@ func (ets *ethTxSender) initBridge() {
 	ethBridge.VerifyChainID()
 }
 
-func (ets *ethTxSender) buildTx() (rawTx interface{}, err error) {
-	args := &tokens.BuildTxArgs{
+func (ets *ethTxSender) buildTx() (args *tokens.BuildTxArgs, rawTx interface{}, err error) {
+	args = &tokens.BuildTxArgs{
 		From:  ets.sender,
 		To:    ets.receiver,
 		Value: ets.value,
@@ -152,7 +163,8 @@ func (ets *ethTxSender) buildTx() (rawTx interface{}, err error) {
 			EthExtra: ethExtra,
 		},
 	}
-	return ethBridge.BuildRawTransaction(args)
+	rawTx, err = ethBridge.BuildRawTransaction(args)
+	return args, rawTx, err
 }
 
 func sendEthTx(ctx *cli.Context) error {
@@ -161,11 +173,20 @@ func sendEthTx(ctx *cli.Context) error {
 
 	ethSender.doInit()
 
-	rawTx, err := ethSender.buildTx()
+	args, rawTx, err := ethSender.buildTx()
 	if err != nil {
 		log.Fatal("BuildRawTransaction error", "err", err)
 	}
 
+	if ethSender.outputFile != "" {
+		otx, errf := buildOfflineEthTx(rawTx, args, ethBridge.SignerChainID)
+		if errf != nil {
+			log.Fatal("build offline tx failed", "err", errf)
+		}
+		saveOfflineTx(ethSender.outputFile, otx)
+		return nil
+	}
+
 	signedTx, txHash, err := ethBridge.SignTransactionWithPrivateKey(rawTx, ethSender.keyWrapper.PrivateKey)
 	if err != nil {
 		log.Fatal("SignTransaction failed", "err", err)
@@ -104,6 +104,11 @@ var (
 		Name:  "token",
 		Usage: "token address slice",
 	}
+	// PairIDFlag --pairid
+	PairIDFlag = &cli.StringFlag{
+		Name:  "pairid",
+		Usage: "token pair id",
+	}
 	// PairIDSliceFlag --pairid
 	PairIDSliceFlag = &cli.StringSliceFlag{
 		Name:  "pairid",
@@ -119,6 +124,16 @@ var (
 		Name:  "password",
 		Usage: "password file",
 	}
+	// InputFileFlag --input
+	InputFileFlag = &cli.StringFlag{
+		Name:  "input",
+		Usage: "input file",
+	}
+	// OutputFileFlag --output
+	OutputFileFlag = &cli.StringFlag{
+		Name:  "output",
+		Usage: "output file",
+	}
 	// SwapTypeFlag --swaptype
 	SwapTypeFlag = &cli.StringFlag{
 		Name:  "swaptype",
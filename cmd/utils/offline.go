@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+)
+
+// offline signing stages.
+// offline signing is done in three steps: build the unsigned file online,
+// sign it on the air-gapped machine, then broadcast or submit it online.
+const (
+	OfflineStageUnsigned = "unsigned"
+	OfflineStageSigned   = "signed"
+)
+
+// OfflineHeader common header of offline signing files
+type OfflineHeader struct {
+	Kind      string `json:"kind"`
+	Stage     string `json:"stage"`
+	CreatedAt int64  `json:"createdAt"`
+	SignedAt  int64  `json:"signedAt,omitempty"`
+}
+
+// CheckOffline check kind and stage of offline file
+func (h *OfflineHeader) CheckOffline(kind, stage string) error {
+	if h.Kind != kind {
+		return fmt.Errorf("wrong offline file kind, have '%v' want '%v'", h.Kind, kind)
+	}
+	if stage != "" && h.Stage != stage {
+		return fmt.Errorf("wrong offline file stage, have '%v' want '%v'", h.Stage, stage)
+	}
+	return nil
+}
+
+// SaveOfflineFile save offline signing file in json format
+func SaveOfflineFile(file string, content interface{}) error {
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, append(data, '\n'), 0600)
+}
+
+// LoadOfflineFile load offline signing file in json format
+func LoadOfflineFile(file string, content interface{}) error {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, content)
+}
+
+// PeekOfflineKind get the kind of offline file
+func PeekOfflineKind(file string) (string, error) {
+	var header OfflineHeader
+	if err := LoadOfflineFile(file, &header); err != nil {
+		return "", err
+	}
+	return header.Kind, nil
+}
@@ -70,6 +70,14 @@ func setDefaultDcrmNodeInfo(nodeInfo *NodeInfo) {
 	defaultDcrmNode = nodeInfo
 }
 
+// GetDefaultDcrmUser get dcrm user of default dcrm node
+func GetDefaultDcrmUser() common.Address {
+	if defaultDcrmNode == nil {
+		return common.Address{}
+	}
+	return defaultDcrmNode.dcrmUser
+}
+
 // GetAllInitiatorNodes get all initiator dcrm node info
 func GetAllInitiatorNodes() []*NodeInfo {
 	return allInitiatorNodes
@@ -140,7 +148,8 @@ func (ni *NodeInfo) GetDcrmUser() common.Address {
 	return ni.dcrmUser
 }
 
-// LoadKeyStore load keystore
+// LoadKeyStore load keystore, password is got from secrets provider
+// if passfile is secret reference (eg. 'localvault:dcrm-user1')
 func (ni *NodeInfo) LoadKeyStore(keyfile, passfile string) (common.Address, error) {
 	key, err := tools.LoadKeyStore(keyfile, passfile)
 	if err != nil {
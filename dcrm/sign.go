@@ -156,3 +156,12 @@ func BuildDcrmRawTx(nonce uint64, payload []byte, keyWrapper *keystore.Key) (str
 	rawTX := common.ToHex(txdata)
 	return rawTX, nil
 }
+
+// SignWithNodeKey sign hash with keystore of default dcrm node
+// (this is not dcrm sign, it's used to prove the identity of this node)
+func SignWithNodeKey(hash []byte) ([]byte, error) {
+	if defaultDcrmNode == nil || defaultDcrmNode.keyWrapper == nil {
+		return nil, errors.New("dcrm node keystore is not loaded")
+	}
+	return crypto.Sign(hash, defaultDcrmNode.keyWrapper.PrivateKey)
+}
@@ -48,13 +48,25 @@ func GetServerInfo() (*ServerInfo, error) {
 	}, nil
 }
 
-// GetTokenPairInfo api
+// GetTokenPairInfo api, with effective swap fee schedule of swapin (in source token)
+// and swapout (in destination token)
 func GetTokenPairInfo(pairID string) (*tokens.TokenPairConfig, error) {
 	pairCfg := tokens.GetTokenPairConfig(pairID)
 	if pairCfg == nil {
 		return nil, errTokenPairNotExist
 	}
-	return pairCfg, nil
+	result := *pairCfg
+	if pairCfg.SrcToken != nil {
+		srcToken := *pairCfg.SrcToken
+		srcToken.FeeSchedule = tokens.GetSwapFeeSchedule(pairID, true)
+		result.SrcToken = &srcToken
+	}
+	if pairCfg.DestToken != nil {
+		destToken := *pairCfg.DestToken
+		destToken.FeeSchedule = tokens.GetSwapFeeSchedule(pairID, false)
+		result.DestToken = &destToken
+	}
+	return &result, nil
 }
 
 // GetSwapStatistics api
@@ -115,6 +127,24 @@ func GetSwapout(txid, pairID, bindAddr *string) (*SwapInfo, error) {
 	return nil, mongodb.ErrSwapNotFound
 }
 
+// GetSwapinStatusHistory api
+func GetSwapinStatusHistory(txid, pairID, bindAddr *string) ([]*SwapStatusChange, error) {
+	result, err := mongodb.FindSwapStatusHistory(true, *txid, *pairID, *bindAddr)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertMgoSwapStatusChanges(result), nil
+}
+
+// GetSwapoutStatusHistory api
+func GetSwapoutStatusHistory(txid, pairID, bindAddr *string) ([]*SwapStatusChange, error) {
+	result, err := mongodb.FindSwapStatusHistory(false, *txid, *pairID, *bindAddr)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertMgoSwapStatusChanges(result), nil
+}
+
 func processHistoryLimit(limit int) int {
 	switch {
 	case limit == 0:
@@ -149,6 +179,65 @@ func GetSwapoutHistory(address, pairID string, offset, limit int) ([]*SwapInfo,
 	return ConvertMgoSwapResultsToSwapInfos(result), nil
 }
 
+// GetAggregateHistory api, latest first
+func GetAggregateHistory(pairID string, offset, limit int) ([]*AggregateInfo, error) {
+	log.Debug("[api] receive GetAggregateHistory", "pairID", pairID, "offset", offset, "limit", limit)
+	limit = processHistoryLimit(limit)
+	if limit < 0 {
+		limit = -limit
+	}
+	result, err := mongodb.FindAggregates(pairID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertMgoAggregatesToAggregateInfos(result), nil
+}
+
+// GetFeeLedger api, fee summary of pair in time range [from, to), zero means unbounded
+func GetFeeLedger(pairID string, from, to int64) (*FeeLedger, error) {
+	log.Debug("[api] receive GetFeeLedger", "pairID", pairID, "from", from, "to", to)
+	if tokens.GetTokenPairConfig(pairID) == nil {
+		return nil, errTokenPairNotExist
+	}
+	return mongodb.GetFeeLedger(pairID, from, to,
+		tokens.IsSwapFeeNativeCoin(pairID, true),
+		tokens.IsSwapFeeNativeCoin(pairID, false))
+}
+
+// GetFeeLedgerEntries api, latest first, kind is one of collected, network and withdraw, empty means all
+func GetFeeLedgerEntries(pairID, kind string, offset, limit int) ([]*FeeLedgerEntry, error) {
+	log.Debug("[api] receive GetFeeLedgerEntries", "pairID", pairID, "kind", kind, "offset", offset, "limit", limit)
+	limit = processHistoryLimit(limit)
+	if limit < 0 {
+		limit = -limit
+	}
+	result, err := mongodb.FindFeeLedgerEntries(pairID, kind, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertMgoFeeLedgerEntries(result), nil
+}
+
+// GetPairsConfigHash api, hash of pairs config in use
+func GetPairsConfigHash() (*tokens.PairsConfigHash, error) {
+	log.Debug("[api] receive GetPairsConfigHash")
+	return tokens.GetPairsConfigHash(), nil
+}
+
+// GetPairConfigVersions api, latest first
+func GetPairConfigVersions(pairID string, offset, limit int) ([]*PairConfigVersion, error) {
+	log.Debug("[api] receive GetPairConfigVersions", "pairID", pairID, "offset", offset, "limit", limit)
+	limit = processHistoryLimit(limit)
+	if limit < 0 {
+		limit = -limit
+	}
+	result, err := mongodb.FindPairConfigVersions(pairID, offset, limit)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertMgoPairConfigVersions(result), nil
+}
+
 // Swapin api
 func Swapin(txid, pairID *string) (*PostResult, error) {
 	log.Debug("[api] receive Swapin", "txid", *txid, "pairID", *pairID)
@@ -175,7 +264,7 @@ func RetrySwapin(txid, pairID *string) (*PostResult, error) {
 	if !swap.Status.CanRetry() {
 		return nil, errSwapCannotRetry
 	}
-	err = mongodb.UpdateSwapinStatus(txidstr, pairIDStr, bindStr, mongodb.TxNotStable, time.Now().Unix(), "")
+	err = mongodb.UpdateSwapinStatus(txidstr, pairIDStr, bindStr, mongodb.TxNotStable, time.Now().Unix(), "", mongodb.ActorRetry)
 	if err != nil {
 		return nil, err
 	}
@@ -192,22 +281,29 @@ func swap(txid, pairID *string, isSwapin bool) (*PostResult, error) {
 	txidstr := *txid
 	pairIDStr := *pairID
 	bridge := tokens.GetCrossChainBridge(isSwapin)
-	swapInfo, err := bridge.VerifyTransaction(pairIDStr, txidstr, true)
-	if err != nil {
-		txStat := bridge.GetTransactionStatus(txidstr)
-		if txStat != nil && txStat.BlockHeight > 0 {
-			swapInfo, err = bridge.VerifyTransaction(pairIDStr, txidstr, false)
-		}
-	}
 	var txType tokens.SwapTxType
 	if isSwapin {
 		txType = tokens.SwapinTx
 	} else {
 		txType = tokens.SwapoutTx
 	}
-	err = addSwapToDatabase(txidstr, txType, swapInfo, err)
-	if err != nil {
-		return nil, err
+	if verifier, ok := bridge.(tokens.MultiSwapVerifier); ok {
+		err := addMultiSwapsToDatabase(verifier, pairIDStr, txidstr, txType)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		swapInfo, err := bridge.VerifyTransaction(pairIDStr, txidstr, true)
+		if err != nil {
+			txStat := bridge.GetTransactionStatus(txidstr)
+			if txStat != nil && txStat.BlockHeight > 0 {
+				swapInfo, err = bridge.VerifyTransaction(pairIDStr, txidstr, false)
+			}
+		}
+		err = addSwapToDatabase(txidstr, txType, swapInfo, err)
+		if err != nil {
+			return nil, err
+		}
 	}
 	if isSwapin {
 		log.Info("[api] receive swapin register", "txid", txidstr, "pairID", pairIDStr)
@@ -217,6 +313,29 @@ func swap(txid, pairID *string, isSwapin bool) (*PostResult, error) {
 	return &SuccessPostResult, nil
 }
 
+// addMultiSwapsToDatabase add all swaps of pairID in tx
+// returns error if none of them is added
+func addMultiSwapsToDatabase(verifier tokens.MultiSwapVerifier, pairID, txid string, txType tokens.SwapTxType) (err error) {
+	swapInfos, errs := verifier.VerifyTransactionSwaps(pairID, txid, true)
+	if len(swapInfos) == 0 {
+		if len(errs) > 0 && errs[0] != nil {
+			return newRPCError(-32099, "verify swap failed! "+errs[0].Error())
+		}
+		return newRPCError(-32099, "verify swap failed! no swap found")
+	}
+	added := false
+	for i, swapInfo := range swapInfos {
+		err = addSwapToDatabase(txid, txType, swapInfo, errs[i])
+		if err == nil {
+			added = true
+		}
+	}
+	if added {
+		return nil
+	}
+	return err
+}
+
 func addSwapToDatabase(txid string, txType tokens.SwapTxType, swapInfo *tokens.TxSwapInfo, verifyError error) (err error) {
 	if !tokens.ShouldRegisterSwapForError(verifyError) {
 		return newRPCError(-32099, "verify swap failed! "+verifyError.Error())
@@ -270,10 +389,11 @@ func GetP2shAddressInfo(p2shAddress string) (*tokens.P2shAddressInfo, error) {
 }
 
 func calcP2shAddress(bindAddress string, addToDatabase bool) (*tokens.P2shAddressInfo, error) {
-	if btc.BridgeInstance == nil {
+	btcBridge := btc.GetSrcBridge()
+	if btcBridge == nil {
 		return nil, errNotBtcBridge
 	}
-	p2shAddr, redeemScript, err := btc.BridgeInstance.GetP2shAddress(bindAddress)
+	p2shAddr, redeemScript, err := btcBridge.GetP2shAddress(bindAddress)
 	if err != nil {
 		return nil, newRPCInternalError(err)
 	}
@@ -301,15 +421,16 @@ func calcP2shAddress(bindAddress string, addToDatabase bool) (*tokens.P2shAddres
 // P2shSwapin api
 func P2shSwapin(txid, bindAddr *string) (*PostResult, error) {
 	log.Debug("[api] receive P2shSwapin", "txid", *txid, "bindAddress", *bindAddr)
-	if btc.BridgeInstance == nil {
+	btcBridge := btc.GetSrcBridge()
+	if btcBridge == nil {
 		return nil, errNotBtcBridge
 	}
 	txidstr := *txid
-	pairID := btc.PairID
+	pairID := btcBridge.GetP2shPairID()
 	if swap, _ := mongodb.FindSwapin(txidstr, pairID, *bindAddr); swap != nil {
 		return nil, mongodb.ErrItemIsDup
 	}
-	swapInfo, err := btc.BridgeInstance.VerifyP2shTransaction(pairID, txidstr, *bindAddr, true)
+	swapInfo, err := btcBridge.VerifyP2shTransaction(pairID, txidstr, *bindAddr, true)
 	if !tokens.ShouldRegisterSwapForError(err) {
 		return nil, newRPCError(-32099, "verify p2sh swapin failed! "+err.Error())
 	}
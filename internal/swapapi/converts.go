@@ -75,3 +75,78 @@ func ConvertMgoSwapResultsToSwapInfos(mrSlice []*mongodb.MgoSwapResult) []*SwapI
 	}
 	return result
 }
+
+// ConvertMgoSwapStatusChanges convert
+func ConvertMgoSwapStatusChanges(mcSlice []*mongodb.MgoSwapStatusChange) []*SwapStatusChange {
+	result := make([]*SwapStatusChange, len(mcSlice))
+	for k, v := range mcSlice {
+		result[k] = &SwapStatusChange{
+			IsResult:  v.IsResult,
+			From:      v.From,
+			FromMsg:   v.From.String(),
+			To:        v.To,
+			ToMsg:     v.To.String(),
+			Actor:     v.Actor,
+			Reason:    v.Reason,
+			Timestamp: v.Timestamp,
+		}
+	}
+	return result
+}
+
+// ConvertMgoAggregatesToAggregateInfos convert
+func ConvertMgoAggregatesToAggregateInfos(maSlice []*mongodb.MgoAggregate) []*AggregateInfo {
+	result := make([]*AggregateInfo, len(maSlice))
+	for k, v := range maSlice {
+		result[k] = &AggregateInfo{
+			TxHash:        v.Key,
+			PairID:        v.PairID,
+			Kind:          v.Kind,
+			Inputs:        v.Inputs,
+			SumValue:      v.SumValue,
+			RelayFeePerKb: v.RelayFeePerKb,
+			Status:        v.Status,
+			InitTime:      v.InitTime,
+			Timestamp:     v.Timestamp,
+			Memo:          v.Memo,
+		}
+	}
+	return result
+}
+
+// ConvertMgoFeeLedgerEntries convert
+func ConvertMgoFeeLedgerEntries(mfSlice []*mongodb.MgoFeeLedgerEntry) []*FeeLedgerEntry {
+	result := make([]*FeeLedgerEntry, len(mfSlice))
+	for k, v := range mfSlice {
+		result[k] = &FeeLedgerEntry{
+			PairID:    v.PairID,
+			Kind:      v.Kind,
+			IsSwapin:  v.IsSwapin,
+			TxID:      v.TxID,
+			SwapTx:    v.SwapTx,
+			Amount:    v.Amount,
+			Status:    v.Status,
+			Actor:     v.Actor,
+			Timestamp: v.Timestamp,
+			Memo:      v.Memo,
+		}
+	}
+	return result
+}
+
+// ConvertMgoPairConfigVersions convert
+func ConvertMgoPairConfigVersions(mpSlice []*mongodb.MgoPairConfig) []*PairConfigVersion {
+	result := make([]*PairConfigVersion, len(mpSlice))
+	for k, v := range mpSlice {
+		result[k] = &PairConfigVersion{
+			PairID:    v.PairID,
+			Version:   v.Version,
+			Hash:      v.Hash,
+			PrevHash:  v.PrevHash,
+			Removed:   v.Removed,
+			Admin:     v.Admin,
+			Timestamp: v.Timestamp,
+		}
+	}
+	return result
+}
@@ -0,0 +1,21 @@
+package swapapi
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+)
+
+var errSwapLedgerDisabled = newRPCError(-32093, "swap ledger is not enabled")
+
+// GetSwapAttestation api (oracle only)
+func GetSwapAttestation(since int64, pairID string) (*tools.SwapAttestation, error) {
+	log.Debug("[api] receive GetSwapAttestation", "since", since, "pairID", pairID)
+	if !tools.IsSwapLedgerEnabled() {
+		return nil, errSwapLedgerDisabled
+	}
+	att, err := tools.MakeSwapAttestation(since, pairID)
+	if err != nil {
+		return nil, newRPCInternalError(err)
+	}
+	return att, nil
+}
@@ -33,6 +33,63 @@ type ServerInfo struct {
 	Version             string
 }
 
+// SwapStatusChange swap status change
+type SwapStatusChange struct {
+	IsResult  bool       `json:"isresult"`
+	From      SwapStatus `json:"from"`
+	FromMsg   string     `json:"frommsg"`
+	To        SwapStatus `json:"to"`
+	ToMsg     string     `json:"tomsg"`
+	Actor     string     `json:"actor"`
+	Reason    string     `json:"reason"`
+	Timestamp int64      `json:"timestamp"`
+}
+
+// AggregateInput type alias
+type AggregateInput = mongodb.MgoAggregateInput
+
+// AggregateInfo aggregate tx info
+type AggregateInfo struct {
+	TxHash        string            `json:"txhash"`
+	PairID        string            `json:"pairid"`
+	Kind          string            `json:"kind"`
+	Inputs        []*AggregateInput `json:"inputs"`
+	SumValue      uint64            `json:"sumvalue"`
+	RelayFeePerKb int64             `json:"relayfeeperkb"`
+	Status        string            `json:"status"`
+	InitTime      int64             `json:"inittime"`
+	Timestamp     int64             `json:"timestamp"`
+	Memo          string            `json:"memo"`
+}
+
+// FeeLedger type alias
+type FeeLedger = mongodb.FeeLedger
+
+// FeeLedgerEntry fee ledger entry
+type FeeLedgerEntry struct {
+	PairID    string `json:"pairid"`
+	Kind      string `json:"kind"`
+	IsSwapin  bool   `json:"isswapin"`
+	TxID      string `json:"txid"`
+	SwapTx    string `json:"swaptx"`
+	Amount    string `json:"amount"`
+	Status    string `json:"status,omitempty"`
+	Actor     string `json:"actor,omitempty"`
+	Timestamp int64  `json:"timestamp"`
+	Memo      string `json:"memo,omitempty"`
+}
+
+// PairConfigVersion version of pair config stored in database
+type PairConfigVersion struct {
+	PairID    string `json:"pairid"`
+	Version   uint64 `json:"version"`
+	Hash      string `json:"hash,omitempty"`
+	PrevHash  string `json:"prevhash,omitempty"`
+	Removed   bool   `json:"removed,omitempty"`
+	Admin     string `json:"admin"`
+	Timestamp int64  `json:"timestamp"`
+}
+
 // PostResult post result
 type PostResult string
 
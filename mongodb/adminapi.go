@@ -59,16 +59,16 @@ func QueryBlacklist(address, pairID string) (isBlacked bool, err error) {
 }
 
 // PassSwapinBigValue pass swapin big value
-func PassSwapinBigValue(txid, pairID, bind string) error {
-	return passBigValue(txid, pairID, bind, true)
+func PassSwapinBigValue(txid, pairID, bind, actor string) error {
+	return passBigValue(txid, pairID, bind, actor, true)
 }
 
 // PassSwapoutBigValue pass swapout big value
-func PassSwapoutBigValue(txid, pairID, bind string) error {
-	return passBigValue(txid, pairID, bind, false)
+func PassSwapoutBigValue(txid, pairID, bind, actor string) error {
+	return passBigValue(txid, pairID, bind, actor, false)
 }
 
-func passBigValue(txid, pairID, bind string, isSwapin bool) error {
+func passBigValue(txid, pairID, bind, actor string, isSwapin bool) error {
 	swap, err := FindSwap(isSwapin, txid, pairID, bind)
 	if err != nil {
 		return err
@@ -76,20 +76,20 @@ func passBigValue(txid, pairID, bind string, isSwapin bool) error {
 	if swap.Status != TxWithBigValue {
 		return fmt.Errorf("swap status is %v, not big value status %v", swap.Status.String(), TxWithBigValue.String())
 	}
-	return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotSwapped, time.Now().Unix(), "")
+	return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotSwapped, time.Now().Unix(), "", actor)
 }
 
 // ReverifySwapin reverify swapin
-func ReverifySwapin(txid, pairID, bind string) error {
-	return reverifySwap(txid, pairID, bind, true)
+func ReverifySwapin(txid, pairID, bind, actor string) error {
+	return reverifySwap(txid, pairID, bind, actor, true)
 }
 
 // ReverifySwapout reverify swapout
-func ReverifySwapout(txid, pairID, bind string) error {
-	return reverifySwap(txid, pairID, bind, false)
+func ReverifySwapout(txid, pairID, bind, actor string) error {
+	return reverifySwap(txid, pairID, bind, actor, false)
 }
 
-func reverifySwap(txid, pairID, bind string, isSwapin bool) error {
+func reverifySwap(txid, pairID, bind, actor string, isSwapin bool) error {
 	swap, err := FindSwap(isSwapin, txid, pairID, bind)
 	if err != nil {
 		return err
@@ -97,20 +97,20 @@ func reverifySwap(txid, pairID, bind string, isSwapin bool) error {
 	if !swap.Status.CanReverify() {
 		return fmt.Errorf("swap status is %v, no need to reverify", swap.Status.String())
 	}
-	return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotStable, time.Now().Unix(), "")
+	return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotStable, time.Now().Unix(), "", actor)
 }
 
 // Reswapin reswapin
-func Reswapin(txid, pairID, bind, forceOpt string) error {
-	return reswap(txid, pairID, bind, forceOpt, true)
+func Reswapin(txid, pairID, bind, forceOpt, actor string) error {
+	return reswap(txid, pairID, bind, forceOpt, actor, true)
 }
 
 // Reswapout reswapout
-func Reswapout(txid, pairID, bind, forceOpt string) error {
-	return reswap(txid, pairID, bind, forceOpt, false)
+func Reswapout(txid, pairID, bind, forceOpt, actor string) error {
+	return reswap(txid, pairID, bind, forceOpt, actor, false)
 }
 
-func reswap(txid, pairID, bind, forceOpt string, isSwapin bool) error {
+func reswap(txid, pairID, bind, forceOpt, actor string, isSwapin bool) error {
 	swap, err := FindSwap(isSwapin, txid, pairID, bind)
 	if err != nil {
 		return err
@@ -128,12 +128,12 @@ func reswap(txid, pairID, bind, forceOpt string, isSwapin bool) error {
 	}
 
 	log.Info("[reswap] update status to TxNotSwapped to retry", "txid", txid, "pairID", pairID, "bind", bind, "swaptx", swapResult.SwapTx)
-	err = UpdateSwapResultStatus(isSwapin, txid, pairID, bind, MatchTxEmpty, time.Now().Unix(), "")
+	err = UpdateSwapResultStatus(isSwapin, txid, pairID, bind, MatchTxEmpty, time.Now().Unix(), "", actor)
 	if err != nil {
 		return err
 	}
 
-	return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotSwapped, time.Now().Unix(), "")
+	return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotSwapped, time.Now().Unix(), "", actor)
 }
 
 func checkCanReswap(res *MgoSwapResult, forceOpt string, isSwapin bool) error {
@@ -197,20 +197,20 @@ func checkReswapNonce(bridge tokens.CrossChainBridge, res *MgoSwapResult, forceO
 }
 
 // ManualManageSwap manual manage swap
-func ManualManageSwap(txid, pairID, bind, memo string, isSwapin, isPass bool) error {
+func ManualManageSwap(txid, pairID, bind, memo, actor string, isSwapin, isPass bool) error {
 	swap, err := FindSwap(isSwapin, txid, pairID, bind)
 	if err != nil {
 		return err
 	}
 	if isPass {
 		if swap.Status.CanManualMakePass() {
-			return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotSwapped, time.Now().Unix(), memo)
+			return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotSwapped, time.Now().Unix(), memo, actor)
 		}
 		if swap.Status.CanReverify() {
-			return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotStable, time.Now().Unix(), memo)
+			return UpdateSwapStatus(isSwapin, txid, pairID, bind, TxNotStable, time.Now().Unix(), memo, actor)
 		}
 	} else if swap.Status.CanManualMakeFail() {
-		return UpdateSwapStatus(isSwapin, txid, pairID, bind, ManualMakeFail, time.Now().Unix(), memo)
+		return UpdateSwapStatus(isSwapin, txid, pairID, bind, ManualMakeFail, time.Now().Unix(), memo, actor)
 	}
 	return fmt.Errorf("swap status is %v, can not operate. txid=%v pairID=%v bind=%v isSwapin=%v isPass=%v", swap.Status.String(), txid, pairID, bind, isSwapin, isPass)
 }
@@ -4,7 +4,6 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
-	"sync"
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/common"
@@ -19,26 +18,22 @@ const (
 	allAddresses      = "all"
 )
 
-var (
-	retryLock sync.Mutex
-)
-
 // --------------- swapin and swapout uniform --------------------------------
 
 // UpdateSwapStatus update swap status
-func UpdateSwapStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
+func UpdateSwapStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo, actor string) error {
 	if isSwapin {
-		return updateSwapStatus(collSwapin, txid, pairID, bind, status, timestamp, memo)
+		return updateSwapStatus(collSwapin, txid, pairID, bind, status, timestamp, memo, actor)
 	}
-	return updateSwapStatus(collSwapout, txid, pairID, bind, status, timestamp, memo)
+	return updateSwapStatus(collSwapout, txid, pairID, bind, status, timestamp, memo, actor)
 }
 
 // UpdateSwapResultStatus update swap result status
-func UpdateSwapResultStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
+func UpdateSwapResultStatus(isSwapin bool, txid, pairID, bind string, status SwapStatus, timestamp int64, memo, actor string) error {
 	if isSwapin {
-		return updateSwapResultStatus(collSwapinResult, txid, pairID, bind, status, timestamp, memo)
+		return updateSwapResultStatus(collSwapinResult, txid, pairID, bind, status, timestamp, memo, actor)
 	}
-	return updateSwapResultStatus(collSwapoutResult, txid, pairID, bind, status, timestamp, memo)
+	return updateSwapResultStatus(collSwapoutResult, txid, pairID, bind, status, timestamp, memo, actor)
 }
 
 // FindSwapResult find swap result
@@ -65,8 +60,8 @@ func AddSwapin(ms *MgoSwap) error {
 }
 
 // UpdateSwapinStatus update swapin status
-func UpdateSwapinStatus(txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
-	return updateSwapStatus(collSwapin, txid, pairID, bind, status, timestamp, memo)
+func UpdateSwapinStatus(txid, pairID, bind string, status SwapStatus, timestamp int64, memo, actor string) error {
+	return updateSwapStatus(collSwapin, txid, pairID, bind, status, timestamp, memo, actor)
 }
 
 // FindSwapin find swapin
@@ -97,8 +92,8 @@ func AddSwapout(ms *MgoSwap) error {
 }
 
 // UpdateSwapoutStatus update swapout status
-func UpdateSwapoutStatus(txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
-	return updateSwapStatus(collSwapout, txid, pairID, bind, status, timestamp, memo)
+func UpdateSwapoutStatus(txid, pairID, bind string, status SwapStatus, timestamp int64, memo, actor string) error {
+	return updateSwapStatus(collSwapout, txid, pairID, bind, status, timestamp, memo, actor)
 }
 
 // FindSwapout find swapout
@@ -140,7 +135,7 @@ func addSwap(collection *mgo.Collection, ms *MgoSwap) error {
 	return mgoError(err)
 }
 
-func updateSwapStatus(collection *mgo.Collection, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
+func updateSwapStatus(collection *mgo.Collection, txid, pairID, bind string, status SwapStatus, timestamp int64, memo, actor string) error {
 	pairID = strings.ToLower(pairID)
 	updates := bson.M{"status": status, "timestamp": timestamp}
 	if memo != "" {
@@ -148,15 +143,7 @@ func updateSwapStatus(collection *mgo.Collection, txid, pairID, bind string, sta
 	} else if status == TxNotSwapped || status == TxNotStable {
 		updates["memo"] = ""
 	}
-	if status == TxNotStable {
-		retryLock.Lock()
-		defer retryLock.Unlock()
-		swap, _ := findSwap(collection, txid, pairID, bind)
-		if !(swap.Status.CanRetry() || swap.Status.CanReverify()) {
-			return nil
-		}
-	}
-	err := collection.UpdateId(GetSwapKey(txid, pairID, bind), bson.M{"$set": updates})
+	_, err := transitSwapStatus(collection, GetSwapKey(txid, pairID, bind), status, updates, actor, memo)
 	if err == nil {
 		printLog := log.Info
 		switch status {
@@ -237,8 +224,8 @@ func UpdateSwapinResult(txid, pairID, bind string, items *SwapResultUpdateItems)
 }
 
 // UpdateSwapinResultStatus update swapin result status
-func UpdateSwapinResultStatus(txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
-	return updateSwapResultStatus(collSwapinResult, txid, pairID, bind, status, timestamp, memo)
+func UpdateSwapinResultStatus(txid, pairID, bind string, status SwapStatus, timestamp int64, memo, actor string) error {
+	return updateSwapResultStatus(collSwapinResult, txid, pairID, bind, status, timestamp, memo, actor)
 }
 
 // FindSwapinResult find swapin result
@@ -297,8 +284,8 @@ func UpdateSwapoutResult(txid, pairID, bind string, items *SwapResultUpdateItems
 }
 
 // UpdateSwapoutResultStatus update swapout result status
-func UpdateSwapoutResultStatus(txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
-	return updateSwapResultStatus(collSwapoutResult, txid, pairID, bind, status, timestamp, memo)
+func UpdateSwapoutResultStatus(txid, pairID, bind string, status SwapStatus, timestamp int64, memo, actor string) error {
+	return updateSwapResultStatus(collSwapoutResult, txid, pairID, bind, status, timestamp, memo, actor)
 }
 
 // FindSwapoutResult find swapout result
@@ -379,7 +366,13 @@ func updateSwapResult(collection *mgo.Collection, txid, pairID, bind string, ite
 	} else if items.Status == MatchTxNotStable {
 		updates["memo"] = ""
 	}
-	err := collection.UpdateId(GetSwapKey(txid, pairID, bind), bson.M{"$set": updates})
+	var err error
+	key := GetSwapKey(txid, pairID, bind)
+	if items.Status != KeepStatus {
+		_, err = transitSwapStatus(collection, key, items.Status, updates, items.Actor, items.Memo)
+	} else {
+		err = collection.UpdateId(key, bson.M{"$set": updates})
+	}
 	if err == nil {
 		log.Info("mongodb update swap result", "txid", txid, "pairID", pairID, "bind", bind, "updates", updates, "isSwapin", isSwapin(collection))
 	} else {
@@ -388,7 +381,7 @@ func updateSwapResult(collection *mgo.Collection, txid, pairID, bind string, ite
 	return mgoError(err)
 }
 
-func updateSwapResultStatus(collection *mgo.Collection, txid, pairID, bind string, status SwapStatus, timestamp int64, memo string) error {
+func updateSwapResultStatus(collection *mgo.Collection, txid, pairID, bind string, status SwapStatus, timestamp int64, memo, actor string) error {
 	pairID = strings.ToLower(pairID)
 	updates := bson.M{"status": status, "timestamp": timestamp}
 	if memo != "" {
@@ -400,21 +393,61 @@ func updateSwapResultStatus(collection *mgo.Collection, txid, pairID, bind strin
 		updates["swapheight"] = 0
 		updates["swaptime"] = 0
 	}
-	err := collection.UpdateId(GetSwapKey(txid, pairID, bind), bson.M{"$set": updates})
+	oldStatus, err := transitSwapStatus(collection, GetSwapKey(txid, pairID, bind), status, updates, actor, memo)
 	isSwapin := isSwapin(collection)
 	if err == nil {
 		log.Info("mongodb update swap result status", "txid", txid, "pairID", pairID, "bind", bind, "status", status, "isSwapin", isSwapin)
 	} else {
 		log.Debug("mongodb update swap result status", "txid", txid, "pairID", pairID, "bind", bind, "status", status, "isSwapin", isSwapin, "err", err)
 	}
-	if status == MatchTxStable {
+	if err == nil && status == MatchTxStable && oldStatus != MatchTxStable {
 		if swapResult, errq := findSwapResult(collection, txid, pairID, bind); errq == nil {
 			_ = updateSwapStatistics(pairID, swapResult.Value, swapResult.SwapValue, isSwapin)
+			_ = addCollectedFee(swapResult, isSwapin)
 		}
 	}
 	return mgoError(err)
 }
 
+// transitSwapStatus update swap (or swap result) status if the transition is allowed,
+// and record the transition in swap status history if status is changed.
+// the status check and update are done atomically by 'findAndModify'.
+func transitSwapStatus(collection *mgo.Collection, key string, status SwapStatus, updates bson.M, actor, reason string) (oldStatus SwapStatus, err error) {
+	isResult := isSwapResult(collection)
+	query := bson.M{
+		"_id":    key,
+		"status": bson.M{"$in": getAllowedSourceStatuses(status, isResult)},
+	}
+	var old struct {
+		Status SwapStatus `bson:"status"`
+	}
+	change := mgo.Change{Update: bson.M{"$set": updates}}
+	_, err = collection.Find(query).Select(bson.M{"status": 1}).Apply(change, &old)
+	if err == mgo.ErrNotFound {
+		errf := collection.FindId(key).Select(bson.M{"status": 1}).One(&old)
+		if errf != nil {
+			return old.Status, errf
+		}
+		return old.Status, newSwapStatusTransitionError(old.Status, status)
+	}
+	if err != nil {
+		return old.Status, err
+	}
+	if old.Status != status {
+		addSwapStatusChange(&MgoSwapStatusChange{
+			SwapKey:   key,
+			IsSwapin:  isSwapin(collection),
+			IsResult:  isResult,
+			From:      old.Status,
+			To:        status,
+			Actor:     actor,
+			Reason:    reason,
+			Timestamp: time.Now().Unix(),
+		})
+	}
+	return old.Status, nil
+}
+
 func findSwapResult(collection *mgo.Collection, txid, pairID, bind string) (*MgoSwapResult, error) {
 	result := &MgoSwapResult{}
 	err := findSwapOrSwapResult(result, collection, txid, pairID, bind)
@@ -578,6 +611,34 @@ func GetSwapStatistics(pairID string) (*SwapStatistics, error) {
 	return stat, nil
 }
 
+// ------------------ swap status history ------------------------
+
+func addSwapStatusChange(mc *MgoSwapStatusChange) {
+	mc.Key = bson.NewObjectId().Hex()
+	err := collSwapStatusHistory.Insert(mc)
+	if err != nil {
+		log.Warn("mongodb add swap status change failed", "swapkey", mc.SwapKey, "from", mc.From, "to", mc.To, "isSwapin", mc.IsSwapin, "isResult", mc.IsResult, "err", err)
+	}
+}
+
+// FindSwapStatusHistory find status changes of swap and swap result in time order
+func FindSwapStatusHistory(isSwapin bool, txid, pairID, bind string) ([]*MgoSwapStatusChange, error) {
+	if bind == "" {
+		swap, err := FindSwap(isSwapin, txid, pairID, bind)
+		if err != nil {
+			return nil, err
+		}
+		bind = swap.Bind
+	}
+	query := bson.M{
+		"swapkey":  GetSwapKey(txid, pairID, bind),
+		"isswapin": isSwapin,
+	}
+	result := make([]*MgoSwapStatusChange, 0, 8)
+	err := collSwapStatusHistory.Find(query).Sort("timestamp", "_id").Limit(maxCountOfResults).All(&result)
+	return result, mgoError(err)
+}
+
 // ------------------ p2sh address ------------------------
 
 // AddP2shAddress add p2sh address
@@ -665,6 +726,65 @@ func FindLatestScanInfo(isSrc bool) (*MgoLatestScanInfo, error) {
 	return &result, mgoError(err)
 }
 
+// ------------------ scan ranges ------------------------
+
+func getScanRangeKey(isSrc bool, start uint64) string {
+	if isSrc {
+		return fmt.Sprintf("src:%v", start)
+	}
+	return fmt.Sprintf("dst:%v", start)
+}
+
+// AddScanRange add quick sync range [start, end)
+func AddScanRange(isSrc bool, start, end uint64) (*MgoScanRange, error) {
+	mr := &MgoScanRange{
+		Key:       getScanRangeKey(isSrc, start),
+		IsSrc:     isSrc,
+		Start:     start,
+		End:       end,
+		Next:      start,
+		Timestamp: time.Now().Unix(),
+	}
+	_, err := collScanRanges.UpsertId(mr.Key, mr)
+	if err == nil {
+		log.Info("mongodb add scan range", "key", mr.Key, "end", end)
+	} else {
+		log.Debug("mongodb add scan range", "key", mr.Key, "end", end, "err", err)
+	}
+	return mr, mgoError(err)
+}
+
+// UpdateScanRange update next height to scan of quick sync range
+func UpdateScanRange(key string, next uint64) error {
+	updates := bson.M{
+		"next":      next,
+		"timestamp": time.Now().Unix(),
+	}
+	err := collScanRanges.UpdateId(key, bson.M{"$set": updates})
+	if err != nil {
+		log.Debug("mongodb update scan range", "key", key, "next", next, "err", err)
+	}
+	return mgoError(err)
+}
+
+// RemoveScanRange remove finished quick sync range
+func RemoveScanRange(key string) error {
+	err := collScanRanges.RemoveId(key)
+	if err == nil {
+		log.Info("mongodb remove scan range", "key", key)
+	} else {
+		log.Debug("mongodb remove scan range", "key", key, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindScanRanges find pending quick sync ranges
+func FindScanRanges(isSrc bool) ([]*MgoScanRange, error) {
+	var result []*MgoScanRange
+	err := collScanRanges.Find(bson.M{"issrc": isSrc}).Sort("start").All(&result)
+	return result, mgoError(err)
+}
+
 // ------------------------ register address ------------------------------
 
 // AddRegisteredAddress add register address
@@ -698,28 +818,19 @@ func getSwapNonceKey(address string, isSwapin bool) string {
 	return strings.ToLower(fmt.Sprintf("%v:%v", address, isSwapin))
 }
 
-// UpdateLatestSwapNonce update
+// UpdateLatestSwapNonce update (only increase)
 func UpdateLatestSwapNonce(address string, isSwapin bool, nonce uint64) (err error) {
 	key := getSwapNonceKey(address, isSwapin)
-	oldItem, _ := FindLatestSwapNonce(address, isSwapin)
-	if oldItem != nil && oldItem.SwapNonce >= nonce {
-		return nil // only increase
-	}
-	if oldItem == nil {
-		ma := &MgoLatestSwapNonce{
-			Key:       key,
-			Address:   strings.ToLower(address),
-			IsSwapin:  isSwapin,
-			SwapNonce: nonce,
-			Timestamp: time.Now().Unix(),
-		}
-		err = collLatestSwapNonces.Insert(ma)
-	} else {
-		updates := bson.M{
-			"swapnonce": nonce,
-			"timestamp": time.Now().Unix(),
-		}
-		err = collLatestSwapNonces.UpdateId(key, updates)
+	selector := bson.M{"_id": key, "swapnonce": bson.M{"$lt": nonce}}
+	updates := bson.M{"$set": bson.M{
+		"address":   strings.ToLower(address),
+		"isswapin":  isSwapin,
+		"swapnonce": nonce,
+		"timestamp": time.Now().Unix(),
+	}}
+	_, err = collLatestSwapNonces.Upsert(selector, updates)
+	if mgo.IsDup(err) {
+		return nil // exist a larger or equal nonce
 	}
 	if err == nil {
 		log.Info("mongodb update swap nonce success", "key", key, "nonce", nonce)
@@ -754,3 +865,479 @@ func LoadAllSwapNonces() (swapinNonces, swapoutNonces map[string]uint64) {
 	}
 	return swapinNonces, swapoutNonces
 }
+
+// ------------------ leases ------------------------
+
+// acquireLease acquire or renew lease if it is held by owner or expired
+func acquireLease(collection *mgo.Collection, key, owner string, ttl int64, fields bson.M) (bool, error) {
+	nowTime := time.Now().Unix()
+	selector := bson.M{
+		"_id": key,
+		"$or": []bson.M{
+			{"owner": owner},
+			{"expireat": bson.M{"$lte": nowTime}},
+		},
+	}
+	if fields == nil {
+		fields = bson.M{}
+	}
+	fields["owner"] = owner
+	fields["expireat"] = nowTime + ttl
+	fields["timestamp"] = nowTime
+	_, err := collection.Upsert(selector, bson.M{"$set": fields})
+	if mgo.IsDup(err) {
+		return false, nil // held by others
+	}
+	if err != nil {
+		return false, mgoError(err)
+	}
+	return true, nil
+}
+
+func releaseLease(collection *mgo.Collection, key, owner string) error {
+	selector := bson.M{"_id": key, "owner": owner}
+	updates := bson.M{"$set": bson.M{"expireat": 0, "timestamp": time.Now().Unix()}}
+	err := collection.Update(selector, updates)
+	if err == mgo.ErrNotFound {
+		return nil // held by others now
+	}
+	return mgoError(err)
+}
+
+// AcquireLease acquire or renew lease of name for ttl seconds
+func AcquireLease(name, owner string, ttl int64) (bool, error) {
+	return acquireLease(collLeases, name, owner, ttl, nil)
+}
+
+// ReleaseLease release lease of name if it is held by owner
+func ReleaseLease(name, owner string) error {
+	return releaseLease(collLeases, name, owner)
+}
+
+// FindLease find lease
+func FindLease(name string) (*MgoLease, error) {
+	var result MgoLease
+	err := collLeases.FindId(name).One(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return &result, nil
+}
+
+// ------------------ swap claims ------------------------
+
+func getSwapClaimKey(isSwapin bool, txid, pairID, bind string) string {
+	if isSwapin {
+		return "swapin:" + GetSwapKey(txid, pairID, bind)
+	}
+	return "swapout:" + GetSwapKey(txid, pairID, bind)
+}
+
+// ClaimSwap claim or renew claim of processing swap for ttl seconds
+func ClaimSwap(isSwapin bool, txid, pairID, bind, owner string, ttl int64) (bool, error) {
+	key := getSwapClaimKey(isSwapin, txid, pairID, bind)
+	fields := bson.M{
+		"swapkey":  GetSwapKey(txid, pairID, bind),
+		"isswapin": isSwapin,
+	}
+	return acquireLease(collSwapClaims, key, owner, ttl, fields)
+}
+
+// ReleaseSwapClaim release claim of swap if it is held by owner
+func ReleaseSwapClaim(isSwapin bool, txid, pairID, bind, owner string) error {
+	key := getSwapClaimKey(isSwapin, txid, pairID, bind)
+	return releaseLease(collSwapClaims, key, owner)
+}
+
+// ------------------ swap outbox ------------------------
+
+// AddSwapOutbox add signed swap tx to outbox
+func AddSwapOutbox(item *MgoSwapOutbox) error {
+	item.Key = item.SwapTx
+	item.SwapKey = GetSwapKey(item.TxID, item.PairID, item.Bind)
+	item.Status = OutboxPending
+	item.InitTime = time.Now().Unix()
+	item.Timestamp = item.InitTime
+	_, err := collSwapOutbox.UpsertId(item.Key, item)
+	if err == nil {
+		log.Info("mongodb add swap outbox success", "swaptx", item.SwapTx, "txid", item.TxID, "pairID", item.PairID, "bind", item.Bind, "isSwapin", item.IsSwapin, "nonce", item.SwapNonce)
+	} else {
+		log.Debug("mongodb add swap outbox failed", "swaptx", item.SwapTx, "txid", item.TxID, "pairID", item.PairID, "bind", item.Bind, "isSwapin", item.IsSwapin, "err", err)
+	}
+	return mgoError(err)
+}
+
+// UpdateSwapOutboxStatus update swap outbox status
+func UpdateSwapOutboxStatus(swapTx, status, memo string) error {
+	updates := bson.M{
+		"status":    status,
+		"timestamp": time.Now().Unix(),
+	}
+	if memo != "" {
+		updates["memo"] = memo
+	}
+	err := collSwapOutbox.UpdateId(swapTx, bson.M{"$set": updates})
+	if err == nil {
+		log.Info("mongodb update swap outbox status", "swaptx", swapTx, "status", status, "memo", memo)
+	} else {
+		log.Debug("mongodb update swap outbox status failed", "swaptx", swapTx, "status", status, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindPendingSwapOutbox find pending swap outbox items added before septime
+func FindPendingSwapOutbox(septime int64) ([]*MgoSwapOutbox, error) {
+	qstatus := bson.M{"status": OutboxPending}
+	qtime := bson.M{"inittime": bson.M{"$lte": septime}}
+	queries := []bson.M{qstatus, qtime}
+	result := make([]*MgoSwapOutbox, 0, 20)
+	err := collSwapOutbox.Find(bson.M{"$and": queries}).Sort("inittime").Limit(maxCountOfResults).All(&result)
+	return result, mgoError(err)
+}
+
+// ------------------ aggregate ------------------------
+
+// AddAggregate add signed aggregate tx
+func AddAggregate(item *MgoAggregate) error {
+	item.Status = AggregatePending
+	item.InitTime = time.Now().Unix()
+	item.Timestamp = item.InitTime
+	_, err := collAggregates.UpsertId(item.Key, item)
+	if err == nil {
+		log.Info("mongodb add aggregate success", "txHash", item.Key, "pairID", item.PairID, "kind", item.Kind, "utxos", len(item.Inputs), "sumValue", item.SumValue)
+	} else {
+		log.Debug("mongodb add aggregate failed", "txHash", item.Key, "pairID", item.PairID, "kind", item.Kind, "err", err)
+	}
+	return mgoError(err)
+}
+
+// UpdateAggregateStatus update aggregate status
+func UpdateAggregateStatus(txHash, status, memo string) error {
+	updates := bson.M{
+		"status":    status,
+		"timestamp": time.Now().Unix(),
+	}
+	if memo != "" {
+		updates["memo"] = memo
+	}
+	err := collAggregates.UpdateId(txHash, bson.M{"$set": updates})
+	if err == nil {
+		log.Info("mongodb update aggregate status", "txHash", txHash, "status", status, "memo", memo)
+	} else {
+		log.Debug("mongodb update aggregate status failed", "txHash", txHash, "status", status, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindPendingAggregates find pending aggregates
+func FindPendingAggregates() ([]*MgoAggregate, error) {
+	result := make([]*MgoAggregate, 0, 20)
+	err := collAggregates.Find(bson.M{"status": AggregatePending}).Sort("inittime").Limit(maxCountOfResults).All(&result)
+	return result, mgoError(err)
+}
+
+// FindAggregates find aggregates of pair in reverse time order
+func FindAggregates(pairID string, offset, limit int) ([]*MgoAggregate, error) {
+	pairID = strings.ToLower(pairID)
+	result := make([]*MgoAggregate, 0, 20)
+	var query bson.M
+	if pairID != "" && pairID != allPairs {
+		query = bson.M{"pairid": pairID}
+	}
+	err := collAggregates.Find(query).Sort("-inittime").Skip(offset).Limit(limit).All(&result)
+	return result, mgoError(err)
+}
+
+// ------------------ fee ledger ------------------------
+
+// GetFeeLedgerKey get fee ledger entry key
+func GetFeeLedgerKey(kind string, isSwapin bool, id string) string {
+	direction := "swapout"
+	if isSwapin {
+		direction = "swapin"
+	}
+	return strings.ToLower(fmt.Sprintf("%v:%v:%v", kind, direction, id))
+}
+
+func addCollectedFee(swapResult *MgoSwapResult, isSwapin bool) error {
+	value, _ := new(big.Int).SetString(swapResult.Value, 0)
+	swapValue, _ := new(big.Int).SetString(swapResult.SwapValue, 0)
+	if value == nil || swapValue == nil {
+		return nil
+	}
+	fee := new(big.Int).Sub(value, swapValue)
+	if fee.Sign() <= 0 {
+		return nil
+	}
+	return AddFeeLedgerEntry(&MgoFeeLedgerEntry{
+		Key:      GetFeeLedgerKey(FeeKindCollected, isSwapin, swapResult.Key),
+		PairID:   swapResult.PairID,
+		Kind:     FeeKindCollected,
+		IsSwapin: isSwapin,
+		TxID:     swapResult.TxID,
+		SwapTx:   swapResult.SwapTx,
+		Amount:   fee.String(),
+	})
+}
+
+// AddFeeLedgerEntry add fee ledger entry, the same entry is recorded only once
+func AddFeeLedgerEntry(entry *MgoFeeLedgerEntry) error {
+	entry.PairID = strings.ToLower(entry.PairID)
+	if entry.Timestamp == 0 {
+		entry.Timestamp = time.Now().Unix()
+	}
+	err := collFeeLedger.Insert(entry)
+	if mgo.IsDup(err) {
+		return nil
+	}
+	if err == nil {
+		log.Info("mongodb add fee ledger entry success", "key", entry.Key, "pairID", entry.PairID, "kind", entry.Kind, "amount", entry.Amount, "status", entry.Status)
+	} else {
+		log.Debug("mongodb add fee ledger entry failed", "key", entry.Key, "pairID", entry.PairID, "kind", entry.Kind, "err", err)
+	}
+	return mgoError(err)
+}
+
+// UpdateFeeWithdrawStatus update fee withdraw status
+func UpdateFeeWithdrawStatus(key, status, swapTx, memo string) error {
+	updates := bson.M{
+		"status":    status,
+		"timestamp": time.Now().Unix(),
+	}
+	if swapTx != "" {
+		updates["swaptx"] = swapTx
+	}
+	if memo != "" {
+		updates["memo"] = memo
+	}
+	err := collFeeLedger.UpdateId(key, bson.M{"$set": updates})
+	if err == nil {
+		log.Info("mongodb update fee withdraw status", "key", key, "status", status, "swapTx", swapTx, "memo", memo)
+	} else {
+		log.Debug("mongodb update fee withdraw status failed", "key", key, "status", status, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindFeeLedgerEntries find fee ledger entries of pair in reverse time order
+func FindFeeLedgerEntries(pairID, kind string, offset, limit int) ([]*MgoFeeLedgerEntry, error) {
+	pairID = strings.ToLower(pairID)
+	result := make([]*MgoFeeLedgerEntry, 0, 20)
+	query := bson.M{}
+	if pairID != "" && pairID != allPairs {
+		query["pairid"] = pairID
+	}
+	if kind != "" {
+		query["kind"] = kind
+	}
+	err := collFeeLedger.Find(query).Sort("-timestamp").Skip(offset).Limit(limit).All(&result)
+	return result, mgoError(err)
+}
+
+// FeeLedgerSide fee ledger summary of one swap direction
+type FeeLedgerSide struct {
+	Collected     string `json:"collected"`
+	NetworkFee    string `json:"networkFee"`
+	Withdrawn     string `json:"withdrawn"`
+	NetFee        string `json:"netFee"`
+	Withdrawable  string `json:"withdrawable"`
+	IsNativeFee   bool   `json:"isNativeFee"` // network fee is deducted from net fee only if collected in native coin
+	SwapCount     int    `json:"swapCount"`
+	SwapTxCount   int    `json:"swapTxCount"`
+	WithdrawCount int    `json:"withdrawCount"`
+
+	collected  *big.Int
+	networkFee *big.Int
+	withdrawn  *big.Int
+}
+
+// FeeLedger fee ledger summary of pair
+type FeeLedger struct {
+	PairID  string         `json:"pairid"`
+	From    int64          `json:"from"`
+	To      int64          `json:"to"`
+	Swapin  *FeeLedgerSide `json:"swapin"`
+	Swapout *FeeLedgerSide `json:"swapout"`
+}
+
+func newFeeLedgerSide(isNativeFee bool) *FeeLedgerSide {
+	return &FeeLedgerSide{
+		IsNativeFee: isNativeFee,
+		collected:   big.NewInt(0),
+		networkFee:  big.NewInt(0),
+		withdrawn:   big.NewInt(0),
+	}
+}
+
+func (s *FeeLedgerSide) add(entry *MgoFeeLedgerEntry) {
+	amount, ok := new(big.Int).SetString(entry.Amount, 0)
+	if !ok {
+		return
+	}
+	switch entry.Kind {
+	case FeeKindCollected:
+		s.collected.Add(s.collected, amount)
+		s.SwapCount++
+	case FeeKindNetwork:
+		s.networkFee.Add(s.networkFee, amount)
+		s.SwapTxCount++
+	case FeeKindWithdraw:
+		if entry.Status == FeeWithdrawFailed {
+			return
+		}
+		s.withdrawn.Add(s.withdrawn, amount)
+		s.WithdrawCount++
+	}
+}
+
+func (s *FeeLedgerSide) summary() {
+	net := new(big.Int).Set(s.collected)
+	if s.IsNativeFee {
+		net.Sub(net, s.networkFee)
+	}
+	withdrawable := new(big.Int).Sub(net, s.withdrawn)
+	if withdrawable.Sign() < 0 {
+		withdrawable.SetUint64(0)
+	}
+	s.Collected = s.collected.String()
+	s.NetworkFee = s.networkFee.String()
+	s.Withdrawn = s.withdrawn.String()
+	s.NetFee = net.String()
+	s.Withdrawable = withdrawable.String()
+}
+
+// GetWithdrawable get withdrawable fee amount
+func (s *FeeLedgerSide) GetWithdrawable() *big.Int {
+	withdrawable, _ := new(big.Int).SetString(s.Withdrawable, 0)
+	return withdrawable
+}
+
+// GetFeeLedger sum fee ledger entries of pair in time range [from, to), zero means unbounded.
+// isNativeFee of swapin/swapout tells whether the fee is collected in native coin,
+// in which case the network fee is deducted from the net fee.
+// withdrawable is only meaningful for the unbounded range.
+func GetFeeLedger(pairID string, from, to int64, swapinNativeFee, swapoutNativeFee bool) (*FeeLedger, error) {
+	pairID = strings.ToLower(pairID)
+	ledger := &FeeLedger{
+		PairID:  pairID,
+		From:    from,
+		To:      to,
+		Swapin:  newFeeLedgerSide(swapinNativeFee),
+		Swapout: newFeeLedgerSide(swapoutNativeFee),
+	}
+	query := bson.M{"pairid": pairID}
+	if from > 0 || to > 0 {
+		timeRange := bson.M{}
+		if from > 0 {
+			timeRange["$gte"] = from
+		}
+		if to > 0 {
+			timeRange["$lt"] = to
+		}
+		query["timestamp"] = timeRange
+	}
+	var entry MgoFeeLedgerEntry
+	iter := collFeeLedger.Find(query).Iter()
+	for iter.Next(&entry) {
+		if entry.IsSwapin {
+			ledger.Swapin.add(&entry)
+		} else {
+			ledger.Swapout.add(&entry)
+		}
+		entry = MgoFeeLedgerEntry{}
+	}
+	if err := iter.Close(); err != nil {
+		return nil, mgoError(err)
+	}
+	ledger.Swapin.summary()
+	ledger.Swapout.summary()
+	return ledger, nil
+}
+
+// ------------------------ pair configs ------------------------------
+
+// GetPairConfigKey get pair config key
+func GetPairConfigKey(pairID string, version uint64) string {
+	return strings.ToLower(fmt.Sprintf("%v:%v", pairID, version))
+}
+
+// AddPairConfig add pair config version, return ErrItemIsDup if version exists
+func AddPairConfig(mp *MgoPairConfig) error {
+	mp.PairID = strings.ToLower(mp.PairID)
+	mp.Key = GetPairConfigKey(mp.PairID, mp.Version)
+	if mp.Timestamp == 0 {
+		mp.Timestamp = time.Now().Unix()
+	}
+	err := collPairConfigs.Insert(mp)
+	if err == nil {
+		log.Info("mongodb add pair config success", "pairID", mp.PairID, "version", mp.Version, "hash", mp.Hash, "removed", mp.Removed, "admin", mp.Admin)
+	} else {
+		log.Debug("mongodb add pair config failed", "pairID", mp.PairID, "version", mp.Version, "err", err)
+	}
+	return mgoError(err)
+}
+
+// FindLatestPairConfig find latest version of pair config
+func FindLatestPairConfig(pairID string) (*MgoPairConfig, error) {
+	var result MgoPairConfig
+	query := bson.M{"pairid": strings.ToLower(pairID)}
+	err := collPairConfigs.Find(query).Sort("-version").One(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return &result, nil
+}
+
+// FindPairConfig find pair config of specified version
+func FindPairConfig(pairID string, version uint64) (*MgoPairConfig, error) {
+	var result MgoPairConfig
+	err := collPairConfigs.FindId(GetPairConfigKey(pairID, version)).One(&result)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	return &result, nil
+}
+
+// FindLatestPairConfigs find latest versions of all pair configs (including removed)
+func FindLatestPairConfigs() ([]*MgoPairConfig, error) {
+	var all []*MgoPairConfig
+	err := collPairConfigs.Find(nil).Sort("pairid", "-version").All(&all)
+	if err != nil {
+		return nil, mgoError(err)
+	}
+	result := make([]*MgoPairConfig, 0, len(all))
+	for _, mp := range all {
+		if len(result) == 0 || result[len(result)-1].PairID != mp.PairID {
+			result = append(result, mp)
+		}
+	}
+	return result, nil
+}
+
+// FindPairConfigVersions find versions of pair config in reverse order
+func FindPairConfigVersions(pairID string, offset, limit int) ([]*MgoPairConfig, error) {
+	result := make([]*MgoPairConfig, 0, 20)
+	query := bson.M{"pairid": strings.ToLower(pairID)}
+	err := collPairConfigs.Find(query).Sort("-version").Skip(offset).Limit(limit).All(&result)
+	return result, mgoError(err)
+}
+
+// ------------------------ admin call nonces ------------------------------
+
+// AddAdminCallNonce record used nonce of admin call, return ErrItemIsDup if used
+func AddAdminCallNonce(nonce, admin, method string, expireAt int64) error {
+	mn := &MgoAdminCallNonce{
+		Key:       strings.ToLower(nonce),
+		Admin:     strings.ToLower(admin),
+		Method:    method,
+		ExpireAt:  expireAt,
+		Timestamp: time.Now().Unix(),
+	}
+	err := collAdminCallNonces.Insert(mn)
+	if err == nil {
+		log.Info("mongodb add admin call nonce success", "nonce", mn.Key, "admin", mn.Admin, "method", method)
+	} else {
+		log.Debug("mongodb add admin call nonce failed", "nonce", mn.Key, "err", err)
+	}
+	return mgoError(err)
+}
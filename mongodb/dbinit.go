@@ -5,6 +5,7 @@ import (
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tools/secrets"
 	"gopkg.in/mgo.v2"
 )
 
@@ -20,8 +21,17 @@ func HasSession() bool {
 	return session != nil
 }
 
-// MongoServerInit int mongodb server session
+// MongoServerInit int mongodb server session,
+// user and pass can be secret references (eg. 'env:NAME')
 func MongoServerInit(addrs []string, dbname, user, pass string) {
+	user, err := secrets.GetSecret(user)
+	if err != nil {
+		log.Fatal("[mongodb] get user name failed", "err", err)
+	}
+	pass, err = secrets.GetSecret(pass)
+	if err != nil {
+		log.Fatal("[mongodb] get password failed", "err", err)
+	}
 	initDialInfo(addrs, dbname, user, pass)
 	mongoConnect()
 	initCollections()
@@ -1,6 +1,8 @@
 package mongodb
 
 import (
+	"fmt"
+
 	rpcjson "github.com/gorilla/rpc/v2/json2"
 	"gopkg.in/mgo.v2"
 )
@@ -14,6 +16,9 @@ func newError(ec rpcjson.ErrorCode, message string) error {
 
 func mgoError(err error) error {
 	if err != nil {
+		if _, ok := err.(*rpcjson.Error); ok {
+			return err
+		}
 		if err == mgo.ErrNotFound {
 			return ErrItemNotFound
 		}
@@ -25,6 +30,10 @@ func mgoError(err error) error {
 	return nil
 }
 
+func newSwapStatusTransitionError(from, to SwapStatus) error {
+	return newError(-32013, fmt.Sprintf("mgoError: Swap status can not change from %v to %v", from.String(), to.String()))
+}
+
 // mongodb special errors
 var (
 	ErrItemNotFound = newError(-32002, "mgoError: Item not found")
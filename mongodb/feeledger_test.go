@@ -0,0 +1,69 @@
+package mongodb
+
+import (
+	"testing"
+)
+
+func newTestFeeLedgerSide(isNativeFee bool, entries ...*MgoFeeLedgerEntry) *FeeLedgerSide {
+	side := newFeeLedgerSide(isNativeFee)
+	for _, entry := range entries {
+		side.add(entry)
+	}
+	side.summary()
+	return side
+}
+
+func TestFeeLedgerSideSummary(t *testing.T) {
+	entries := []*MgoFeeLedgerEntry{
+		{Kind: FeeKindCollected, Amount: "1000"},
+		{Kind: FeeKindCollected, Amount: "0x1f4"}, // 500
+		{Kind: FeeKindNetwork, Amount: "300"},
+		{Kind: FeeKindWithdraw, Amount: "200", Status: FeeWithdrawSent},
+		{Kind: FeeKindWithdraw, Amount: "100", Status: FeeWithdrawPending},
+		{Kind: FeeKindWithdraw, Amount: "400", Status: FeeWithdrawFailed},
+		{Kind: FeeKindCollected, Amount: "wrong"},
+	}
+
+	tests := []struct {
+		isNativeFee  bool
+		netFee       string
+		withdrawable string
+	}{
+		{true, "1200", "900"},
+		{false, "1500", "1200"},
+	}
+	for _, test := range tests {
+		side := newTestFeeLedgerSide(test.isNativeFee, entries...)
+		if side.Collected != "1500" || side.NetworkFee != "300" || side.Withdrawn != "300" {
+			t.Errorf("isNativeFee %v: wrong sums, collected %v network %v withdrawn %v", test.isNativeFee, side.Collected, side.NetworkFee, side.Withdrawn)
+		}
+		if side.SwapCount != 2 || side.SwapTxCount != 1 || side.WithdrawCount != 2 {
+			t.Errorf("isNativeFee %v: wrong counts, swap %v swaptx %v withdraw %v", test.isNativeFee, side.SwapCount, side.SwapTxCount, side.WithdrawCount)
+		}
+		if side.NetFee != test.netFee {
+			t.Errorf("isNativeFee %v: net fee mismatch, have %v want %v", test.isNativeFee, side.NetFee, test.netFee)
+		}
+		if side.GetWithdrawable().String() != test.withdrawable {
+			t.Errorf("isNativeFee %v: withdrawable mismatch, have %v want %v", test.isNativeFee, side.GetWithdrawable(), test.withdrawable)
+		}
+	}
+}
+
+func TestFeeLedgerSideOverWithdrawn(t *testing.T) {
+	side := newTestFeeLedgerSide(true,
+		&MgoFeeLedgerEntry{Kind: FeeKindCollected, Amount: "1000"},
+		&MgoFeeLedgerEntry{Kind: FeeKindNetwork, Amount: "600"},
+		&MgoFeeLedgerEntry{Kind: FeeKindWithdraw, Amount: "500", Status: FeeWithdrawSent},
+	)
+	if side.NetFee != "400" {
+		t.Errorf("net fee mismatch, have %v want 400", side.NetFee)
+	}
+	if side.GetWithdrawable().Sign() != 0 {
+		t.Errorf("withdrawable should not be negative, have %v", side.GetWithdrawable())
+	}
+
+	empty := newTestFeeLedgerSide(false)
+	if empty.Collected != "0" || empty.GetWithdrawable().Sign() != 0 {
+		t.Errorf("empty ledger should have nothing withdrawable, have %v", empty.GetWithdrawable())
+	}
+}
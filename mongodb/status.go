@@ -2,11 +2,14 @@ package mongodb
 
 import (
 	"fmt"
+	"strings"
 )
 
 // -----------------------------------------------
 // swap status change graph
 // symbol '--->' mean transfer only under checked condition (eg. manual process)
+// the graph is encoded in 'swapStatusTransitions' and 'swapResultStatusTransitions',
+// and is enforced when updating status, every change is recorded in status history.
 //
 // -----------------------------------------------
 // 1. swap register status change graph
@@ -62,6 +65,106 @@ const (
 	KeepStatus = 255
 )
 
+// swap status change actors
+const (
+	ActorVerify = "verify"
+	ActorSwap   = "swap"
+	ActorStable = "stable"
+	ActorRetry  = "retry"
+)
+
+// AdminActor actor of admin operation
+func AdminActor(admin string) string {
+	return "admin:" + strings.ToLower(admin)
+}
+
+// swapStatusTransitions allowed status transitions of registered swaps.
+// keep this in accordance with the status change graph above.
+var swapStatusTransitions = map[SwapStatus][]SwapStatus{
+	TxNotStable: {
+		TxVerifyFailed,
+		TxWithWrongMemo,
+		TxWithWrongSender,
+		TxWithWrongValue,
+		SwapInBlacklist,
+		TxIncompatible,
+		ManualMakeFail,
+		BindAddrIsContract,
+		RPCQueryError,
+		TxWithBigValue,
+		TxSenderNotRegistered,
+		TxNotSwapped,
+	},
+	TxNotSwapped:          {TxProcessed, SwapInBlacklist, ManualMakeFail},
+	TxProcessed:           {TxSwapFailed, TxNotSwapped},
+	TxSwapFailed:          {TxNotSwapped},
+	TxWithBigValue:        {TxNotSwapped, TxNotStable},
+	TxSenderNotRegistered: {TxNotStable},
+	RPCQueryError:         {TxNotStable},
+	TxVerifyFailed:        {TxNotStable},
+	TxWithWrongValue:      {TxNotStable},
+	TxIncompatible:        {TxNotStable},
+	SwapInBlacklist:       {TxNotStable},
+	ManualMakeFail:        {TxNotStable},
+	BindAddrIsContract:    {TxNotStable},
+}
+
+// swapResultStatusTransitions allowed status transitions of swap results.
+// initial statuses (MatchTxEmpty and verify errors) go to MatchTxNotStable when swapped.
+var swapResultStatusTransitions = map[SwapStatus][]SwapStatus{
+	MatchTxEmpty:       {MatchTxNotStable, TxSwapFailed},
+	TxWithBigValue:     {MatchTxNotStable, TxSwapFailed},
+	TxWithWrongValue:   {MatchTxNotStable, TxSwapFailed},
+	BindAddrIsContract: {MatchTxNotStable, TxSwapFailed},
+	MatchTxNotStable:   {MatchTxStable, MatchTxFailed, TxSwapFailed, MatchTxEmpty},
+	TxSwapFailed:       {MatchTxEmpty},
+	MatchTxFailed:      {MatchTxEmpty},
+}
+
+// swap status and swap result status which can transit to the key status
+var (
+	swapStatusSources       = getStatusSources(swapStatusTransitions)
+	swapResultStatusSources = getStatusSources(swapResultStatusTransitions)
+)
+
+func getStatusSources(transitions map[SwapStatus][]SwapStatus) map[SwapStatus][]SwapStatus {
+	sources := make(map[SwapStatus][]SwapStatus)
+	for from, tos := range transitions {
+		for _, to := range tos {
+			sources[to] = append(sources[to], from)
+		}
+	}
+	return sources
+}
+
+// getAllowedSourceStatuses get statuses which can transit to `status` (include itself)
+func getAllowedSourceStatuses(status SwapStatus, isResult bool) []SwapStatus {
+	var sources []SwapStatus
+	if isResult {
+		sources = swapResultStatusSources[status]
+	} else {
+		sources = swapStatusSources[status]
+	}
+	return append([]SwapStatus{status}, sources...)
+}
+
+// CanTransitTo whether swap (or swap result if `isResult`) status can transit to `next`
+func (status SwapStatus) CanTransitTo(next SwapStatus, isResult bool) bool {
+	if status == next {
+		return true
+	}
+	transitions := swapStatusTransitions
+	if isResult {
+		transitions = swapResultStatusTransitions
+	}
+	for _, to := range transitions[status] {
+		if to == next {
+			return true
+		}
+	}
+	return false
+}
+
 // CanManualMakePass can manual make pass
 func (status SwapStatus) CanManualMakePass() bool {
 	switch status {
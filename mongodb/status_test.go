@@ -0,0 +1,71 @@
+package mongodb
+
+import (
+	"testing"
+)
+
+var allSwapStatuses = []SwapStatus{
+	TxNotStable, TxVerifyFailed, TxWithWrongSender, TxWithWrongValue,
+	TxIncompatible, TxNotSwapped, TxSwapFailed, TxProcessed,
+	MatchTxEmpty, MatchTxNotStable, MatchTxStable, TxWithWrongMemo,
+	TxWithBigValue, TxSenderNotRegistered, MatchTxFailed, SwapInBlacklist,
+	ManualMakeFail, BindAddrIsContract, RPCQueryError,
+}
+
+// admin operations and retry must be allowed by the transition tables
+func TestSwapStatusTransitionsCoverOperations(t *testing.T) {
+	for _, status := range allSwapStatuses {
+		if status.CanManualMakePass() && !status.CanTransitTo(TxNotSwapped, false) {
+			t.Errorf("%v can manual make pass but can not transit to TxNotSwapped", status)
+		}
+		if status.CanManualMakeFail() && !status.CanTransitTo(ManualMakeFail, false) {
+			t.Errorf("%v can manual make fail but can not transit to ManualMakeFail", status)
+		}
+		if (status.CanRetry() || status.CanReverify()) && !status.CanTransitTo(TxNotStable, false) {
+			t.Errorf("%v can retry or reverify but can not transit to TxNotStable", status)
+		}
+		if status.CanReswap() && !status.CanTransitTo(TxNotSwapped, false) {
+			t.Errorf("%v can reswap but can not transit to TxNotSwapped", status)
+		}
+	}
+	for _, status := range []SwapStatus{TxSwapFailed, MatchTxNotStable, MatchTxFailed} {
+		if !status.CanTransitTo(MatchTxEmpty, true) {
+			t.Errorf("swap result %v can reswap but can not transit to MatchTxEmpty", status)
+		}
+	}
+}
+
+func TestSwapStatusTransitions(t *testing.T) {
+	tests := []struct {
+		from, to SwapStatus
+		isResult bool
+		allowed  bool
+	}{
+		{TxNotStable, TxNotSwapped, false, true},
+		{TxNotSwapped, TxProcessed, false, true},
+		{TxProcessed, TxProcessed, false, true},
+		{TxProcessed, TxNotStable, false, false},
+		{TxWithWrongMemo, TxNotSwapped, false, false},
+		{TxSwapFailed, TxProcessed, false, false},
+		{MatchTxEmpty, MatchTxNotStable, true, true},
+		{MatchTxNotStable, MatchTxStable, true, true},
+		{MatchTxStable, MatchTxEmpty, true, false},
+		{MatchTxFailed, MatchTxStable, true, false},
+	}
+	for _, test := range tests {
+		if allowed := test.from.CanTransitTo(test.to, test.isResult); allowed != test.allowed {
+			t.Errorf("transit from %v to %v (isResult=%v) want %v have %v", test.from, test.to, test.isResult, test.allowed, allowed)
+		}
+		sources := getAllowedSourceStatuses(test.to, test.isResult)
+		found := false
+		for _, source := range sources {
+			if source == test.from {
+				found = true
+				break
+			}
+		}
+		if found != test.allowed {
+			t.Errorf("allowed source statuses of %v (isResult=%v) mismatch, want %v have %v", test.to, test.isResult, test.allowed, found)
+		}
+	}
+}
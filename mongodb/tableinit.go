@@ -15,12 +15,25 @@ var (
 	collRegisteredAddress *mgo.Collection
 	collBlacklist         *mgo.Collection
 	collLatestSwapNonces  *mgo.Collection
+	collScanRanges        *mgo.Collection
+	collSwapStatusHistory *mgo.Collection
+	collLeases            *mgo.Collection
+	collSwapClaims        *mgo.Collection
+	collSwapOutbox        *mgo.Collection
+	collAggregates        *mgo.Collection
+	collFeeLedger         *mgo.Collection
+	collPairConfigs       *mgo.Collection
+	collAdminCallNonces   *mgo.Collection
 )
 
 func isSwapin(collection *mgo.Collection) bool {
 	return collection == collSwapin || collection == collSwapinResult
 }
 
+func isSwapResult(collection *mgo.Collection) bool {
+	return collection == collSwapinResult || collection == collSwapoutResult
+}
+
 // do this when reconnect to the database
 func deinintCollections() {
 	collSwapin = database.C(tbSwapins)
@@ -33,6 +46,15 @@ func deinintCollections() {
 	collRegisteredAddress = database.C(tbRegisteredAddress)
 	collBlacklist = database.C(tbBlacklist)
 	collLatestSwapNonces = database.C(tbLatestSwapNonces)
+	collScanRanges = database.C(tbScanRanges)
+	collSwapStatusHistory = database.C(tbSwapStatusHistory)
+	collLeases = database.C(tbLeases)
+	collSwapClaims = database.C(tbSwapClaims)
+	collSwapOutbox = database.C(tbSwapOutbox)
+	collAggregates = database.C(tbAggregates)
+	collFeeLedger = database.C(tbFeeLedger)
+	collPairConfigs = database.C(tbPairConfigs)
+	collAdminCallNonces = database.C(tbAdminCallNonces)
 }
 
 func initCollections() {
@@ -46,6 +68,15 @@ func initCollections() {
 	initCollection(tbRegisteredAddress, &collRegisteredAddress)
 	initCollection(tbBlacklist, &collBlacklist)
 	initCollection(tbLatestSwapNonces, &collLatestSwapNonces, "address")
+	initCollection(tbScanRanges, &collScanRanges, "issrc")
+	initCollection(tbSwapStatusHistory, &collSwapStatusHistory, "swapkey")
+	initCollection(tbLeases, &collLeases)
+	initCollection(tbSwapClaims, &collSwapClaims, "owner")
+	initCollection(tbSwapOutbox, &collSwapOutbox, "status", "inittime")
+	initCollection(tbAggregates, &collAggregates, "pairid", "inittime")
+	initCollection(tbFeeLedger, &collFeeLedger, "pairid", "timestamp")
+	initCollection(tbPairConfigs, &collPairConfigs, "pairid", "version")
+	initCollection(tbAdminCallNonces, &collAdminCallNonces, "expireat")
 
 	initDefaultValue()
 }
@@ -11,6 +11,15 @@ const (
 	tbRegisteredAddress string = "RegisteredAddress"
 	tbBlacklist         string = "Blacklist"
 	tbLatestSwapNonces  string = "LatestSwapNonces"
+	tbScanRanges        string = "ScanRanges"
+	tbSwapStatusHistory string = "SwapStatusHistory"
+	tbLeases            string = "Leases"
+	tbSwapClaims        string = "SwapClaims"
+	tbSwapOutbox        string = "SwapOutbox"
+	tbAggregates        string = "Aggregates"
+	tbFeeLedger         string = "FeeLedger"
+	tbPairConfigs       string = "PairConfigs"
+	tbAdminCallNonces   string = "AdminCallNonces"
 
 	keyOfSrcLatestScanInfo string = "srclatest"
 	keyOfDstLatestScanInfo string = "dstlatest"
@@ -30,6 +39,19 @@ type MgoSwap struct {
 	Memo      string     `bson:"memo"`
 }
 
+// MgoSwapStatusChange swap status change history
+type MgoSwapStatusChange struct {
+	Key       string     `bson:"_id"`
+	SwapKey   string     `bson:"swapkey"` // txid + pairid + bind
+	IsSwapin  bool       `bson:"isswapin"`
+	IsResult  bool       `bson:"isresult"`
+	From      SwapStatus `bson:"from"`
+	To        SwapStatus `bson:"to"`
+	Actor     string     `bson:"actor"`
+	Reason    string     `bson:"reason"`
+	Timestamp int64      `bson:"timestamp"`
+}
+
 // MgoSwapResult swap result (verified swap)
 type MgoSwapResult struct {
 	Key        string     `bson:"_id"` // txid + pairid + bind
@@ -67,6 +89,7 @@ type SwapResultUpdateItems struct {
 	Status     SwapStatus
 	Timestamp  int64
 	Memo       string
+	Actor      string
 }
 
 // MgoP2shAddress key is the bind address
@@ -100,6 +123,17 @@ type MgoLatestScanInfo struct {
 	Timestamp   int64  `bson:"timestamp"`
 }
 
+// MgoScanRange quick sync range of chain scanning,
+// blocks in [Start, Next) are scanned, and [Next, End) are pending
+type MgoScanRange struct {
+	Key       string `bson:"_id"` // src/dst + start
+	IsSrc     bool   `bson:"issrc"`
+	Start     uint64 `bson:"start"`
+	End       uint64 `bson:"end"`
+	Next      uint64 `bson:"next"`
+	Timestamp int64  `bson:"timestamp"`
+}
+
 // MgoBlackAccount key is address
 type MgoBlackAccount struct {
 	Key       string `bson:"_id"` // address + pairid
@@ -116,3 +150,139 @@ type MgoLatestSwapNonce struct {
 	SwapNonce uint64 `bson:"swapnonce"`
 	Timestamp int64  `bson:"timestamp"`
 }
+
+// MgoLease lease of job or lock held by replica until expired
+type MgoLease struct {
+	Key       string `bson:"_id"` // lease name
+	Owner     string `bson:"owner"`
+	ExpireAt  int64  `bson:"expireat"`
+	Timestamp int64  `bson:"timestamp"`
+}
+
+// MgoSwapClaim claim of processing swap by replica
+type MgoSwapClaim struct {
+	Key       string `bson:"_id"` // swapin/swapout + txid + pairid + bind
+	SwapKey   string `bson:"swapkey"`
+	IsSwapin  bool   `bson:"isswapin"`
+	Owner     string `bson:"owner"`
+	ExpireAt  int64  `bson:"expireat"`
+	Timestamp int64  `bson:"timestamp"`
+}
+
+// swap outbox status
+const (
+	OutboxPending  = "pending"  // signed and persisted, not known to be sent
+	OutboxSent     = "sent"     // sent successfully
+	OutboxFailed   = "failed"   // send failed
+	OutboxObsolete = "obsolete" // superseded, no need to send
+)
+
+// MgoSwapOutbox signed swap tx persisted before sending
+type MgoSwapOutbox struct {
+	Key       string `bson:"_id"` // swap tx hash
+	SwapKey   string `bson:"swapkey"`
+	IsSwapin  bool   `bson:"isswapin"`
+	TxID      string `bson:"txid"`
+	PairID    string `bson:"pairid"`
+	Bind      string `bson:"bind"`
+	From      string `bson:"from"`
+	SwapTx    string `bson:"swaptx"`
+	SwapValue string `bson:"swapvalue"`
+	SwapType  uint32 `bson:"swaptype"`
+	SwapNonce uint64 `bson:"swapnonce"`
+	IsReplace bool   `bson:"isreplace"`
+	SignedTx  string `bson:"signedtx"` // empty if not serializable
+	Status    string `bson:"status"`
+	InitTime  int64  `bson:"inittime"`
+	Timestamp int64  `bson:"timestamp"`
+	Memo      string `bson:"memo"`
+}
+
+// aggregate kinds
+const (
+	AggregateKindP2sh    = "p2sh"    // aggregate utxos of p2sh deposit addresses
+	AggregateKindDeposit = "deposit" // consolidate dust utxos of deposit address
+)
+
+// aggregate tx status
+const (
+	AggregatePending = "pending" // signed and persisted, not known to be sent
+	AggregateSent    = "sent"    // sent successfully
+	AggregateFailed  = "failed"  // send failed
+)
+
+// MgoAggregateInput input of aggregate tx
+type MgoAggregateInput struct {
+	Address string `bson:"address" json:"address"`
+	Txid    string `bson:"txid" json:"txid"`
+	Vout    uint32 `bson:"vout" json:"vout"`
+	Value   uint64 `bson:"value" json:"value"`
+}
+
+// MgoAggregate signed aggregate tx persisted before sending
+type MgoAggregate struct {
+	Key           string               `bson:"_id"` // aggregate tx hash
+	PairID        string               `bson:"pairid"`
+	Kind          string               `bson:"kind"`
+	Inputs        []*MgoAggregateInput `bson:"inputs"`
+	SumValue      uint64               `bson:"sumvalue"`
+	RelayFeePerKb int64                `bson:"relayfeeperkb"`
+	SignedTx      string               `bson:"signedtx"`
+	Status        string               `bson:"status"`
+	InitTime      int64                `bson:"inittime"`
+	Timestamp     int64                `bson:"timestamp"`
+	Memo          string               `bson:"memo"`
+}
+
+// fee ledger entry kinds
+const (
+	FeeKindCollected = "collected" // swap fee deducted from swap value (in token unit)
+	FeeKindNetwork   = "network"   // network fee paid by swap tx (in native coin unit)
+	FeeKindWithdraw  = "withdraw"  // collected fee withdrawn to fee treasury (in token unit)
+)
+
+// fee withdraw status
+const (
+	FeeWithdrawPending = "pending" // signed, not known to be sent
+	FeeWithdrawSent    = "sent"    // sent successfully
+	FeeWithdrawFailed  = "failed"  // build, sign or send failed
+)
+
+// MgoFeeLedgerEntry fee ledger entry, swapin fee is collected on destination chain,
+// swapout fee is collected on source chain
+type MgoFeeLedgerEntry struct {
+	Key       string `bson:"_id"` // kind + swapin/swapout + swap key or tx hash
+	PairID    string `bson:"pairid"`
+	Kind      string `bson:"kind"`
+	IsSwapin  bool   `bson:"isswapin"`
+	TxID      string `bson:"txid"`   // swap txid or fee withdraw id
+	SwapTx    string `bson:"swaptx"` // swap tx or fee withdraw tx
+	Amount    string `bson:"amount"`
+	Status    string `bson:"status,omitempty"` // fee withdraw only
+	Actor     string `bson:"actor,omitempty"`  // fee withdraw only
+	Timestamp int64  `bson:"timestamp"`
+	Memo      string `bson:"memo,omitempty"`
+}
+
+// MgoPairConfig version of token pair config, pushed by signed admin call
+type MgoPairConfig struct {
+	Key       string `bson:"_id"` // pairid + version
+	PairID    string `bson:"pairid"`
+	Version   uint64 `bson:"version"`
+	Config    string `bson:"config"`   // toml content
+	Hash      string `bson:"hash"`     // hash of parsed pair config
+	PrevHash  string `bson:"prevhash"` // hash of previous version
+	Removed   bool   `bson:"removed"`  // pair is removed since this version
+	Admin     string `bson:"admin"`
+	AdminTx   string `bson:"admintx"` // signed admin call, can be verified again
+	Timestamp int64  `bson:"timestamp"`
+}
+
+// MgoAdminCallNonce used nonce of offline signed admin call
+type MgoAdminCallNonce struct {
+	Key       string `bson:"_id"` // nonce
+	Admin     string `bson:"admin"`
+	Method    string `bson:"method"`
+	ExpireAt  int64  `bson:"expireat"`
+	Timestamp int64  `bson:"timestamp"`
+}
@@ -2,78 +2,144 @@ package params
 
 import (
 	"errors"
+	"fmt"
 	"math/big"
+	"os"
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/rpc/client"
 )
 
+// ConfigSectionError error of config section
+type ConfigSectionError struct {
+	Section string
+	Err     error
+}
+
+// Error impl error interface
+func (e *ConfigSectionError) Error() string {
+	return fmt.Sprintf("%v: %v", e.Section, e.Err)
+}
+
 // CheckConfig check config
 func CheckConfig(isServer bool) (err error) {
-	config := GetConfig()
-	if config.Identifier == "" {
-		return errors.New("server must config non empty 'Identifier'")
-	}
-	if isServer {
-		if config.MongoDB == nil {
-			return errors.New("server must config 'MongoDB'")
-		}
-		if config.APIServer == nil {
-			return errors.New("server must config 'APIServer'")
-		}
-	} else {
-		if config.Oracle == nil {
-			return errors.New("oracle must config 'Oracle'")
-		}
-		err = config.Oracle.CheckConfig()
-		if err != nil {
-			return err
-		}
-	}
-	err = checkChainAndGatewayConfig()
-	if err != nil {
-		return err
+	if errs := CheckConfigSections(isServer); len(errs) > 0 {
+		return errs[0].Err
 	}
-	if config.Dcrm == nil {
-		return errors.New("server must config 'Dcrm'")
-	}
-	err = config.Dcrm.CheckConfig(isServer)
-	if err != nil {
-		return err
-	}
-	if config.Extra != nil {
-		err = config.Extra.CheckConfig()
-		if err != nil {
-			return err
-		}
+	if !isServer {
+		GetConfig().Oracle.waitServerConnected()
 	}
 	return nil
 }
 
-func checkChainAndGatewayConfig() (err error) {
+// CheckConfigSections check config sections in order without connecting to
+// server, and return errors of all failed sections (not only the first one)
+func CheckConfigSections(isServer bool) (errs []*ConfigSectionError) {
 	config := GetConfig()
-	if config.SrcChain == nil {
-		return errors.New("server must config 'SrcChain'")
-	}
-	if config.SrcGateway == nil {
-		return errors.New("server must config 'SrcGateway'")
-	}
-	if config.DestChain == nil {
-		return errors.New("server must config 'DestChain'")
-	}
-	if config.DestGateway == nil {
-		return errors.New("server must config 'DestGateway'")
-	}
-	err = config.SrcChain.CheckConfig()
-	if err != nil {
-		return err
+	sections := []struct {
+		name  string
+		check func() error
+	}{
+		{"Identifier", func() error {
+			if config.Identifier == "" {
+				return errors.New("server must config non empty 'Identifier'")
+			}
+			return nil
+		}},
+		{"MongoDB", func() error {
+			if isServer && config.MongoDB == nil {
+				return errors.New("server must config 'MongoDB'")
+			}
+			return nil
+		}},
+		{"APIServer", func() error {
+			if isServer && config.APIServer == nil {
+				return errors.New("server must config 'APIServer'")
+			}
+			return nil
+		}},
+		{"Oracle", func() error {
+			if isServer {
+				return nil
+			}
+			if config.Oracle == nil {
+				return errors.New("oracle must config 'Oracle'")
+			}
+			err := config.Oracle.checkConfig()
+			if err == nil && config.Oracle.LedgerFile != "" && config.SrcChain != nil && config.DestChain != nil &&
+				(!config.SrcChain.EnableScan || !config.DestChain.EnableScan) {
+				err = errors.New("oracle with 'LedgerFile' must enable scan of both chains")
+			}
+			return err
+		}},
+		{"SrcChain", func() error {
+			if config.SrcChain == nil {
+				return errors.New("server must config 'SrcChain'")
+			}
+			if config.SrcGateway == nil {
+				return errors.New("server must config 'SrcGateway'")
+			}
+			return config.SrcChain.CheckConfig()
+		}},
+		{"DestChain", func() error {
+			if config.DestChain == nil {
+				return errors.New("server must config 'DestChain'")
+			}
+			if config.DestGateway == nil {
+				return errors.New("server must config 'DestGateway'")
+			}
+			return config.DestChain.CheckConfig()
+		}},
+		{"Dcrm", func() error {
+			if config.Dcrm == nil {
+				return errors.New("server must config 'Dcrm'")
+			}
+			return config.Dcrm.CheckConfig(isServer)
+		}},
+		{"Extra", func() error {
+			if config.Extra == nil {
+				return nil
+			}
+			return config.Extra.CheckConfig()
+		}},
+		{"Replica", func() error {
+			if !isServer || config.Replica == nil {
+				return nil
+			}
+			return config.Replica.CheckConfig()
+		}},
+		{"PriceFeed", func() error {
+			if config.PriceFeed == nil {
+				return nil
+			}
+			return config.PriceFeed.CheckConfig()
+		}},
+		{"Secrets", func() error {
+			if config.Secrets == nil {
+				return nil
+			}
+			return config.Secrets.CheckConfig()
+		}},
+		{"PairsSource", func() error {
+			switch config.PairsSource {
+			case "", PairsSourceDir:
+			case PairsSourceDB:
+				if !isServer {
+					return errors.New("oracle can not load token pairs config from db")
+				}
+			default:
+				return fmt.Errorf("unknown 'PairsSource' '%v'", config.PairsSource)
+			}
+			return nil
+		}},
 	}
-	err = config.DestChain.CheckConfig()
-	if err != nil {
-		return err
+	for _, section := range sections {
+		if err := section.check(); err != nil {
+			errs = append(errs, &ConfigSectionError{Section: section.name, Err: err})
+		}
 	}
-	return nil
+	return errs
 }
 
 // CheckConfig check dcrm config
@@ -129,23 +195,44 @@ func (c *DcrmNodeConfig) CheckConfig(isServer bool) (err error) {
 	return nil
 }
 
-// CheckConfig check oracle config
+// CheckConfig check oracle config, and wait until server is connected
 func (c *OracleConfig) CheckConfig() (err error) {
+	err = c.checkConfig()
+	if err != nil {
+		return err
+	}
+	c.waitServerConnected()
+	return nil
+}
+
+func (c *OracleConfig) checkConfig() error {
 	ServerAPIAddress = c.ServerAPIAddress
 	if ServerAPIAddress == "" {
 		return errors.New("oracle must config 'ServerAPIAddress'")
 	}
-	var version string
+	return nil
+}
+
+func (c *OracleConfig) waitServerConnected() {
 	for {
-		err = client.RPCPost(&version, ServerAPIAddress, "swap.GetVersionInfo")
+		err := c.CheckServerConnection()
 		if err == nil {
-			log.Info("oracle get server version info succeed", "version", version)
 			break
 		}
-		log.Warn("oracle connect ServerAPIAddress failed", "ServerAPIAddress", ServerAPIAddress, "err", err)
+		log.Warn("oracle connect ServerAPIAddress failed", "ServerAPIAddress", c.ServerAPIAddress, "err", err)
 		time.Sleep(3 * time.Second)
 	}
-	return err
+}
+
+// CheckServerConnection check server is connected by getting its version info
+func (c *OracleConfig) CheckServerConnection() error {
+	var version string
+	err := client.RPCPost(&version, c.ServerAPIAddress, "swap.GetVersionInfo")
+	if err != nil {
+		return err
+	}
+	log.Info("oracle get server version info succeed", "version", version)
+	return nil
 }
 
 // CheckConfig extra config
@@ -159,3 +246,30 @@ func (c *ExtraConfig) CheckConfig() (err error) {
 	}
 	return nil
 }
+
+// CheckConfig check replica config
+func (c *ReplicaConfig) CheckConfig() (err error) {
+	switch c.Mode {
+	case ReplicaActiveActive, ReplicaActiveStandby:
+	default:
+		return fmt.Errorf("replica must config 'Mode' to '%v' or '%v'", ReplicaActiveActive, ReplicaActiveStandby)
+	}
+	if c.LeaseTTL < 0 || c.ClaimTTL < 0 {
+		return errors.New("replica config 'LeaseTTL' and 'ClaimTTL' must not be negative")
+	}
+	if c.LeaseTTL == 0 {
+		c.LeaseTTL = defaultLeaseTTL
+	}
+	if c.ClaimTTL == 0 {
+		c.ClaimTTL = defaultClaimTTL
+	}
+	if c.Name == "" {
+		hostname, errh := os.Hostname()
+		if errh != nil {
+			return fmt.Errorf("replica get hostname failed: %v", errh)
+		}
+		c.Name = fmt.Sprintf("%v:%v", hostname, os.Getpid())
+	}
+	log.Info("check replica config", "name", c.Name, "mode", c.Mode, "leaseTTL", c.LeaseTTL, "claimTTL", c.ClaimTTL)
+	return nil
+}
@@ -2,6 +2,7 @@ package params
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 	"sync"
 
@@ -9,11 +10,15 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/common"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tools/secrets"
 )
 
 const (
 	defaultAPIPort      = 11556
 	defServerConfigFile = "config.toml"
+
+	defaultLeaseTTL = 30  // seconds
+	defaultClaimTTL = 600 // seconds
 )
 
 var (
@@ -34,11 +39,15 @@ type ServerConfig struct {
 	SrcGateway          *tokens.GatewayConfig
 	DestChain           *tokens.ChainConfig
 	DestGateway         *tokens.GatewayConfig
-	Dcrm                *DcrmConfig            `toml:",omitempty" json:",omitempty"`
-	Oracle              *OracleConfig          `toml:",omitempty" json:",omitempty"`
-	BtcExtra            *tokens.BtcExtraConfig `toml:",omitempty" json:",omitempty"`
-	Extra               *ExtraConfig           `toml:",omitempty" json:",omitempty"`
-	Admins              []string               `toml:",omitempty" json:",omitempty"`
+	Dcrm                *DcrmConfig             `toml:",omitempty" json:",omitempty"`
+	Oracle              *OracleConfig           `toml:",omitempty" json:",omitempty"`
+	BtcExtra            *tokens.BtcExtraConfig  `toml:",omitempty" json:",omitempty"`
+	Extra               *ExtraConfig            `toml:",omitempty" json:",omitempty"`
+	Replica             *ReplicaConfig          `toml:",omitempty" json:",omitempty"`
+	PriceFeed           *tokens.PriceFeedConfig `toml:",omitempty" json:",omitempty"`
+	PairsSource         string                  `toml:",omitempty" json:",omitempty"` // where server loads token pairs config from, 'dir' (default) or 'db'
+	Secrets             *secrets.Config         `toml:",omitempty" json:",omitempty"`
+	Admins              []string                `toml:",omitempty" json:",omitempty"`
 }
 
 // DcrmConfig dcrm related config
@@ -63,7 +72,9 @@ type DcrmNodeConfig struct {
 
 // OracleConfig oracle config
 type OracleConfig struct {
-	ServerAPIAddress string
+	ServerAPIAddress  string
+	LedgerFile        string `toml:",omitempty" json:",omitempty"`
+	VerifyPairsConfig bool   `toml:",omitempty" json:",omitempty"` // ignore signs of pairs whose config hash mismatch with server
 }
 
 // APIServerConfig api service config
@@ -85,6 +96,26 @@ type ExtraConfig struct {
 	MinReserveFee string
 }
 
+// token pairs config sources
+const (
+	PairsSourceDir = "dir"
+	PairsSourceDB  = "db"
+)
+
+// replica modes
+const (
+	ReplicaActiveActive  = "active-active"
+	ReplicaActiveStandby = "active-standby"
+)
+
+// ReplicaConfig config of running multiple swap servers with the same database
+type ReplicaConfig struct {
+	Name     string `toml:",omitempty" json:",omitempty"` // default is hostname:pid
+	Mode     string // active-active or active-standby
+	LeaseTTL int64  `toml:",omitempty" json:",omitempty"` // seconds
+	ClaimTTL int64  `toml:",omitempty" json:",omitempty"` // seconds
+}
+
 // GetAPIPort get api service port
 func GetAPIPort() int {
 	apiPort := GetConfig().APIServer.Port
@@ -119,6 +150,27 @@ func IsDcrmInitiator(account string) bool {
 	return false
 }
 
+// IsPairsConfigInDB is token pairs config loaded from database
+func IsPairsConfigInDB() bool {
+	return GetConfig().PairsSource == PairsSourceDB
+}
+
+// IsVerifyPairsConfig is oracle verify pairs config hash with server
+func IsVerifyPairsConfig() bool {
+	oracle := GetConfig().Oracle
+	return oracle != nil && oracle.VerifyPairsConfig
+}
+
+// IsReplicaEnabled is running with other replicas
+func IsReplicaEnabled() bool {
+	return GetConfig().Replica != nil
+}
+
+// GetReplicaConfig get replica config
+func GetReplicaConfig() *ReplicaConfig {
+	return GetConfig().Replica
+}
+
 // GetConfig get config items structure
 func GetConfig() *ServerConfig {
 	return serverConfig
@@ -146,12 +198,9 @@ func LoadConfig(configFile string, isServer bool) *ServerConfig {
 			configFile = common.AbsolutePath(dir, defServerConfigFile)
 		}
 		log.Println("Config file is", configFile)
-		if !common.FileExist(configFile) {
-			log.Fatalf("LoadConfig error: config file %v not exist", configFile)
-		}
-		config := &ServerConfig{}
-		if _, err := toml.DecodeFile(configFile, &config); err != nil {
-			log.Fatalf("LoadConfig error (toml DecodeFile): %v", err)
+		config, err := LoadConfigFile(configFile)
+		if err != nil {
+			log.Fatalf("LoadConfig error: %v", err)
 		}
 
 		SetConfig(config)
@@ -165,10 +214,25 @@ func LoadConfig(configFile string, isServer bool) *ServerConfig {
 		if err := CheckConfig(isServer); err != nil {
 			log.Fatalf("Check config failed. %v", err)
 		}
+		if err := secrets.Init(config.Secrets); err != nil {
+			log.Fatalf("Init secrets providers failed. %v", err)
+		}
 	})
 	return serverConfig
 }
 
+// LoadConfigFile load config file without checking
+func LoadConfigFile(configFile string) (*ServerConfig, error) {
+	if !common.FileExist(configFile) {
+		return nil, fmt.Errorf("config file %v not exist", configFile)
+	}
+	config := &ServerConfig{}
+	if _, err := toml.DecodeFile(configFile, &config); err != nil {
+		return nil, fmt.Errorf("toml decode file error: %v", err)
+	}
+	return config, nil
+}
+
 // HasAdmin has admin
 func HasAdmin() bool {
 	return len(serverConfig.Admins) != 0
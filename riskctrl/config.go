@@ -8,6 +8,7 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/common"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tools/secrets"
 )
 
 var (
@@ -24,7 +25,8 @@ type RiskConfig struct {
 	DestToken   *tokens.TokenConfig
 	DestGateway *tokens.GatewayConfig
 
-	Email *EmailConfig
+	Email   *EmailConfig
+	Secrets *secrets.Config `toml:",omitempty" json:",omitempty"`
 
 	InitialDiffValue         float64
 	MaxAuditBalanceDiffValue float64
@@ -110,6 +112,9 @@ func LoadConfig(configFile string) *RiskConfig {
 	if err := CheckConfig(); err != nil {
 		log.Fatalf("Check config failed. %v", err)
 	}
+	if err := secrets.Init(config.Secrets); err != nil {
+		log.Fatalf("Init secrets providers failed. %v", err)
+	}
 
 	return riskConfig
 }
@@ -5,6 +5,7 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tokens/bridge"
 	"github.com/anyswap/CrossChain-Bridge/tools"
+	"github.com/anyswap/CrossChain-Bridge/tools/secrets"
 )
 
 var (
@@ -48,7 +49,10 @@ func InitEmailConfig() {
 	port := riskConfig.Email.Port
 	from := riskConfig.Email.From
 	name := riskConfig.Email.FromName
-	password := riskConfig.Email.Password
+	password, err := secrets.GetSecret(riskConfig.Email.Password)
+	if err != nil {
+		log.Fatal("get email password failed", "err", err)
+	}
 	tools.InitEmailConfig(server, port, from, name, password)
 	log.Info("init email config", "server", server, "port", port, "from", from, "name", name)
 }
@@ -3,6 +3,7 @@ package riskctrl
 import (
 	"fmt"
 	"math/big"
+	"strings"
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/log"
@@ -276,6 +277,9 @@ func getWithdrawBalance() *big.Int {
 }
 
 func getTotalSupply() *big.Int {
+	if GetConfig().DestToken.IsLockRelease {
+		return getDestLockedSupply()
+	}
 	var (
 		totalSupply *big.Int
 		err         error
@@ -291,3 +295,28 @@ func getTotalSupply() *big.Int {
 	}
 	return totalSupply
 }
+
+// in lock/release mode, dest token is not minted but released from vault.
+// we regard the negative locked balance on dest chain as total supply,
+// then (src locked + dest locked) is audited against InitialDiffValue.
+func getDestLockedSupply() *big.Int {
+	config := GetConfig()
+	vaults := []string{config.DestToken.DcrmAddress}
+	if !strings.EqualFold(config.DestToken.DepositAddress, config.DestToken.DcrmAddress) {
+		vaults = append(vaults, config.DestToken.DepositAddress)
+	}
+	lockedBalance := big.NewInt(0)
+	for _, vault := range vaults {
+		for {
+			balance, err := dstBridge.GetTokenBalance(tokenType, dstTokenAddress, vault)
+			if err == nil {
+				log.Info("get dest locked balance success", "token", dstTokenAddress, "vault", vault, "balance", balance)
+				lockedBalance.Add(lockedBalance, balance)
+				break
+			}
+			log.Warn("get dest locked balance failed", "token", dstTokenAddress, "vault", vault, "err", err)
+			time.Sleep(retryInterval)
+		}
+	}
+	return lockedBalance.Neg(lockedBalance)
+}
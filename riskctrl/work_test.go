@@ -0,0 +1,50 @@
+package riskctrl
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// fakeBalanceBridge bridge with token balances of accounts
+type fakeBalanceBridge struct {
+	tokens.CrossChainBridge
+	balances map[string]*big.Int
+}
+
+func (b *fakeBalanceBridge) GetTokenBalance(tokenType, tokenAddress, accountAddress string) (*big.Int, error) {
+	return b.balances[accountAddress], nil
+}
+
+func TestGetDestLockedSupply(t *testing.T) {
+	oldConfig, oldBridge := GetConfig(), dstBridge
+	defer func() {
+		SetConfig(oldConfig)
+		dstBridge = oldBridge
+	}()
+
+	dstBridge = &fakeBalanceBridge{balances: map[string]*big.Int{
+		"0xvault":   big.NewInt(300),
+		"0xdeposit": big.NewInt(200),
+		"0xempty":   big.NewInt(0),
+	}}
+	tests := []struct {
+		dcrmAddress    string
+		depositAddress string
+		want           int64
+	}{
+		{"0xvault", "0xdeposit", -500},
+		{"0xvault", "0xVault", -300}, // vault is counted once
+		{"0xvault", "0xempty", -300},
+	}
+	for _, test := range tests {
+		SetConfig(&RiskConfig{DestToken: &tokens.TokenConfig{
+			DcrmAddress:    test.dcrmAddress,
+			DepositAddress: test.depositAddress,
+		}})
+		if have := getDestLockedSupply(); have.Int64() != test.want {
+			t.Errorf("dest locked supply of vault %v and deposit %v mismatch, have %v want %v", test.dcrmAddress, test.depositAddress, have, test.want)
+		}
+	}
+}
@@ -122,6 +122,26 @@ func GetSwapoutHandler(w http.ResponseWriter, r *http.Request) {
 	writeResponse(w, res, err)
 }
 
+// GetSwapinStatusHistoryHandler handler
+func GetSwapinStatusHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	txid := vars["txid"]
+	pairID := vars["pairid"]
+	bind := getBindParam(r)
+	res, err := swapapi.GetSwapinStatusHistory(&txid, &pairID, &bind)
+	writeResponse(w, res, err)
+}
+
+// GetSwapoutStatusHistoryHandler handler
+func GetSwapoutStatusHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	txid := vars["txid"]
+	pairID := vars["pairid"]
+	bind := getBindParam(r)
+	res, err := swapapi.GetSwapoutStatusHistory(&txid, &pairID, &bind)
+	writeResponse(w, res, err)
+}
+
 func getHistoryParams(r *http.Request) (address, pairID string, offset, limit int, err error) {
 	vars := mux.Vars(r)
 	vals := r.URL.Query()
@@ -170,6 +190,50 @@ func SwapoutHistoryHandler(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// AggregateHistoryHandler handler
+func AggregateHistoryHandler(w http.ResponseWriter, r *http.Request) {
+	_, pairID, offset, limit, err := getHistoryParams(r)
+	if err != nil {
+		writeResponse(w, nil, err)
+	} else {
+		res, err := swapapi.GetAggregateHistory(pairID, offset, limit)
+		writeResponse(w, res, err)
+	}
+}
+
+// FeeLedgerHandler handler
+func FeeLedgerHandler(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	vals := r.URL.Query()
+	pairID := vars["pairid"]
+	var from, to int
+	var err error
+	if fromStr, exist := vals["from"]; exist {
+		from, err = common.GetIntFromStr(fromStr[0])
+	}
+	if toStr, exist := vals["to"]; exist && err == nil {
+		to, err = common.GetIntFromStr(toStr[0])
+	}
+	if err != nil {
+		writeResponse(w, nil, err)
+	} else {
+		res, err := swapapi.GetFeeLedger(pairID, int64(from), int64(to))
+		writeResponse(w, res, err)
+	}
+}
+
+// FeeLedgerEntriesHandler handler
+func FeeLedgerEntriesHandler(w http.ResponseWriter, r *http.Request) {
+	_, pairID, offset, limit, err := getHistoryParams(r)
+	if err != nil {
+		writeResponse(w, nil, err)
+	} else {
+		kind := r.URL.Query().Get("kind")
+		res, err := swapapi.GetFeeLedgerEntries(pairID, kind, offset, limit)
+		writeResponse(w, res, err)
+	}
+}
+
 // PostSwapinHandler handler
 func PostSwapinHandler(w http.ResponseWriter, r *http.Request) {
 	vars := mux.Vars(r)
@@ -237,3 +301,20 @@ func GetRegisteredAddress(w http.ResponseWriter, r *http.Request) {
 	res, err := swapapi.GetRegisteredAddress(address)
 	writeResponse(w, res, err)
 }
+
+// SwapAttestationHandler handler (oracle only)
+func SwapAttestationHandler(w http.ResponseWriter, r *http.Request) {
+	vals := r.URL.Query()
+	var since int64
+	if sinceStr, exist := vals["since"]; exist {
+		sinceVal, err := common.GetIntFromStr(sinceStr[0])
+		if err != nil {
+			writeResponse(w, nil, err)
+			return
+		}
+		since = int64(sinceVal)
+	}
+	pairID := vals.Get("pairid")
+	res, err := swapapi.GetSwapAttestation(since, pairID)
+	writeResponse(w, res, err)
+}
@@ -2,6 +2,7 @@ package rpcapi
 
 import (
 	"fmt"
+	"math/big"
 	"net/http"
 	"strings"
 
@@ -22,6 +23,7 @@ const (
 	failSwapinOp  = "failswapin"
 	failSwapoutOp = "failswapout"
 	forceFlag     = "--force"
+	dryRunFlag    = "dryrun"
 )
 
 // AdminCall admin call
@@ -40,29 +42,49 @@ func (s *RPCAPI) AdminCall(r *http.Request, rawTx, result *string) (err error) {
 	if !params.IsAdmin(sender.String()) {
 		return fmt.Errorf("sender %v is not admin", sender.String())
 	}
-	return doCall(args, result)
+	if args.Nonce != "" {
+		err = mongodb.AddAdminCallNonce(args.Nonce, sender.String(), args.Method, args.ExpireTime())
+		if err == mongodb.ErrItemIsDup {
+			return fmt.Errorf("admin call nonce %v is already used", args.Nonce)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return doCall(sender.String(), *rawTx, args, result)
 }
 
-func doCall(args *admin.CallArgs, result *string) error {
+// doCall do admin call, actor is recorded in swap status history,
+// and the signed rawTx is recorded with pushed pair config
+func doCall(sender, rawTx string, args *admin.CallArgs, result *string) error {
+	actor := mongodb.AdminActor(sender)
 	switch args.Method {
 	case "blacklist":
 		return blacklist(args, result)
 	case "bigvalue":
-		return bigvalue(args, result)
+		return bigvalue(actor, args, result)
 	case "maintain":
 		return maintain(args, result)
 	case "reverify":
-		return reverify(args, result)
+		return reverify(actor, args, result)
 	case "reswap":
-		return reswap(args, result)
+		return reswap(actor, args, result)
 	case "replaceswap":
 		return replaceswap(args, result)
 	case "manual":
-		return manual(args, result)
+		return manual(actor, args, result)
 	case "setnonce":
 		return setnonce(args, result)
 	case "addpair":
 		return addpair(args, result)
+	case "reloadpairs":
+		return reloadpairs(args, result)
+	case worker.PushPairMethod:
+		return pushpair(sender, rawTx, args, result)
+	case worker.RemovePairMethod:
+		return removepair(sender, rawTx, args, result)
+	case "withdrawfee":
+		return withdrawfee(actor, args, result)
 	default:
 		return fmt.Errorf("unknown admin method '%v'", args.Method)
 	}
@@ -103,7 +125,7 @@ func blacklist(args *admin.CallArgs, result *string) (err error) {
 	return nil
 }
 
-func bigvalue(args *admin.CallArgs, result *string) (err error) {
+func bigvalue(actor string, args *admin.CallArgs, result *string) (err error) {
 	if len(args.Params) != 4 {
 		return fmt.Errorf("wrong number of params, have %v want 4", len(args.Params))
 	}
@@ -113,9 +135,9 @@ func bigvalue(args *admin.CallArgs, result *string) (err error) {
 	bind := args.Params[3]
 	switch operation {
 	case passSwapinOp:
-		err = mongodb.PassSwapinBigValue(txid, pairID, bind)
+		err = mongodb.PassSwapinBigValue(txid, pairID, bind, actor)
 	case passSwapoutOp:
-		err = mongodb.PassSwapoutBigValue(txid, pairID, bind)
+		err = mongodb.PassSwapoutBigValue(txid, pairID, bind, actor)
 	default:
 		return fmt.Errorf("unknown operation '%v'", operation)
 	}
@@ -212,16 +234,16 @@ func getOpTxAndPairID(args *admin.CallArgs) (operation, txid, pairID, bind, forc
 	return operation, txid, pairID, bind, forceOpt, nil
 }
 
-func reverify(args *admin.CallArgs, result *string) (err error) {
+func reverify(actor string, args *admin.CallArgs, result *string) (err error) {
 	operation, txid, pairID, bind, _, err := getOpTxAndPairID(args)
 	if err != nil {
 		return err
 	}
 	switch operation {
 	case swapinOp:
-		err = mongodb.ReverifySwapin(txid, pairID, bind)
+		err = mongodb.ReverifySwapin(txid, pairID, bind, actor)
 	case swapoutOp:
-		err = mongodb.ReverifySwapout(txid, pairID, bind)
+		err = mongodb.ReverifySwapout(txid, pairID, bind, actor)
 	default:
 		return fmt.Errorf("unknown operation '%v'", operation)
 	}
@@ -232,16 +254,16 @@ func reverify(args *admin.CallArgs, result *string) (err error) {
 	return nil
 }
 
-func reswap(args *admin.CallArgs, result *string) (err error) {
+func reswap(actor string, args *admin.CallArgs, result *string) (err error) {
 	operation, txid, pairID, bind, forceOpt, err := getOpTxAndPairID(args)
 	if err != nil {
 		return err
 	}
 	switch operation {
 	case swapinOp:
-		err = mongodb.Reswapin(txid, pairID, bind, forceOpt)
+		err = mongodb.Reswapin(txid, pairID, bind, forceOpt, actor)
 	case swapoutOp:
-		err = mongodb.Reswapout(txid, pairID, bind, forceOpt)
+		err = mongodb.Reswapout(txid, pairID, bind, forceOpt, actor)
 	default:
 		return fmt.Errorf("unknown operation '%v'", operation)
 	}
@@ -279,7 +301,7 @@ func replaceswap(args *admin.CallArgs, result *string) (err error) {
 	return nil
 }
 
-func manual(args *admin.CallArgs, result *string) (err error) {
+func manual(actor string, args *admin.CallArgs, result *string) (err error) {
 	if !(len(args.Params) == 4 || len(args.Params) == 5) {
 		return fmt.Errorf("wrong number of params, have %v want 4 or 5", len(args.Params))
 	}
@@ -310,7 +332,7 @@ func manual(args *admin.CallArgs, result *string) (err error) {
 	default:
 		return fmt.Errorf("unknown operation '%v'", operation)
 	}
-	err = mongodb.ManualManageSwap(txid, pairID, bind, memo, isSwapin, isPass)
+	err = mongodb.ManualManageSwap(txid, pairID, bind, memo, actor, isSwapin, isPass)
 	if err != nil {
 		return err
 	}
@@ -350,6 +372,9 @@ func addpair(args *admin.CallArgs, result *string) (err error) {
 	if len(args.Params) != 1 {
 		return fmt.Errorf("wrong number of params, have %v want 1", len(args.Params))
 	}
+	if params.IsPairsConfigInDB() {
+		return fmt.Errorf("token pairs config is loaded from db, push pair config instead")
+	}
 	configFile := args.Params[0]
 	pairConfig, err := tokens.AddPairConfig(configFile)
 	if err != nil {
@@ -359,3 +384,82 @@ func addpair(args *admin.CallArgs, result *string) (err error) {
 	*result = successReuslt
 	return nil
 }
+
+func reloadpairs(args *admin.CallArgs, result *string) (err error) {
+	if len(args.Params) > 1 {
+		return fmt.Errorf("wrong number of params, have %v want 0 or 1", len(args.Params))
+	}
+	dryRun := false
+	if len(args.Params) == 1 {
+		if args.Params[0] != dryRunFlag {
+			return fmt.Errorf("unknown param '%v'", args.Params[0])
+		}
+		dryRun = true
+	}
+	changes, err := worker.ReloadTokenPairs(dryRun)
+	if err != nil {
+		return err
+	}
+	lines := make([]string, 0, len(changes)+1)
+	switch {
+	case len(changes) == 0:
+		lines = append(lines, "no changes")
+	case dryRun:
+		lines = append(lines, fmt.Sprintf("dry run, %v changes", len(changes)))
+	default:
+		lines = append(lines, successReuslt)
+	}
+	for _, change := range changes {
+		lines = append(lines, change.String())
+	}
+	*result = strings.Join(lines, "\n")
+	return nil
+}
+
+func pushpair(sender, rawTx string, args *admin.CallArgs, result *string) (err error) {
+	mp, err := worker.PushPairConfig(args.Params, sender, rawTx)
+	if err != nil {
+		return err
+	}
+	*result = fmt.Sprintf("%v, pair %v version %v hash %v, reload pairs to apply it", successReuslt, mp.PairID, mp.Version, mp.Hash)
+	return nil
+}
+
+func removepair(sender, rawTx string, args *admin.CallArgs, result *string) (err error) {
+	mp, err := worker.RemovePairConfig(args.Params, sender, rawTx)
+	if err != nil {
+		return err
+	}
+	*result = fmt.Sprintf("%v, pair %v removed in version %v, reload pairs to apply it", successReuslt, mp.PairID, mp.Version)
+	return nil
+}
+
+func withdrawfee(actor string, args *admin.CallArgs, result *string) (err error) {
+	if !(len(args.Params) == 2 || len(args.Params) == 3) {
+		return fmt.Errorf("wrong number of params, have %v want 2 or 3", len(args.Params))
+	}
+	operation := args.Params[0]
+	pairID := args.Params[1]
+	var amount *big.Int
+	if len(args.Params) > 2 {
+		amount, err = common.GetBigIntFromStr(args.Params[2])
+		if err != nil {
+			return fmt.Errorf("wrong amount value, %v", err)
+		}
+	}
+	var isSwapin bool
+	switch operation {
+	case swapinOp:
+		isSwapin = true
+	case swapoutOp:
+		isSwapin = false
+	default:
+		return fmt.Errorf("unknown operation '%v'", operation)
+	}
+	txHash, err := worker.WithdrawFee(pairID, isSwapin, amount, actor)
+	if err != nil {
+		return err
+	}
+	*result = successReuslt + " txHash is " + txHash
+	return nil
+}
@@ -147,6 +147,32 @@ func (s *RPCAPI) GetSwapout(r *http.Request, args *RPCTxAndPairIDArgs, result *s
 	return err
 }
 
+// GetSwapinStatusHistory api
+func (s *RPCAPI) GetSwapinStatusHistory(r *http.Request, args *RPCTxAndPairIDArgs, result *[]*swapapi.SwapStatusChange) error {
+	txid, pairID, bind, err := args.getTxAndPairID()
+	if err != nil {
+		return err
+	}
+	res, err := swapapi.GetSwapinStatusHistory(txid, pairID, bind)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// GetSwapoutStatusHistory api
+func (s *RPCAPI) GetSwapoutStatusHistory(r *http.Request, args *RPCTxAndPairIDArgs, result *[]*swapapi.SwapStatusChange) error {
+	txid, pairID, bind, err := args.getTxAndPairID()
+	if err != nil {
+		return err
+	}
+	res, err := swapapi.GetSwapoutStatusHistory(txid, pairID, bind)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
 // RPCQueryHistoryArgs args
 type RPCQueryHistoryArgs struct {
 	Address string `json:"address"`
@@ -173,6 +199,80 @@ func (s *RPCAPI) GetSwapoutHistory(r *http.Request, args *RPCQueryHistoryArgs, r
 	return err
 }
 
+// RPCQueryAggregateHistoryArgs args
+type RPCQueryAggregateHistoryArgs struct {
+	PairID string `json:"pairid"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+}
+
+// GetAggregateHistory api
+func (s *RPCAPI) GetAggregateHistory(r *http.Request, args *RPCQueryAggregateHistoryArgs, result *[]*swapapi.AggregateInfo) error {
+	res, err := swapapi.GetAggregateHistory(args.PairID, args.Offset, args.Limit)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// RPCQueryFeeLedgerArgs args
+type RPCQueryFeeLedgerArgs struct {
+	PairID string `json:"pairid"`
+	From   int64  `json:"from"`
+	To     int64  `json:"to"`
+}
+
+// GetFeeLedger api
+func (s *RPCAPI) GetFeeLedger(r *http.Request, args *RPCQueryFeeLedgerArgs, result *swapapi.FeeLedger) error {
+	res, err := swapapi.GetFeeLedger(args.PairID, args.From, args.To)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// RPCQueryFeeLedgerEntriesArgs args
+type RPCQueryFeeLedgerEntriesArgs struct {
+	PairID string `json:"pairid"`
+	Kind   string `json:"kind"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+}
+
+// GetFeeLedgerEntries api
+func (s *RPCAPI) GetFeeLedgerEntries(r *http.Request, args *RPCQueryFeeLedgerEntriesArgs, result *[]*swapapi.FeeLedgerEntry) error {
+	res, err := swapapi.GetFeeLedgerEntries(args.PairID, args.Kind, args.Offset, args.Limit)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
+// GetPairsConfigHash api
+func (s *RPCAPI) GetPairsConfigHash(r *http.Request, args *RPCNullArgs, result *tokens.PairsConfigHash) error {
+	res, err := swapapi.GetPairsConfigHash()
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// RPCQueryPairConfigVersionsArgs args
+type RPCQueryPairConfigVersionsArgs struct {
+	PairID string `json:"pairid"`
+	Offset int    `json:"offset"`
+	Limit  int    `json:"limit"`
+}
+
+// GetPairConfigVersions api
+func (s *RPCAPI) GetPairConfigVersions(r *http.Request, args *RPCQueryPairConfigVersionsArgs, result *[]*swapapi.PairConfigVersion) error {
+	res, err := swapapi.GetPairConfigVersions(args.PairID, args.Offset, args.Limit)
+	if err == nil && res != nil {
+		*result = res
+	}
+	return err
+}
+
 // Swapin api
 func (s *RPCAPI) Swapin(r *http.Request, args *RPCTxAndPairIDArgs, result *swapapi.PostResult) error {
 	txid, pairID, _, err := args.getTxAndPairID()
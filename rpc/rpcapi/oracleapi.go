@@ -0,0 +1,44 @@
+package rpcapi
+
+import (
+	"net/http"
+
+	"github.com/anyswap/CrossChain-Bridge/internal/swapapi"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+)
+
+// OracleAPI oracle rpc api handler
+type OracleAPI struct{}
+
+// GetVersionInfo api
+func (s *OracleAPI) GetVersionInfo(r *http.Request, args *RPCNullArgs, result *string) error {
+	version := params.VersionWithMeta
+	*result = version
+	return nil
+}
+
+// RPCSwapAttestationArgs attestation args
+type RPCSwapAttestationArgs struct {
+	Since  int64  `json:"since"`
+	PairID string `json:"pairid"`
+}
+
+// GetSwapAttestation api
+func (s *OracleAPI) GetSwapAttestation(r *http.Request, args *RPCSwapAttestationArgs, result *tools.SwapAttestation) error {
+	res, err := swapapi.GetSwapAttestation(args.Since, args.PairID)
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
+
+// GetPairsConfigHash api
+func (s *OracleAPI) GetPairsConfigHash(r *http.Request, args *RPCNullArgs, result *tokens.PairsConfigHash) error {
+	res, err := swapapi.GetPairsConfigHash()
+	if err == nil && res != nil {
+		*result = *res
+	}
+	return err
+}
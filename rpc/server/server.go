@@ -18,8 +18,15 @@ import (
 
 // StartAPIServer start api server
 func StartAPIServer() {
-	router := initRouter()
+	startAPIServer(initRouter())
+}
+
+// StartOracleAPIServer start oracle api server
+func StartOracleAPIServer() {
+	startAPIServer(initOracleRouter())
+}
 
+func startAPIServer(router *mux.Router) {
 	apiPort := params.GetAPIPort()
 	apiServer := params.GetConfig().APIServer
 	allowedOrigins := apiServer.AllowedOrigins
@@ -70,8 +77,13 @@ func initRouter() *mux.Router {
 	r.HandleFunc("/swapout/{pairid}/{txid}/raw", restapi.GetRawSwapoutHandler).Methods("GET")
 	r.HandleFunc("/swapin/{pairid}/{txid}/rawresult", restapi.GetRawSwapinResultHandler).Methods("GET")
 	r.HandleFunc("/swapout/{pairid}/{txid}/rawresult", restapi.GetRawSwapoutResultHandler).Methods("GET")
+	r.HandleFunc("/swapin/{pairid}/{txid}/statushistory", restapi.GetSwapinStatusHistoryHandler).Methods("GET")
+	r.HandleFunc("/swapout/{pairid}/{txid}/statushistory", restapi.GetSwapoutStatusHistoryHandler).Methods("GET")
 	r.HandleFunc("/swapin/history/{pairid}/{address}", restapi.SwapinHistoryHandler).Methods("GET")
 	r.HandleFunc("/swapout/history/{pairid}/{address}", restapi.SwapoutHistoryHandler).Methods("GET")
+	r.HandleFunc("/aggregate/history/{pairid}", restapi.AggregateHistoryHandler).Methods("GET")
+	r.HandleFunc("/fee/ledger/{pairid}", restapi.FeeLedgerHandler).Methods("GET")
+	r.HandleFunc("/fee/entries/{pairid}", restapi.FeeLedgerEntriesHandler).Methods("GET")
 	r.HandleFunc("/p2sh/{address}", restapi.GetP2shAddressInfo).Methods("GET", "POST")
 	r.HandleFunc("/p2sh/bind/{address}", restapi.RegisterP2shAddress).Methods("GET", "POST")
 	r.HandleFunc("/registered/{address}", restapi.GetRegisteredAddress).Methods("GET", "POST")
@@ -95,8 +107,13 @@ func initRouter() *mux.Router {
 	r.HandleFunc("/swapout/{pairid}/{txid}/raw", warnHandler).Methods(methodsExcluesGet...)
 	r.HandleFunc("/swapin/{pairid}/{txid}/rawresult", warnHandler).Methods(methodsExcluesGet...)
 	r.HandleFunc("/swapout/{pairid}/{txid}/rawresult", warnHandler).Methods(methodsExcluesGet...)
+	r.HandleFunc("/swapin/{pairid}/{txid}/statushistory", warnHandler).Methods(methodsExcluesGet...)
+	r.HandleFunc("/swapout/{pairid}/{txid}/statushistory", warnHandler).Methods(methodsExcluesGet...)
 	r.HandleFunc("/swapin/history/{pairid}/{address}", warnHandler).Methods(methodsExcluesGet...)
 	r.HandleFunc("/swapout/history/{pairid}/{address}", warnHandler).Methods(methodsExcluesGet...)
+	r.HandleFunc("/aggregate/history/{pairid}", warnHandler).Methods(methodsExcluesGet...)
+	r.HandleFunc("/fee/ledger/{pairid}", warnHandler).Methods(methodsExcluesGet...)
+	r.HandleFunc("/fee/entries/{pairid}", warnHandler).Methods(methodsExcluesGet...)
 	r.HandleFunc("/p2sh/{address}", warnHandler).Methods(methodsExcluesGetAndPost...)
 	r.HandleFunc("/p2sh/bind/{address}", warnHandler).Methods(methodsExcluesGetAndPost...)
 	r.HandleFunc("/registered/{address}", warnHandler).Methods(methodsExcluesGetAndPost...)
@@ -105,6 +122,25 @@ func initRouter() *mux.Router {
 	return r
 }
 
+func initOracleRouter() *mux.Router {
+	r := mux.NewRouter()
+
+	rpcserver := rpc.NewServer()
+	rpcserver.RegisterCodec(rpcjson.NewCodec(), "application/json")
+	_ = rpcserver.RegisterService(new(rpcapi.OracleAPI), "oracle")
+
+	r.Handle("/rpc", rpcserver)
+	r.HandleFunc("/versioninfo", restapi.VersionInfoHandler).Methods("GET")
+	r.HandleFunc("/attestation", restapi.SwapAttestationHandler).Methods("GET")
+
+	methodsExcluesGet := []string{"POST", "HEAD", "PUT", "DELETE", "CONNECT", "OPTIONS", "TRACE", "PATCH"}
+
+	r.HandleFunc("/versioninfo", warnHandler).Methods(methodsExcluesGet...)
+	r.HandleFunc("/attestation", warnHandler).Methods(methodsExcluesGet...)
+
+	return r
+}
+
 func warnHandler(w http.ResponseWriter, r *http.Request) {
 	fmt.Fprintf(w, "Forbid '%v' on '%v'\n", r.Method, r.RequestURI)
 }
@@ -10,11 +10,13 @@ const (
 	LockMemoPrefix   = "SWAPTO:"
 	UnlockMemoPrefix = "SWAPTX:"
 	AggregateMemo    = "aggregate"
+	FeeWithdrawMemo  = "feewithdraw"
 )
 
 // common variables
 var (
-	AggregateIdentifier = "aggregate"
+	AggregateIdentifier   = "aggregate"
+	FeeWithdrawIdentifier = "feewithdraw"
 
 	SrcBridge CrossChainBridge
 	DstBridge CrossChainBridge
@@ -74,6 +76,19 @@ func (b *CrossChainBridgeBase) GetDcrmPublicKey(pairID string) string {
 	return ""
 }
 
+// SignRawTransaction sign raw tx with the signer of dcrm address
+// configed in token config, and sign through dcrm if not configed
+func SignRawTransaction(bridge CrossChainBridge, rawTx interface{}, args *BuildTxArgs) (signedTx interface{}, txHash string, err error) {
+	tokenCfg := bridge.GetTokenConfig(args.PairID)
+	if tokenCfg == nil {
+		return nil, "", ErrUnknownPairID
+	}
+	if tokenCfg.GetDcrmAddressSigner() != nil {
+		return bridge.SignTransaction(rawTx, args.PairID)
+	}
+	return bridge.DcrmSignTransaction(rawTx, args.GetExtraArgs())
+}
+
 // GetCrossChainBridge get bridge of specified endpoint
 func GetCrossChainBridge(isSrc bool) CrossChainBridge {
 	if isSrc {
@@ -110,7 +125,9 @@ func GetBigValueThreshold(pairID string, isSrc bool) *big.Int {
 	return token.bigValThreshhold
 }
 
-// CheckSwapValue check swap value is in right range
+// CheckSwapValue check swap value is in right range.
+// deposit is checked with static swap fee, as the verify result is recorded,
+// the dynamic swap fee is applied when the swap is processed.
 func CheckSwapValue(pairID string, value *big.Int, isSrc bool) bool {
 	token := GetTokenConfig(pairID, isSrc)
 	if value.Cmp(token.minSwap) < 0 {
@@ -119,28 +136,47 @@ func CheckSwapValue(pairID string, value *big.Int, isSrc bool) bool {
 	if value.Cmp(token.maxSwap) > 0 {
 		return false
 	}
-	swappedValue := CalcSwappedValue(pairID, value, isSrc)
+	swappedValue := subSwapFee(value, token.calcSwapFee(value, nil))
 	return swappedValue.Sign() > 0
 }
 
+// CalcSwapFee calc current swap fee, minimum swap fee is raised by fee policy if configed
+func CalcSwapFee(pairID string, value *big.Int, isSrc bool) *big.Int {
+	token := GetTokenConfig(pairID, isSrc)
+	dynamicMinFee, _ := GetDynamicMinSwapFee(pairID, isSrc)
+	return token.calcSwapFee(value, dynamicMinFee)
+}
+
 // CalcSwappedValue calc swapped value (get rid of fee)
 func CalcSwappedValue(pairID string, value *big.Int, isSrc bool) *big.Int {
+	return subSwapFee(value, CalcSwapFee(pairID, value, isSrc))
+}
+
+// CalcSwappedValueWithFee calc swapped value with the snapshotted swap fee,
+// which is bounded by the static swap fee and maximum swap fee,
+// so swap tx rebuilt by oracles is the same without knowing current network fee.
+// oracles verify the snapshot with VerifySwapFeeSnapshot before agreeing to sign.
+func CalcSwappedValueWithFee(pairID string, value, swapFee *big.Int, isSrc bool) *big.Int {
 	token := GetTokenConfig(pairID, isSrc)
+	return subSwapFee(value, token.getSnapshotSwapFee(value, swapFee))
+}
 
-	if *token.SwapFeeRate == 0.0 {
-		return value
+// getSnapshotSwapFee get swap fee charged with the snapshotted swap fee
+func (c *TokenConfig) getSnapshotSwapFee(value, swapFee *big.Int) *big.Int {
+	staticFee := c.calcSwapFee(value, nil)
+	switch {
+	case swapFee == nil || *c.SwapFeeRate == 0.0 || swapFee.Cmp(staticFee) < 0:
+		return staticFee
+	case swapFee.Cmp(c.maxSwapFee) > 0:
+		return c.maxSwapFee
 	}
+	return swapFee
+}
 
-	feeRateMul1e18 := new(big.Int).SetUint64(uint64(*token.SwapFeeRate * 1e18))
-	swapFee := new(big.Int).Mul(value, feeRateMul1e18)
-	swapFee.Div(swapFee, big.NewInt(1e18))
-
-	if swapFee.Cmp(token.minSwapFee) < 0 {
-		swapFee = token.minSwapFee
-	} else if swapFee.Cmp(token.maxSwapFee) > 0 {
-		swapFee = token.maxSwapFee
+func subSwapFee(value, swapFee *big.Int) *big.Int {
+	if swapFee.Sign() == 0 {
+		return value
 	}
-
 	if value.Cmp(swapFee) > 0 {
 		return new(big.Int).Sub(value, swapFee)
 	}
@@ -0,0 +1,75 @@
+// Package bch implements the bitcoin cash bridge on the btc code path.
+package bch
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+)
+
+const (
+	netMainnet  = "mainnet"
+	netTestnet3 = "testnet3"
+)
+
+// MainNetParams bitcoin cash mainnet params (no segwit)
+var MainNetParams = chaincfg.Params{
+	Name:        netMainnet,
+	Net:         0xe8f3e1e3,
+	DefaultPort: "8333",
+
+	PubKeyHashAddrID: 0x00, // starts with 1
+	ScriptHashAddrID: 0x05, // starts with 3
+	PrivateKeyID:     0x80, // starts with 5 (uncompressed) or K (compressed)
+
+	HDPrivateKeyID: [4]byte{0x04, 0x88, 0xad, 0xe4}, // starts with xprv
+	HDPublicKeyID:  [4]byte{0x04, 0x88, 0xb2, 0x1e}, // starts with xpub
+
+	HDCoinType: 145,
+}
+
+// TestNetParams bitcoin cash testnet3 params (no segwit)
+var TestNetParams = chaincfg.Params{
+	Name:        netTestnet3,
+	Net:         0xf4f3e5f4,
+	DefaultPort: "18333",
+
+	PubKeyHashAddrID: 0x6f, // starts with m or n
+	ScriptHashAddrID: 0xc4, // starts with 2
+	PrivateKeyID:     0xef, // starts with 9 (uncompressed) or c (compressed)
+
+	HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94}, // starts with tprv
+	HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // starts with tpub
+
+	HDCoinType: 1,
+}
+
+// Descriptor bch chain descriptor.
+// addresses are in cashaddr format, signatures use SIGHASH_FORKID.
+var Descriptor = &btc.ChainDescriptor{
+	Name:   "BitcoinCash",
+	Symbol: "BCH",
+	Networks: map[string]*chaincfg.Params{
+		netMainnet:  &MainNetParams,
+		netTestnet3: &TestNetParams,
+	},
+	DefaultNetwork: netTestnet3,
+
+	AddressCodec: CashAddrCodec,
+
+	SigHashType: txscript.SigHashAll | btc.SigHashForkID,
+
+	MaxAmount: btcutil.MaxSatoshi,
+
+	MinRelayFee:       400,
+	MaxMinRelayFee:    100000,
+	MinRelayFeePerKb:  1000,
+	MaxRelayFeePerKb:  100000,
+	EstimateFeeBlocks: 6,
+}
+
+// NewCrossChainBridge new bch bridge
+func NewCrossChainBridge(isSrc bool) *btc.Bridge {
+	return btc.NewUtxoChainBridge(isSrc, Descriptor)
+}
@@ -0,0 +1,187 @@
+package bch
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/bech32"
+)
+
+// cashaddr type bits of version byte
+const (
+	cashAddrTypeP2KH = 0
+	cashAddrTypeP2SH = 1
+)
+
+const cashAddrCharset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var cashAddrGenerators = [5]uint64{
+	0x98f2bc8e61,
+	0x79b76d99e2,
+	0xf33e5fb3c4,
+	0xae2eabe2a8,
+	0x1e4f43e470,
+}
+
+// errors
+var (
+	ErrCashAddrChecksum   = errors.New("cashaddr checksum mismatch")
+	ErrCashAddrPrefix     = errors.New("cashaddr prefix mismatch")
+	ErrCashAddrMixedCase  = errors.New("cashaddr has mixed case")
+	ErrCashAddrUnknownNet = errors.New("cashaddr unknown network")
+)
+
+// CashAddrCodec cashaddr codec, it also decodes legacy base58 addresses
+var CashAddrCodec btc.AddressCodec = &cashAddrCodec{}
+
+type cashAddrCodec struct{}
+
+func getCashAddrPrefix(params *chaincfg.Params) (string, error) {
+	switch params.Net {
+	case MainNetParams.Net:
+		return "bitcoincash", nil
+	case TestNetParams.Net:
+		return "bchtest", nil
+	default:
+		return "", ErrCashAddrUnknownNet
+	}
+}
+
+func (c *cashAddrCodec) DecodeAddress(addr string, params *chaincfg.Params) (btcutil.Address, error) {
+	prefix, err := getCashAddrPrefix(params)
+	if err != nil {
+		return nil, err
+	}
+	addrType, hash, err := decodeCashAddr(addr, prefix)
+	if err == nil {
+		switch addrType {
+		case cashAddrTypeP2KH:
+			return btcutil.NewAddressPubKeyHash(hash, params)
+		case cashAddrTypeP2SH:
+			return btcutil.NewAddressScriptHashFromHash(hash, params)
+		default:
+			return nil, fmt.Errorf("unsupported cashaddr type %v", addrType)
+		}
+	}
+	if strings.Contains(addr, ":") {
+		return nil, err
+	}
+	// legacy base58 address
+	address, errl := btc.Base58Codec.DecodeAddress(addr, params)
+	if errl != nil {
+		return nil, err
+	}
+	switch address.(type) {
+	case *btcutil.AddressPubKeyHash, *btcutil.AddressScriptHash:
+		return address, nil
+	default:
+		return nil, fmt.Errorf("unsupported address type %T", address)
+	}
+}
+
+func (c *cashAddrCodec) EncodeAddress(address btcutil.Address, params *chaincfg.Params) (string, error) {
+	prefix, err := getCashAddrPrefix(params)
+	if err != nil {
+		return "", err
+	}
+	switch address.(type) {
+	case *btcutil.AddressPubKeyHash:
+		return encodeCashAddr(prefix, cashAddrTypeP2KH, address.ScriptAddress())
+	case *btcutil.AddressScriptHash:
+		return encodeCashAddr(prefix, cashAddrTypeP2SH, address.ScriptAddress())
+	default:
+		return "", fmt.Errorf("unsupported address type %T", address)
+	}
+}
+
+func cashAddrPolymod(values []byte) uint64 {
+	c := uint64(1)
+	for _, d := range values {
+		c0 := byte(c >> 35)
+		c = ((c & 0x07ffffffff) << 5) ^ uint64(d)
+		for i, gen := range cashAddrGenerators {
+			if (c0>>uint(i))&1 == 1 {
+				c ^= gen
+			}
+		}
+	}
+	return c ^ 1
+}
+
+func expandCashAddrPrefix(prefix string) []byte {
+	result := make([]byte, len(prefix)+1)
+	for i := 0; i < len(prefix); i++ {
+		result[i] = prefix[i] & 0x1f
+	}
+	return result
+}
+
+func encodeCashAddr(prefix string, addrType byte, hash []byte) (string, error) {
+	if len(hash) != 20 {
+		return "", fmt.Errorf("unsupported cashaddr hash size %v", len(hash))
+	}
+	// version byte: type bits and size bits (0 for 160 bits hash)
+	payload, err := bech32.ConvertBits(append([]byte{addrType << 3}, hash...), 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+	values := append(expandCashAddrPrefix(prefix), payload...)
+	values = append(values, make([]byte, 8)...)
+	polymod := cashAddrPolymod(values)
+	for i := 0; i < 8; i++ {
+		payload = append(payload, byte((polymod>>uint(5*(7-i)))&0x1f))
+	}
+
+	var sb strings.Builder
+	sb.WriteString(prefix)
+	sb.WriteByte(':')
+	for _, v := range payload {
+		sb.WriteByte(cashAddrCharset[v])
+	}
+	return sb.String(), nil
+}
+
+func decodeCashAddr(addr, defaultPrefix string) (addrType byte, hash []byte, err error) {
+	lower := strings.ToLower(addr)
+	if lower != addr && strings.ToUpper(addr) != addr {
+		return 0, nil, ErrCashAddrMixedCase
+	}
+	prefix := defaultPrefix
+	data := lower
+	if pos := strings.LastIndexByte(lower, ':'); pos >= 0 {
+		prefix, data = lower[:pos], lower[pos+1:]
+		if prefix != defaultPrefix {
+			return 0, nil, ErrCashAddrPrefix
+		}
+	}
+	if len(data) <= 8 {
+		return 0, nil, errors.New("cashaddr is too short")
+	}
+	values := make([]byte, len(data))
+	for i := 0; i < len(data); i++ {
+		pos := strings.IndexByte(cashAddrCharset, data[i])
+		if pos < 0 {
+			return 0, nil, fmt.Errorf("cashaddr has invalid character '%c'", data[i])
+		}
+		values[i] = byte(pos)
+	}
+	if cashAddrPolymod(append(expandCashAddrPrefix(prefix), values...)) != 0 {
+		return 0, nil, ErrCashAddrChecksum
+	}
+	decoded, err := bech32.ConvertBits(values[:len(values)-8], 5, 8, false)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(decoded) != 21 {
+		return 0, nil, fmt.Errorf("unsupported cashaddr payload size %v", len(decoded))
+	}
+	version := decoded[0]
+	if version&0x87 != 0 {
+		return 0, nil, fmt.Errorf("unsupported cashaddr version byte %v", version)
+	}
+	return version >> 3, decoded[1:], nil
+}
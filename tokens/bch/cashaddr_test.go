@@ -0,0 +1,51 @@
+package bch
+
+import (
+	"testing"
+)
+
+var cashAddrTests = []struct {
+	legacy, cashaddr string
+}{
+	{"1BpEi6DfDAUFd7GtittLSdBeYJvcoaVggu", "bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvy22gdx6a"},
+	{"1KXrWXciRDZUpQwQmuM1DbwsKDLYAYsVLR", "bitcoincash:qr95sy3j9xwd2ap32xkykttr4cvcu7as4y0qverfuy"},
+	{"3CWFddi6m4ndiGyKqzYvsFYagqDLPVMTzC", "bitcoincash:ppm2qsznhks23z7629mms6s4cwef74vcwvn0h829pq"},
+}
+
+func TestCashAddrCodec(t *testing.T) {
+	for _, test := range cashAddrTests {
+		for _, addr := range []string{test.legacy, test.cashaddr, test.cashaddr[len("bitcoincash:"):]} {
+			address, err := CashAddrCodec.DecodeAddress(addr, &MainNetParams)
+			if err != nil {
+				t.Errorf("decode %v failed, %v", addr, err)
+				continue
+			}
+			if address.EncodeAddress() != test.legacy {
+				t.Errorf("decode %v mismatch, have %v want %v", addr, address.EncodeAddress(), test.legacy)
+			}
+			encoded, err := CashAddrCodec.EncodeAddress(address, &MainNetParams)
+			if err != nil {
+				t.Errorf("encode %v failed, %v", addr, err)
+				continue
+			}
+			if encoded != test.cashaddr {
+				t.Errorf("encode %v mismatch, have %v want %v", addr, encoded, test.cashaddr)
+			}
+		}
+	}
+}
+
+func TestCashAddrDecodeErrors(t *testing.T) {
+	valid := cashAddrTests[0].cashaddr
+	invalids := []string{
+		valid[:len(valid)-1] + "q",                               // wrong checksum
+		"bchtest:" + valid[len("bitcoincash:"):],                 // wrong prefix
+		"bitcoincash:qpm2qsznhks23z7629mms6s4cwef74vcwvY22gdx6a", // mixed case
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4",             // segwit
+	}
+	for _, addr := range invalids {
+		if _, err := CashAddrCodec.DecodeAddress(addr, &MainNetParams); err == nil {
+			t.Errorf("decode invalid address %v should fail", addr)
+		}
+	}
+}
@@ -0,0 +1,94 @@
+package bch
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"math/big"
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+// unsigned tx spending two outputs of pubkey hash 1d0f172a0ecb48aee1be1f2687d2963ae33f71a1
+const (
+	forkIDTestTxHex    = "0100000002fff7f7881a8099afa6940d42d1e7f6362bec38171ea3edf433541db4e4ad969f0000000000eeffffffef51e1b804cc89d182d279655c3aa89e815b1e9b2eccca1d2e6b7e0d3b0e5b1a0100000000ffffffff02202cb206000000001976a9148280b37df378db99f66f85c95a783a76ac7a6d5988ac9093510d000000001976a9143bde42dbee7e4dbe6a21b2d50ce2f0167faa815988ac11000000"
+	forkIDTestPkScript = "76a9141d0f172a0ecb48aee1be1f2687d2963ae33f71a188ac"
+	forkIDTestAmount   = 600000000
+)
+
+// SIGHASH_ALL|SIGHASH_FORKID digests of the test tx inputs
+var forkIDSigHashTests = []string{
+	"aac00d806666dde81b0acb1505b0c34a9ce083aaebd1e44a5b9ce5d0ff4a5622",
+	"db312a1eb4f0adecd6930f7247d572be227394c398662a25b5394aff0bcd2fcf",
+}
+
+func writeOutPoint(buf *bytes.Buffer, op *wire.OutPoint) {
+	buf.Write(op.Hash[:])
+	_ = binary.Write(buf, binary.LittleEndian, op.Index)
+}
+
+// calcForkIDSigHash calc sighash of SIGHASH_ALL with the digest algorithm
+// of the bitcoin cash replay protected sighash spec (BIP143 serialization)
+func calcForkIDSigHash(tx *wire.MsgTx, idx int, pkScript []byte, amount int64, hashType uint32) []byte {
+	var prevouts, sequences, outputs bytes.Buffer
+	for _, txIn := range tx.TxIn {
+		writeOutPoint(&prevouts, &txIn.PreviousOutPoint)
+		_ = binary.Write(&sequences, binary.LittleEndian, txIn.Sequence)
+	}
+	for _, txOut := range tx.TxOut {
+		_ = wire.WriteTxOut(&outputs, 0, 0, txOut)
+	}
+
+	var preimage bytes.Buffer
+	_ = binary.Write(&preimage, binary.LittleEndian, tx.Version)
+	preimage.Write(chainhash.DoubleHashB(prevouts.Bytes()))
+	preimage.Write(chainhash.DoubleHashB(sequences.Bytes()))
+	writeOutPoint(&preimage, &tx.TxIn[idx].PreviousOutPoint)
+	_ = wire.WriteVarBytes(&preimage, 0, pkScript)
+	_ = binary.Write(&preimage, binary.LittleEndian, amount)
+	_ = binary.Write(&preimage, binary.LittleEndian, tx.TxIn[idx].Sequence)
+	preimage.Write(chainhash.DoubleHashB(outputs.Bytes()))
+	_ = binary.Write(&preimage, binary.LittleEndian, tx.LockTime)
+	_ = binary.Write(&preimage, binary.LittleEndian, hashType)
+	return chainhash.DoubleHashB(preimage.Bytes())
+}
+
+func TestCalcSignatureHash(t *testing.T) {
+	txData, _ := hex.DecodeString(forkIDTestTxHex)
+	tx := wire.NewMsgTx(wire.TxVersion)
+	if err := tx.Deserialize(bytes.NewReader(txData)); err != nil {
+		t.Fatal(err)
+	}
+	pkScript, _ := hex.DecodeString(forkIDTestPkScript)
+
+	b := NewCrossChainBridge(true)
+	for i, want := range forkIDSigHashTests {
+		ref := calcForkIDSigHash(tx, i, pkScript, forkIDTestAmount, uint32(Descriptor.SigHashType))
+		if hex.EncodeToString(ref) != want {
+			t.Fatalf("reference sighash of input %v mismatch, have %x want %v", i, ref, want)
+		}
+		sigHash, err := b.CalcSignatureHash(pkScript, tx, i, forkIDTestAmount)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(sigHash) != want {
+			t.Errorf("sighash of input %v mismatch, have %x want %v", i, sigHash, want)
+		}
+		// forkid sighash commits to the amount of the spent output
+		sigHash, err = b.CalcSignatureHash(pkScript, tx, i, forkIDTestAmount+1)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if hex.EncodeToString(sigHash) == want {
+			t.Errorf("sighash of input %v should change with the spent amount", i)
+		}
+	}
+
+	// signature is suffixed with SIGHASH_ALL|SIGHASH_FORKID
+	sig := b.SerializeSignature(big.NewInt(1), big.NewInt(1))
+	if sig[len(sig)-1] != 0x41 {
+		t.Fatalf("signature hash type mismatch, have %#x want %#x", sig[len(sig)-1], 0x41)
+	}
+}
@@ -0,0 +1,379 @@
+package block
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+	"github.com/btcsuite/btcd/btcjson"
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/rpcclient"
+)
+
+const (
+	p2pkhType    = "p2pkh"
+	p2shType     = "p2sh"
+	opReturnType = "op_return"
+)
+
+var errNoCoreAPI = errors.New("blocknet gateway must config 'BlockExtra.CoreAPIs'")
+
+// CoreClient extends btcd rpcclient
+type CoreClient struct {
+	*rpcclient.Client
+	Address string
+}
+
+// coreAPI blocknet gateway api, it calls blocknet core rpc,
+// and calls cloudchains api to find utxos
+type coreAPI struct {
+	cclis            []CoreClient
+	utxoAPIAddresses []string
+}
+
+func newCoreAPI(b *btc.Bridge) btc.GatewayAPI {
+	api := &coreAPI{}
+	cfg := b.GetGatewayConfig()
+	if cfg == nil || cfg.Extras == nil || cfg.Extras.BlockExtra == nil {
+		return api
+	}
+	for _, args := range cfg.Extras.BlockExtra.CoreAPIs {
+		connCfg := &rpcclient.ConnConfig{
+			Host:         args.APIAddress,
+			User:         args.RPCUser,
+			Pass:         args.RPCPassword,
+			HTTPPostMode: true,            // Bitcoin core only supports HTTP POST mode
+			DisableTLS:   args.DisableTLS, // Bitcoin core does not provide TLS by default
+		}
+		client, err := rpcclient.New(connCfg, nil)
+		if err != nil {
+			log.Warn("new blocknet core rpc client failed", "address", args.APIAddress, "err", err)
+			continue
+		}
+		api.cclis = append(api.cclis, CoreClient{Client: client, Address: connCfg.Host})
+	}
+	api.utxoAPIAddresses = cfg.Extras.BlockExtra.UTXOAPIAddresses
+	return api
+}
+
+func joinErrors(errs []error) error {
+	if len(errs) == 0 {
+		return errNoCoreAPI
+	}
+	return fmt.Errorf("%+v", errs)
+}
+
+// GetLatestBlockNumberOf impl
+func (api *coreAPI) GetLatestBlockNumberOf(apiAddress string) (uint64, error) {
+	for _, ccli := range api.cclis {
+		if ccli.Address == apiAddress {
+			number, err := ccli.GetBlockCount()
+			return uint64(number), err
+		}
+	}
+	return 0, nil
+}
+
+// GetLatestBlockNumber impl
+func (api *coreAPI) GetLatestBlockNumber() (uint64, error) {
+	errs := make([]error, 0)
+	for _, ccli := range api.cclis {
+		number, err := ccli.GetBlockCount()
+		if err == nil {
+			return uint64(number), nil
+		}
+		errs = append(errs, err)
+	}
+	return 0, joinErrors(errs)
+}
+
+// GetTransactionByHash impl
+func (api *coreAPI) GetTransactionByHash(txHash string) (*electrs.ElectTx, error) {
+	hash, err := chainhash.NewHashFromStr(txHash)
+	if err != nil {
+		return nil, err
+	}
+	errs := make([]error, 0)
+	for _, ccli := range api.cclis {
+		tx, err := ccli.GetRawTransactionVerbose(hash)
+		if err == nil {
+			return ConvertTx(tx), nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, joinErrors(errs)
+}
+
+// GetElectTransactionStatus impl
+func (api *coreAPI) GetElectTransactionStatus(txHash string) (*electrs.ElectTxStatus, error) {
+	hash, err := chainhash.NewHashFromStr(txHash)
+	if err != nil {
+		return nil, err
+	}
+	errs := make([]error, 0)
+	for _, ccli := range api.cclis {
+		tx, err := ccli.GetRawTransactionVerbose(hash)
+		if err == nil {
+			txstatus := TxStatus(tx)
+			if blk, errf := api.GetBlock(tx.BlockHash); errf == nil {
+				*txstatus.BlockHeight = uint64(*blk.Height)
+			}
+			return txstatus, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, joinErrors(errs)
+}
+
+// FindUtxos impl (call cloudchains api)
+func (api *coreAPI) FindUtxos(addr string) (utxos []*electrs.ElectUtxo, err error) {
+	currentHeight, err := api.GetLatestBlockNumber()
+	if err != nil {
+		return nil, err
+	}
+
+	errs := make([]error, 0)
+	for _, url := range api.utxoAPIAddresses {
+		res := struct {
+			Utxos []CloudchainUtxo `json:"utxos"`
+		}{}
+
+		reqdata := fmt.Sprintf(`{ "version": 2.0, "id": "lalala", "method": "getutxos", "params": [ "BLOCK", "[\"%s\"]" ] }`, addr)
+		err = callCloudchains(url, reqdata, &res)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, cutxo := range res.Utxos {
+			blockNumber := cutxo.BlockNumber
+			confirmed := currentHeight-blockNumber > 6
+			status := &electrs.ElectTxStatus{
+				Confirmed:   &confirmed,
+				BlockHeight: &blockNumber,
+			}
+			if blkhash, errf := api.GetBlockHash(blockNumber); errf == nil {
+				status.BlockHash = &blkhash
+				if blk, errf := api.GetBlock(blkhash); errf == nil {
+					blockTime := uint64(*blk.Timestamp)
+					status.BlockTime = &blockTime
+				}
+			}
+
+			txid := cutxo.Txhash
+			vout := cutxo.Vout
+			value := uint64(cutxo.Value * 1e8)
+			utxos = append(utxos, &electrs.ElectUtxo{
+				Txid:   &txid,
+				Vout:   &vout,
+				Value:  &value,
+				Status: status,
+			})
+		}
+		sort.Sort(electrs.SortableElectUtxoSlice(utxos))
+		return utxos, nil
+	}
+	return nil, fmt.Errorf("%+v", errs)
+}
+
+// callCloudchains
+func callCloudchains(url, reqdata string, result interface{}) error {
+	client := &http.Client{}
+	var data = strings.NewReader(reqdata)
+	req, err := http.NewRequest("POST", url, data)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	bodyText, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(bodyText, &result)
+}
+
+// CloudchainUtxo struct
+type CloudchainUtxo struct {
+	Address     string  `json:"address"`
+	Txhash      string  `json:"Txhash"`
+	Vout        uint32  `json:"Vout"`
+	BlockNumber uint64  `json:"block_number"`
+	Value       float64 `json:"value"`
+}
+
+// GetPoolTxidList impl
+func (api *coreAPI) GetPoolTxidList() ([]string, error) {
+	errs := make([]error, 0)
+	for _, ccli := range api.cclis {
+		hashes, err := ccli.GetRawMempool()
+		if err == nil {
+			txids := make([]string, 0, len(hashes))
+			for _, hash := range hashes {
+				txids = append(txids, hash.String())
+			}
+			return txids, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, joinErrors(errs)
+}
+
+// GetPoolTransactions impl (returns all txs in pool)
+func (api *coreAPI) GetPoolTransactions(addr string) (txs []*electrs.ElectTx, err error) {
+	txids, err := api.GetPoolTxidList()
+	if err != nil {
+		return nil, err
+	}
+	errs := make([]error, 0)
+	for _, txid := range txids {
+		tx, errf := api.GetTransactionByHash(txid)
+		if errf != nil {
+			errs = append(errs, errf)
+			continue
+		}
+		txs = append(txs, tx)
+	}
+	if len(errs) > 0 {
+		err = fmt.Errorf("%+v", errs)
+	}
+	return txs, err
+}
+
+// GetTransactionHistory impl (not supported by blocknet core)
+func (api *coreAPI) GetTransactionHistory(addr, lastSeenTxid string) ([]*electrs.ElectTx, error) {
+	return nil, nil
+}
+
+// GetOutspend impl
+// Only to find out if txout is spent, does not tell in which transactions it is spent.
+func (api *coreAPI) GetOutspend(txHash string, vout uint32) (*electrs.ElectOutspend, error) {
+	hash, err := chainhash.NewHashFromStr(txHash)
+	if err != nil {
+		return nil, err
+	}
+	errs := make([]error, 0)
+	for _, ccli := range api.cclis {
+		txout, err := ccli.GetTxOut(hash, vout, true)
+		if err == nil {
+			return TxOutspend(txout), nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, joinErrors(errs)
+}
+
+// PostTransaction impl
+func (api *coreAPI) PostTransaction(txHex string) (txHash string, err error) {
+	msgtx := DecodeTxHex(txHex, 0, false)
+	if msgtx == nil {
+		return "", fmt.Errorf("wrong tx hex %v", txHex)
+	}
+	errs := make([]error, 0)
+	var success bool
+	for _, ccli := range api.cclis {
+		hash, err := ccli.SendRawTransaction(msgtx, true)
+		if err == nil && !success {
+			success = true
+			txHash = hash.String()
+		}
+		errs = append(errs, err)
+	}
+	if success {
+		return txHash, nil
+	}
+	return "", joinErrors(errs)
+}
+
+// GetBlockHash impl
+func (api *coreAPI) GetBlockHash(height uint64) (string, error) {
+	errs := make([]error, 0)
+	for _, ccli := range api.cclis {
+		bh, err := ccli.GetBlockHash(int64(height))
+		if err == nil {
+			return bh.String(), nil
+		}
+		errs = append(errs, err)
+	}
+	return "", joinErrors(errs)
+}
+
+func (api *coreAPI) getBlockVerbose(blockHash string) (*btcjson.GetBlockVerboseResult, error) {
+	hash, err := chainhash.NewHashFromStr(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	errs := make([]error, 0)
+	for _, ccli := range api.cclis {
+		block, err := ccli.GetBlockVerbose(hash)
+		if err == nil {
+			return block, nil
+		}
+		errs = append(errs, err)
+	}
+	return nil, joinErrors(errs)
+}
+
+// GetBlockTxids impl
+func (api *coreAPI) GetBlockTxids(blockHash string) ([]string, error) {
+	block, err := api.getBlockVerbose(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	return block.Tx, nil
+}
+
+// GetBlock impl
+func (api *coreAPI) GetBlock(blockHash string) (*electrs.ElectBlock, error) {
+	block, err := api.getBlockVerbose(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	return ConvertBlock(block), nil
+}
+
+// GetBlockTransactions impl (returns all txs from start index)
+func (api *coreAPI) GetBlockTransactions(blockHash string, startIndex uint32) (etxs []*electrs.ElectTx, err error) {
+	block, err := api.getBlockVerbose(blockHash)
+	if err != nil {
+		return nil, err
+	}
+	if int(startIndex) >= len(block.Tx) {
+		return nil, nil
+	}
+	for _, txid := range block.Tx[startIndex:] {
+		etx, errf := api.GetTransactionByHash(txid)
+		if errf != nil {
+			continue
+		}
+		etxs = append(etxs, etx)
+	}
+	return etxs, nil
+}
+
+// EstimateFeePerKb impl
+func (api *coreAPI) EstimateFeePerKb(blocks int) (int64, error) {
+	errs := make([]error, 0)
+	for _, ccli := range api.cclis {
+		res, err := ccli.Client.EstimateSmartFee(int64(blocks), &btcjson.EstimateModeEconomical)
+		if err == nil {
+			if len(res.Errors) > 0 {
+				errs = append(errs, fmt.Errorf("%+v", res.Errors))
+				continue
+			}
+			return int64(*res.FeeRate * 1e8), nil
+		}
+		errs = append(errs, err)
+	}
+	return 0, joinErrors(errs)
+}
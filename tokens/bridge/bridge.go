@@ -8,9 +8,11 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/params"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/bch"
 	"github.com/anyswap/CrossChain-Bridge/tokens/block"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
 	"github.com/anyswap/CrossChain-Bridge/tokens/cosmos"
+	"github.com/anyswap/CrossChain-Bridge/tokens/doge"
 	"github.com/anyswap/CrossChain-Bridge/tokens/etc"
 	"github.com/anyswap/CrossChain-Bridge/tokens/eth"
 	"github.com/anyswap/CrossChain-Bridge/tokens/fsn"
@@ -20,27 +22,39 @@ import (
 
 // NewCrossChainBridge new bridge according to chain name
 func NewCrossChainBridge(id string, isSrc bool) tokens.CrossChainBridge {
+	bridge, err := CreateCrossChainBridge(id, isSrc)
+	if err != nil {
+		log.Fatalf("%v", err)
+	}
+	return bridge
+}
+
+// CreateCrossChainBridge create bridge according to chain name
+func CreateCrossChainBridge(id string, isSrc bool) (tokens.CrossChainBridge, error) {
 	blockChainIden := strings.ToUpper(id)
 	switch {
+	case strings.HasPrefix(blockChainIden, "BITCOINCASH"):
+		return bch.NewCrossChainBridge(isSrc), nil
 	case strings.HasPrefix(blockChainIden, "BITCOIN"):
-		return btc.NewCrossChainBridge(isSrc)
+		return btc.NewCrossChainBridge(isSrc), nil
 	case strings.HasPrefix(blockChainIden, "LITECOIN"):
-		return ltc.NewCrossChainBridge(isSrc)
+		return ltc.NewCrossChainBridge(isSrc), nil
+	case strings.HasPrefix(blockChainIden, "DOGECOIN"):
+		return doge.NewCrossChainBridge(isSrc), nil
 	case strings.HasPrefix(blockChainIden, "BLOCK"):
-		return block.NewCrossChainBridge(isSrc)
+		return block.NewCrossChainBridge(isSrc), nil
 	case strings.HasPrefix(blockChainIden, "ETHCLASSIC"):
-		return etc.NewCrossChainBridge(isSrc)
+		return etc.NewCrossChainBridge(isSrc), nil
 	case strings.HasPrefix(blockChainIden, "ETHEREUM"):
-		return eth.NewCrossChainBridge(isSrc)
+		return eth.NewCrossChainBridge(isSrc), nil
 	case strings.HasPrefix(blockChainIden, "FUSION"):
-		return fsn.NewCrossChainBridge(isSrc)
+		return fsn.NewCrossChainBridge(isSrc), nil
 	case strings.HasPrefix(blockChainIden, "COSMOS"):
-		return cosmos.NewCrossChainBridge(isSrc)
+		return cosmos.NewCrossChainBridge(isSrc), nil
 	case strings.HasPrefix(blockChainIden, "TERRA"):
-		return terra.NewCrossChainBridge(isSrc)
+		return terra.NewCrossChainBridge(isSrc), nil
 	default:
-		log.Fatalf("Unsupported block chain %v", id)
-		return nil
+		return nil, fmt.Errorf("Unsupported block chain %v", id)
 	}
 }
 
@@ -58,15 +72,16 @@ func InitCrossChainBridge(isServer bool) {
 	dstNet := dstChain.NetID
 
 	tokens.AggregateIdentifier = fmt.Sprintf("%s:%s", params.GetIdentifier(), tokens.AggregateIdentifier)
+	tokens.FeeWithdrawIdentifier = fmt.Sprintf("%s:%s", params.GetIdentifier(), tokens.FeeWithdrawIdentifier)
 
 	tokens.SrcBridge = NewCrossChainBridge(srcID, true)
 	tokens.DstBridge = NewCrossChainBridge(dstID, false)
 	log.Info("New bridge finished", "source", srcID, "sourceNet", srcNet, "dest", dstID, "destNet", dstNet)
 
 	BlockChain := strings.ToUpper(srcChain.BlockChain)
-	switch BlockChain {
-	case "COSMOS", "TERRA":
-		tokens.SrcBridge.(cosmos.CosmosBridgeInterface).BeforeConfig()
+	cosmosBridge := getCosmosBridge(BlockChain, strings.ToUpper(dstChain.BlockChain))
+	if cosmosBridge != nil {
+		cosmosBridge.BeforeConfig()
 	}
 
 	tokens.SrcBridge.SetChainAndGateway(srcChain, srcGateway)
@@ -76,20 +91,45 @@ func InitCrossChainBridge(isServer bool) {
 	log.Info("Init bridge destation", "dest", dstID, "gateway", dstGateway)
 
 	tokens.IsDcrmDisabled = cfg.Dcrm.Disable
+	if err := tokens.InitPriceFeed(cfg.PriceFeed); err != nil {
+		log.Fatal("init price feed failed", "err", err)
+	}
 	tokens.LoadTokenPairsConfig(true)
 
 	switch BlockChain {
-	case "BITCOIN":
-		btc.Init(cfg.BtcExtra)
-	case "LITECOIN":
-		ltc.Init(cfg.BtcExtra)
-	case "BLOCK":
-		block.Init(cfg.BtcExtra)
-	case "COSMOS", "TERRA":
-		tokens.SrcBridge.(cosmos.CosmosBridgeInterface).AfterConfig()
+	case "BITCOIN", "LITECOIN", "DOGECOIN", "BITCOINCASH", "BLOCK":
+		btc.Init(tokens.SrcBridge.(*btc.Bridge), cfg.BtcExtra)
+	}
+	if cosmosBridge != nil {
+		cosmosBridge.AfterConfig()
 	}
 
 	dcrm.Init(cfg.Dcrm, isServer)
 
 	log.Info("Init bridge success", "isServer", isServer, "dcrmEnabled", !cfg.Dcrm.Disable)
 }
+
+func isCosmosChain(blockChain string) bool {
+	switch blockChain {
+	case "COSMOS", "TERRA":
+		return true
+	default:
+		return false
+	}
+}
+
+// getCosmosBridge returns the cosmos bridge of either endpoint.
+// cosmos bridges share codec and fee getter, so only one endpoint can be cosmos chain.
+func getCosmosBridge(srcBlockChain, dstBlockChain string) cosmos.CosmosBridgeInterface {
+	isSrcCosmos := isCosmosChain(srcBlockChain)
+	isDstCosmos := isCosmosChain(dstBlockChain)
+	switch {
+	case isSrcCosmos && isDstCosmos:
+		log.Fatalf("Unsupported cosmos chain on both endpoints, source %v, dest %v", srcBlockChain, dstBlockChain)
+	case isSrcCosmos:
+		return tokens.SrcBridge.(cosmos.CosmosBridgeInterface)
+	case isDstCosmos:
+		return tokens.DstBridge.(cosmos.CosmosBridgeInterface)
+	}
+	return nil
+}
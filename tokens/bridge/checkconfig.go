@@ -0,0 +1,40 @@
+package bridge
+
+import (
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/cosmos"
+)
+
+// NewConfigCheckers new bridges of both endpoints to check config without
+// retrying or exiting, checker of unsupported endpoint is nil with error.
+func NewConfigCheckers(srcID, dstID string) (srcChecker, dstChecker tokens.ConfigChecker, srcErr, dstErr error) {
+	srcChecker, srcErr = newConfigChecker(srcID, true)
+	dstChecker, dstErr = newConfigChecker(dstID, false)
+
+	srcCosmos, isSrcCosmos := srcChecker.(cosmos.CosmosBridgeInterface)
+	dstCosmos, isDstCosmos := dstChecker.(cosmos.CosmosBridgeInterface)
+	switch {
+	case isSrcCosmos && isDstCosmos:
+		dstChecker, dstErr = nil, fmt.Errorf("unsupported cosmos chain on both endpoints, source %v, dest %v", srcID, dstID)
+		srcCosmos.BeforeConfig()
+	case isSrcCosmos:
+		srcCosmos.BeforeConfig()
+	case isDstCosmos:
+		dstCosmos.BeforeConfig()
+	}
+	return srcChecker, dstChecker, srcErr, dstErr
+}
+
+func newConfigChecker(id string, isSrc bool) (tokens.ConfigChecker, error) {
+	bridge, err := CreateCrossChainBridge(id, isSrc)
+	if err != nil {
+		return nil, err
+	}
+	checker, ok := bridge.(tokens.ConfigChecker)
+	if !ok {
+		return nil, fmt.Errorf("config checking of block chain %v is not supported", id)
+	}
+	return checker, nil
+}
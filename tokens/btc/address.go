@@ -1,33 +1,37 @@
 package btc
 
 import (
-	"fmt"
-
 	"github.com/btcsuite/btcutil"
 )
 
 // DecodeAddress decode address
 func (b *Bridge) DecodeAddress(addr string) (address btcutil.Address, err error) {
-	chainConfig := b.Inherit.GetChainParams()
-	address, err = btcutil.DecodeAddress(addr, chainConfig)
+	return b.Chain.getAddressCodec().DecodeAddress(addr, b.GetChainParams())
+}
+
+// EncodeAddress encode address in chain format
+func (b *Bridge) EncodeAddress(address btcutil.Address) (string, error) {
+	return b.Chain.getAddressCodec().EncodeAddress(address, b.GetChainParams())
+}
+
+// IsCanonicalAddress is address valid and in chain format
+func (b *Bridge) IsCanonicalAddress(addr string) bool {
+	address, err := b.DecodeAddress(addr)
 	if err != nil {
-		return
-	}
-	if !address.IsForNet(chainConfig) {
-		err = fmt.Errorf("invalid address for net")
-		return
+		return false
 	}
-	return
+	encoded, err := b.EncodeAddress(address)
+	return err == nil && encoded == addr
 }
 
 // NewAddressPubKeyHash encap
 func (b *Bridge) NewAddressPubKeyHash(pkData []byte) (*btcutil.AddressPubKeyHash, error) {
-	return btcutil.NewAddressPubKeyHash(btcutil.Hash160(pkData), b.Inherit.GetChainParams())
+	return btcutil.NewAddressPubKeyHash(btcutil.Hash160(pkData), b.GetChainParams())
 }
 
 // NewAddressScriptHash encap
 func (b *Bridge) NewAddressScriptHash(redeemScript []byte) (*btcutil.AddressScriptHash, error) {
-	return btcutil.NewAddressScriptHash(redeemScript, b.Inherit.GetChainParams())
+	return btcutil.NewAddressScriptHash(redeemScript, b.GetChainParams())
 }
 
 // IsValidAddress check address
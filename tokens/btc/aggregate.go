@@ -5,6 +5,8 @@ import (
 
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+	"github.com/btcsuite/btcwallet/wallet/txrules"
+	"github.com/btcsuite/btcwallet/wallet/txsizes"
 )
 
 const (
@@ -13,30 +15,70 @@ const (
 
 // ShouldAggregate should aggregate
 func (b *Bridge) ShouldAggregate(aggUtxoCount int, aggSumVal uint64) bool {
-	if aggUtxoCount >= cfgUtxoAggregateMinCount {
+	if aggUtxoCount >= b.cfg.utxoAggregateMinCount {
 		return true
 	}
-	if aggSumVal >= cfgUtxoAggregateMinValue {
+	if aggSumVal >= b.cfg.utxoAggregateMinValue {
 		return true
 	}
 	return false
 }
 
-// AggregateUtxos aggregate uxtos
-func (b *Bridge) AggregateUtxos(addrs []string, utxos []*electrs.ElectUtxo) (string, error) {
-	relayFee, err := b.getRelayFeePerKb()
+// GetAggregateRelayFee get relay fee per kb of aggregate tx, and whether
+// it is in low fee window (not greater than configed AggregateMaxFeePerKb)
+func (b *Bridge) GetAggregateRelayFee() (relayFeePerKb int64, isLowFee bool, err error) {
+	relayFeePerKb, err = b.getRelayFeePerKb()
 	if err != nil {
-		return "", err
+		return 0, false, err
 	}
+	isLowFee = b.cfg.aggregateMaxFeePerKb == 0 || relayFeePerKb <= b.cfg.aggregateMaxFeePerKb
+	return relayFeePerKb, isLowFee, nil
+}
+
+// FindDustUtxos find available utxos of address not greater than configed
+// AggregateDustMaxValue, utxos not worth the fee of spending them are excluded
+func (b *Bridge) FindDustUtxos(addr string, relayFeePerKb int64) ([]*electrs.ElectUtxo, error) {
+	if b.cfg.aggregateDustMaxValue == 0 {
+		return nil, nil
+	}
+	utxoSet := b.getUtxoSet(addr)
+	err := b.refreshUtxoSet(utxoSet, false)
+	if err != nil {
+		return nil, err
+	}
+	inputFee := int64(txrules.FeeForSerializeSize(btcAmountType(relayFeePerKb), txsizes.RedeemP2PKHInputSize))
+	var dusts []*electrs.ElectUtxo
+	for _, utxo := range sortUtxosByValueDesc(utxoSet.Available()) {
+		if utxo.Value <= inputFee || uint64(utxo.Value) > b.cfg.aggregateDustMaxValue {
+			continue
+		}
+		txid, vout, value := utxo.Txid, utxo.Vout, uint64(utxo.Value)
+		dusts = append(dusts, &electrs.ElectUtxo{
+			Txid:  &txid,
+			Vout:  &vout,
+			Value: &value,
+		})
+	}
+	return dusts, nil
+}
 
+// BuildSignedAggregateTx build and sign aggregate tx, inputs in tracked utxo sets
+// are reserved until the tx is sent or the reservation is expired
+func (b *Bridge) BuildSignedAggregateTx(pairID string, relayFee int64, addrs []string, utxos []*electrs.ElectUtxo) (signedTx interface{}, txHash string, err error) {
 	authoredTx, err := b.BuildAggregateTransaction(relayFee, addrs, utxos)
 	if err != nil {
-		return "", err
+		return nil, "", err
+	}
+
+	owner := getAggregateReserveOwner(pairID, authoredTx.Tx)
+	err = b.reserveAggregateUtxos(owner, authoredTx.Tx.TxIn)
+	if err != nil {
+		return nil, "", err
 	}
 
 	args := &tokens.BuildTxArgs{
 		SwapInfo: tokens.SwapInfo{
-			PairID:     PairID,
+			PairID:     pairID,
 			Identifier: tokens.AggregateIdentifier,
 		},
 		Extra: &tokens.AllExtras{
@@ -55,22 +97,12 @@ func (b *Bridge) AggregateUtxos(addrs []string, utxos []*electrs.ElectUtxo) (str
 		}
 	}
 
-	var signedTx interface{}
-	var txHash string
-	tokenCfg := b.GetTokenConfig(PairID)
-	if tokenCfg.GetDcrmAddressPrivateKey() != nil {
-		signedTx, txHash, err = b.SignTransaction(authoredTx, PairID)
-	} else {
-		signedTx, txHash, err = b.DcrmSignTransaction(authoredTx, args.GetExtraArgs())
-	}
-	if err != nil {
-		return "", err
-	}
-	_, err = b.SendTransaction(signedTx)
+	signedTx, txHash, err = tokens.SignRawTransaction(b, authoredTx, args)
 	if err != nil {
-		return "", err
+		b.releaseUtxos(owner)
+		return nil, "", err
 	}
-	return txHash, nil
+	return signedTx, txHash, nil
 }
 
 // VerifyAggregateMsgHash verify aggregate msgHash
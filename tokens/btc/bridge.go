@@ -3,6 +3,7 @@ package btc
 import (
 	"fmt"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/log"
@@ -15,29 +16,39 @@ const (
 	netCustom   = "custom"
 )
 
-// PairID unique btc pair ID
-var PairID = "btc"
+var _ BridgeInterface = &Bridge{}
 
-// Bridge btc bridge
+// Bridge btc bridge, also the bridge of utxo chains described by chain descriptor
 type Bridge struct {
 	*tokens.CrossChainBridgeBase
-	Inherit Inheritable
+	Chain *ChainDescriptor
+
+	cfg        *extraConfig
+	p2shPairID string
+
+	apiLock sync.Mutex
+	api     GatewayAPI
+
+	utxoSetsLock sync.Mutex
+	utxoSets     map[string]*UtxoSet
 }
 
 // NewCrossChainBridge new btc bridge
 func NewCrossChainBridge(isSrc bool) *Bridge {
-	if !isSrc {
-		log.Fatalf("btc::NewCrossChainBridge error %v", tokens.ErrBridgeDestinationNotSupported)
-	}
-	instance := &Bridge{CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(isSrc)}
-	BridgeInstance = instance
-	instance.SetInherit(instance)
-	return instance
+	return NewUtxoChainBridge(isSrc, BitcoinDescriptor)
 }
 
-// SetInherit set inherit
-func (b *Bridge) SetInherit(inherit Inheritable) {
-	b.Inherit = inherit
+// NewUtxoChainBridge new bridge of utxo chain built on btc code path
+func NewUtxoChainBridge(isSrc bool, chain *ChainDescriptor) *Bridge {
+	if !isSrc {
+		log.Fatalf("%v::NewCrossChainBridge error %v", strings.ToLower(chain.Symbol), tokens.ErrBridgeDestinationNotSupported)
+	}
+	return &Bridge{
+		CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(isSrc),
+		Chain:                chain,
+		cfg:                  newExtraConfig(chain),
+		utxoSets:             make(map[string]*UtxoSet),
+	}
 }
 
 // SetChainAndGateway set chain and gateway config
@@ -49,15 +60,36 @@ func (b *Bridge) SetChainAndGateway(chainCfg *tokens.ChainConfig, gatewayCfg *to
 
 // VerifyChainConfig verify chain config
 func (b *Bridge) VerifyChainConfig() {
-	chainCfg := b.ChainConfig
-	networkID := strings.ToLower(chainCfg.NetID)
-	switch networkID {
-	case netMainnet, netTestnet3:
-	case netCustom:
-		return
-	default:
-		log.Fatal("unsupported bitcoin network", "netID", chainCfg.NetID)
+	if err := b.checkChainConfig(); err != nil {
+		log.Fatal("unsupported "+b.Chain.Name+" network", "netID", b.ChainConfig.NetID)
+	}
+}
+
+func (b *Bridge) checkChainConfig() error {
+	networkID := strings.ToLower(b.ChainConfig.NetID)
+	if networkID != netCustom && !b.Chain.IsSupportedNetwork(networkID) {
+		return fmt.Errorf("unsupported %v network %v", b.Chain.Name, b.ChainConfig.NetID)
 	}
+	return nil
+}
+
+// CheckChainAndGateway impl ConfigChecker
+func (b *Bridge) CheckChainAndGateway(chainCfg *tokens.ChainConfig, gatewayCfg *tokens.GatewayConfig, online bool) error {
+	b.CrossChainBridgeBase.SetChainAndGateway(chainCfg, gatewayCfg)
+	if err := b.checkChainConfig(); err != nil {
+		return err
+	}
+	if online {
+		if _, err := b.GetLatestBlockNumber(); err != nil {
+			return fmt.Errorf("get latest block number failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// CheckTokenConfig impl ConfigChecker
+func (b *Bridge) CheckTokenConfig(tokenCfg *tokens.TokenConfig, online bool) error {
+	return b.VerifyTokenConfig(tokenCfg)
 }
 
 // VerifyTokenConfig verify token config
@@ -68,8 +100,16 @@ func (b *Bridge) VerifyTokenConfig(tokenCfg *tokens.TokenConfig) error {
 	if !b.IsValidAddress(tokenCfg.DepositAddress) {
 		return fmt.Errorf("invalid deposit address: %v", tokenCfg.DepositAddress)
 	}
-	if strings.EqualFold(tokenCfg.Symbol, "BTC") && *tokenCfg.Decimals != 8 {
-		return fmt.Errorf("invalid decimals for BTC: want 8 but have %v", *tokenCfg.Decimals)
+	if tokenCfg.FeeTreasury != "" && !b.IsValidAddress(tokenCfg.FeeTreasury) {
+		return fmt.Errorf("invalid fee treasury: %v", tokenCfg.FeeTreasury)
+	}
+	for _, address := range []string{tokenCfg.DcrmAddress, tokenCfg.DepositAddress} {
+		if !b.IsCanonicalAddress(address) {
+			return fmt.Errorf("address %v is not in canonical format", address)
+		}
+	}
+	if strings.EqualFold(tokenCfg.Symbol, b.Chain.Symbol) && *tokenCfg.Decimals != 8 {
+		return fmt.Errorf("invalid decimals for %v: want 8 but have %v", b.Chain.Symbol, *tokenCfg.Decimals)
 	}
 	return nil
 }
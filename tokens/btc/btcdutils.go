@@ -4,8 +4,8 @@ import (
 	"crypto/ecdsa"
 	"fmt"
 	"math/big"
-	"strings"
 
+	"github.com/anyswap/CrossChain-Bridge/tools/signer"
 	"github.com/btcsuite/btcd/btcec"
 	"github.com/btcsuite/btcd/chaincfg"
 	"github.com/btcsuite/btcd/chaincfg/chainhash"
@@ -14,11 +14,6 @@ import (
 	"github.com/btcsuite/btcutil"
 )
 
-// Inheritable interface
-type Inheritable interface {
-	GetChainParams() *chaincfg.Params
-}
-
 type btcAmountType = btcutil.Amount
 type wireTxInType = wire.TxIn
 type wireTxOutType = wire.TxOut
@@ -27,23 +22,13 @@ func disasmScriptToString(pkScript []byte) (string, error) {
 	return txscript.DisasmString(pkScript)
 }
 
-func isValidValue(value btcAmountType) bool {
-	return value > 0 && value <= btcutil.MaxSatoshi
-}
-
-func newAmount(value float64) (btcAmountType, error) {
-	return btcutil.NewAmount(value)
+func (b *Bridge) isValidValue(value btcAmountType) bool {
+	return value > 0 && int64(value) <= b.Chain.MaxAmount
 }
 
 // GetChainParams get chain config (net params)
 func (b *Bridge) GetChainParams() *chaincfg.Params {
-	networkID := strings.ToLower(b.ChainConfig.NetID)
-	switch networkID {
-	case netMainnet:
-		return &chaincfg.MainNetParams
-	default:
-		return &chaincfg.TestNet3Params
-	}
+	return b.Chain.GetNetParams(b.ChainConfig.NetID)
 }
 
 // ParsePkScript parse pkScript
@@ -79,15 +64,19 @@ func (b *Bridge) IsPayToScriptHash(sigScript []byte) bool {
 	return txscript.IsPayToScriptHash(sigScript)
 }
 
-// CalcSignatureHash calc sig hash
-func (b *Bridge) CalcSignatureHash(sigScript []byte, tx *wire.MsgTx, i int) (sigHash []byte, err error) {
-	return txscript.CalcSignatureHash(sigScript, txscript.SigHashAll, tx, i)
+// CalcSignatureHash calc sig hash, forkid sighash commits to amount of the spent output
+func (b *Bridge) CalcSignatureHash(sigScript []byte, tx *wire.MsgTx, i int, amount int64) (sigHash []byte, err error) {
+	hashType := b.Chain.SigHashType
+	if b.Chain.IsForkIDSigHash() {
+		return txscript.CalcWitnessSigHash(sigScript, txscript.NewTxSigHashes(tx), hashType, tx, i, amount)
+	}
+	return txscript.CalcSignatureHash(sigScript, hashType, tx, i)
 }
 
 // SerializeSignature serialize signature
 func (b *Bridge) SerializeSignature(r, s *big.Int) []byte {
 	sign := &btcec.Signature{R: r, S: s}
-	return append(sign.Serialize(), byte(txscript.SigHashAll))
+	return append(sign.Serialize(), byte(b.Chain.SigHashType))
 }
 
 // GetSigScript get script
@@ -149,6 +138,23 @@ func (b *Bridge) SignWithECDSA(privKey *ecdsa.PrivateKey, msgHash []byte) (rsv s
 	return rsv, nil
 }
 
+// GetPublicKeyFromSigner get public key of signer
+func (b *Bridge) GetPublicKeyFromSigner(keySigner signer.Signer, compressed bool) []byte {
+	if compressed {
+		return (*btcec.PublicKey)(keySigner.PublicKey()).SerializeCompressed()
+	}
+	return (*btcec.PublicKey)(keySigner.PublicKey()).SerializeUncompressed()
+}
+
+// SignWithSigner sign with signer
+func (b *Bridge) SignWithSigner(keySigner signer.Signer, msgHash []byte) (rsv string, err error) {
+	signature, err := keySigner.SignHash(msgHash)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%X", signature), nil
+}
+
 // NewTxIn new txin
 func (b *Bridge) NewTxIn(txid string, vout uint32, pkScript []byte) (*wire.TxIn, error) {
 	txHash, err := chainhash.NewHashFromStr(txid)
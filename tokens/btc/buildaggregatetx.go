@@ -27,7 +27,7 @@ func (b *Bridge) BuildAggregateTransaction(relayFeePerKb int64, addrs []string,
 	}
 
 	changeSource := func() ([]byte, error) {
-		return b.GetPayToAddrScript(cfgUtxoAggregateToAddress)
+		return b.GetPayToAddrScript(b.cfg.utxoAggregateToAddress)
 	}
 
 	return b.NewUnsignedTransaction(txOuts, btcAmountType(relayFeePerKb), inputSource, changeSource, true)
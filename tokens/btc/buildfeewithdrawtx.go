@@ -0,0 +1,63 @@
+package btc
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// BuildFeeWithdrawTx build tx of withdrawing collected swapout fee (OriginValue)
+// from dcrm address to fee treasury, the selected utxos are reserved until sent
+func (b *Bridge) BuildFeeWithdrawTx(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	tokenCfg := b.GetTokenConfig(args.PairID)
+	if tokenCfg == nil {
+		return nil, tokens.ErrUnknownPairID
+	}
+	if tokenCfg.FeeTreasury == "" {
+		return nil, tokens.ErrNoFeeTreasury
+	}
+	if args.SwapType != tokens.SwapoutType {
+		return nil, tokens.ErrSwapTypeNotSupported
+	}
+	if args.Identifier != tokens.FeeWithdrawIdentifier {
+		return nil, errors.New("wrong fee withdraw identifier")
+	}
+	amount := args.OriginValue
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, errors.New("wrong fee withdraw amount")
+	}
+
+	args.From = tokenCfg.DcrmAddress
+	args.Bind = tokenCfg.FeeTreasury
+
+	// change back to dcrm address
+	if args.Extra != nil && args.Extra.BtcExtra != nil {
+		args.Extra.BtcExtra.ChangeAddress = nil
+	}
+
+	// build as non swap tx, as the value is not calculated from swap value
+	buildArgs := *args
+	buildArgs.SwapType = tokens.NoSwapType
+	buildArgs.To = tokenCfg.FeeTreasury
+	buildArgs.Value = amount
+	buildArgs.Memo = tokens.FeeWithdrawMemo
+	rawTx, err = b.BuildRawTransaction(&buildArgs)
+	if err != nil {
+		return nil, err
+	}
+	args.Extra = buildArgs.Extra
+	return rawTx, nil
+}
+
+// GetTxFee get network fee paid by tx
+func (b *Bridge) GetTxFee(txHash string) (*big.Int, error) {
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if tx.Fee == nil {
+		return nil, errors.New("tx without fee info")
+	}
+	return new(big.Int).SetUint64(*tx.Fee), nil
+}
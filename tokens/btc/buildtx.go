@@ -25,26 +25,45 @@ const (
 )
 
 func (b *Bridge) getRelayFeePerKb() (estimateFee int64, err error) {
+	if b.Chain.NoFeeEstimation {
+		estimateFee = b.cfg.minRelayFeePerKb
+	} else {
+		estimateFee, err = b.estimateFeePerKbWithRetry()
+		if err != nil {
+			return 0, err
+		}
+	}
+	if b.cfg.plusFeePercentage > 0 {
+		estimateFee += estimateFee * int64(b.cfg.plusFeePercentage) / 100
+	}
+	if estimateFee > b.cfg.maxRelayFeePerKb {
+		estimateFee = b.cfg.maxRelayFeePerKb
+	} else if estimateFee < b.cfg.minRelayFeePerKb {
+		estimateFee = b.cfg.minRelayFeePerKb
+	}
+	return estimateFee, nil
+}
+
+func (b *Bridge) estimateFeePerKbWithRetry() (estimateFee int64, err error) {
 	for i := 0; i < retryCount; i++ {
-		estimateFee, err = b.EstimateFeePerKb(cfgEstimateFeeBlocks)
+		estimateFee, err = b.EstimateFeePerKb(b.cfg.estimateFeeBlocks)
 		if err == nil {
-			break
+			return estimateFee, nil
 		}
 		time.Sleep(retryInterval)
 	}
+	log.Warn("estimate smart fee failed", "err", err)
+	return 0, err
+}
+
+// EstimateNetworkFee impl NetworkFeeEstimator (relay fee per kb * expected size)
+func (b *Bridge) EstimateNetworkFee(pairID string, expectedTxCost uint64) (*big.Int, error) {
+	relayFeePerKb, err := b.getRelayFeePerKb()
 	if err != nil {
-		log.Warn("estimate smart fee failed", "err", err)
-		return 0, err
-	}
-	if cfgPlusFeePercentage > 0 {
-		estimateFee += estimateFee * int64(cfgPlusFeePercentage) / 100
-	}
-	if estimateFee > cfgMaxRelayFeePerKb {
-		estimateFee = cfgMaxRelayFeePerKb
-	} else if estimateFee < cfgMinRelayFeePerKb {
-		estimateFee = cfgMinRelayFeePerKb
+		return nil, err
 	}
-	return estimateFee, nil
+	fee := new(big.Int).Mul(big.NewInt(relayFeePerKb), new(big.Int).SetUint64(expectedTxCost))
+	return fee.Div(fee, big.NewInt(1000)), nil
 }
 
 // BuildRawTransaction build raw tx
@@ -68,10 +87,10 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 	case tokens.SwapinType:
 		return nil, tokens.ErrSwapTypeNotSupported
 	case tokens.SwapoutType:
-		from = token.DcrmAddress                                          // from
-		to = args.Bind                                                    // to
-		changeAddress = token.DcrmAddress                                 // change
-		amount = tokens.CalcSwappedValue(pairID, args.OriginValue, false) // amount
+		from = token.DcrmAddress          // from
+		to = args.Bind                    // to
+		changeAddress = token.DcrmAddress // change
+		amount = args.GetSwapValue()      // amount
 		memo = tokens.UnlockMemoPrefix + args.SwapID
 	}
 
@@ -82,7 +101,10 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 	var extra *tokens.BtcExtraArgs
 	if args.Extra == nil || args.Extra.BtcExtra == nil {
 		extra = &tokens.BtcExtraArgs{}
-		args.Extra = &tokens.AllExtras{BtcExtra: extra}
+		if args.Extra == nil {
+			args.Extra = &tokens.AllExtras{}
+		}
+		args.Extra.BtcExtra = extra
 	} else {
 		extra = args.Extra.BtcExtra
 		if extra.ChangeAddress != nil && args.SwapType == tokens.NoSwapType {
@@ -106,22 +128,38 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		return nil, err
 	}
 
+	isSelectUtxos := len(extra.PreviousOutPoints) == 0
+
 	inputSource := func(target btcAmountType) (total btcAmountType, inputs []*wireTxInType, inputValues []btcAmountType, scripts [][]byte, err error) {
-		if len(extra.PreviousOutPoints) != 0 {
+		if !isSelectUtxos {
 			return b.getUtxos(from, target, extra.PreviousOutPoints)
 		}
-		return b.selectUtxos(from, target)
+		return b.selectUtxos(from, target, relayFeePerKb)
 	}
 
 	changeSource := func() ([]byte, error) {
 		return b.GetPayToAddrScript(changeAddress)
 	}
 
+	if isSelectUtxos {
+		utxoSet := b.getUtxoSet(from)
+		utxoSet.buildLock.Lock()
+		defer utxoSet.buildLock.Unlock()
+	}
+
 	authoredTx, err := b.NewUnsignedTransaction(txOuts, relayFeePerKb, inputSource, changeSource, false)
 	if err != nil {
 		return nil, err
 	}
 
+	// reserve the selected utxos until the swap tx is sent or failed
+	if owner := getUtxoReserveOwner(args); isSelectUtxos && owner != "" {
+		err = b.reserveUtxos(from, owner, authoredTx.Tx.TxIn)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	updateExtraInfo(extra, authoredTx.Tx.TxIn)
 
 	if args.SwapType != tokens.NoSwapType {
@@ -166,7 +204,7 @@ func (b *Bridge) BuildTransaction(from string, receivers []string, amounts []int
 	}
 
 	inputSource := func(target btcAmountType) (total btcAmountType, inputs []*wireTxInType, inputValues []btcAmountType, scripts [][]byte, err error) {
-		return b.selectUtxos(from, target)
+		return b.selectUtxos(from, target, btcAmountType(relayFeePerKb))
 	}
 
 	changeSource := func() ([]byte, error) {
@@ -251,61 +289,56 @@ func (b *Bridge) getOutspendWithRetry(point *tokens.BtcOutPoint) (outspend *elec
 	return outspend, err
 }
 
-func (b *Bridge) selectUtxos(from string, target btcAmountType) (total btcAmountType, inputs []*wireTxInType, inputValues []btcAmountType, scripts [][]byte, err error) {
-	p2pkhScript, err := b.GetPayToAddrScript(from)
-	if err != nil {
-		return 0, nil, nil, nil, err
+func (b *Bridge) getCoinSelectParams(target, relayFeePerKb btcAmountType) *coinSelectParams {
+	// plus one to avoid insufficient fee caused by rounding
+	inputFee := txrules.FeeForSerializeSize(relayFeePerKb, txsizes.RedeemP2PKHInputSize) + 1
+	// change less than dust threshold is dropped, so the excess is changeless
+	dustThreshold := txrules.GetDustThreshold(txsizes.P2PKHPkScriptSize, txrules.DefaultRelayFeePerKb)
+	return &coinSelectParams{
+		target:       int64(target),
+		inputFee:     int64(inputFee),
+		costOfChange: int64(dustThreshold) - 1,
+		consolidate:  int64(relayFeePerKb) <= b.cfg.consolidateFeePerKb,
+		maxInputs:    b.cfg.consolidateMaxInputs,
 	}
+}
 
-	utxos, err := b.findUxtosWithRetry(from)
+// selectUtxos select utxos from the local utxo set,
+// refresh the utxo set and select again if not enough balance.
+func (b *Bridge) selectUtxos(from string, target, relayFeePerKb btcAmountType) (total btcAmountType, inputs []*wireTxInType, inputValues []btcAmountType, scripts [][]byte, err error) {
+	p2pkhScript, err := b.GetPayToAddrScript(from)
 	if err != nil {
 		return 0, nil, nil, nil, err
 	}
 
-	var (
-		tx      *electrs.ElectTx
-		success bool
-	)
+	utxoSet := b.getUtxoSet(from)
+	params := b.getCoinSelectParams(target, relayFeePerKb)
 
-	for _, utxo := range utxos {
-		value := btcAmountType(*utxo.Value)
-		if !isValidValue(value) {
-			continue
-		}
-		tx, err = b.getTransactionByHashWithRetry(*utxo.Txid)
+	var selected []*Utxo
+	for i := 0; i < 2; i++ {
+		err = b.refreshUtxoSet(utxoSet, i > 0)
 		if err != nil {
-			continue
-		}
-		if *utxo.Vout >= uint32(len(tx.Vout)) {
-			continue
-		}
-		output := tx.Vout[*utxo.Vout]
-		if *output.ScriptpubkeyType != p2pkhType {
-			continue
+			return 0, nil, nil, nil, err
 		}
-		if output.ScriptpubkeyAddress == nil || *output.ScriptpubkeyAddress != from {
-			continue
+		selected, err = selectCoins(b.cfg.coinSelection, utxoSet.Available(), params)
+		if err == nil {
+			break
 		}
+	}
+	if err != nil {
+		return 0, nil, nil, nil, err
+	}
 
-		txIn, errf := b.NewTxIn(*utxo.Txid, *utxo.Vout, p2pkhScript)
+	for _, utxo := range selected {
+		txIn, errf := b.NewTxIn(utxo.Txid, utxo.Vout, p2pkhScript)
 		if errf != nil {
-			continue
+			return 0, nil, nil, nil, errf
 		}
-
+		value := btcAmountType(utxo.Value)
 		total += value
 		inputs = append(inputs, txIn)
 		inputValues = append(inputValues, value)
 		scripts = append(scripts, p2pkhScript)
-
-		if total >= target {
-			success = true
-			break
-		}
-	}
-
-	if !success {
-		err = fmt.Errorf("not enough balance, total %v < target %v", total, target)
-		return 0, nil, nil, nil, err
 	}
 
 	return total, inputs, inputValues, scripts, nil
@@ -398,8 +431,8 @@ func (b *Bridge) NewUnsignedTransaction(outputs []*wireTxOutType, relayFeePerKb
 
 		maxSignedSize := b.estimateSize(scripts, outputs, true, isAggregate)
 		maxRequiredFee := txrules.FeeForSerializeSize(relayFeePerKb, maxSignedSize)
-		if maxRequiredFee < btcAmountType(cfgMinRelayFee) {
-			maxRequiredFee = btcAmountType(cfgMinRelayFee)
+		if maxRequiredFee < btcAmountType(b.cfg.minRelayFee) {
+			maxRequiredFee = btcAmountType(b.cfg.minRelayFee)
 		}
 		remainingAmount := inputAmount - targetAmount
 		if remainingAmount < maxRequiredFee {
@@ -5,81 +5,99 @@ import (
 	"math/big"
 
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil"
 )
 
 // GetLatestBlockNumberOf impl
 func (b *Bridge) GetLatestBlockNumberOf(apiAddress string) (uint64, error) {
-	return electrs.GetLatestBlockNumberOf(apiAddress)
+	return b.getGatewayAPI().GetLatestBlockNumberOf(apiAddress)
 }
 
 // GetLatestBlockNumber impl
 func (b *Bridge) GetLatestBlockNumber() (uint64, error) {
-	return electrs.GetLatestBlockNumber(b)
+	return b.getGatewayAPI().GetLatestBlockNumber()
 }
 
 // GetTransactionByHash impl
 func (b *Bridge) GetTransactionByHash(txHash string) (*electrs.ElectTx, error) {
-	return electrs.GetTransactionByHash(b, txHash)
+	result, err := b.getGatewayAPI().GetTransactionByHash(txHash)
+	if err == nil {
+		b.fromAPITx(result)
+	}
+	return result, err
 }
 
 // GetElectTransactionStatus impl
 func (b *Bridge) GetElectTransactionStatus(txHash string) (*electrs.ElectTxStatus, error) {
-	return electrs.GetElectTransactionStatus(b, txHash)
+	return b.getGatewayAPI().GetElectTransactionStatus(txHash)
 }
 
 // FindUtxos impl
 func (b *Bridge) FindUtxos(addr string) ([]*electrs.ElectUtxo, error) {
-	return electrs.FindUtxos(b, addr)
+	return b.getGatewayAPI().FindUtxos(b.toAPIAddress(addr))
 }
 
 // GetPoolTxidList impl
 func (b *Bridge) GetPoolTxidList() ([]string, error) {
-	return electrs.GetPoolTxidList(b)
+	return b.getGatewayAPI().GetPoolTxidList()
 }
 
 // GetPoolTransactions impl
 func (b *Bridge) GetPoolTransactions(addr string) ([]*electrs.ElectTx, error) {
-	return electrs.GetPoolTransactions(b, addr)
+	results, err := b.getGatewayAPI().GetPoolTransactions(b.toAPIAddress(addr))
+	if err == nil {
+		b.fromAPITxs(results)
+	}
+	return results, err
 }
 
 // GetTransactionHistory impl
 func (b *Bridge) GetTransactionHistory(addr, lastSeenTxid string) ([]*electrs.ElectTx, error) {
-	return electrs.GetTransactionHistory(b, addr, lastSeenTxid)
+	results, err := b.getGatewayAPI().GetTransactionHistory(b.toAPIAddress(addr), lastSeenTxid)
+	if err == nil {
+		b.fromAPITxs(results)
+	}
+	return results, err
 }
 
 // GetOutspend impl
 func (b *Bridge) GetOutspend(txHash string, vout uint32) (*electrs.ElectOutspend, error) {
-	return electrs.GetOutspend(b, txHash, vout)
+	return b.getGatewayAPI().GetOutspend(txHash, vout)
 }
 
 // PostTransaction impl
 func (b *Bridge) PostTransaction(txHex string) (txHash string, err error) {
-	return electrs.PostTransaction(b, txHex)
+	return b.getGatewayAPI().PostTransaction(txHex)
 }
 
 // GetBlockHash impl
 func (b *Bridge) GetBlockHash(height uint64) (string, error) {
-	return electrs.GetBlockHash(b, height)
+	return b.getGatewayAPI().GetBlockHash(height)
 }
 
 // GetBlockTxids impl
 func (b *Bridge) GetBlockTxids(blockHash string) ([]string, error) {
-	return electrs.GetBlockTxids(b, blockHash)
+	return b.getGatewayAPI().GetBlockTxids(blockHash)
 }
 
 // GetBlock impl
 func (b *Bridge) GetBlock(blockHash string) (*electrs.ElectBlock, error) {
-	return electrs.GetBlock(b, blockHash)
+	return b.getGatewayAPI().GetBlock(blockHash)
 }
 
 // GetBlockTransactions impl
 func (b *Bridge) GetBlockTransactions(blockHash string, startIndex uint32) ([]*electrs.ElectTx, error) {
-	return electrs.GetBlockTransactions(b, blockHash, startIndex)
+	results, err := b.getGatewayAPI().GetBlockTransactions(blockHash, startIndex)
+	if err == nil {
+		b.fromAPITxs(results)
+	}
+	return results, err
 }
 
 // EstimateFeePerKb impl
 func (b *Bridge) EstimateFeePerKb(blocks int) (int64, error) {
-	return electrs.EstimateFeePerKb(b, blocks)
+	return b.getGatewayAPI().EstimateFeePerKb(blocks)
 }
 
 // GetBalance impl
@@ -104,3 +122,73 @@ func (b *Bridge) GetTokenBalance(tokenType, tokenAddress, accountAddress string)
 func (b *Bridge) GetTokenSupply(tokenType, tokenAddress string) (*big.Int, error) {
 	return nil, fmt.Errorf("[%v] can not get token supply of token with type '%v'", b.ChainConfig.BlockChain, tokenType)
 }
+
+func (b *Bridge) getAPINetParams() *chaincfg.Params {
+	if b.Chain.APINetParams != nil {
+		return b.Chain.APINetParams
+	}
+	return b.GetChainParams()
+}
+
+// toAPIAddress convert address to the format used by gateway api
+func (b *Bridge) toAPIAddress(addr string) string {
+	if !b.Chain.hasAPIAddressFormat() {
+		return addr
+	}
+	address, err := b.DecodeAddress(addr)
+	if err != nil {
+		return addr
+	}
+	apiAddr, err := b.Chain.getAPIAddressCodec().EncodeAddress(address, b.getAPINetParams())
+	if err != nil {
+		return addr
+	}
+	return apiAddr
+}
+
+// fromAPIAddress convert address from gateway api to chain format
+func (b *Bridge) fromAPIAddress(addr string) string {
+	var (
+		address btcutil.Address
+		err     error
+	)
+	if b.Chain.hasAPIAddressFormat() {
+		address, err = b.Chain.getAPIAddressCodec().DecodeAddress(addr, b.getAPINetParams())
+	} else {
+		address, err = b.DecodeAddress(addr)
+	}
+	if err != nil {
+		return addr
+	}
+	chainAddr, err := b.EncodeAddress(address)
+	if err != nil {
+		return addr
+	}
+	return chainAddr
+}
+
+func (b *Bridge) fromAPIVout(vout *electrs.ElectTxOut) {
+	if vout == nil || vout.ScriptpubkeyAddress == nil {
+		return
+	}
+	*vout.ScriptpubkeyAddress = b.fromAPIAddress(*vout.ScriptpubkeyAddress)
+}
+
+// fromAPITx convert addresses in tx from gateway api to chain format
+func (b *Bridge) fromAPITx(tx *electrs.ElectTx) {
+	if tx == nil || !b.Chain.needNormalizeAPIAddress() {
+		return
+	}
+	for _, vin := range tx.Vin {
+		b.fromAPIVout(vin.Prevout)
+	}
+	for _, vout := range tx.Vout {
+		b.fromAPIVout(vout)
+	}
+}
+
+func (b *Bridge) fromAPITxs(txs []*electrs.ElectTx) {
+	for _, tx := range txs {
+		b.fromAPITx(tx)
+	}
+}
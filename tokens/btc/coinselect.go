@@ -0,0 +1,224 @@
+package btc
+
+import (
+	"fmt"
+	"sort"
+)
+
+// coin selection strategies
+const (
+	CoinSelectBranchAndBound = "bnb"
+	CoinSelectLargestFirst   = "largestfirst"
+	CoinSelectConsolidate    = "consolidate"
+)
+
+// max tries of branch and bound search
+const bnbMaxTries = 100000
+
+// Utxo spendable output of tracked address
+type Utxo struct {
+	Txid  string
+	Vout  uint32
+	Value int64
+}
+
+// Key outpoint key of utxo
+func (u *Utxo) Key() string {
+	return getOutPointKey(u.Txid, u.Vout)
+}
+
+func getOutPointKey(txid string, vout uint32) string {
+	return fmt.Sprintf("%v:%v", txid, vout)
+}
+
+// coinSelectParams params of coin selection.
+// a selection of n inputs is enough if sum(value) - n*inputFee >= target - inputFee,
+// as target includes outputs value and fee of tx with one input and change output.
+type coinSelectParams struct {
+	target       int64 // outputs value plus fee of tx with one input and change output
+	inputFee     int64 // fee of per input
+	costOfChange int64 // excess not more than this is dropped as dust change
+	consolidate  bool  // whether fee is cheap enough to consolidate utxos
+	maxInputs    int   // max inputs count when consolidating
+}
+
+func (p *coinSelectParams) needed() int64 {
+	return p.target - p.inputFee
+}
+
+func (p *coinSelectParams) effectiveValue(utxo *Utxo) int64 {
+	return utxo.Value - p.inputFee
+}
+
+func isValidCoinSelection(strategy string) bool {
+	switch strategy {
+	case CoinSelectBranchAndBound, CoinSelectLargestFirst, CoinSelectConsolidate:
+		return true
+	default:
+		return false
+	}
+}
+
+// selectCoins select utxos with strategy, utxos with non-positive effective value are ignored
+func selectCoins(strategy string, utxos []*Utxo, params *coinSelectParams) (selected []*Utxo, err error) {
+	switch strategy {
+	case CoinSelectLargestFirst:
+		selected = selectCoinsLargestFirst(utxos, params)
+	case CoinSelectConsolidate:
+		if params.consolidate {
+			selected = selectCoinsConsolidate(utxos, params)
+		} else {
+			selected = selectCoinsBnBOrLargestFirst(utxos, params)
+		}
+	default:
+		selected = selectCoinsBnBOrLargestFirst(utxos, params)
+	}
+	if selected == nil {
+		var total int64
+		for _, utxo := range utxos {
+			total += utxo.Value
+		}
+		return nil, fmt.Errorf("not enough balance, total %v < target %v", total, params.target)
+	}
+	return selected, nil
+}
+
+// sortUtxosByValueDesc sort by value descending, then by key to be deterministic
+func sortUtxosByValueDesc(utxos []*Utxo) []*Utxo {
+	sorted := make([]*Utxo, len(utxos))
+	copy(sorted, utxos)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Value != sorted[j].Value {
+			return sorted[i].Value > sorted[j].Value
+		}
+		return sorted[i].Key() < sorted[j].Key()
+	})
+	return sorted
+}
+
+func selectCoinsBnBOrLargestFirst(utxos []*Utxo, params *coinSelectParams) []*Utxo {
+	if selected := selectCoinsBnB(utxos, params); selected != nil {
+		return selected
+	}
+	return selectCoinsLargestFirst(utxos, params)
+}
+
+// selectCoinsLargestFirst select the largest utxos until enough
+func selectCoinsLargestFirst(utxos []*Utxo, params *coinSelectParams) []*Utxo {
+	needed := params.needed()
+	var (
+		selected []*Utxo
+		total    int64
+	)
+	for _, utxo := range sortUtxosByValueDesc(utxos) {
+		ev := params.effectiveValue(utxo)
+		if ev <= 0 {
+			break
+		}
+		selected = append(selected, utxo)
+		total += ev
+		if total >= needed {
+			return selected
+		}
+	}
+	return nil
+}
+
+// selectCoinsBnB branch and bound search for a changeless selection,
+// whose effective value is in range [needed, needed+costOfChange],
+// prefer the one with least excess then less inputs.
+// ref. https://murch.one/wp-content/uploads/2016/11/erhardt2016coinselection.pdf
+func selectCoinsBnB(utxos []*Utxo, params *coinSelectParams) []*Utxo {
+	needed := params.needed()
+	upper := needed + params.costOfChange
+
+	sorted := sortUtxosByValueDesc(utxos)
+	values := make([]int64, 0, len(sorted))
+	var remaining int64
+	for _, utxo := range sorted {
+		ev := params.effectiveValue(utxo)
+		if ev <= 0 {
+			break
+		}
+		values = append(values, ev)
+		remaining += ev
+	}
+	if remaining < needed {
+		return nil
+	}
+
+	var (
+		tries     int
+		current   = make([]bool, len(values))
+		best      []bool
+		bestCount int
+		bestTotal int64
+	)
+
+	var search func(depth, count int, total, remaining int64)
+	search = func(depth, count int, total, remaining int64) {
+		tries++
+		if tries > bnbMaxTries {
+			return
+		}
+		if total > upper || total+remaining < needed {
+			return
+		}
+		if total >= needed {
+			if best == nil || total < bestTotal || (total == bestTotal && count < bestCount) {
+				best = append(best[:0], current...)
+				bestTotal = total
+				bestCount = count
+			}
+			return
+		}
+		if depth >= len(values) {
+			return
+		}
+		value := values[depth]
+		// skip including utxo with the same value as the previous excluded one
+		if depth == 0 || current[depth-1] || values[depth-1] != value {
+			current[depth] = true
+			search(depth+1, count+1, total+value, remaining-value)
+			current[depth] = false
+		}
+		search(depth+1, count, total, remaining-value)
+	}
+	search(0, 0, 0, remaining)
+
+	if best == nil {
+		return nil
+	}
+	selected := make([]*Utxo, 0, bestCount)
+	for i, chosen := range best {
+		if chosen {
+			selected = append(selected, sorted[i])
+		}
+	}
+	return selected
+}
+
+// selectCoinsConsolidate select the largest utxos until enough,
+// then add the smallest utxos (with positive effective value) up to max inputs
+func selectCoinsConsolidate(utxos []*Utxo, params *coinSelectParams) []*Utxo {
+	selected := selectCoinsLargestFirst(utxos, params)
+	if selected == nil || len(selected) >= params.maxInputs {
+		return selected
+	}
+	chosen := make(map[string]struct{}, len(selected))
+	for _, utxo := range selected {
+		chosen[utxo.Key()] = struct{}{}
+	}
+	sorted := sortUtxosByValueDesc(utxos)
+	for i := len(sorted) - 1; i >= 0 && len(selected) < params.maxInputs; i-- {
+		utxo := sorted[i]
+		if params.effectiveValue(utxo) <= 0 {
+			continue
+		}
+		if _, exist := chosen[utxo.Key()]; exist {
+			continue
+		}
+		selected = append(selected, utxo)
+	}
+	return selected
+}
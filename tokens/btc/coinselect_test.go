@@ -0,0 +1,158 @@
+package btc
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+const (
+	simInputFee     = 148 * 10 // 10 sat/byte
+	simCostOfChange = 545
+	simTxFee        = 226 * 10 // fee of tx with one input and change output
+)
+
+func newSimUtxos(r *rand.Rand, count int, maxValue int64) []*Utxo {
+	utxos := make([]*Utxo, count)
+	for i := 0; i < count; i++ {
+		utxos[i] = &Utxo{
+			Txid:  fmt.Sprintf("%064x", i),
+			Vout:  uint32(r.Intn(4)),
+			Value: 1 + r.Int63n(maxValue),
+		}
+	}
+	return utxos
+}
+
+func newSimParams(amount int64) *coinSelectParams {
+	return &coinSelectParams{
+		target:       amount + simTxFee,
+		inputFee:     simInputFee,
+		costOfChange: simCostOfChange,
+		maxInputs:    20,
+	}
+}
+
+func checkSelection(t *testing.T, strategy string, utxos, selected []*Utxo, params *coinSelectParams) {
+	t.Helper()
+	all := make(map[string]struct{}, len(utxos))
+	for _, utxo := range utxos {
+		all[utxo.Key()] = struct{}{}
+	}
+	used := make(map[string]struct{}, len(selected))
+	var total int64
+	for _, utxo := range selected {
+		key := utxo.Key()
+		if _, exist := all[key]; !exist {
+			t.Fatalf("%v selected unknown utxo %v", strategy, key)
+		}
+		if _, exist := used[key]; exist {
+			t.Fatalf("%v selected duplicate utxo %v", strategy, key)
+		}
+		used[key] = struct{}{}
+		if params.effectiveValue(utxo) <= 0 {
+			t.Fatalf("%v selected uneconomic utxo %v", strategy, key)
+		}
+		total += params.effectiveValue(utxo)
+	}
+	if total < params.needed() {
+		t.Fatalf("%v selected not enough, have %v want %v", strategy, total, params.needed())
+	}
+}
+
+func TestCoinSelectSimulation(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	strategies := []string{CoinSelectBranchAndBound, CoinSelectLargestFirst, CoinSelectConsolidate}
+	for round := 0; round < 200; round++ {
+		utxos := newSimUtxos(r, 1+r.Intn(200), 1e6)
+		var balance int64
+		for _, utxo := range utxos {
+			balance += utxo.Value
+		}
+		params := newSimParams(1 + r.Int63n(balance))
+		params.consolidate = round%2 == 0
+		for _, strategy := range strategies {
+			selected, err := selectCoins(strategy, utxos, params)
+			if err != nil {
+				// must be really insufficient in this case
+				if largest := selectCoinsLargestFirst(utxos, params); largest != nil {
+					t.Fatalf("round %v %v failed but largest first succeed", round, strategy)
+				}
+				continue
+			}
+			checkSelection(t, strategy, utxos, selected, params)
+			if strategy == CoinSelectConsolidate && params.consolidate &&
+				len(selected) < params.maxInputs && len(selected) < len(utxos) {
+				for _, utxo := range utxos {
+					if params.effectiveValue(utxo) > 0 && !containsUtxo(selected, utxo) {
+						t.Fatalf("round %v consolidate left economic utxo %v under max inputs", round, utxo.Key())
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestCoinSelectBnBChangeless(t *testing.T) {
+	r := rand.New(rand.NewSource(2))
+	for round := 0; round < 200; round++ {
+		utxos := newSimUtxos(r, 2+r.Intn(30), 1e6)
+		// pick a random subset as the exact target, so a changeless solution exists
+		var (
+			subset []*Utxo
+			amount int64
+		)
+		for _, utxo := range utxos {
+			if utxo.Value > simInputFee && r.Intn(3) == 0 {
+				subset = append(subset, utxo)
+				amount += utxo.Value - simInputFee
+			}
+		}
+		if len(subset) == 0 {
+			continue
+		}
+		params := newSimParams(amount + simInputFee - simTxFee)
+		selected := selectCoinsBnB(utxos, params)
+		if selected == nil {
+			t.Fatalf("round %v bnb found no solution but exact subset exists", round)
+		}
+		checkSelection(t, CoinSelectBranchAndBound, utxos, selected, params)
+		var total int64
+		for _, utxo := range selected {
+			total += params.effectiveValue(utxo)
+		}
+		if total > params.needed()+params.costOfChange {
+			t.Fatalf("round %v bnb selection is not changeless, excess %v", round, total-params.needed())
+		}
+	}
+}
+
+func TestCoinSelectLargestFirst(t *testing.T) {
+	utxos := []*Utxo{
+		{Txid: "a", Value: 1000},
+		{Txid: "b", Value: 50000},
+		{Txid: "c", Value: 20000},
+		{Txid: "d", Value: 100}, // uneconomic
+	}
+	params := &coinSelectParams{target: 60000, inputFee: 500}
+	selected, err := selectCoins(CoinSelectLargestFirst, utxos, params)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(selected) != 2 || selected[0].Txid != "b" || selected[1].Txid != "c" {
+		t.Fatalf("wrong largest first selection %v", selected)
+	}
+	params.target = 100000
+	if _, err = selectCoins(CoinSelectLargestFirst, utxos, params); err == nil {
+		t.Fatal("select more than balance should fail")
+	}
+}
+
+func containsUtxo(utxos []*Utxo, utxo *Utxo) bool {
+	for _, item := range utxos {
+		if item.Key() == utxo.Key() {
+			return true
+		}
+	}
+	return false
+}
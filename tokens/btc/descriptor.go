@@ -0,0 +1,159 @@
+package btc
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+)
+
+// SigHashForkID sighash flag of chains forked with replay protection (eg. BCH)
+const SigHashForkID txscript.SigHashType = 0x40
+
+// AddressCodec encode and decode addresses of utxo chain
+type AddressCodec interface {
+	DecodeAddress(addr string, params *chaincfg.Params) (btcutil.Address, error)
+	EncodeAddress(address btcutil.Address, params *chaincfg.Params) (string, error)
+}
+
+// ChainDescriptor describe a utxo chain built on the btc code path,
+// it holds network params, address codec and sighash rules of the chain.
+type ChainDescriptor struct {
+	Name   string // chain name used in logs
+	Symbol string // native coin symbol
+
+	// Networks net params of supported net ids (lower case),
+	// DefaultNetwork is used for other net ids (eg. custom)
+	Networks       map[string]*chaincfg.Params
+	DefaultNetwork string
+
+	// AddressCodec codec of chain addresses, nil means base58 codec
+	AddressCodec AddressCodec
+
+	// APIAddressCodec and APINetParams are used if gateway api
+	// use different address format from the chain (eg. ltc electrs)
+	APIAddressCodec AddressCodec
+	APINetParams    *chaincfg.Params
+
+	// NewGatewayAPI create gateway api of bridge, nil means electrs api
+	NewGatewayAPI func(b *Bridge) GatewayAPI
+
+	// SigHashType sighash type appended to signatures,
+	// sign with BIP143 digest if it has SigHashForkID flag
+	SigHashType txscript.SigHashType
+
+	// MaxAmount max amount of output (in smallest unit)
+	MaxAmount int64
+
+	// relay fees default values and limits (in smallest unit)
+	MinRelayFee       int64
+	MaxMinRelayFee    int64
+	MinRelayFeePerKb  int64
+	MaxRelayFeePerKb  int64
+	EstimateFeeBlocks int
+
+	// NoFeeEstimation use min relay fee per kb instead of estimating fee by gateway api
+	NoFeeEstimation bool
+}
+
+// BitcoinDescriptor btc chain descriptor
+var BitcoinDescriptor = &ChainDescriptor{
+	Name:   "Bitcoin",
+	Symbol: "BTC",
+	Networks: map[string]*chaincfg.Params{
+		netMainnet:  &chaincfg.MainNetParams,
+		netTestnet3: &chaincfg.TestNet3Params,
+	},
+	DefaultNetwork: netTestnet3,
+
+	SigHashType: txscript.SigHashAll,
+
+	MaxAmount: btcutil.MaxSatoshi,
+
+	MinRelayFee:       400,
+	MaxMinRelayFee:    100000,
+	MinRelayFeePerKb:  2000,
+	MaxRelayFeePerKb:  500000,
+	EstimateFeeBlocks: 6,
+}
+
+// Base58Codec base58 address codec with segwit support, ref. btcutil
+var Base58Codec AddressCodec = &base58Codec{}
+
+type base58Codec struct{}
+
+func (c *base58Codec) DecodeAddress(addr string, params *chaincfg.Params) (btcutil.Address, error) {
+	address, err := btcutil.DecodeAddress(addr, params)
+	if err != nil {
+		return nil, err
+	}
+	if !address.IsForNet(params) {
+		return nil, fmt.Errorf("invalid address for net")
+	}
+	return address, nil
+}
+
+func (c *base58Codec) EncodeAddress(address btcutil.Address, params *chaincfg.Params) (string, error) {
+	var err error
+	switch addr := address.(type) {
+	case *btcutil.AddressPubKeyHash:
+		address, err = btcutil.NewAddressPubKeyHash(addr.ScriptAddress(), params)
+	case *btcutil.AddressScriptHash:
+		address, err = btcutil.NewAddressScriptHashFromHash(addr.ScriptAddress(), params)
+	case *btcutil.AddressWitnessPubKeyHash:
+		address, err = btcutil.NewAddressWitnessPubKeyHash(addr.ScriptAddress(), params)
+	case *btcutil.AddressWitnessScriptHash:
+		address, err = btcutil.NewAddressWitnessScriptHash(addr.ScriptAddress(), params)
+	default:
+		return "", fmt.Errorf("unsupported address type %T", address)
+	}
+	if err != nil {
+		return "", err
+	}
+	return address.EncodeAddress(), nil
+}
+
+// GetNetParams get net params of net id
+func (c *ChainDescriptor) GetNetParams(netID string) *chaincfg.Params {
+	if params, exist := c.Networks[strings.ToLower(netID)]; exist {
+		return params
+	}
+	return c.Networks[c.DefaultNetwork]
+}
+
+// IsSupportedNetwork is net id supported
+func (c *ChainDescriptor) IsSupportedNetwork(netID string) bool {
+	_, exist := c.Networks[strings.ToLower(netID)]
+	return exist
+}
+
+func (c *ChainDescriptor) getAddressCodec() AddressCodec {
+	if c.AddressCodec != nil {
+		return c.AddressCodec
+	}
+	return Base58Codec
+}
+
+func (c *ChainDescriptor) getAPIAddressCodec() AddressCodec {
+	if c.APIAddressCodec != nil {
+		return c.APIAddressCodec
+	}
+	return Base58Codec
+}
+
+// hasAPIAddressFormat whether gateway api use different address format
+func (c *ChainDescriptor) hasAPIAddressFormat() bool {
+	return c.APIAddressCodec != nil || c.APINetParams != nil
+}
+
+// needNormalizeAPIAddress whether addresses from gateway api should be converted
+func (c *ChainDescriptor) needNormalizeAPIAddress() bool {
+	return c.AddressCodec != nil || c.hasAPIAddressFormat()
+}
+
+// IsForkIDSigHash whether sign with BIP143 digest and forkid
+func (c *ChainDescriptor) IsForkIDSigHash() bool {
+	return c.SigHashType&SigHashForkID != 0
+}
@@ -0,0 +1,105 @@
+package btc
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
+)
+
+// GatewayAPI api of utxo chain gateway, results are in electrs format.
+// addresses passed to and returned by the api are in gateway api format.
+type GatewayAPI interface {
+	GetLatestBlockNumberOf(apiAddress string) (uint64, error)
+	GetLatestBlockNumber() (uint64, error)
+	GetTransactionByHash(txHash string) (*electrs.ElectTx, error)
+	GetElectTransactionStatus(txHash string) (*electrs.ElectTxStatus, error)
+	FindUtxos(addr string) ([]*electrs.ElectUtxo, error)
+	GetPoolTxidList() ([]string, error)
+	GetPoolTransactions(addr string) ([]*electrs.ElectTx, error)
+	GetTransactionHistory(addr, lastSeenTxid string) ([]*electrs.ElectTx, error)
+	GetOutspend(txHash string, vout uint32) (*electrs.ElectOutspend, error)
+	PostTransaction(txHex string) (txHash string, err error)
+	GetBlockHash(height uint64) (string, error)
+	GetBlockTxids(blockHash string) ([]string, error)
+	GetBlock(blockHash string) (*electrs.ElectBlock, error)
+	GetBlockTransactions(blockHash string, startIndex uint32) ([]*electrs.ElectTx, error)
+	EstimateFeePerKb(blocks int) (int64, error)
+}
+
+// getGatewayAPI get gateway api of bridge, it's created on first use
+// as gateway config may be set directly (eg. by swapscan)
+func (b *Bridge) getGatewayAPI() GatewayAPI {
+	b.apiLock.Lock()
+	defer b.apiLock.Unlock()
+	if b.api == nil {
+		if b.Chain.NewGatewayAPI != nil {
+			b.api = b.Chain.NewGatewayAPI(b)
+		} else {
+			b.api = &electrsAPI{bridge: b}
+		}
+	}
+	return b.api
+}
+
+// electrsAPI electrs gateway api, the default gateway api
+type electrsAPI struct {
+	bridge *Bridge
+}
+
+func (api *electrsAPI) GetLatestBlockNumberOf(apiAddress string) (uint64, error) {
+	return electrs.GetLatestBlockNumberOf(apiAddress)
+}
+
+func (api *electrsAPI) GetLatestBlockNumber() (uint64, error) {
+	return electrs.GetLatestBlockNumber(api.bridge)
+}
+
+func (api *electrsAPI) GetTransactionByHash(txHash string) (*electrs.ElectTx, error) {
+	return electrs.GetTransactionByHash(api.bridge, txHash)
+}
+
+func (api *electrsAPI) GetElectTransactionStatus(txHash string) (*electrs.ElectTxStatus, error) {
+	return electrs.GetElectTransactionStatus(api.bridge, txHash)
+}
+
+func (api *electrsAPI) FindUtxos(addr string) ([]*electrs.ElectUtxo, error) {
+	return electrs.FindUtxos(api.bridge, addr)
+}
+
+func (api *electrsAPI) GetPoolTxidList() ([]string, error) {
+	return electrs.GetPoolTxidList(api.bridge)
+}
+
+func (api *electrsAPI) GetPoolTransactions(addr string) ([]*electrs.ElectTx, error) {
+	return electrs.GetPoolTransactions(api.bridge, addr)
+}
+
+func (api *electrsAPI) GetTransactionHistory(addr, lastSeenTxid string) ([]*electrs.ElectTx, error) {
+	return electrs.GetTransactionHistory(api.bridge, addr, lastSeenTxid)
+}
+
+func (api *electrsAPI) GetOutspend(txHash string, vout uint32) (*electrs.ElectOutspend, error) {
+	return electrs.GetOutspend(api.bridge, txHash, vout)
+}
+
+func (api *electrsAPI) PostTransaction(txHex string) (txHash string, err error) {
+	return electrs.PostTransaction(api.bridge, txHex)
+}
+
+func (api *electrsAPI) GetBlockHash(height uint64) (string, error) {
+	return electrs.GetBlockHash(api.bridge, height)
+}
+
+func (api *electrsAPI) GetBlockTxids(blockHash string) ([]string, error) {
+	return electrs.GetBlockTxids(api.bridge, blockHash)
+}
+
+func (api *electrsAPI) GetBlock(blockHash string) (*electrs.ElectBlock, error) {
+	return electrs.GetBlock(api.bridge, blockHash)
+}
+
+func (api *electrsAPI) GetBlockTransactions(blockHash string, startIndex uint32) ([]*electrs.ElectTx, error) {
+	return electrs.GetBlockTransactions(api.bridge, blockHash, startIndex)
+}
+
+func (api *electrsAPI) EstimateFeePerKb(blocks int) (int64, error) {
+	return electrs.EstimateFeePerKb(api.bridge, blocks)
+}
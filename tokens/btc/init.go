@@ -1,108 +1,176 @@
 package btc
 
 import (
+	"strings"
+
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 )
 
-var (
-	cfgMinRelayFee       int64  = 400
-	cfgMinRelayFeePerKb  int64  = 2000
-	cfgMaxRelayFeePerKb  int64  = 500000
-	cfgPlusFeePercentage uint64 = 0
-	cfgEstimateFeeBlocks        = 6
+// extraConfig effective 'BtcExtra' config of bridge,
+// defaults of relay fees are from the chain descriptor
+type extraConfig struct {
+	minRelayFee       int64
+	minRelayFeePerKb  int64
+	maxRelayFeePerKb  int64
+	plusFeePercentage uint64
+	estimateFeeBlocks int
+
+	coinSelection        string
+	consolidateFeePerKb  int64
+	consolidateMaxInputs int
+
+	utxoAggregateMinCount  int
+	utxoAggregateMinValue  uint64
+	utxoAggregateToAddress string
+	aggregateMaxFeePerKb   int64
+	aggregateDustMaxValue  uint64
+}
 
-	cfgFromPublicKey string
+func newExtraConfig(chain *ChainDescriptor) *extraConfig {
+	return &extraConfig{
+		minRelayFee:       chain.MinRelayFee,
+		minRelayFeePerKb:  chain.MinRelayFeePerKb,
+		maxRelayFeePerKb:  chain.MaxRelayFeePerKb,
+		estimateFeeBlocks: chain.EstimateFeeBlocks,
 
-	cfgUtxoAggregateMinCount  = 20
-	cfgUtxoAggregateMinValue  = uint64(1000000)
-	cfgUtxoAggregateToAddress string
-)
+		coinSelection:        CoinSelectBranchAndBound,
+		consolidateMaxInputs: 50,
+
+		utxoAggregateMinCount: 20,
+		utxoAggregateMinValue: 1000000,
+	}
+}
 
 // Init init btc extra
-func Init(btcExtra *tokens.BtcExtraConfig) {
-	if BridgeInstance == nil {
+func Init(b *Bridge, btcExtra *tokens.BtcExtraConfig) {
+	if b == nil {
 		return
 	}
 
 	if btcExtra == nil {
-		log.Fatal("Btc bridge must config 'BtcExtra'")
+		log.Fatal(b.Chain.Name + " bridge must config 'BtcExtra'")
 	}
 
-	initFromPublicKey()
-	initRelayFee(btcExtra)
-	initAggregate(btcExtra)
+	b.initFromPublicKeys()
+	RegisterBridge(b, tokens.GetAllPairIDs()...)
+	b.initP2shPairID(btcExtra)
+	b.initRelayFee(btcExtra)
+	b.initCoinSelection(btcExtra)
+	b.initAggregate(btcExtra)
 }
 
-func initFromPublicKey() {
-	if len(tokens.GetTokenPairsConfig()) != 1 {
-		log.Fatalf("Btc bridge does not support multiple tokens")
+func (b *Bridge) initFromPublicKeys() {
+	pairIDs := tokens.GetAllPairIDs()
+	if len(pairIDs) == 0 {
+		log.Fatalf("%v bridge must have token pairs", b.Chain.Name)
 	}
-
-	pairCfg, exist := tokens.GetTokenPairsConfig()[PairID]
-	if !exist {
-		log.Fatalf("Btc bridge must have pairID %v", PairID)
+	for _, pairID := range pairIDs {
+		tokenCfg := b.GetTokenConfig(pairID)
+		if tokenCfg == nil {
+			log.Fatalf("%v bridge must have pairID %v", b.Chain.Name, pairID)
+		}
+		_, err := b.GetCompressedPublicKey(tokenCfg.DcrmPubkey, true)
+		if err != nil {
+			log.Fatal("wrong "+b.Chain.Name+" dcrm public key", "pairID", pairID, "err", err)
+		}
 	}
+}
 
-	cfgFromPublicKey = pairCfg.SrcToken.DcrmPubkey
-	_, err := BridgeInstance.GetCompressedPublicKey(cfgFromPublicKey, true)
-	if err != nil {
-		log.Fatal("wrong btc dcrm public key", "err", err)
+func (b *Bridge) initP2shPairID(btcExtra *tokens.BtcExtraConfig) {
+	pairID := strings.ToLower(btcExtra.P2shPairID)
+	if pairID == "" {
+		pairIDs := tokens.GetAllPairIDs()
+		if len(pairIDs) != 1 {
+			log.Fatal(b.Chain.Name + " bridge must config 'P2shPairID' if have multiple token pairs")
+		}
+		pairID = pairIDs[0]
+	} else if !tokens.IsTokenPairExist(pairID) {
+		log.Fatal(b.Chain.Name+" bridge config 'P2shPairID' is not exist", "pairID", pairID)
 	}
+	b.p2shPairID = pairID
+	log.Info("Init "+b.Chain.Name+" extra", "P2shPairID", pairID)
 }
 
-func initRelayFee(btcExtra *tokens.BtcExtraConfig) {
+func (b *Bridge) initRelayFee(btcExtra *tokens.BtcExtraConfig) {
 	if btcExtra.MinRelayFee > 0 {
-		cfgMinRelayFee = btcExtra.MinRelayFee
-		maxMinRelayFee, _ := newAmount(0.001)
-		minRelayFee := btcAmountType(cfgMinRelayFee)
+		b.cfg.minRelayFee = btcExtra.MinRelayFee
+		maxMinRelayFee := btcAmountType(b.Chain.MaxMinRelayFee)
+		minRelayFee := btcAmountType(b.cfg.minRelayFee)
 		if minRelayFee > maxMinRelayFee {
-			log.Fatal("BtcMinRelayFee is too large", "value", minRelayFee, "max", maxMinRelayFee)
+			log.Fatal("MinRelayFee is too large", "value", minRelayFee, "max", maxMinRelayFee)
 		}
 	}
 
 	if btcExtra.EstimateFeeBlocks > 0 {
-		cfgEstimateFeeBlocks = btcExtra.EstimateFeeBlocks
-		if cfgEstimateFeeBlocks > 25 {
+		b.cfg.estimateFeeBlocks = btcExtra.EstimateFeeBlocks
+		if b.cfg.estimateFeeBlocks > 25 {
 			log.Fatal("EstimateFeeBlocks is too large, must <= 25")
 		}
 	}
 
 	if btcExtra.PlusFeePercentage > 0 {
-		cfgPlusFeePercentage = btcExtra.PlusFeePercentage
-		if cfgPlusFeePercentage > 5000 {
+		b.cfg.plusFeePercentage = btcExtra.PlusFeePercentage
+		if b.cfg.plusFeePercentage > 5000 {
 			log.Fatal("PlusFeePercentage is too large, must <= 5000")
 		}
 	}
 
 	if btcExtra.MaxRelayFeePerKb > 0 {
-		cfgMaxRelayFeePerKb = btcExtra.MaxRelayFeePerKb
+		b.cfg.maxRelayFeePerKb = btcExtra.MaxRelayFeePerKb
 	}
 
 	if btcExtra.MinRelayFeePerKb > 0 {
-		cfgMinRelayFeePerKb = btcExtra.MinRelayFeePerKb
+		b.cfg.minRelayFeePerKb = btcExtra.MinRelayFeePerKb
 	}
 
-	if cfgMinRelayFeePerKb > cfgMaxRelayFeePerKb {
-		log.Fatal("MinRelayFeePerKb is larger than MaxRelayFeePerKb", "min", cfgMinRelayFeePerKb, "max", cfgMaxRelayFeePerKb)
+	if b.cfg.minRelayFeePerKb > b.cfg.maxRelayFeePerKb {
+		log.Fatal("MinRelayFeePerKb is larger than MaxRelayFeePerKb", "min", b.cfg.minRelayFeePerKb, "max", b.cfg.maxRelayFeePerKb)
 	}
 
-	log.Info("Init Btc extra", "MinRelayFee", cfgMinRelayFee, "MinRelayFeePerKb", cfgMinRelayFeePerKb, "MaxRelayFeePerKb", cfgMaxRelayFeePerKb, "PlusFeePercentage", cfgPlusFeePercentage)
+	log.Info("Init "+b.Chain.Name+" extra", "MinRelayFee", b.cfg.minRelayFee, "MinRelayFeePerKb", b.cfg.minRelayFeePerKb, "MaxRelayFeePerKb", b.cfg.maxRelayFeePerKb, "PlusFeePercentage", b.cfg.plusFeePercentage)
 }
 
-func initAggregate(btcExtra *tokens.BtcExtraConfig) {
+func (b *Bridge) initCoinSelection(btcExtra *tokens.BtcExtraConfig) {
+	if btcExtra.CoinSelection != "" {
+		b.cfg.coinSelection = strings.ToLower(btcExtra.CoinSelection)
+		if !isValidCoinSelection(b.cfg.coinSelection) {
+			log.Fatal("unknown coin selection strategy", "strategy", btcExtra.CoinSelection)
+		}
+	}
+
+	// consolidate when relay fee is at the lowest by default
+	b.cfg.consolidateFeePerKb = b.cfg.minRelayFeePerKb
+	if btcExtra.ConsolidateFeePerKb > 0 {
+		b.cfg.consolidateFeePerKb = btcExtra.ConsolidateFeePerKb
+	}
+
+	if btcExtra.ConsolidateMaxInputs > 0 {
+		b.cfg.consolidateMaxInputs = btcExtra.ConsolidateMaxInputs
+	}
+
+	log.Info("Init "+b.Chain.Name+" extra", "CoinSelection", b.cfg.coinSelection, "ConsolidateFeePerKb", b.cfg.consolidateFeePerKb, "ConsolidateMaxInputs", b.cfg.consolidateMaxInputs)
+}
+
+func (b *Bridge) initAggregate(btcExtra *tokens.BtcExtraConfig) {
 	if btcExtra.UtxoAggregateMinCount > 0 {
-		cfgUtxoAggregateMinCount = btcExtra.UtxoAggregateMinCount
+		b.cfg.utxoAggregateMinCount = btcExtra.UtxoAggregateMinCount
 	}
 
 	if btcExtra.UtxoAggregateMinValue > 0 {
-		cfgUtxoAggregateMinValue = btcExtra.UtxoAggregateMinValue
+		b.cfg.utxoAggregateMinValue = btcExtra.UtxoAggregateMinValue
+	}
+
+	b.cfg.utxoAggregateToAddress = btcExtra.UtxoAggregateToAddress
+	if !b.IsValidAddress(b.cfg.utxoAggregateToAddress) {
+		log.Fatal("wrong utxo aggregate to address", "toAddress", b.cfg.utxoAggregateToAddress)
 	}
 
-	cfgUtxoAggregateToAddress = btcExtra.UtxoAggregateToAddress
-	if !BridgeInstance.IsValidAddress(cfgUtxoAggregateToAddress) {
-		log.Fatal("wrong utxo aggregate to address", "toAddress", cfgUtxoAggregateToAddress)
+	b.cfg.aggregateMaxFeePerKb = btcExtra.AggregateMaxFeePerKb
+	if b.cfg.aggregateMaxFeePerKb < 0 {
+		log.Fatal("AggregateMaxFeePerKb must not be negative", "value", b.cfg.aggregateMaxFeePerKb)
 	}
+	b.cfg.aggregateDustMaxValue = btcExtra.AggregateDustMaxValue
 
-	log.Info("Init Btc extra", "UtxoAggregateMinCount", cfgUtxoAggregateMinCount, "UtxoAggregateMinValue", cfgUtxoAggregateMinValue, "UtxoAggregateToAddress", cfgUtxoAggregateToAddress)
+	log.Info("Init "+b.Chain.Name+" extra", "UtxoAggregateMinCount", b.cfg.utxoAggregateMinCount, "UtxoAggregateMinValue", b.cfg.utxoAggregateMinValue, "UtxoAggregateToAddress", b.cfg.utxoAggregateToAddress, "AggregateMaxFeePerKb", b.cfg.aggregateMaxFeePerKb, "AggregateDustMaxValue", b.cfg.aggregateDustMaxValue)
 }
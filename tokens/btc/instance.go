@@ -1,23 +1,82 @@
 package btc
 
 import (
+	"strings"
+	"sync"
+
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
 )
 
-// BridgeInstance btc bridge instance
-var BridgeInstance BridgeInterface
-
 // BridgeInterface btc bridge interface
 type BridgeInterface interface {
 	tokens.CrossChainBridge
 
 	GetCompressedPublicKey(fromPublicKey string, needVerify bool) (cPkData []byte, err error)
+	GetP2shPairID() string
 	GetP2shAddress(bindAddr string) (p2shAddress string, redeemScript []byte, err error)
 	VerifyP2shTransaction(pairID, txHash, bindAddress string, allowUnstable bool) (*tokens.TxSwapInfo, error)
 	VerifyAggregateMsgHash(msgHash []string, args *tokens.BuildTxArgs) error
-	AggregateUtxos(addrs []string, utxos []*electrs.ElectUtxo) (string, error)
+	GetAggregateRelayFee() (relayFeePerKb int64, isLowFee bool, err error)
+	BuildSignedAggregateTx(pairID string, relayFeePerKb int64, addrs []string, utxos []*electrs.ElectUtxo) (signedTx interface{}, txHash string, err error)
 	FindUtxos(addr string) ([]*electrs.ElectUtxo, error)
+	FindDustUtxos(addr string, relayFeePerKb int64) ([]*electrs.ElectUtxo, error)
 	StartSwapHistoryScanJob()
 	ShouldAggregate(aggUtxoCount int, aggSumVal uint64) bool
 }
+
+var (
+	// utxo bridges of token pairs, key is lower case pairID
+	pairBridges     = make(map[string]BridgeInterface)
+	pairBridgesLock sync.RWMutex
+)
+
+// RegisterBridge register utxo bridge of pairs,
+// so pairs of different utxo chains (eg. btc and ltc) can coexist
+func RegisterBridge(bridge BridgeInterface, pairIDs ...string) {
+	pairBridgesLock.Lock()
+	defer pairBridgesLock.Unlock()
+	for _, pairID := range pairIDs {
+		pairBridges[strings.ToLower(pairID)] = bridge
+	}
+}
+
+// GetBridgeByPairID get utxo bridge of pair,
+// pairs added after init are pairs of the source chain
+func GetBridgeByPairID(pairID string) BridgeInterface {
+	pairID = strings.ToLower(pairID)
+	if !tokens.IsTokenPairExist(pairID) {
+		return nil
+	}
+	pairBridgesLock.RLock()
+	bridge, exist := pairBridges[pairID]
+	pairBridgesLock.RUnlock()
+	if exist {
+		return bridge
+	}
+	bridge = GetSrcBridge()
+	if bridge != nil {
+		RegisterBridge(bridge, pairID)
+	}
+	return bridge
+}
+
+// GetBridges get all registered utxo bridges
+func GetBridges() (bridges []BridgeInterface) {
+	pairBridgesLock.RLock()
+	defer pairBridgesLock.RUnlock()
+	exist := make(map[BridgeInterface]struct{})
+	for _, bridge := range pairBridges {
+		if _, ok := exist[bridge]; !ok {
+			exist[bridge] = struct{}{}
+			bridges = append(bridges, bridge)
+		}
+	}
+	return bridges
+}
+
+// GetSrcBridge get source bridge if it's a utxo bridge
+func GetSrcBridge() BridgeInterface {
+	bridge, _ := tokens.SrcBridge.(BridgeInterface)
+	return bridge
+}
@@ -0,0 +1,49 @@
+package btc
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+func TestGetBridgeByPairID(t *testing.T) {
+	oldPairsConfig := tokens.GetTokenPairsConfig()
+	oldSrcBridge := tokens.SrcBridge
+	defer func() {
+		tokens.SetTokenPairsConfig(oldPairsConfig, false)
+		tokens.SrcBridge = oldSrcBridge
+	}()
+
+	tokens.SetTokenPairsConfig(map[string]*tokens.TokenPairConfig{
+		"btc":    {PairID: "BTC"},
+		"ltc":    {PairID: "LTC"},
+		"btcnew": {PairID: "btcnew"},
+	}, false)
+
+	btcBridge := NewCrossChainBridge(true)
+	ltcBridge := NewCrossChainBridge(true)
+	tokens.SrcBridge = btcBridge
+	RegisterBridge(btcBridge, "BTC")
+	RegisterBridge(ltcBridge, "ltc")
+
+	tests := []struct {
+		pairID string
+		want   BridgeInterface
+	}{
+		{"btc", btcBridge},
+		{"BTC", btcBridge},
+		{"Ltc", ltcBridge},
+		{"btcnew", btcBridge}, // added after init, belongs to source chain
+		{"notexist", nil},
+	}
+	for _, test := range tests {
+		if have := GetBridgeByPairID(test.pairID); have != test.want {
+			t.Errorf("get bridge of pair %v, have %p want %p", test.pairID, have, test.want)
+		}
+	}
+
+	bridges := GetBridges()
+	if len(bridges) != 2 {
+		t.Fatalf("get bridges, have %v want %v", len(bridges), 2)
+	}
+}
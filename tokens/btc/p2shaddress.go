@@ -2,6 +2,7 @@ package btc
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/anyswap/CrossChain-Bridge/common"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
@@ -17,18 +18,27 @@ func (b *Bridge) getP2shAddressWithMemo(memo, pubKeyHash []byte) (p2shAddress st
 	if err != nil {
 		return
 	}
-	p2shAddress = addressScriptHash.EncodeAddress()
+	p2shAddress, err = b.EncodeAddress(addressScriptHash)
 	return
 }
 
+// GetP2shPairID get pairID used for p2sh deposit addresses
+func (b *Bridge) GetP2shPairID() string {
+	return b.p2shPairID
+}
+
+// SetP2shPairID set pairID used for p2sh deposit addresses
+func (b *Bridge) SetP2shPairID(pairID string) {
+	b.p2shPairID = strings.ToLower(pairID)
+}
+
 // GetP2shAddress get p2sh address from bind address
 func (b *Bridge) GetP2shAddress(bindAddr string) (p2shAddress string, redeemScript []byte, err error) {
 	if !tokens.GetCrossChainBridge(!b.IsSrc).IsValidAddress(bindAddr) {
 		return "", nil, fmt.Errorf("invalid bind address %v", bindAddr)
 	}
 	memo := common.FromHex(bindAddr)
-	pairID := PairID
-	tokenCfg := b.GetTokenConfig(pairID)
+	tokenCfg := b.GetTokenConfig(b.GetP2shPairID())
 	if tokenCfg == nil {
 		return "", nil, tokens.ErrUnknownPairID
 	}
@@ -47,11 +57,14 @@ func (b *Bridge) getRedeemScriptByOutputScrpit(preScript []byte) ([]byte, error)
 	if err != nil {
 		return nil, err
 	}
-	p2shAddress, err := pkScript.Address(b.Inherit.GetChainParams())
+	p2shAddress, err := pkScript.Address(b.GetChainParams())
+	if err != nil {
+		return nil, err
+	}
+	p2shAddr, err := b.EncodeAddress(p2shAddress)
 	if err != nil {
 		return nil, err
 	}
-	p2shAddr := p2shAddress.String()
 	bindAddr := tools.GetP2shBindAddress(p2shAddr)
 	if bindAddr == "" {
 		return nil, fmt.Errorf("ps2h address %v is registered", p2shAddr)
@@ -70,7 +83,7 @@ func (b *Bridge) GetP2shAddressByRedeemScript(redeemScript []byte) (string, erro
 	if err != nil {
 		return "", err
 	}
-	return addressScriptHash.EncodeAddress(), nil
+	return b.EncodeAddress(addressScriptHash)
 }
 
 // GetP2shSigScript get p2sh signature script
@@ -2,6 +2,7 @@ package btc
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
@@ -26,33 +27,35 @@ func (b *Bridge) processTransaction(txid string) {
 }
 
 func (b *Bridge) processTransactionImpl(tx *electrs.ElectTx) {
-	p2shBindAddrs, err := b.CheckSwapinTxType(tx)
-	if err != nil {
-		return
-	}
 	txid := *tx.Txid
-	if len(p2shBindAddrs) > 0 {
-		for _, p2shBindAddr := range p2shBindAddrs {
-			b.processP2shSwapin(txid, p2shBindAddr)
+	for _, pairID := range tokens.GetAllPairIDs() {
+		p2shBindAddrs, err := b.CheckSwapinTxType(tx, pairID)
+		if err != nil {
+			continue
+		}
+		if len(p2shBindAddrs) > 0 {
+			for _, p2shBindAddr := range p2shBindAddrs {
+				b.processP2shSwapin(pairID, txid, p2shBindAddr)
+			}
+		} else {
+			b.processSwapin(pairID, txid)
 		}
-	} else {
-		b.processSwapin(txid)
 	}
 }
 
-func (b *Bridge) processSwapin(txid string) {
-	if tools.IsSwapExist(txid, PairID, "", true) {
+func (b *Bridge) processSwapin(pairID, txid string) {
+	if tools.IsSwapExist(txid, pairID, "", true) {
 		return
 	}
-	swapInfo, err := b.verifySwapinTx(PairID, txid, true)
+	swapInfo, err := b.verifySwapinTx(pairID, txid, true)
 	tools.RegisterSwapin(txid, []*tokens.TxSwapInfo{swapInfo}, []error{err})
 }
 
-func (b *Bridge) processP2shSwapin(txid, bindAddress string) {
-	if tools.IsSwapExist(txid, PairID, bindAddress, true) {
+func (b *Bridge) processP2shSwapin(pairID, txid, bindAddress string) {
+	if tools.IsSwapExist(txid, pairID, bindAddress, true) {
 		return
 	}
-	swapInfo, err := b.verifyP2shSwapinTx(PairID, txid, bindAddress, true)
+	swapInfo, err := b.verifyP2shSwapinTx(pairID, txid, bindAddress, true)
 	tools.RegisterP2shSwapin(txid, swapInfo, err)
 }
 
@@ -73,13 +76,14 @@ func isP2pkhSwapinPrior(tx *electrs.ElectTx, depositAddress string) bool {
 	return bindOk && tokens.DstBridge.IsValidAddress(bindAddress)
 }
 
-// CheckSwapinTxType check swapin type
-func (b *Bridge) CheckSwapinTxType(tx *electrs.ElectTx) (p2shBindAddrs []string, err error) {
-	tokenCfg := b.GetTokenConfig(PairID)
+// CheckSwapinTxType check swapin type of pair, p2sh swapin is only for p2sh pair
+func (b *Bridge) CheckSwapinTxType(tx *electrs.ElectTx, pairID string) (p2shBindAddrs []string, err error) {
+	tokenCfg := b.GetTokenConfig(pairID)
 	if tokenCfg == nil {
-		return nil, fmt.Errorf("swap pair '%v' is not configed", PairID)
+		return nil, fmt.Errorf("swap pair '%v' is not configed", pairID)
 	}
 	depositAddress := tokenCfg.DepositAddress
+	isP2shPair := strings.EqualFold(pairID, b.GetP2shPairID())
 	p2pkhSwapinPrior := isP2pkhSwapinPrior(tx, depositAddress)
 	p2shAddressMap := make(map[string]struct{})
 	for _, output := range tx.Vout {
@@ -88,6 +92,9 @@ func (b *Bridge) CheckSwapinTxType(tx *electrs.ElectTx) (p2shBindAddrs []string,
 		}
 		switch *output.ScriptpubkeyType {
 		case p2shType:
+			if !isP2shPair {
+				continue
+			}
 			// use the first registered p2sh address
 			p2shAddress := *output.ScriptpubkeyAddress
 			if _, exist := p2shAddressMap[p2shAddress]; exist {
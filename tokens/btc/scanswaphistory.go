@@ -5,26 +5,45 @@ import (
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
 )
 
 var (
 	maxFirstScanHeight = uint64(1000)
-
-	firstScannedTxs   = tools.NewCachedScannedTxs(500)
-	historyScannedTxs = tools.NewCachedScannedTxs(500)
 )
 
-// StartSwapHistoryScanJob scan job
+// StartSwapHistoryScanJob scan job, scan history of every deposit address
 func (b *Bridge) StartSwapHistoryScanJob() {
 	log.Infof("[swaphistory] start scan %v swap history job", b.ChainConfig.BlockChain)
 
-	go b.scanFirstLoop()
+	for depositAddress, pairIDs := range b.getDepositPairs() {
+		go b.scanFirstLoop(depositAddress, pairIDs)
+		go b.scanTransactionHistory(depositAddress, pairIDs)
+	}
+}
 
-	b.scanTransactionHistory()
+// getDepositPairs get pairIDs of deposit addresses, key is deposit address
+func (b *Bridge) getDepositPairs() map[string][]string {
+	result := make(map[string][]string)
+	for _, pairID := range tokens.GetAllPairIDs() {
+		tokenCfg := b.GetTokenConfig(pairID)
+		if tokenCfg == nil {
+			continue
+		}
+		depositAddress := tokenCfg.DepositAddress
+		result[depositAddress] = append(result[depositAddress], pairID)
+	}
+	return result
 }
 
-func (b *Bridge) scanFirstLoop() {
+func (b *Bridge) processSwapinOfPairs(pairIDs []string, txid string) {
+	for _, pairID := range pairIDs {
+		b.processSwapin(pairID, txid)
+	}
+}
+
+func (b *Bridge) scanFirstLoop(depositAddress string, pairIDs []string) {
 	// first loop process all tx history no matter whether processed before
 	latest := tools.LoopGetLatestBlockNumber(b)
 	minHeight := *b.ChainConfig.InitialHeight
@@ -32,14 +51,14 @@ func (b *Bridge) scanFirstLoop() {
 		minHeight = latest - maxFirstScanHeight
 	}
 	chainName := b.ChainConfig.BlockChain
-	log.Infof("[scanFirstLoop] start %v first scan loop to min height %v", chainName, minHeight)
+	log.Infof("[scanFirstLoop] start %v first scan loop of %v to min height %v", chainName, depositAddress, minHeight)
 
-	tokenCfg := b.GetTokenConfig(PairID)
+	firstScannedTxs := tools.NewCachedScannedTxs(500)
 	lastSeenTxid := ""
 
 FIRST_LOOP:
 	for {
-		txHistory, err := b.GetTransactionHistory(tokenCfg.DepositAddress, lastSeenTxid)
+		txHistory, err := b.GetTransactionHistory(depositAddress, lastSeenTxid)
 		if err != nil {
 			time.Sleep(retryIntervalInScanJob)
 			continue
@@ -58,20 +77,22 @@ FIRST_LOOP:
 			txid := *tx.Txid
 			if !firstScannedTxs.IsTxScanned(txid) {
 				log.Tracef("[scanFirstLoop] process %v tx. txid=%v height=%v", chainName, txid, height)
-				b.processSwapin(txid)
+				b.processSwapinOfPairs(pairIDs, txid)
 				firstScannedTxs.CacheScannedTx(txid)
 			}
 		}
 		lastSeenTxid = *txHistory[len(txHistory)-1].Txid
 	}
 
-	log.Infof("[scanFirstLoop] finish %v first scan loop to min height %v", chainName, minHeight)
+	log.Infof("[scanFirstLoop] finish %v first scan loop of %v to min height %v", chainName, depositAddress, minHeight)
 }
 
-func (b *Bridge) scanTransactionHistory() {
+func (b *Bridge) scanTransactionHistory(depositAddress string, pairIDs []string) {
 	var (
 		lastSeenTxid = ""
 		rescan       = true
+
+		historyScannedTxs = tools.NewCachedScannedTxs(500)
 	)
 
 	latest := tools.LoopGetLatestBlockNumber(b)
@@ -83,12 +104,10 @@ func (b *Bridge) scanTransactionHistory() {
 	chainName := b.ChainConfig.BlockChain
 	errorSubject := fmt.Sprintf("[scanhistory] get %v tx history failed", chainName)
 	scanSubject := fmt.Sprintf("[scanhistory] scanned %v tx", chainName)
-	log.Infof("[scanhistory] start %v scan swap history loop from height %v", chainName, minHeight)
-
-	tokenCfg := b.GetTokenConfig(PairID)
+	log.Infof("[scanhistory] start %v scan swap history loop of %v from height %v", chainName, depositAddress, minHeight)
 
 	for {
-		txHistory, err := b.GetTransactionHistory(tokenCfg.DepositAddress, lastSeenTxid)
+		txHistory, err := b.GetTransactionHistory(depositAddress, lastSeenTxid)
 		if err != nil {
 			log.Error(errorSubject, "err", err)
 			time.Sleep(retryIntervalInScanJob)
@@ -114,7 +133,7 @@ func (b *Bridge) scanTransactionHistory() {
 				break // rescan if already processed
 			}
 			log.Trace(scanSubject, "txid", txid, "height", height)
-			b.processSwapin(txid)
+			b.processSwapinOfPairs(pairIDs, txid)
 			historyScannedTxs.CacheScannedTx(txid)
 		}
 		if rescan {
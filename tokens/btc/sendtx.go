@@ -6,6 +6,7 @@ import (
 
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/btcsuite/btcd/wire"
 	"github.com/btcsuite/btcwallet/wallet/txauthor"
 )
 
@@ -29,5 +30,38 @@ func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error
 	txHex := hex.EncodeToString(buf.Bytes())
 	log.Info("Bridge send tx", "hash", tx.TxHash())
 
-	return b.PostTransaction(txHex)
+	txHash, err = b.PostTransaction(txHex)
+	if err == nil {
+		b.markUtxosSpent(tx)
+	}
+	return txHash, err
+}
+
+// SerializeSignedTx serialize signed tx to hex string
+func (b *Bridge) SerializeSignedTx(signedTx interface{}) (string, error) {
+	authoredTx, ok := signedTx.(*txauthor.AuthoredTx)
+	if !ok || authoredTx.Tx == nil {
+		return "", tokens.ErrWrongRawTx
+	}
+	tx := authoredTx.Tx
+	buf := bytes.NewBuffer(make([]byte, 0, tx.SerializeSize()))
+	err := tx.Serialize(buf)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf.Bytes()), nil
+}
+
+// DeserializeSignedTx deserialize signed tx from hex string
+func (b *Bridge) DeserializeSignedTx(data string) (signedTx interface{}, err error) {
+	txData, err := hex.DecodeString(data)
+	if err != nil {
+		return nil, err
+	}
+	tx := new(wire.MsgTx)
+	err = tx.Deserialize(bytes.NewReader(txData))
+	if err != nil {
+		return nil, err
+	}
+	return &txauthor.AuthoredTx{Tx: tx}, nil
 }
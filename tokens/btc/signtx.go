@@ -14,13 +14,14 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+	"github.com/anyswap/CrossChain-Bridge/tools/signer"
 	"github.com/btcsuite/btcwallet/wallet/txauthor"
 )
 
 func (b *Bridge) verifyTransactionWithArgs(tx *txauthor.AuthoredTx, args *tokens.BuildTxArgs) error {
 	checkReceiver := args.Bind
 	if args.Identifier == tokens.AggregateIdentifier {
-		checkReceiver = cfgUtxoAggregateToAddress
+		checkReceiver = b.cfg.utxoAggregateToAddress
 	}
 	payToReceiverScript, err := b.GetPayToAddrScript(checkReceiver)
 	if err != nil {
@@ -51,7 +52,12 @@ func (b *Bridge) DcrmSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs
 		return nil, "", err
 	}
 
-	cPkData, err := b.GetCompressedPublicKey(cfgFromPublicKey, false)
+	fromPublicKey, err := b.getDcrmPublicKey(args.PairID)
+	if err != nil {
+		return nil, "", err
+	}
+
+	cPkData, err := b.GetCompressedPublicKey(fromPublicKey, false)
 	if err != nil {
 		return nil, "", err
 	}
@@ -74,7 +80,7 @@ func (b *Bridge) DcrmSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs
 			hasP2shInput = true
 		}
 
-		sigHash, err = b.CalcSignatureHash(sigScript, authoredTx.Tx, i)
+		sigHash, err = b.calcSigHashOfInput(sigScript, authoredTx, i)
 		if err != nil {
 			return nil, "", err
 		}
@@ -94,6 +100,17 @@ func (b *Bridge) DcrmSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs
 	return b.MakeSignedTransaction(authoredTx, msgHashes, rsvs, sigScripts, cPkData)
 }
 
+// calcSigHashOfInput calc sig hash of the i-th input of authored tx
+func (b *Bridge) calcSigHashOfInput(sigScript []byte, authoredTx *txauthor.AuthoredTx, i int) ([]byte, error) {
+	var amount int64
+	if i < len(authoredTx.PrevInputValues) {
+		amount = int64(authoredTx.PrevInputValues[i])
+	} else if b.Chain.IsForkIDSigHash() {
+		return nil, errors.New("missing previous input value of forkid sighash")
+	}
+	return b.CalcSignatureHash(sigScript, authoredTx.Tx, i, amount)
+}
+
 func checkEqualLength(authoredTx *txauthor.AuthoredTx, msgHash, rsv []string, sigScripts [][]byte) error {
 	txIn := authoredTx.Tx.TxIn
 	if len(txIn) != len(msgHash) {
@@ -167,21 +184,33 @@ func (b *Bridge) getSigDataFromRSV(rsv string) ([]byte, bool) {
 	return b.SerializeSignature(rr, ss), true
 }
 
-func (b *Bridge) verifyPublickeyData(pkData []byte) error {
-	tokenCfg := b.GetTokenConfig(PairID)
+func (b *Bridge) getDcrmPublicKey(pairID string) (string, error) {
+	tokenCfg := b.GetTokenConfig(pairID)
 	if tokenCfg == nil {
-		return nil
+		return "", tokens.ErrUnknownPairID
 	}
-	dcrmAddress := tokenCfg.DcrmAddress
-	if dcrmAddress == "" {
-		return nil
+	return tokenCfg.DcrmPubkey, nil
+}
+
+// verifyPublickeyData verify public key with dcrm address of pairs using it
+func (b *Bridge) verifyPublickeyData(fromPublicKey string, pkData []byte) error {
+	pubKeyHash, err := b.NewAddressPubKeyHash(pkData)
+	if err != nil {
+		return err
 	}
-	address, err := b.NewAddressPubKeyHash(pkData)
+	address, err := b.EncodeAddress(pubKeyHash)
 	if err != nil {
 		return err
 	}
-	if address.EncodeAddress() != dcrmAddress {
-		return fmt.Errorf("public key address %v is not the configed dcrm address %v", address, dcrmAddress)
+	for _, pairID := range tokens.GetAllPairIDs() {
+		tokenCfg := b.GetTokenConfig(pairID)
+		if tokenCfg == nil || tokenCfg.DcrmPubkey != fromPublicKey {
+			continue
+		}
+		dcrmAddress := tokenCfg.DcrmAddress
+		if dcrmAddress != "" && address != dcrmAddress {
+			return fmt.Errorf("public key address %v is not the configed dcrm address %v of pair %v", address, dcrmAddress, pairID)
+		}
 	}
 	return nil
 }
@@ -197,7 +226,7 @@ func (b *Bridge) GetCompressedPublicKey(fromPublicKey string, needVerify bool) (
 		return nil, err
 	}
 	if needVerify {
-		err = b.verifyPublickeyData(cPkData)
+		err = b.verifyPublickeyData(fromPublicKey, cPkData)
 		if err != nil {
 			return nil, err
 		}
@@ -222,11 +251,15 @@ func (b *Bridge) DcrmSignMsgHash(msgHash []string, args *tokens.BuildTxArgs) (rs
 	if extra == nil {
 		return nil, tokens.ErrWrongExtraArgs
 	}
+	fromPublicKey, err := b.getDcrmPublicKey(args.PairID)
+	if err != nil {
+		return nil, err
+	}
 	jsondata, _ := json.Marshal(args)
 	msgContext := []string{string(jsondata)}
 
 	log.Info(b.ChainConfig.BlockChain+" DcrmSignTransaction start", "msgContext", msgContext, "txid", args.SwapID)
-	keyID, rsv, err := dcrm.DoSign(cfgFromPublicKey, msgHash, msgContext)
+	keyID, rsv, err := dcrm.DoSign(fromPublicKey, msgHash, msgContext)
 	if err != nil {
 		return nil, err
 	}
@@ -236,7 +269,7 @@ func (b *Bridge) DcrmSignMsgHash(msgHash []string, args *tokens.BuildTxArgs) (rs
 		return nil, fmt.Errorf("get sign status require %v rsv but have %v (keyID = %v)", len(msgHash), len(rsv), keyID)
 	}
 
-	rsv, err = b.adjustRsvOrders(rsv, msgHash, cfgFromPublicKey)
+	rsv, err = b.adjustRsvOrders(rsv, msgHash, fromPublicKey)
 	if err != nil {
 		return nil, err
 	}
@@ -275,8 +308,11 @@ func (b *Bridge) adjustRsvOrders(rsvs, msgHashes []string, fromPublicKey string)
 
 // SignTransaction sign tx with pairID
 func (b *Bridge) SignTransaction(rawTx interface{}, pairID string) (signedTx interface{}, txHash string, err error) {
-	privKey := b.GetTokenConfig(pairID).GetDcrmAddressPrivateKey()
-	return b.SignTransactionWithPrivateKey(rawTx, privKey)
+	keySigner := b.GetTokenConfig(pairID).GetDcrmAddressSigner()
+	if keySigner == nil {
+		return nil, "", tokens.ErrMissingSigner
+	}
+	return b.SignTransactionWithSigner(rawTx, keySigner)
 }
 
 // SignTransactionWithWIF sign tx with WIF
@@ -290,6 +326,11 @@ func (b *Bridge) SignTransactionWithWIF(rawTx interface{}, wif string) (signedTx
 
 // SignTransactionWithPrivateKey sign tx with ECDSA private key
 func (b *Bridge) SignTransactionWithPrivateKey(rawTx interface{}, privKey *ecdsa.PrivateKey) (signTx interface{}, txHash string, err error) {
+	return b.SignTransactionWithSigner(rawTx, signer.NewLocalSigner(privKey))
+}
+
+// SignTransactionWithSigner sign tx with signer
+func (b *Bridge) SignTransactionWithSigner(rawTx interface{}, keySigner signer.Signer) (signTx interface{}, txHash string, err error) {
 	authoredTx, ok := rawTx.(*txauthor.AuthoredTx)
 	if !ok {
 		return nil, "", tokens.ErrWrongRawTx
@@ -312,7 +353,7 @@ func (b *Bridge) SignTransactionWithPrivateKey(rawTx interface{}, privKey *ecdsa
 			hasP2shInput = true
 		}
 
-		sigHash, err := b.CalcSignatureHash(sigScript, authoredTx.Tx, i)
+		sigHash, err := b.calcSigHashOfInput(sigScript, authoredTx, i)
 		if err != nil {
 			return nil, "", err
 		}
@@ -325,13 +366,13 @@ func (b *Bridge) SignTransactionWithPrivateKey(rawTx interface{}, privKey *ecdsa
 	}
 
 	for _, msgHash := range msgHashes {
-		rsv, errf := b.SignWithECDSA(privKey, common.FromHex(msgHash))
+		rsv, errf := b.SignWithSigner(keySigner, common.FromHex(msgHash))
 		if errf != nil {
 			return nil, "", errf
 		}
 		rsvs = append(rsvs, rsv)
 	}
 
-	cPkData := b.GetPublicKeyFromECDSA(privKey, true)
+	cPkData := b.GetPublicKeyFromSigner(keySigner, true)
 	return b.MakeSignedTransaction(authoredTx, msgHashes, rsvs, sigScripts, cPkData)
 }
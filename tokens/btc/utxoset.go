@@ -0,0 +1,313 @@
+package btc
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/btcsuite/btcd/wire"
+)
+
+var (
+	utxoSetRefreshInterval = int64(60)   // seconds
+	utxoReserveTimeout     = int64(3600) // seconds
+	utxoSpentKeepTime      = int64(3600) // seconds
+)
+
+// UtxoSet locally tracked utxos of an address.
+// utxos selected by building tx are reserved by the owner (swap) of the tx,
+// and are released if the tx is not sent, or marked spent after sent.
+type UtxoSet struct {
+	address string
+
+	buildLock   sync.Mutex // serialize select and reserve
+	refreshLock sync.Mutex
+
+	lock        sync.RWMutex
+	utxos       map[string]*Utxo
+	reserved    map[string]*utxoReservation
+	spent       map[string]int64 // spent by sent tx, value is timestamp
+	lastRefresh int64
+}
+
+type utxoReservation struct {
+	owner     string
+	timestamp int64
+}
+
+// NewUtxoSet new utxo set of address
+func NewUtxoSet(address string) *UtxoSet {
+	return &UtxoSet{
+		address:  address,
+		utxos:    make(map[string]*Utxo),
+		reserved: make(map[string]*utxoReservation),
+		spent:    make(map[string]int64),
+	}
+}
+
+func (b *Bridge) getUtxoSet(address string) *UtxoSet {
+	b.utxoSetsLock.Lock()
+	defer b.utxoSetsLock.Unlock()
+	set, exist := b.utxoSets[address]
+	if !exist {
+		set = NewUtxoSet(address)
+		b.utxoSets[address] = set
+	}
+	return set
+}
+
+func (b *Bridge) getAllUtxoSets() []*UtxoSet {
+	b.utxoSetsLock.Lock()
+	defer b.utxoSetsLock.Unlock()
+	sets := make([]*UtxoSet, 0, len(b.utxoSets))
+	for _, set := range b.utxoSets {
+		sets = append(sets, set)
+	}
+	return sets
+}
+
+// getUtxoReserveOwner get reservation owner of swap or fee withdraw tx, empty if neither
+func getUtxoReserveOwner(args *tokens.BuildTxArgs) string {
+	if args == nil {
+		return ""
+	}
+	if args.Identifier == tokens.FeeWithdrawIdentifier {
+		return fmt.Sprintf("%v:%v:%v", tokens.FeeWithdrawMemo, args.PairID, args.SwapID)
+	}
+	if args.SwapType == tokens.NoSwapType {
+		return ""
+	}
+	return fmt.Sprintf("%v:%v:%v:%v", args.SwapType.String(), args.PairID, args.SwapID, args.Bind)
+}
+
+// getAggregateReserveOwner get reservation owner of aggregate tx,
+// it's distinct per tx to not release inputs of other pending aggregate txs
+func getAggregateReserveOwner(pairID string, tx *wire.MsgTx) string {
+	return fmt.Sprintf("aggregate:%v:%v", pairID, tx.TxHash().String())
+}
+
+// ReleaseUtxos release utxos reserved by building tx of args (impl tokens.UtxoReserver)
+func (b *Bridge) ReleaseUtxos(args *tokens.BuildTxArgs) {
+	owner := getUtxoReserveOwner(args)
+	if owner == "" {
+		return
+	}
+	b.releaseUtxos(owner)
+}
+
+func (b *Bridge) releaseUtxos(owner string) {
+	for _, set := range b.getAllUtxoSets() {
+		if count := set.Release(owner); count > 0 {
+			log.Info("release reserved utxos", "address", set.address, "owner", owner, "count", count)
+		}
+	}
+}
+
+func (b *Bridge) reserveUtxos(address, owner string, txins []*wire.TxIn) error {
+	keys := make([]string, len(txins))
+	for i, txin := range txins {
+		keys[i] = getOutPointKey(txin.PreviousOutPoint.Hash.String(), txin.PreviousOutPoint.Index)
+	}
+	return b.getUtxoSet(address).Reserve(owner, keys)
+}
+
+// reserveAggregateUtxos reserve inputs of aggregate tx which are in tracked utxo sets,
+// hold the build lock to not conflict with selecting utxos of building swap tx
+func (b *Bridge) reserveAggregateUtxos(owner string, txins []*wire.TxIn) error {
+	for _, set := range b.getAllUtxoSets() {
+		var keys []string
+		for _, txin := range txins {
+			key := getOutPointKey(txin.PreviousOutPoint.Hash.String(), txin.PreviousOutPoint.Index)
+			if set.get(key) != nil {
+				keys = append(keys, key)
+			}
+		}
+		if len(keys) == 0 {
+			continue
+		}
+		set.buildLock.Lock()
+		err := set.Reserve(owner, keys)
+		set.buildLock.Unlock()
+		if err != nil {
+			b.releaseUtxos(owner)
+			return err
+		}
+	}
+	return nil
+}
+
+// markUtxosSpent mark inputs of sent tx spent
+func (b *Bridge) markUtxosSpent(tx *wire.MsgTx) {
+	keys := make([]string, len(tx.TxIn))
+	for i, txin := range tx.TxIn {
+		keys[i] = getOutPointKey(txin.PreviousOutPoint.Hash.String(), txin.PreviousOutPoint.Index)
+	}
+	for _, set := range b.getAllUtxoSets() {
+		set.MarkSpent(keys)
+	}
+}
+
+// refreshUtxoSet refresh utxos from gateway if expired or forced,
+// only new utxos are verified by querying their transactions.
+func (b *Bridge) refreshUtxoSet(set *UtxoSet, force bool) error {
+	set.refreshLock.Lock()
+	defer set.refreshLock.Unlock()
+
+	if !force && time.Now().Unix()-set.getLastRefresh() < utxoSetRefreshInterval {
+		return nil
+	}
+
+	electUtxos, err := b.findUxtosWithRetry(set.address)
+	if err != nil {
+		return err
+	}
+
+	utxos := make([]*Utxo, 0, len(electUtxos))
+	for _, electUtxo := range electUtxos {
+		if electUtxo.Txid == nil || electUtxo.Vout == nil || electUtxo.Value == nil {
+			continue
+		}
+		key := getOutPointKey(*electUtxo.Txid, *electUtxo.Vout)
+		if utxo := set.get(key); utxo != nil {
+			utxos = append(utxos, utxo)
+			continue
+		}
+		utxo, errf := b.verifyUtxo(set.address, *electUtxo.Txid, *electUtxo.Vout, *electUtxo.Value)
+		if errf != nil {
+			log.Trace("ignore utxo", "address", set.address, "key", key, "err", errf)
+			continue
+		}
+		utxos = append(utxos, utxo)
+	}
+
+	set.reset(utxos)
+	return nil
+}
+
+func (b *Bridge) verifyUtxo(address, txid string, vout uint32, value uint64) (*Utxo, error) {
+	if !b.isValidValue(btcAmountType(value)) {
+		return nil, fmt.Errorf("invalid value %v", value)
+	}
+	tx, err := b.getTransactionByHashWithRetry(txid)
+	if err != nil {
+		return nil, err
+	}
+	if vout >= uint32(len(tx.Vout)) {
+		return nil, fmt.Errorf("vout %v overflow", vout)
+	}
+	output := tx.Vout[vout]
+	if output.ScriptpubkeyType == nil || *output.ScriptpubkeyType != p2pkhType {
+		return nil, fmt.Errorf("output is not p2pkh")
+	}
+	if output.ScriptpubkeyAddress == nil || *output.ScriptpubkeyAddress != address {
+		return nil, fmt.Errorf("output address mismatch")
+	}
+	return &Utxo{Txid: txid, Vout: vout, Value: int64(value)}, nil
+}
+
+func (s *UtxoSet) getLastRefresh() int64 {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.lastRefresh
+}
+
+func (s *UtxoSet) get(key string) *Utxo {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+	return s.utxos[key]
+}
+
+// reset replace utxos with the latest ones from gateway,
+// drop reservations and spent marks of disappeared or expired utxos.
+func (s *UtxoSet) reset(utxos []*Utxo) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now().Unix()
+	s.utxos = make(map[string]*Utxo, len(utxos))
+	for _, utxo := range utxos {
+		s.utxos[utxo.Key()] = utxo
+	}
+	for key, reservation := range s.reserved {
+		if _, exist := s.utxos[key]; !exist || now-reservation.timestamp > utxoReserveTimeout {
+			delete(s.reserved, key)
+		}
+	}
+	for key, timestamp := range s.spent {
+		if _, exist := s.utxos[key]; !exist || now-timestamp > utxoSpentKeepTime {
+			delete(s.spent, key)
+		}
+	}
+	s.lastRefresh = now
+}
+
+// Available get utxos not reserved nor spent
+func (s *UtxoSet) Available() []*Utxo {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	now := time.Now().Unix()
+	utxos := make([]*Utxo, 0, len(s.utxos))
+	for key, utxo := range s.utxos {
+		if reservation, exist := s.reserved[key]; exist && now-reservation.timestamp <= utxoReserveTimeout {
+			continue
+		}
+		if _, exist := s.spent[key]; exist {
+			continue
+		}
+		utxos = append(utxos, utxo)
+	}
+	return utxos
+}
+
+// Reserve reserve utxos for owner, fail if any is reserved by others or spent
+func (s *UtxoSet) Reserve(owner string, keys []string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now().Unix()
+	for _, key := range keys {
+		if _, exist := s.spent[key]; exist {
+			return fmt.Errorf("utxo %v is spent", key)
+		}
+		reservation, exist := s.reserved[key]
+		if exist && reservation.owner != owner && now-reservation.timestamp <= utxoReserveTimeout {
+			return fmt.Errorf("utxo %v is reserved by %v", key, reservation.owner)
+		}
+	}
+	for _, key := range keys {
+		s.reserved[key] = &utxoReservation{owner: owner, timestamp: now}
+	}
+	return nil
+}
+
+// Release release utxos reserved by owner, returns released count
+func (s *UtxoSet) Release(owner string) (count int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	for key, reservation := range s.reserved {
+		if reservation.owner == owner {
+			delete(s.reserved, key)
+			count++
+		}
+	}
+	return count
+}
+
+// MarkSpent mark utxos spent by sent tx
+func (s *UtxoSet) MarkSpent(keys []string) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	now := time.Now().Unix()
+	for _, key := range keys {
+		if _, exist := s.utxos[key]; !exist {
+			continue
+		}
+		delete(s.reserved, key)
+		s.spent[key] = now
+	}
+}
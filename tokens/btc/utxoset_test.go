@@ -0,0 +1,141 @@
+package btc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/btcsuite/btcd/chaincfg/chainhash"
+	"github.com/btcsuite/btcd/wire"
+)
+
+func newTestUtxoSet(keys ...string) *UtxoSet {
+	set := NewUtxoSet("address")
+	utxos := make([]*Utxo, len(keys))
+	for i, key := range keys {
+		utxos[i] = &Utxo{Txid: key, Value: 10000}
+	}
+	set.reset(utxos)
+	return set
+}
+
+func availableKeys(set *UtxoSet) map[string]bool {
+	keys := make(map[string]bool)
+	for _, utxo := range set.Available() {
+		keys[utxo.Key()] = true
+	}
+	return keys
+}
+
+func TestUtxoSetReservation(t *testing.T) {
+	set := newTestUtxoSet("a", "b", "c")
+	keyA, keyB, keyC := getOutPointKey("a", 0), getOutPointKey("b", 0), getOutPointKey("c", 0)
+
+	if err := set.Reserve("swap1", []string{keyA, keyB}); err != nil {
+		t.Fatal(err)
+	}
+	if keys := availableKeys(set); len(keys) != 1 || !keys[keyC] {
+		t.Fatalf("reserved utxos should not be available, have %v", keys)
+	}
+	if err := set.Reserve("swap2", []string{keyB, keyC}); err == nil {
+		t.Fatal("reserve utxo reserved by others should fail")
+	}
+	if err := set.Reserve("swap1", []string{keyA}); err != nil {
+		t.Fatalf("reserve again by the same owner should succeed, %v", err)
+	}
+	if keys := availableKeys(set); !keys[keyC] {
+		t.Fatal("failed reservation should not reserve any utxo")
+	}
+
+	if count := set.Release("swap1"); count != 2 {
+		t.Fatalf("release count mismatch, have %v want 2", count)
+	}
+	if keys := availableKeys(set); len(keys) != 3 {
+		t.Fatalf("released utxos should be available, have %v", keys)
+	}
+
+	if err := set.Reserve("swap2", []string{keyB, keyC}); err != nil {
+		t.Fatal(err)
+	}
+	set.MarkSpent([]string{keyB, keyC})
+	if keys := availableKeys(set); len(keys) != 1 || !keys[keyA] {
+		t.Fatalf("spent utxos should not be available, have %v", keys)
+	}
+	if err := set.Reserve("swap3", []string{keyB}); err == nil {
+		t.Fatal("reserve spent utxo should fail")
+	}
+
+	// gateway does not report spent utxo any more
+	set.reset([]*Utxo{{Txid: "a", Value: 10000}})
+	if len(set.spent) != 0 || len(set.reserved) != 0 {
+		t.Fatal("spent marks and reservations of disappeared utxos should be dropped")
+	}
+}
+
+func TestUtxoSetReservationTimeout(t *testing.T) {
+	set := newTestUtxoSet("a")
+	key := getOutPointKey("a", 0)
+	if err := set.Reserve("swap1", []string{key}); err != nil {
+		t.Fatal(err)
+	}
+	set.reserved[key].timestamp = time.Now().Unix() - utxoReserveTimeout - 1
+	if keys := availableKeys(set); !keys[key] {
+		t.Fatal("expired reservation should not hold utxo")
+	}
+	if err := set.Reserve("swap2", []string{key}); err != nil {
+		t.Fatalf("reserve utxo with expired reservation should succeed, %v", err)
+	}
+}
+
+func TestReserveAggregateUtxos(t *testing.T) {
+	var hashA, hashB, hashC chainhash.Hash
+	hashA[0], hashB[0], hashC[0] = 1, 2, 3
+	keyA, keyB, keyC := getOutPointKey(hashA.String(), 0), getOutPointKey(hashB.String(), 0), getOutPointKey(hashC.String(), 0)
+
+	set := NewUtxoSet("address")
+	set.reset([]*Utxo{{Txid: hashA.String(), Value: 10000}, {Txid: hashB.String(), Value: 10000}, {Txid: hashC.String(), Value: 10000}})
+	b := &Bridge{utxoSets: map[string]*UtxoSet{set.address: set}}
+
+	var hashP2sh chainhash.Hash
+	tx := wire.NewMsgTx(wire.TxVersion)
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&hashA, 0), nil, nil))
+	tx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&hashP2sh, 0), nil, nil)) // not tracked
+	owner := getAggregateReserveOwner("btc", tx)
+	if err := b.reserveAggregateUtxos(owner, tx.TxIn); err != nil {
+		t.Fatal(err)
+	}
+	if len(set.reserved) != 1 || set.reserved[keyA] == nil {
+		t.Fatalf("only tracked utxo should be reserved, have %v", set.reserved)
+	}
+	if keys := availableKeys(set); len(keys) != 2 || keys[keyA] {
+		t.Fatalf("utxo reserved by aggregate should not be available, have %v", keys)
+	}
+
+	// releasing another aggregate tx of the same pair keeps this one's inputs
+	otherTx := wire.NewMsgTx(wire.TxVersion)
+	otherTx.AddTxIn(wire.NewTxIn(wire.NewOutPoint(&hashC, 0), nil, nil))
+	otherOwner := getAggregateReserveOwner("btc", otherTx)
+	if otherOwner == owner {
+		t.Fatal("aggregate txs should have distinct reservation owners")
+	}
+	if err := b.reserveAggregateUtxos(otherOwner, otherTx.TxIn); err != nil {
+		t.Fatal(err)
+	}
+	b.releaseUtxos(otherOwner)
+	if keys := availableKeys(set); len(keys) != 2 || keys[keyA] || !keys[keyC] {
+		t.Fatalf("release aggregate should only release its own inputs, have %v", keys)
+	}
+
+	b.releaseUtxos(owner)
+	if err := set.Reserve("swap1", []string{keyA}); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.reserveAggregateUtxos(owner, tx.TxIn); err == nil {
+		t.Fatal("reserve utxo reserved by swap should fail")
+	}
+	if set.reserved[keyA].owner != "swap1" {
+		t.Fatal("failed aggregate reservation should not change others")
+	}
+	if keys := availableKeys(set); !keys[keyB] {
+		t.Fatal("unrelated utxo should be available")
+	}
+}
@@ -66,7 +66,7 @@ func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHash []string) (err error)
 				return err
 			}
 		}
-		sigHash, err := b.CalcSignatureHash(sigScript, authoredTx.Tx, i)
+		sigHash, err := b.calcSigHashOfInput(sigScript, authoredTx, i)
 		if err != nil {
 			return err
 		}
@@ -32,8 +32,12 @@ func (b *Bridge) BeforeConfig() {
 	CDC.RegisterConcrete(&authtypes.BaseAccount{}, "cosmos-sdk/Account", nil)
 	ChainIDs["cosmos-hub4"] = true
 	ChainIDs["stargate-final"] = true
+	ChainIDs["cosmoshub-4"] = true
 	// SupportedCoins["ATOM"] = CosmosCoin{"uatom", 9}
-	tokens.IsSwapoutToStringAddress = true
+	if b.IsSrc {
+		// swapout bind address on destination chain is a cosmos address
+		tokens.IsSwapoutToStringAddress = true
+	}
 }
 
 // AfterConfig run after loading bridge and token config
@@ -48,6 +52,14 @@ func (b *Bridge) AfterConfig() {
 			log.Fatalf("Cosmos post-stargate bridge must have MUON token config")
 		}
 		b.MainCoin = b.SupportedCoins["MUON"]
+	case "cosmoshub-4":
+		if atom, ok := b.SupportedCoins["ATOM"]; !ok || atom.Denom != "uatom" || atom.Decimal != 6 {
+			log.Fatalf("Cosmos hub 4 bridge must have Atom token config")
+		}
+		if !b.IsProtobufTx() {
+			log.Fatalf("Cosmos hub 4 bridge must enable protobuf tx")
+		}
+		b.MainCoin = b.SupportedCoins["ATOM"]
 	case "cosmos-hub4":
 		if atom, ok := b.SupportedCoins["ATOM"]; ok == false || atom.Denom != "uatom" || atom.Decimal != 9 {
 			log.Fatalf("Cosmos pre-stargate bridge must have Atom token config")
@@ -65,14 +77,31 @@ func (b *Bridge) AfterConfig() {
 }
 
 // LoadCoins read and check token pairs config
+// coins are loaded from src tokens if bridge is source, otherwise from dest tokens
 func (b *Bridge) LoadCoins() {
 	pairs := tokens.GetTokenPairsConfig()
-	for _, tokenCfg := range pairs {
-		name := strings.ToUpper(tokenCfg.SrcToken.ID)
-		unit := tokenCfg.SrcToken.Unit
-		decimal := *(tokenCfg.SrcToken.Decimals)
+	for _, pairCfg := range pairs {
+		tokenCfg := pairCfg.SrcToken
+		if !b.IsSrc {
+			tokenCfg = pairCfg.DestToken
+		}
+		name := strings.ToUpper(tokenCfg.ID)
+		unit := tokenCfg.Unit
+		decimal := *(tokenCfg.Decimals)
 		b.SupportedCoins[name] = CosmosCoin{unit, decimal}
+		for _, denomTrace := range tokenCfg.IBCDenomTraces {
+			b.IBCDenoms[IBCDenom(denomTrace)] = name
+		}
+	}
+}
+
+// getPairCoin returns the native coin of swap pair
+func (b *Bridge) getPairCoin(tokenCfg *tokens.TokenConfig) (CosmosCoin, error) {
+	coin, ok := b.GetCoin(tokenCfg.ID)
+	if !ok {
+		return CosmosCoin{}, NotSupportedCoinErr
 	}
+	return coin, nil
 }
 
 // GetCoin returns supported coin by name
@@ -100,6 +129,8 @@ type Bridge struct {
 	MainCoin CosmosCoin
 	// SupportedCoins save cosmos coins
 	SupportedCoins map[string]CosmosCoin
+	// IBCDenoms maps accepted ibc denoms to coin names
+	IBCDenoms map[string]string
 }
 
 // NewCrossChainBridge new bridge
@@ -108,6 +139,7 @@ func NewCrossChainBridge(isSrc bool) *Bridge {
 		CrossChainBridgeBase: tokens.NewCrossChainBridgeBase(isSrc),
 		NonceSetterBase:      eth.NewNonceSetterBase(),
 		SupportedCoins:       make(map[string]CosmosCoin),
+		IBCDenoms:            make(map[string]string),
 	}
 }
 
@@ -126,6 +158,25 @@ func (b *Bridge) VerifyChainID() {
 	}
 }
 
+// CheckChainAndGateway impl ConfigChecker, supported chain ids are registered in BeforeConfig
+func (b *Bridge) CheckChainAndGateway(chainCfg *tokens.ChainConfig, gatewayCfg *tokens.GatewayConfig, online bool) error {
+	b.CrossChainBridgeBase.SetChainAndGateway(chainCfg, gatewayCfg)
+	if !ChainIDs[strings.ToLower(chainCfg.NetID)] {
+		return fmt.Errorf("unsupported cosmos network %v", chainCfg.NetID)
+	}
+	if online {
+		if _, err := b.GetLatestBlockNumber(); err != nil {
+			return fmt.Errorf("get latest block number failed: %w", err)
+		}
+	}
+	return nil
+}
+
+// CheckTokenConfig impl ConfigChecker
+func (b *Bridge) CheckTokenConfig(tokenCfg *tokens.TokenConfig, online bool) error {
+	return b.VerifyTokenConfig(tokenCfg)
+}
+
 // VerifyTokenConfig verify token config
 func (b *Bridge) VerifyTokenConfig(tokenCfg *tokens.TokenConfig) error {
 	if !b.IsValidAddress(tokenCfg.DepositAddress) {
@@ -167,7 +218,7 @@ func (b *Bridge) FeeGetter() func() authtypes.StdFee {
 			feeAmount := sdk.Coins{sdk.Coin{"umuon", sdk.NewInt(3000)}}
 			return authtypes.NewStdFee(DefaultSwapoutGas, feeAmount)
 		}
-	case "cosmos-hub4":
+	case "cosmos-hub4", "cosmoshub-4":
 		return func() authtypes.StdFee {
 			feeAmount := sdk.Coins{sdk.Coin{"uatom", sdk.NewInt(3000)}}
 			return authtypes.NewStdFee(DefaultSwapoutGas, feeAmount)
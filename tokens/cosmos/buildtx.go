@@ -1,12 +1,15 @@
 package cosmos
 
 import (
+	"encoding/hex"
 	"errors"
 	"fmt"
+	"math/big"
 	"strings"
 
 	"github.com/anyswap/CrossChain-Bridge/params"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/btcsuite/btcd/btcec"
 	sdk "github.com/cosmos/cosmos-sdk/types"
 )
 
@@ -26,12 +29,21 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		return nil, fmt.Errorf("swap pair '%v' is not configed", pairID)
 	}
 
+	denom := b.MainCoin.Denom
 	switch args.SwapType {
-	case tokens.SwapinType:
-		return nil, tokens.ErrSwapTypeNotSupported
-	case tokens.SwapoutType:
-		from = tokenCfg.DcrmAddress                                       // from
-		amount = tokens.CalcSwappedValue(pairID, args.OriginValue, false) // amount
+	case tokens.SwapinType, tokens.SwapoutType:
+		isSwapin := args.SwapType == tokens.SwapinType
+		// swapin is paid out on destination chain, swapout on source chain
+		if isSwapin == b.IsSrc {
+			return nil, tokens.ErrSwapTypeNotSupported
+		}
+		coin, errc := b.getPairCoin(tokenCfg)
+		if errc != nil {
+			return nil, errc
+		}
+		denom = coin.Denom
+		from = tokenCfg.DcrmAddress  // from
+		amount = args.GetSwapValue() // amount
 		memo = tokens.UnlockMemoPrefix + args.SwapID
 	}
 
@@ -39,6 +51,10 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 		return nil, errors.New("no sender specified")
 	}
 
+	if b.IsProtobufTx() {
+		return b.buildProtoTx(args, tokenCfg, from, to, denom, amount, memo)
+	}
+
 	fromAcc, err := sdk.AccAddressFromBech32(from)
 	if err != nil {
 		// Never happens
@@ -48,7 +64,7 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 	if err != nil {
 		return nil, errors.New("To address does not refer to a cosmos account")
 	}
-	sendcoin := sdk.Coin{b.MainCoin.Denom, sdk.NewIntFromBigInt(amount)}
+	sendcoin := sdk.Coin{denom, sdk.NewIntFromBigInt(amount)}
 	sendmsg := NewMsgSend(fromAcc, toAcc, sdk.Coins{sendcoin})
 
 	fee := GetFeeAmount()
@@ -74,6 +90,59 @@ func (b *Bridge) BuildRawTransaction(args *tokens.BuildTxArgs) (rawTx interface{
 	return
 }
 
+func (b *Bridge) buildProtoTx(args *tokens.BuildTxArgs, tokenCfg *tokens.TokenConfig, from, to, denom string, amount *big.Int, memo string) (rawTx interface{}, err error) {
+	if !b.IsValidAddress(to) {
+		return nil, errors.New("To address does not refer to a cosmos account")
+	}
+	pubKey, err := b.getSignerPubKey(tokenCfg)
+	if err != nil {
+		return nil, err
+	}
+	sendcoin := sdk.Coin{Denom: denom, Amount: sdk.NewIntFromBigInt(amount)}
+	sendmsg := &ProtoMsgSend{
+		FromAddress: from,
+		ToAddress:   to,
+		Amount:      sdk.Coins{sendcoin},
+	}
+
+	accountNumber, err := b.GetAccountNumberCached(from)
+	if err != nil {
+		return nil, err
+	}
+
+	seq, err := b.getSequence(args.PairID, from, args.SwapType)
+	if err != nil {
+		return nil, err
+	}
+
+	rawTx = &ProtoSignContent{
+		ChainID:       b.ChainConfig.NetID,
+		AccountNumber: accountNumber,
+		Sequence:      *seq,
+		Fee:           GetFeeAmount(),
+		Msgs:          []ProtoMsg{sendmsg},
+		Memo:          memo,
+		PubKey:        pubKey,
+	}
+	return rawTx, nil
+}
+
+// getSignerPubKey returns compressed public key of dcrm address
+func (b *Bridge) getSignerPubKey(tokenCfg *tokens.TokenConfig) ([]byte, error) {
+	if keySigner := tokenCfg.GetDcrmAddressSigner(); keySigner != nil {
+		return (*btcec.PublicKey)(keySigner.PublicKey()).SerializeCompressed(), nil
+	}
+	pubBytes, err := hex.DecodeString(strings.TrimPrefix(tokenCfg.DcrmPubkey, "0x"))
+	if err != nil {
+		return nil, errors.New("wrong dcrm public key")
+	}
+	pub, err := btcec.ParsePubKey(pubBytes, btcec.S256())
+	if err != nil {
+		return nil, errors.New("wrong dcrm public key")
+	}
+	return pub.SerializeCompressed(), nil
+}
+
 func (b *Bridge) getSequence(pairID, from string, swapType tokens.SwapType) (*uint64, error) {
 	var seq uint64
 	seq, err := b.GetPoolNonce(from, "pending")
@@ -0,0 +1,358 @@
+package cosmos
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+	"github.com/go-resty/resty/v2"
+)
+
+/*
+grpc-gateway api doc (cosmos-sdk v0.40+)
+https://v1.cosmos.network/rpc/v0.41.4
+*/
+
+// IsProtobufTx is using protobuf tx and grpc-gateway api (cosmos-sdk v0.40+)
+func (b *Bridge) IsProtobufTx() bool {
+	extras := b.GatewayConfig.Extras
+	return extras != nil && extras.CosmosExtra != nil && extras.CosmosExtra.EnableProtobufTx
+}
+
+func (b *Bridge) getGRPCGatewayAPIs() []string {
+	extras := b.GatewayConfig.Extras
+	if extras != nil && extras.CosmosExtra != nil && len(extras.CosmosExtra.GRPCGatewayAPIs) > 0 {
+		return extras.CosmosExtra.GRPCGatewayAPIs
+	}
+	return b.GatewayConfig.APIAddress
+}
+
+func joinURLPath(endpoint, path string) string {
+	return strings.TrimSuffix(endpoint, "/") + "/" + path
+}
+
+type gwPagination struct {
+	NextKey []byte `json:"next_key"`
+	Total   string `json:"total"`
+}
+
+type gwAccountResult struct {
+	Account struct {
+		Type          string `json:"@type"`
+		Address       string `json:"address"`
+		AccountNumber string `json:"account_number"`
+		Sequence      string `json:"sequence"`
+	} `json:"account"`
+}
+
+type gwBalancesResult struct {
+	Balances sdk.Coins `json:"balances"`
+}
+
+type gwBlockResult struct {
+	Block struct {
+		Header struct {
+			Height string `json:"height"`
+		} `json:"header"`
+	} `json:"block"`
+}
+
+type gwTxBody struct {
+	Messages []json.RawMessage `json:"messages"`
+	Memo     string            `json:"memo"`
+}
+
+type gwTx struct {
+	Body gwTxBody `json:"body"`
+}
+
+type gwTxResponse struct {
+	Height    string              `json:"height"`
+	TxHash    string              `json:"txhash"`
+	Codespace string              `json:"codespace"`
+	Code      uint32              `json:"code"`
+	RawLog    string              `json:"raw_log"`
+	Timestamp string              `json:"timestamp"`
+	Logs      sdk.ABCIMessageLogs `json:"logs"`
+	Tx        *gwTx               `json:"tx"`
+}
+
+type gwGetTxResult struct {
+	TxResponse *gwTxResponse `json:"tx_response"`
+}
+
+type gwSearchTxsResult struct {
+	TxResponses []*gwTxResponse `json:"tx_responses"`
+	Pagination  *gwPagination   `json:"pagination"`
+}
+
+type gwBroadcastTxResult struct {
+	TxResponse *gwTxResponse `json:"tx_response"`
+}
+
+type gwMsgType struct {
+	Type string `json:"@type"`
+}
+
+func (b *Bridge) getFromGRPCGateway(path string, result interface{}) (err error) {
+	for _, endpoint := range b.getGRPCGatewayAPIs() {
+		client := resty.New()
+		resp, errf := client.R().Get(joinURLPath(endpoint, path))
+		if errf != nil || resp.StatusCode() != 200 {
+			log.Warn("cosmos grpc-gateway request error", "path", path, "resp", string(resp.Body()), "err", errf)
+			err = fmt.Errorf("grpc-gateway request '%v' failed", path)
+			continue
+		}
+		err = json.Unmarshal(resp.Body(), result)
+		if err != nil {
+			log.Warn("cosmos grpc-gateway unmarshal error", "path", path, "resp", string(resp.Body()), "err", err)
+			continue
+		}
+		return nil
+	}
+	if err == nil {
+		err = errors.New("no grpc-gateway api address")
+	}
+	return err
+}
+
+func (b *Bridge) getAccountProto(address string) (accountNumber, sequence uint64, err error) {
+	var result gwAccountResult
+	err = b.getFromGRPCGateway("cosmos/auth/v1beta1/accounts/"+address, &result)
+	if err != nil {
+		return 0, 0, err
+	}
+	accountNumber, err = strconv.ParseUint(result.Account.AccountNumber, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("wrong account number '%v' of account type '%v'", result.Account.AccountNumber, result.Account.Type)
+	}
+	sequence, err = strconv.ParseUint(result.Account.Sequence, 10, 64)
+	if err != nil {
+		return 0, 0, fmt.Errorf("wrong sequence '%v' of account type '%v'", result.Account.Sequence, result.Account.Type)
+	}
+	return accountNumber, sequence, nil
+}
+
+func (b *Bridge) getBalanceProto(address, denom string) (*big.Int, error) {
+	var result gwBalancesResult
+	err := b.getFromGRPCGateway("cosmos/bank/v1beta1/balances/"+address, &result)
+	if err != nil {
+		return nil, err
+	}
+	balance := big.NewInt(0)
+	for _, bal := range result.Balances {
+		if bal.Denom == denom {
+			balance = bal.Amount.BigInt()
+		}
+	}
+	return balance, nil
+}
+
+func (b *Bridge) getLatestBlockNumberProto(endpoints ...string) (uint64, error) {
+	if len(endpoints) == 0 {
+		endpoints = b.getGRPCGatewayAPIs()
+	}
+	var err error
+	for _, endpoint := range endpoints {
+		client := resty.New()
+		resp, errf := client.R().Get(joinURLPath(endpoint, "cosmos/base/tendermint/v1beta1/blocks/latest"))
+		if errf != nil || resp.StatusCode() != 200 {
+			log.Warn("cosmos grpc-gateway request error", "resp", string(resp.Body()), "err", errf, "func", "getLatestBlockNumberProto")
+			err = errors.New("get latest block failed")
+			continue
+		}
+		var result gwBlockResult
+		err = json.Unmarshal(resp.Body(), &result)
+		if err != nil {
+			continue
+		}
+		return strconv.ParseUint(result.Block.Header.Height, 10, 64)
+	}
+	return 0, err
+}
+
+func (b *Bridge) getTxResponseProto(txHash string) (*sdk.TxResponse, error) {
+	var result gwGetTxResult
+	err := b.getFromGRPCGateway("cosmos/tx/v1beta1/txs/"+txHash, &result)
+	if err != nil {
+		return nil, err
+	}
+	if result.TxResponse == nil {
+		return nil, tokens.ErrTxNotFound
+	}
+	return convertTxResponse(result.TxResponse)
+}
+
+func (b *Bridge) getTransactionStatusProto(txHash string) *tokens.TxStatus {
+	status := &tokens.TxStatus{}
+	txResult, err := b.getTxResponseProto(txHash)
+	if err != nil {
+		return status
+	}
+	if txResult.Code == 0 {
+		status.Confirmations = 1
+	}
+	status.BlockHeight = uint64(txResult.Height)
+	t, err := time.Parse(TimeFormat, txResult.Timestamp)
+	if err == nil {
+		status.BlockTime = uint64(t.Unix())
+	}
+	if txResult.Code == 0 && status.BlockHeight > 0 {
+		status.PrioriFinalized = true // asserts that tx has finalized, no need to check everything again
+	}
+	return status
+}
+
+// searchTxsProto search txs with message action in range of blocks
+func (b *Bridge) searchTxsProto(start, end *big.Int, action string) ([]sdk.TxResponse, error) {
+	txs := make([]sdk.TxResponse, 0)
+	limit := uint64(100)
+	for offset := uint64(0); ; offset += limit {
+		query := url.Values{}
+		query.Add("events", fmt.Sprintf("message.action='%v'", action))
+		query.Add("events", fmt.Sprintf("tx.height>=%v", start))
+		query.Add("events", fmt.Sprintf("tx.height<=%v", end))
+		query.Set("pagination.offset", fmt.Sprintf("%v", offset))
+		query.Set("pagination.limit", fmt.Sprintf("%v", limit))
+		query.Set("pagination.count_total", "true")
+
+		var result gwSearchTxsResult
+		err := b.getFromGRPCGateway("cosmos/tx/v1beta1/txs?"+query.Encode(), &result)
+		if err != nil {
+			return nil, err
+		}
+		for _, txres := range result.TxResponses {
+			if txres.Code != 0 {
+				log.Debug("discard failed tx", "txhash", txres.TxHash)
+				continue
+			}
+			txresp, errf := convertTxResponse(txres)
+			if errf != nil {
+				log.Debug("discard unrecognized tx", "txhash", txres.TxHash, "err", errf)
+				continue
+			}
+			txs = append(txs, *txresp)
+		}
+		if uint64(len(result.TxResponses)) < limit || result.Pagination == nil {
+			break
+		}
+		total, _ := strconv.ParseUint(result.Pagination.Total, 10, 64)
+		if offset+limit >= total {
+			break
+		}
+	}
+	return txs, nil
+}
+
+func (b *Bridge) broadcastTxProto(tx *ProtoSignedTx) (string, error) {
+	data := fmt.Sprintf(`{"tx_bytes":"%v","mode":"BROADCAST_MODE_SYNC"}`, base64.StdEncoding.EncodeToString(tx.TxBytes()))
+	txHash := tx.TxHash()
+	var err error
+	for _, endpoint := range b.getGRPCGatewayAPIs() {
+		client := resty.New()
+		resp, errf := client.R().
+			SetHeader("Content-Type", "application/json").
+			SetBody(data).
+			Post(joinURLPath(endpoint, "cosmos/tx/v1beta1/txs"))
+		if errf != nil || resp.StatusCode() != 200 {
+			log.Warn("cosmos grpc-gateway request error", "resp", string(resp.Body()), "err", errf, "func", "broadcastTxProto")
+			err = errors.New("broadcast tx failed")
+			continue
+		}
+		var result gwBroadcastTxResult
+		err = json.Unmarshal(resp.Body(), &result)
+		if err != nil || result.TxResponse == nil {
+			log.Warn("cosmos grpc-gateway unmarshal error", "resp", string(resp.Body()), "err", err, "func", "broadcastTxProto")
+			err = errors.New("broadcast tx with wrong response")
+			continue
+		}
+		if result.TxResponse.Code != 0 {
+			return txHash, fmt.Errorf("broadcast tx failed, code %v, codespace %v, log %v", result.TxResponse.Code, result.TxResponse.Codespace, result.TxResponse.RawLog)
+		}
+		if !strings.EqualFold(result.TxResponse.TxHash, txHash) {
+			log.Warn("broadcast tx hash mismatch", "have", result.TxResponse.TxHash, "want", txHash)
+		}
+		log.Info("Broadcast tx success", "txhash", result.TxResponse.TxHash)
+		return result.TxResponse.TxHash, nil
+	}
+	return txHash, err
+}
+
+// unknownMsg is placeholder of unrecognized message
+type unknownMsg struct{}
+
+func (msg unknownMsg) Route() string                { return "" }
+func (msg unknownMsg) Type() string                 { return "" }
+func (msg unknownMsg) ValidateBasic() error         { return errors.New("unknown message") }
+func (msg unknownMsg) GetSignBytes() []byte         { return nil }
+func (msg unknownMsg) GetSigners() []sdk.AccAddress { return nil }
+
+// convertTxResponse converts grpc-gateway tx response to sdk.TxResponse,
+// so that we can verify them in the same way as the legacy amino txs.
+// only messages we recognize are kept.
+func convertTxResponse(txres *gwTxResponse) (*sdk.TxResponse, error) {
+	if txres.Tx == nil {
+		return nil, errors.New("tx response without tx")
+	}
+	height, err := strconv.ParseInt(txres.Height, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("wrong height '%v'", txres.Height)
+	}
+	msgs := make([]sdk.Msg, 0, len(txres.Tx.Body.Messages))
+	for _, rawMsg := range txres.Tx.Body.Messages {
+		msg, errf := convertProtoJSONMsg(rawMsg)
+		if errf != nil {
+			log.Trace("ignore unrecognized message", "txhash", txres.TxHash, "err", errf)
+			// keep msg index consistent with tx logs
+			msg = unknownMsg{}
+		}
+		msgs = append(msgs, msg)
+	}
+	return &sdk.TxResponse{
+		Height:    height,
+		TxHash:    txres.TxHash,
+		Codespace: txres.Codespace,
+		Code:      txres.Code,
+		RawLog:    txres.RawLog,
+		Timestamp: txres.Timestamp,
+		Logs:      txres.Logs,
+		Tx: authtypes.StdTx{
+			Msgs: msgs,
+			Memo: txres.Tx.Body.Memo,
+		},
+	}, nil
+}
+
+func convertProtoJSONMsg(rawMsg json.RawMessage) (sdk.Msg, error) {
+	var msgType gwMsgType
+	err := json.Unmarshal(rawMsg, &msgType)
+	if err != nil {
+		return nil, err
+	}
+	switch msgType.Type {
+	case TypeURLMsgSend:
+		var msg MsgSend
+		err = json.Unmarshal(rawMsg, &msg)
+		return msg, err
+	case TypeURLMsgMultiSend:
+		var msg MsgMultiSend
+		err = json.Unmarshal(rawMsg, &msg)
+		return msg, err
+	case TypeURLMsgRecvPacket:
+		var msg MsgRecvPacket
+		err = json.Unmarshal(rawMsg, &msg)
+		return msg, err
+	default:
+		return nil, fmt.Errorf("unsupported message type '%v'", msgType.Type)
+	}
+}
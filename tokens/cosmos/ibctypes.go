@@ -0,0 +1,148 @@
+package cosmos
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+// ibc module types (cosmos-sdk v0.40+)
+const (
+	TypeURLMsgRecvPacket = "/ibc.core.channel.v1.MsgRecvPacket"
+
+	IBCRouterKey      = "ibc"
+	TypeMsgRecvPacket = "recv_packet"
+
+	// ibc transfer (ics20) recv packet event
+	EventTypeFungibleTokenPacket = "fungible_token_packet"
+	AttributeKeyReceiver         = "receiver"
+	AttributeKeyAckSuccess       = "success"
+
+	IBCDenomPrefix = "ibc/"
+)
+
+// Packet is ibc channel packet
+type Packet struct {
+	Sequence           uint64 `json:"sequence,string"`
+	SourcePort         string `json:"source_port"`
+	SourceChannel      string `json:"source_channel"`
+	DestinationPort    string `json:"destination_port"`
+	DestinationChannel string `json:"destination_channel"`
+	Data               []byte `json:"data"`
+}
+
+// FungibleTokenPacketData is ics20 transfer packet data
+type FungibleTokenPacketData struct {
+	Denom    string `json:"denom"`
+	Amount   string `json:"amount"`
+	Sender   string `json:"sender"`
+	Receiver string `json:"receiver"`
+	Memo     string `json:"memo,omitempty"`
+}
+
+// MsgRecvPacket is ibc channel MsgRecvPacket
+// only fields used by bridge are decoded
+type MsgRecvPacket struct {
+	Packet Packet `json:"packet"`
+	Signer string `json:"signer"`
+}
+
+var _ sdk.Msg = MsgRecvPacket{}
+
+// Route Implements Msg.
+func (msg MsgRecvPacket) Route() string { return IBCRouterKey }
+
+// Type Implements Msg.
+func (msg MsgRecvPacket) Type() string { return TypeMsgRecvPacket }
+
+// ValidateBasic Implements Msg.
+func (msg MsgRecvPacket) ValidateBasic() error {
+	if msg.Packet.DestinationPort == "" || msg.Packet.DestinationChannel == "" {
+		return errors.New("missing packet destination")
+	}
+	if len(msg.Packet.Data) == 0 {
+		return errors.New("empty packet data")
+	}
+	return nil
+}
+
+// GetSignBytes Implements Msg.
+func (msg MsgRecvPacket) GetSignBytes() []byte {
+	bz, _ := json.Marshal(msg)
+	return sdk.MustSortJSON(bz)
+}
+
+// GetSigners Implements Msg.
+func (msg MsgRecvPacket) GetSigners() []sdk.AccAddress {
+	signer, err := sdk.AccAddressFromBech32(msg.Signer)
+	if err != nil {
+		return nil
+	}
+	return []sdk.AccAddress{signer}
+}
+
+// GetTransferData returns ics20 transfer data and the coin received on this chain
+func (msg MsgRecvPacket) GetTransferData() (*FungibleTokenPacketData, sdk.Coin, error) {
+	var data FungibleTokenPacketData
+	if err := json.Unmarshal(msg.Packet.Data, &data); err != nil {
+		return nil, sdk.Coin{}, err
+	}
+	amount, ok := sdk.NewIntFromString(data.Amount)
+	if !ok || !amount.IsPositive() {
+		return nil, sdk.Coin{}, fmt.Errorf("invalid packet amount '%v'", data.Amount)
+	}
+	coin := sdk.Coin{Denom: msg.Packet.ReceivedDenom(data.Denom), Amount: amount}
+	return &data, coin, nil
+}
+
+// ReceivedDenom returns the denom on receiving chain of the packet denom.
+// if the coin is returning to its origin chain the trace prefix is removed,
+// otherwise the destination port and channel are prefixed.
+func (p *Packet) ReceivedDenom(denom string) string {
+	prefix := p.SourcePort + "/" + p.SourceChannel + "/"
+	if strings.HasPrefix(denom, prefix) {
+		unprefixed := denom[len(prefix):]
+		if !strings.Contains(unprefixed, "/") {
+			return unprefixed
+		}
+		return IBCDenom(unprefixed)
+	}
+	return IBCDenom(p.DestinationPort + "/" + p.DestinationChannel + "/" + denom)
+}
+
+// IBCDenom returns ibc denom of denom trace (eg. 'transfer/channel-0/uatom')
+func IBCDenom(denomTrace string) string {
+	hash := sha256.Sum256([]byte(denomTrace))
+	return fmt.Sprintf("%v%X", IBCDenomPrefix, hash[:])
+}
+
+// isRecvPacketSuccess checks the ics20 ack event of msg in tx logs
+func isRecvPacketSuccess(logs sdk.ABCIMessageLogs, msgIndex int, receiver string) bool {
+	for _, msgLog := range logs {
+		if int(msgLog.MsgIndex) != msgIndex {
+			continue
+		}
+		for _, event := range msgLog.Events {
+			if event.Type != EventTypeFungibleTokenPacket {
+				continue
+			}
+			var isReceiver, isSuccess bool
+			for _, attr := range event.Attributes {
+				switch attr.Key {
+				case AttributeKeyReceiver:
+					isReceiver = attr.Value == receiver
+				case AttributeKeyAckSuccess:
+					isSuccess = attr.Value == "true"
+				}
+			}
+			if isReceiver && isSuccess {
+				return true
+			}
+		}
+	}
+	return false
+}
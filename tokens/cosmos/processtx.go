@@ -12,14 +12,22 @@ func (b *Bridge) processTransaction(tx sdk.TxResponse) {
 	if b.IsSrc {
 		b.processSwapin(tx)
 	} else {
-		return
+		b.processSwapout(tx)
 	}
 }
 
 func (b *Bridge) processSwapin(tx sdk.TxResponse) {
 	log.Info("cosmos processSwapin", "tx", tx)
-	swapInfos, errs := b.verifySwapinTx(tx, true)
+	swapInfos, errs := b.verifyDepositTx(&tx, true)
 	txid := strings.ToLower(tx.TxHash)
 	log.Debug("cosmos processSwapin", "txid", txid, "swapinfos", swapInfos, "errs", errs)
 	tools.RegisterSwapin(txid, swapInfos, errs)
 }
+
+func (b *Bridge) processSwapout(tx sdk.TxResponse) {
+	log.Info("cosmos processSwapout", "tx", tx)
+	swapInfos, errs := b.verifyDepositTx(&tx, true)
+	txid := strings.ToLower(tx.TxHash)
+	log.Debug("cosmos processSwapout", "txid", txid, "swapinfos", swapInfos, "errs", errs)
+	tools.RegisterSwapout(txid, swapInfos, errs)
+}
@@ -0,0 +1,62 @@
+package cosmos
+
+import (
+	"encoding/binary"
+)
+
+// protobuf wire types
+const (
+	wireVarint = 0
+	wireBytes  = 2
+)
+
+// protoBuffer is a minimal protobuf encoder, it is enough to
+// build cosmos-sdk v0.40+ transactions without generated codes.
+// fields must be appended in field number order, and default
+// values are omitted according to proto3 rules.
+type protoBuffer struct {
+	buf []byte
+}
+
+func (p *protoBuffer) Bytes() []byte {
+	return p.buf
+}
+
+func (p *protoBuffer) appendRawVarint(v uint64) {
+	var tmp [binary.MaxVarintLen64]byte
+	n := binary.PutUvarint(tmp[:], v)
+	p.buf = append(p.buf, tmp[:n]...)
+}
+
+func (p *protoBuffer) appendTag(field, wireType int) {
+	p.appendRawVarint(uint64(field)<<3 | uint64(wireType))
+}
+
+func (p *protoBuffer) appendUint64(field int, v uint64) {
+	if v == 0 {
+		return
+	}
+	p.appendTag(field, wireVarint)
+	p.appendRawVarint(v)
+}
+
+func (p *protoBuffer) appendBytes(field int, data []byte) {
+	if len(data) == 0 {
+		return
+	}
+	p.appendTag(field, wireBytes)
+	p.appendRawVarint(uint64(len(data)))
+	p.buf = append(p.buf, data...)
+}
+
+func (p *protoBuffer) appendString(field int, s string) {
+	p.appendBytes(field, []byte(s))
+}
+
+// appendMessage append embedded message, empty message is kept
+// (it differs from the default value nil message)
+func (p *protoBuffer) appendMessage(field int, data []byte) {
+	p.appendTag(field, wireBytes)
+	p.appendRawVarint(uint64(len(data)))
+	p.buf = append(p.buf, data...)
+}
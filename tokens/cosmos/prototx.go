@@ -0,0 +1,152 @@
+package cosmos
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+// protobuf type urls (cosmos-sdk v0.40+)
+const (
+	TypeURLMsgSend         = "/cosmos.bank.v1beta1.MsgSend"
+	TypeURLMsgMultiSend    = "/cosmos.bank.v1beta1.MsgMultiSend"
+	TypeURLSecp256k1PubKey = "/cosmos.crypto.secp256k1.PubKey"
+
+	// SignModeDirect is SIGN_MODE_DIRECT enum value
+	SignModeDirect = 1
+)
+
+// ProtoMsg is a message which can be packed into protobuf Any
+type ProtoMsg interface {
+	TypeURL() string
+	Marshal() []byte
+}
+
+// ProtoMsgSend is protobuf bank MsgSend
+type ProtoMsgSend struct {
+	FromAddress string
+	ToAddress   string
+	Amount      sdk.Coins
+}
+
+// TypeURL impl ProtoMsg
+func (msg *ProtoMsgSend) TypeURL() string {
+	return TypeURLMsgSend
+}
+
+// Marshal impl ProtoMsg
+func (msg *ProtoMsgSend) Marshal() []byte {
+	var p protoBuffer
+	p.appendString(1, msg.FromAddress)
+	p.appendString(2, msg.ToAddress)
+	for _, coin := range msg.Amount {
+		p.appendMessage(3, marshalCoin(coin))
+	}
+	return p.Bytes()
+}
+
+func marshalCoin(coin sdk.Coin) []byte {
+	var p protoBuffer
+	p.appendString(1, coin.Denom)
+	p.appendString(2, coin.Amount.String())
+	return p.Bytes()
+}
+
+func marshalAny(typeURL string, value []byte) []byte {
+	var p protoBuffer
+	p.appendString(1, typeURL)
+	p.appendBytes(2, value)
+	return p.Bytes()
+}
+
+func marshalSecp256k1PubKey(pubKey []byte) []byte {
+	var p protoBuffer
+	p.appendBytes(1, pubKey)
+	return marshalAny(TypeURLSecp256k1PubKey, p.Bytes())
+}
+
+// ProtoSignContent saves all tx components required to build
+// SIGN_MODE_DIRECT sign doc (cosmos-sdk v0.40+)
+type ProtoSignContent struct {
+	AccountNumber uint64
+	ChainID       string
+	Fee           authtypes.StdFee
+	Memo          string
+	Msgs          []ProtoMsg
+	Sequence      uint64
+	PubKey        []byte // compressed secp256k1 public key of signer
+}
+
+// ProtoSignedTx saves all data of a signed protobuf tx
+type ProtoSignedTx struct {
+	ProtoSignContent
+	Signature []byte
+}
+
+// BodyBytes returns protobuf encoded TxBody
+func (tx *ProtoSignContent) BodyBytes() []byte {
+	var p protoBuffer
+	for _, msg := range tx.Msgs {
+		p.appendMessage(1, marshalAny(msg.TypeURL(), msg.Marshal()))
+	}
+	p.appendString(2, tx.Memo)
+	return p.Bytes()
+}
+
+// AuthInfoBytes returns protobuf encoded AuthInfo
+func (tx *ProtoSignContent) AuthInfoBytes() []byte {
+	var modeInfoSingle protoBuffer
+	modeInfoSingle.appendUint64(1, SignModeDirect)
+	var modeInfo protoBuffer
+	modeInfo.appendMessage(1, modeInfoSingle.Bytes())
+
+	var signerInfo protoBuffer
+	signerInfo.appendMessage(1, marshalSecp256k1PubKey(tx.PubKey))
+	signerInfo.appendMessage(2, modeInfo.Bytes())
+	signerInfo.appendUint64(3, tx.Sequence)
+
+	var fee protoBuffer
+	for _, coin := range tx.Fee.Amount {
+		fee.appendMessage(1, marshalCoin(coin))
+	}
+	fee.appendUint64(2, tx.Fee.Gas)
+
+	var p protoBuffer
+	p.appendMessage(1, signerInfo.Bytes())
+	p.appendMessage(2, fee.Bytes())
+	return p.Bytes()
+}
+
+// SignBytes returns protobuf encoded SignDoc
+func (tx *ProtoSignContent) SignBytes() []byte {
+	var p protoBuffer
+	p.appendBytes(1, tx.BodyBytes())
+	p.appendBytes(2, tx.AuthInfoBytes())
+	p.appendString(3, tx.ChainID)
+	p.appendUint64(4, tx.AccountNumber)
+	return p.Bytes()
+}
+
+// Hash returns tx sign bytes hash string
+// not the tx hash
+func (tx *ProtoSignContent) Hash() string {
+	signHash := sha256.Sum256(tx.SignBytes())
+	return fmt.Sprintf("%X", signHash[:])
+}
+
+// TxBytes returns protobuf encoded TxRaw
+func (tx *ProtoSignedTx) TxBytes() []byte {
+	var p protoBuffer
+	p.appendBytes(1, tx.BodyBytes())
+	p.appendBytes(2, tx.AuthInfoBytes())
+	p.appendMessage(3, tx.Signature)
+	return p.Bytes()
+}
+
+// TxHash returns tx hash
+func (tx *ProtoSignedTx) TxHash() string {
+	txHash := sha256.Sum256(tx.TxBytes())
+	return fmt.Sprintf("%X", txHash[:])
+}
@@ -0,0 +1,54 @@
+package cosmos
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+// golden vectors are generated by cosmos-sdk v0.42.4 generated codes
+// (tx.SignDoc and tx.TxRaw) from the same tx content
+// nolint:lll // allow long line of golden vectors
+const (
+	goldenSignDocHex = "0ac4010a90010a1c2f636f736d6f732e62616e6b2e763162657461312e4d736753656e6412700a2d636f736d6f73317671686633667168396a76306e3477307163726b387165666b677a3433666d3679346a336635122d636f736d6f7331787639746b6c773764383273657a683968616135373377756667793539766d776536787865351a100a057561746f6d120731323334353637122f62696e643a30783131313131313131313131313131313131313131313131313131313131313131313131313131313112670a500a460a1f2f636f736d6f732e63727970746f2e736563703235366b312e5075624b657912230a2102a1633cafcc01ebfb6d78e39f687a1f0995c62fc95f51ead10a02ee0be551b5dc12040a020801180712130a0d0a057561746f6d12043530303010c09a0c1a0b636f736d6f736875622d3420b960"
+	goldenTxRawHex   = "0ac4010a90010a1c2f636f736d6f732e62616e6b2e763162657461312e4d736753656e6412700a2d636f736d6f73317671686633667168396a76306e3477307163726b387165666b677a3433666d3679346a336635122d636f736d6f7331787639746b6c773764383273657a683968616135373377756667793539766d776536787865351a100a057561746f6d120731323334353637122f62696e643a30783131313131313131313131313131313131313131313131313131313131313131313131313131313112670a500a460a1f2f636f736d6f732e63727970746f2e736563703235366b312e5075624b657912230a2102a1633cafcc01ebfb6d78e39f687a1f0995c62fc95f51ead10a02ee0be551b5dc12040a020801180712130a0d0a057561746f6d12043530303010c09a0c1a400102030405060708090a0b0c0d0e0f101112131415161718191a1b1c1d1e1f202122232425262728292a2b2c2d2e2f303132333435363738393a3b3c3d3e3f40"
+)
+
+func newGoldenProtoSignedTx() *ProtoSignedTx {
+	signature := make([]byte, 64)
+	for i := range signature {
+		signature[i] = byte(i + 1)
+	}
+	return &ProtoSignedTx{
+		ProtoSignContent: ProtoSignContent{
+			AccountNumber: 12345,
+			ChainID:       "cosmoshub-4",
+			Fee: authtypes.StdFee{
+				Amount: sdk.NewCoins(sdk.NewInt64Coin("uatom", 5000)),
+				Gas:    200000,
+			},
+			Memo: "bind:0x1111111111111111111111111111111111111111",
+			Msgs: []ProtoMsg{&ProtoMsgSend{
+				FromAddress: "cosmos1vqhf3fqh9jv0n4w0qcrk8qefkgz43fm6y4j3f5",
+				ToAddress:   "cosmos1xv9tklw7d82sezh9haa573wufgy59vmwe6xxe5",
+				Amount:      sdk.NewCoins(sdk.NewInt64Coin("uatom", 1234567)),
+			}},
+			Sequence: 7,
+			PubKey:   common.FromHex("02a1633cafcc01ebfb6d78e39f687a1f0995c62fc95f51ead10a02ee0be551b5dc"),
+		},
+		Signature: signature,
+	}
+}
+
+func TestProtoSignBytes(t *testing.T) {
+	tx := newGoldenProtoSignedTx()
+	if have, want := tx.SignBytes(), common.FromHex(goldenSignDocHex); !bytes.Equal(have, want) {
+		t.Errorf("sign doc mismatch\nhave %x\nwant %x", have, want)
+	}
+	if have, want := tx.TxBytes(), common.FromHex(goldenTxRawHex); !bytes.Equal(have, want) {
+		t.Errorf("tx raw mismatch\nhave %x\nwant %x", have, want)
+	}
+}
@@ -35,6 +35,9 @@ const TimeFormat = time.RFC3339Nano
 // GetBalance gets main token balance
 // call  rest api"/bank/balances/"
 func (b *Bridge) GetBalance(account string) (balance *big.Int, err error) {
+	if b.IsProtobufTx() {
+		return b.getBalanceProto(account, b.MainCoin.Denom)
+	}
 	endpoints := b.GatewayConfig.APIAddress
 	for _, endpoint := range endpoints {
 		endpointURL, err := url.Parse(endpoint)
@@ -75,6 +78,9 @@ func (b *Bridge) GetTokenBalance(tokenType, tokenName, accountAddress string) (b
 	if !ok {
 		return nil, fmt.Errorf("Unsupported coin: %v", tokenName)
 	}
+	if b.IsProtobufTx() {
+		return b.getBalanceProto(accountAddress, coin.Denom)
+	}
 	endpoints := b.GatewayConfig.APIAddress
 	for _, endpoint := range endpoints {
 		endpointURL, err := url.Parse(endpoint)
@@ -116,6 +122,16 @@ func (b *Bridge) GetTokenSupply(tokenType, tokenAddress string) (*big.Int, error
 // GetTransaction gets tx by hash, returns sdk.Tx
 // call rest api "/txs/{txhash}"
 func (b *Bridge) GetTransaction(txHash string) (tx interface{}, err error) {
+	if b.IsProtobufTx() {
+		txResult, errf := b.getTxResponseProto(txHash)
+		if errf != nil {
+			return nil, errf
+		}
+		if txResult.Code != 0 {
+			return nil, fmt.Errorf("tx status error: %+v", txResult)
+		}
+		return txResult.Tx, nil
+	}
 	endpoints := b.GatewayConfig.APIAddress
 	for _, endpoint := range endpoints {
 		endpointURL, err := url.Parse(endpoint)
@@ -153,6 +169,9 @@ func (b *Bridge) GetTransaction(txHash string) (tx interface{}, err error) {
 // GetTransactionStatus returns tx status
 // call rest api "/txs/{txhash}"
 func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus) {
+	if b.IsProtobufTx() {
+		return b.getTransactionStatusProto(txHash)
+	}
 	status = &tokens.TxStatus{
 		// Receipt
 		//Confirmations
@@ -209,6 +228,9 @@ func (b *Bridge) GetTransactionStatus(txHash string) (status *tokens.TxStatus) {
 // GetLatestBlockNumber returns current block height
 // call rest api "/blocks/latest"
 func (b *Bridge) GetLatestBlockNumber() (height uint64, err error) {
+	if b.IsProtobufTx() {
+		return b.getLatestBlockNumberProto()
+	}
 	endpoints := b.GatewayConfig.APIAddress
 	for _, endpoint := range endpoints {
 		endpointURL, err := url.Parse(endpoint)
@@ -238,6 +260,9 @@ func (b *Bridge) GetLatestBlockNumber() (height uint64, err error) {
 // GetLatestBlockNumberOf returns current block height of given node
 // call rest api "/blocks/latest"
 func (b *Bridge) GetLatestBlockNumberOf(apiAddress string) (uint64, error) {
+	if b.IsProtobufTx() {
+		return b.getLatestBlockNumberProto(apiAddress)
+	}
 	endpointURL, err := url.Parse(apiAddress)
 	if err != nil {
 		return 0, err
@@ -263,6 +288,10 @@ func (b *Bridge) GetLatestBlockNumberOf(apiAddress string) (uint64, error) {
 // GetAccountNumber gets account number, a series number of account on a cosmos state
 // call rest api "/auth/accounts/"
 func (b *Bridge) GetAccountNumber(address string) (uint64, error) {
+	if b.IsProtobufTx() {
+		accountNumber, _, err := b.getAccountProto(address)
+		return accountNumber, err
+	}
 	endpoints := b.GatewayConfig.APIAddress
 	for _, endpoint := range endpoints {
 		endpointURL, err := url.Parse(endpoint)
@@ -308,6 +337,10 @@ func (b *Bridge) GetAccountNumber(address string) (uint64, error) {
 // GetPoolNonce gets account sequence
 // call rest api "/auth/accounts/"
 func (b *Bridge) GetPoolNonce(address, height string) (uint64, error) {
+	if b.IsProtobufTx() {
+		_, sequence, err := b.getAccountProto(address)
+		return sequence, err
+	}
 	endpoints := b.GatewayConfig.APIAddress
 	for _, endpoint := range endpoints {
 		endpointURL, err := url.Parse(endpoint)
@@ -353,101 +386,69 @@ func (b *Bridge) GetPoolNonce(address, height string) (uint64, error) {
 // SearchTxsHash searches tx in range of blocks
 // call rest api "/txs?..."
 func (b *Bridge) SearchTxsHash(start, end *big.Int) ([]string, error) {
-	txs := make([]string, 0)
-	var limit = 100
-	var page = 1
-	var pageTotal = 1
-	endpoints := b.GatewayConfig.APIAddress
-
-	// search send
-	for page <= pageTotal {
-		log.Debug("Search send msgs", "start", start, "end", end, "limit", limit, "page", page, "pageTotal", pageTotal)
-		for _, endpoint := range endpoints {
-			endpointURL, err := url.Parse(endpoint)
-			if err != nil {
-				continue
-			}
-			endpoint = endpointURL.String()
-			client := resty.New()
-			params := fmt.Sprintf("?message.action=send&page=%v&limit=%v&tx.minheight=%v&tx.maxheight=%v", page, limit, start, end)
-			resp, err := client.R().Get(fmt.Sprintf("%vtxs%v", endpoint, params))
-			if err != nil || resp.StatusCode() != 200 {
-				log.Warn("cosmos rest request error", "resp", string(resp.Body()), "request error", err, "func", "SearchTxsHash")
-				continue
-			}
-			var res sdk.SearchTxsResult
-			err = CDC.UnmarshalJSON(resp.Body(), &res)
-			if err != nil {
-				log.Warn("Search txs unmarshal error", "start", start, "end", end, "page", page, "func", "SearchTxsHash")
-				continue
-			}
-			pageTotal = res.PageTotal
-			log.Debug("Txs containing Send msgs", "length", len(res.Txs))
-			for _, tx := range res.Txs {
-				if tx.Code != 0 {
-					log.Debug("discard failed tx")
-					continue
-				}
-				txs = append(txs, tx.TxHash)
-			}
-			break
-		}
-		page = page + 1
+	txresps, err := b.SearchTxs(start, end)
+	if err != nil {
+		return nil, err
 	}
-
-	// search multisend
-	page = 1
-	pageTotal = 1
-	for page <= pageTotal {
-		log.Debug("Search multisend msgs", "start", start, "end", end, "limit", limit, "page", page, "pageTotal", pageTotal)
-		for _, endpoint := range endpoints {
-			endpointURL, err := url.Parse(endpoint)
-			if err != nil {
-				continue
-			}
-			endpoint = endpointURL.String()
-			client := resty.New()
-			params := fmt.Sprintf("?message.action=multisend&page=%v&limit=%v&tx.minheight=%v&tx.maxheight=%v", page, limit, start, end)
-			resp, err := client.R().Get(fmt.Sprintf("%vtxs%v", endpoint, params))
-			if err != nil || resp.StatusCode() != 200 {
-				log.Warn("cosmos rest request error", "resp", string(resp.Body()), "request error", err, "func", "SearchTxsHash")
-				continue
-			}
-			var res sdk.SearchTxsResult
-			err = CDC.UnmarshalJSON(resp.Body(), &res)
-			if err != nil {
-				log.Warn("Search txs unmarshal error", "start", start, "end", end, "page", page, "func", "SearchTxsHash")
-				continue
-			}
-			pageTotal = res.PageTotal
-			log.Debug("Txs containing MultiSend msgs", "length", len(res.Txs))
-			for _, tx := range res.Txs {
-				if tx.Code != 0 {
-					log.Debug("discard failed tx")
-					continue
-				}
-				txs = append(txs, tx.TxHash)
-			}
-			break
-		}
-		page = page + 1
+	txs := make([]string, 0, len(txresps))
+	for _, txresp := range txresps {
+		txs = append(txs, txresp.TxHash)
 	}
-	log.Debug("Search txs finish")
 	return txs, nil
 }
 
+// getSearchActions message actions of txs which may contain deposits.
+// ibc recv packet action is its msg type url since cosmos-sdk v0.46,
+// and is searched by both action names in grpc-gateway.
+func (b *Bridge) getSearchActions() []string {
+	if b.IsProtobufTx() {
+		return []string{TypeMsgSend, TypeMsgMultiSend, TypeMsgRecvPacket, TypeURLMsgRecvPacket}
+	}
+	return []string{TypeMsgSend, TypeMsgMultiSend, TypeMsgRecvPacket}
+}
+
 // SearchTxs searches tx in range of blocks
 // call rest api "/txs?..."
+// tx containing msgs of several searched actions is returned only once
 func (b *Bridge) SearchTxs(start, end *big.Int) ([]sdk.TxResponse, error) {
 	txs := make([]sdk.TxResponse, 0)
-	var limit = 100
+	exist := make(map[string]struct{})
+	for _, action := range b.getSearchActions() {
+		var res []sdk.TxResponse
+		var err error
+		if b.IsProtobufTx() {
+			res, err = b.searchTxsProto(start, end, action)
+		} else {
+			res, err = b.searchTxsLegacy(start, end, action)
+		}
+		if err != nil {
+			return nil, err
+		}
+		txs = appendUniqueTxs(txs, res, exist)
+	}
+	return txs, nil
+}
 
-	// search send
+func appendUniqueTxs(txs, res []sdk.TxResponse, exist map[string]struct{}) []sdk.TxResponse {
+	for _, txresp := range res {
+		key := strings.ToLower(txresp.TxHash)
+		if _, ok := exist[key]; ok {
+			continue
+		}
+		exist[key] = struct{}{}
+		txs = append(txs, txresp)
+	}
+	return txs
+}
+
+func (b *Bridge) searchTxsLegacy(start, end *big.Int, action string) ([]sdk.TxResponse, error) {
+	txs := make([]sdk.TxResponse, 0)
+	var limit = 100
 	var page = 1
 	var pageTotal = 1
 	endpoints := b.GatewayConfig.APIAddress
 	for page <= pageTotal {
-		log.Debug("Search send msgs", "start", start, "end", end, "limit", limit, "page", page, "pageTotal", pageTotal)
+		log.Debug("Search txs", "action", action, "start", start, "end", end, "limit", limit, "page", page, "pageTotal", pageTotal)
 		for _, endpoint := range endpoints {
 			endpointURL, err := url.Parse(endpoint)
 			if err != nil {
@@ -455,7 +456,7 @@ func (b *Bridge) SearchTxs(start, end *big.Int) ([]sdk.TxResponse, error) {
 			}
 			endpoint = endpointURL.String()
 			client := resty.New()
-			params := fmt.Sprintf("?message.action=send&page=%v&limit=%v&tx.minheight=%v&tx.maxheight=%v", page, limit, start, end)
+			params := fmt.Sprintf("?message.action=%v&page=%v&limit=%v&tx.minheight=%v&tx.maxheight=%v", action, page, limit, start, end)
 			resp, err := client.R().Get(fmt.Sprintf("%vtxs%v", endpoint, params))
 			if err != nil || resp.StatusCode() != 200 {
 				log.Warn("cosmos rest request error", "resp", string(resp.Body()), "request error", err, "func", "SearchTxs")
@@ -464,49 +465,11 @@ func (b *Bridge) SearchTxs(start, end *big.Int) ([]sdk.TxResponse, error) {
 			var res sdk.SearchTxsResult
 			err = CDC.UnmarshalJSON(resp.Body(), &res)
 			if err != nil {
-				log.Warn("Search txs unmarshal error", "start", start, "end", end, "page", page, "func", "SearchTxs")
-				continue
-			}
-			pageTotal = res.PageTotal
-			log.Debug("Txs containing Send msgs", "length", len(res.Txs))
-			for _, txresp := range res.Txs {
-				if txresp.Code != 0 {
-					log.Debug("discard failed tx")
-					continue
-				}
-				txs = append(txs, txresp)
-			}
-			break
-		}
-		page = page + 1
-	}
-
-	// search multisend
-	page = 1
-	pageTotal = 1
-	for page <= pageTotal {
-		log.Debug("Search multisend msgs", "start", start, "end", end, "limit", limit, "page", page, "pageTotal", pageTotal)
-		for _, endpoint := range endpoints {
-			endpointURL, err := url.Parse(endpoint)
-			if err != nil {
-				continue
-			}
-			endpoint = endpointURL.String()
-			client := resty.New()
-			params := fmt.Sprintf("?message.action=multisend&page=%v&limit=%v&tx.minheight=%v&tx.maxheight=%v", page, limit, start, end)
-			resp, err := client.R().Get(fmt.Sprintf("%v/txs%v", endpoint, params))
-			if err != nil || resp.StatusCode() != 200 {
-				log.Warn("cosmos rest request error", "resp", string(resp.Body()), "request error", err, "func", "SearchTxs")
-				continue
-			}
-			var res sdk.SearchTxsResult
-			err = CDC.UnmarshalJSON(resp.Body(), &res)
-			if err != nil {
-				log.Warn("Search txs unmarshal error", "start", start, "end", end, "page", page, "func", "SearchTxs")
+				log.Warn("Search txs unmarshal error", "action", action, "start", start, "end", end, "page", page, "func", "SearchTxs")
 				continue
 			}
 			pageTotal = res.PageTotal
-			log.Debug("Txs containing MultiSend msgs", "length", len(res.Txs))
+			log.Debug("Txs containing msgs", "action", action, "length", len(res.Txs))
 			for _, txresp := range res.Txs {
 				if txresp.Code != 0 {
 					log.Debug("discard failed tx")
@@ -552,7 +515,7 @@ func (b *Bridge) BroadcastTx(tx HashableStdTx) (string, error) {
 	value["signatures"] = signatures
 	bz2, err := json.Marshal(value)
 	if err != nil {
-		return txhash, fmt.Errorf("Remarshal, std tx error", "err", err)
+		return txhash, fmt.Errorf("Remarshal, std tx error: %v", err)
 	}
 	data := fmt.Sprintf(`{"tx":%v,"mode":"block"}`, string(bz2))
 
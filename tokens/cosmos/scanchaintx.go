@@ -1,7 +1,6 @@
 package cosmos
 
 import (
-	"context"
 	"fmt"
 	"math/big"
 	"sync"
@@ -12,10 +11,12 @@ import (
 )
 
 var (
-	quickSyncFinish  bool
 	quickSyncWorkers = uint64(4)
 
+	// scan head blocks in loop if behind no more than this value,
+	// otherwise add quick sync job to catch up the behind blocks
 	maxScanHeight          = uint64(100)
+	quickSyncBatch         = uint64(100)
 	retryIntervalInScanJob = 3 * time.Second
 	restIntervalInScanJob  = 3 * time.Second
 )
@@ -42,9 +43,6 @@ func (b *Bridge) getStartAndLatestHeight() (start, latest uint64) {
 	if start < initialHeight {
 		start = initialHeight
 	}
-	if start+maxScanHeight < latest {
-		start = latest - maxScanHeight
-	}
 	return start, latest
 }
 
@@ -54,35 +52,27 @@ func (b *Bridge) StartChainTransactionScanJob() {
 	log.Infof("[scanchain] start %v scan chain job", chainName)
 
 	start, latest := b.getStartAndLatestHeight()
-	_ = tools.UpdateLatestScanInfo(b.IsSrc, start)
 	log.Infof("[scanchain] start %v scan chain loop from %v latest=%v", chainName, start, latest)
 
+	// resume unfinished quick sync ranges, then catch up from latest scan height
+	for i, r := range tools.GetPendingScanRanges(b.IsSrc) {
+		go b.quickSyncRange(uint64(i+1), r, nil)
+	}
 	if latest > start {
-		go b.quickSync(context.Background(), nil, start, latest+1)
-	} else {
-		quickSyncFinish = true
+		b.quickSync(start, latest+1)
 	}
+	_ = tools.UpdateLatestScanInfo(b.IsSrc, latest)
 
 	stable := latest
 	//errorSubject := fmt.Sprintf("[scanchain] get %v block failed", chainName)
 	scanSubject := fmt.Sprintf("[scanchain] scanned %v block", chainName)
 
 	scannedRange := tools.NewCachedScannedBlocks(67)
-	var quickSyncCtx context.Context
-	var quickSyncCancel context.CancelFunc
 	for {
 		latest = tools.LoopGetLatestBlockNumber(b)
 		if stable+maxScanHeight < latest {
-			if quickSyncCancel != nil {
-				select {
-				case <-quickSyncCtx.Done():
-				default:
-					log.Warn("cancel quick sync range", "stable", stable, "latest", latest)
-					quickSyncCancel()
-				}
-			}
-			quickSyncCtx, quickSyncCancel = context.WithCancel(context.Background())
-			go b.quickSync(quickSyncCtx, quickSyncCancel, stable+1, latest)
+			// catch up behind blocks concurrently, keep scanning the head
+			b.quickSync(stable+1, latest)
 			stable = latest
 		}
 
@@ -114,71 +104,55 @@ func (b *Bridge) StartChainTransactionScanJob() {
 			h = end + 1
 			stable = end + 1
 		}
-		if quickSyncFinish {
-			_ = tools.UpdateLatestScanInfo(b.IsSrc, stable)
-		}
+		_ = tools.UpdateLatestScanInfo(b.IsSrc, stable)
 		time.Sleep(restIntervalInScanJob)
 	}
 }
 
-func (b *Bridge) quickSync(ctx context.Context, cancel context.CancelFunc, start, end uint64) {
+// quickSync scan blocks [start, end) with workers in background,
+// the ranges of workers are checkpointed before return
+func (b *Bridge) quickSync(start, end uint64) {
 	chainName := b.ChainConfig.BlockChain
 	log.Printf("[scanchain] begin %v syncRange job. start=%v end=%v", chainName, start, end)
-	count := end - start
-	workers := quickSyncWorkers
-	if count < 10 {
-		workers = 1
-	}
-	step := count / workers
+	ranges := tools.NewScanRanges(b.IsSrc, start, end, quickSyncWorkers)
 	wg := new(sync.WaitGroup)
-	wg.Add(int(workers))
-	for i := uint64(0); i < workers; i++ {
-		wstt := start + i*step
-		wend := start + (i+1)*step
-		if i+1 == workers {
-			wend = end
-		}
-		go b.quickSyncRange(ctx, i+1, wstt, wend, wg)
+	wg.Add(len(ranges))
+	for i, r := range ranges {
+		go b.quickSyncRange(uint64(i+1), r, wg)
 	}
-	wg.Wait()
-	if cancel != nil {
-		cancel()
-	} else {
-		quickSyncFinish = true
-	}
-	log.Printf("[scanchain] finish %v syncRange job. start=%v end=%v", chainName, start, end)
+	go func() {
+		wg.Wait()
+		log.Printf("[scanchain] finish %v syncRange job. start=%v end=%v", chainName, start, end)
+	}()
 }
 
-func (b *Bridge) quickSyncRange(ctx context.Context, idx, start, end uint64, wg *sync.WaitGroup) {
-	defer wg.Done()
+func (b *Bridge) quickSyncRange(idx uint64, r *tools.ScanRange, wg *sync.WaitGroup) {
+	if wg != nil {
+		defer wg.Done()
+	}
 	chainName := b.ChainConfig.BlockChain
+	start, end := r.Next, r.End
 	log.Printf("[scanchain] id=%v begin %v syncRange start=%v end=%v", idx, chainName, start, end)
 
-	select {
-	case <-ctx.Done():
-		break
-	default:
-	}
-
 	for h := start; h < end; {
-		h2 := h
-		if end-h > 100 {
-			h2 = h + 100
-
-			h = h2 + 1
-		} else {
+		h2 := h + quickSyncBatch
+		if h2 > end {
 			h2 = end
 		}
-		txs, err := b.SearchTxs(big.NewInt(int64(h)), big.NewInt(int64(h2)))
+		// search txs api's height range is closed interval
+		txs, err := b.SearchTxs(big.NewInt(int64(h)), big.NewInt(int64(h2-1)))
 		if err != nil {
-			log.Warn("Search txs in range error", "range", fmt.Sprintf("%v-%v", h, h2), "error", err)
+			log.Warn("Search txs in range error", "range", fmt.Sprintf("%v-%v", h, h2-1), "error", err)
+			time.Sleep(retryIntervalInScanJob)
 			continue
 		}
 		for _, tx := range txs {
 			b.processTransaction(tx)
 		}
-		h = h2 + 1
+		h = h2
+		r.UpdateProgress(h)
 	}
+	r.Finish()
 
 	log.Printf("[scanchain] id=%v finish %v syncRange start=%v end=%v", idx, chainName, start, end)
 }
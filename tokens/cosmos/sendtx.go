@@ -8,6 +8,14 @@ import (
 
 // SendTransaction send signed tx
 func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error) {
+	if protoTx, ok := signedTx.(*ProtoSignedTx); ok {
+		txHash, err = b.broadcastTxProto(protoTx)
+		if err != nil {
+			log.Info("SendTransaction failed", "hash", txHash, "err", err)
+			return txHash, err
+		}
+		return txHash, nil
+	}
 	tx, ok := signedTx.(HashableStdTx)
 	if !ok {
 		return "", errors.New("wrong signed transaction type")
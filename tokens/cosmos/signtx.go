@@ -1,32 +1,29 @@
 package cosmos
 
 import (
+	"bytes"
 	"crypto/ecdsa"
-	"encoding/hex"
+	"crypto/sha256"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"strings"
-	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/common"
 	"github.com/anyswap/CrossChain-Bridge/dcrm"
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+	"github.com/anyswap/CrossChain-Bridge/tools/signer"
 
 	"github.com/btcsuite/btcd/btcec"
+	sdk "github.com/cosmos/cosmos-sdk/types"
 	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
 	"github.com/tendermint/tendermint/crypto/secp256k1"
 )
 
-const (
-	retryGetSignStatusCount    = 70
-	retryGetSignStatusInterval = 10 * time.Second
-)
-
 func (b *Bridge) verifyTransactionWithArgs(tx StdSignContent, args *tokens.BuildTxArgs) error {
-	tokenCfg := b.GetTokenConfig(args.PairID)
 	if len(tx.Msgs) != 1 {
 		return errors.New("wrong msgs length")
 	}
@@ -34,33 +31,57 @@ func (b *Bridge) verifyTransactionWithArgs(tx StdSignContent, args *tokens.Build
 	if !ok {
 		return errors.New("msg types error")
 	}
+	return b.verifySendWithArgs(msg.FromAddress.String(), msg.ToAddress.String(), msg.Amount, args)
+}
+
+func (b *Bridge) verifyProtoTransactionWithArgs(tx *ProtoSignContent, args *tokens.BuildTxArgs) error {
+	if len(tx.Msgs) != 1 {
+		return errors.New("wrong msgs length")
+	}
+	msg, ok := tx.Msgs[0].(*ProtoMsgSend)
+	if !ok {
+		return errors.New("msg types error")
+	}
+	return b.verifySendWithArgs(msg.FromAddress, msg.ToAddress, msg.Amount, args)
+}
+
+// verifySendWithArgs verify send msg of legacy or protobuf tx is built with args,
+// the sent amount must be exactly the swap value after swap fee.
+func (b *Bridge) verifySendWithArgs(from, to string, coins sdk.Coins, args *tokens.BuildTxArgs) error {
+	tokenCfg := b.GetTokenConfig(args.PairID)
+	if tokenCfg == nil {
+		return tokens.ErrUnknownPairID
+	}
 	switch {
-	case strings.EqualFold(args.From, msg.FromAddress.String()) == false:
-		return fmt.Errorf("[cosmos verify transaction with args] From address not match, args.From: %v, msg.FromAddress: %v", args.From, msg.FromAddress.String())
-	case strings.EqualFold(msg.FromAddress.String(), tokenCfg.DcrmAddress) == false:
-		return fmt.Errorf("[cosmos verify transaction with args] From address is not dcrm address, args.From: %v, dcrm address: %v", msg.FromAddress.String(), tokenCfg.DcrmAddress)
-	case b.IsValidAddress(args.Bind) == false:
+	case !strings.EqualFold(args.From, from):
+		return fmt.Errorf("[cosmos verify transaction with args] From address not match, args.From: %v, msg.FromAddress: %v", args.From, from)
+	case !strings.EqualFold(from, tokenCfg.DcrmAddress):
+		return fmt.Errorf("[cosmos verify transaction with args] From address is not dcrm address, args.From: %v, dcrm address: %v", from, tokenCfg.DcrmAddress)
+	case !b.IsValidAddress(args.Bind):
 		return fmt.Errorf("[cosmos verify transaction with args] Invalid to address: %v", args.Bind)
-	case strings.EqualFold(args.Bind, msg.ToAddress.String()) == false:
-		return fmt.Errorf("[cosmos verify transaction with args] To address not match, args.To: %v, msg.ToAddress: %v", args.Bind, msg.ToAddress.String())
-	default:
+	case !strings.EqualFold(args.Bind, to):
+		return fmt.Errorf("[cosmos verify transaction with args] To address not match, args.To: %v, msg.ToAddress: %v", args.Bind, to)
 	}
-	if len(msg.Amount) != 1 {
+	if len(coins) != 1 {
 		return errors.New("wrong amount length")
 	}
-	amount := msg.Amount[0]
-	checkPairID, err := b.getPairID(amount)
+	coin := coins[0]
+	checkPairID, err := b.getPairID(coin)
 	if err != nil || checkPairID != args.PairID {
 		return fmt.Errorf("[cosmos verify transaction with args] Token type not match, %v, %v", checkPairID, args.PairID)
 	}
-	if amount.Amount.BigInt().Cmp(args.OriginValue) >= 0 {
-		return fmt.Errorf("[cosmos verify transaction with args] Amount not match, %v, %v", amount.Amount.BigInt(), args.OriginValue)
+	swapValue := args.GetSwapValue()
+	if coin.Amount.BigInt().Cmp(swapValue) != 0 {
+		return fmt.Errorf("[cosmos verify transaction with args] Amount not match, %v, %v", coin.Amount.BigInt(), swapValue)
 	}
 	return nil
 }
 
 // DcrmSignTransaction dcrm sign raw tx
 func (b *Bridge) DcrmSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs) (signedTx interface{}, txHash string, err error) {
+	if protoTx, ok := rawTx.(*ProtoSignContent); ok {
+		return b.dcrmSignProtoTransaction(protoTx, args)
+	}
 	tx, ok := rawTx.(StdSignContent)
 	if !ok {
 		return nil, "", errors.New("wrong raw tx param")
@@ -70,99 +91,154 @@ func (b *Bridge) DcrmSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs
 		return nil, "", err
 	}
 	msgHash := tx.Hash()
-	jsondata, _ := json.Marshal(args)
-	msgContext := string(jsondata)
-	rpcAddr, keyID, err := dcrm.DoSignOne(b.GetDcrmPublicKey(args.PairID), msgHash, msgContext)
+	keyID, signatureBytes, err := b.dcrmSignMsgHash(msgHash, args)
 	if err != nil {
 		return nil, "", err
 	}
-	log.Info(b.ChainConfig.BlockChain+" DcrmSignTransaction start", "keyID", keyID, "msghash", msgHash, "txid", args.SwapID)
-	time.Sleep(retryGetSignStatusInterval)
 
-	var rsv string
-	i := 0
-	for ; i < retryGetSignStatusCount; i++ {
-		signStatus, err2 := dcrm.GetSignStatus(keyID, rpcAddr)
-		if err2 == nil {
-			if len(signStatus.Rsv) != 1 {
-				return nil, "", fmt.Errorf("get sign status require one rsv but have %v (keyID = %v)", len(signStatus.Rsv), keyID)
-			}
+	pubkey, err := b.getDcrmPubKeySecp256k1(args.PairID)
+	if err != nil {
+		return nil, "", err
+	}
 
-			rsv = signStatus.Rsv[0]
-			break
-		}
-		switch err2 {
-		case dcrm.ErrGetSignStatusFailed, dcrm.ErrGetSignStatusTimeout:
-			return nil, "", err2
-		}
-		log.Warn("retry get sign status as error", "err", err2, "txid", args.SwapID, "keyID", keyID, "bridge", args.Identifier, "swaptype", args.SwapType.String())
-		time.Sleep(retryGetSignStatusInterval)
+	stdsig := authtypes.StdSignature{
+		PubKey:    pubkey,
+		Signature: signatureBytes,
 	}
-	if i == retryGetSignStatusCount || rsv == "" {
-		return nil, "", errors.New("get sign status failed")
+	signedTx = HashableStdTx{
+		StdSignContent: tx,
+		Signatures:     []authtypes.StdSignature{stdsig},
 	}
 
-	log.Trace(b.ChainConfig.BlockChain+" DcrmSignTransaction get rsv success", "keyID", keyID, "rsv", rsv)
+	if pubkey.VerifyBytes(tx.SignBytes(), signatureBytes) == false {
+		log.Error("Dcrm sign verify error")
+		return nil, "", errors.New("wrong signature")
+	}
 
-	signature := common.FromHex(rsv)
+	txHash = msgHash
+	log.Info(b.ChainConfig.BlockChain+" DcrmSignTransaction success", "keyID", keyID, "txhash", txHash, "nonce", signedTx.(HashableStdTx).Sequence)
+	return signedTx, txHash, err
+}
 
-	if len(signature) != crypto.SignatureLength {
-		log.Error("DcrmSignTransaction wrong length of signature")
-		return nil, "", errors.New("wrong signature of keyID " + keyID)
+func (b *Bridge) dcrmSignProtoTransaction(tx *ProtoSignContent, args *tokens.BuildTxArgs) (signedTx interface{}, txHash string, err error) {
+	err = b.verifyProtoTransactionWithArgs(tx, args)
+	if err != nil {
+		return nil, "", err
 	}
-
-	// pub
-	pubHex := b.GetDcrmPublicKey(args.PairID)
-	pubBytes, err := hex.DecodeString(pubHex)
+	msgHash := tx.Hash()
+	keyID, signatureBytes, err := b.dcrmSignMsgHash(msgHash, args)
 	if err != nil {
-		return nil, "", errors.New("wrong dcrm public key")
+		return nil, "", err
 	}
 
-	pub, err := btcec.ParsePubKey(pubBytes, btcec.S256())
+	pubkey, err := b.getDcrmPubKeySecp256k1(args.PairID)
 	if err != nil {
-		return nil, "", errors.New("wrong dcrm public key")
+		return nil, "", err
+	}
+	if !bytes.Equal(pubkey[:], tx.PubKey) {
+		return nil, "", errors.New("signer public key mismatch")
+	}
+	if !pubkey.VerifyBytes(tx.SignBytes(), signatureBytes) {
+		log.Error("Dcrm sign verify error")
+		return nil, "", errors.New("wrong signature")
 	}
 
-	cpub := pub.SerializeCompressed()
-	var arr [33]byte
-	copy(arr[:], cpub[:33])
-	pubkey := secp256k1.PubKeySecp256k1(arr)
+	protoSignedTx := &ProtoSignedTx{
+		ProtoSignContent: *tx,
+		Signature:        signatureBytes,
+	}
+	txHash = protoSignedTx.TxHash()
+	log.Info(b.ChainConfig.BlockChain+" DcrmSignTransaction success", "keyID", keyID, "txhash", txHash, "nonce", tx.Sequence)
+	return protoSignedTx, txHash, nil
+}
+
+// dcrmSignMsgHash dcrm sign msg hash, returns 64 bytes signature (R || S) with low S
+func (b *Bridge) dcrmSignMsgHash(msgHash string, args *tokens.BuildTxArgs) (keyID string, signature []byte, err error) {
+	jsondata, _ := json.Marshal(args)
+	msgContext := string(jsondata)
 
-	rsvb, err := hex.DecodeString(rsv)
+	log.Info(b.ChainConfig.BlockChain+" DcrmSignTransaction start", "msghash", msgHash, "txid", args.SwapID)
+	keyID, rsvs, err := dcrm.DoSignOne(b.GetDcrmPublicKey(args.PairID), msgHash, msgContext)
 	if err != nil {
-		return
+		return "", nil, err
 	}
-	var signatureBytes []byte
-	if len(rsvb) == 65 {
-		signatureBytes = rsvb[:64]
+	if len(rsvs) != 1 {
+		return "", nil, fmt.Errorf("get sign status require one rsv but have %v (keyID = %v)", len(rsvs), keyID)
 	}
-	stdsig := authtypes.StdSignature{
-		PubKey:    pubkey,
-		Signature: signatureBytes,
-	}
-	signedTx = HashableStdTx{
-		StdSignContent: tx,
-		Signatures:     []authtypes.StdSignature{stdsig},
+	rsv := rsvs[0]
+	log.Trace(b.ChainConfig.BlockChain+" DcrmSignTransaction get rsv success", "keyID", keyID, "rsv", rsv)
+
+	rsvb := common.FromHex(rsv)
+	if len(rsvb) != crypto.SignatureLength {
+		log.Error("DcrmSignTransaction wrong length of signature")
+		return "", nil, errors.New("wrong signature of keyID " + keyID)
 	}
+	return keyID, toLowS(rsvb[:64]), nil
+}
 
-	if pubkey.VerifyBytes(tx.SignBytes(), signatureBytes) == false {
-		log.Error("Dcrm sign verify error")
-		return nil, "", errors.New("wrong signature")
+// toLowS normalize signature to have low S value (required by cosmos)
+func toLowS(sig []byte) []byte {
+	curveN := btcec.S256().N
+	halfN := new(big.Int).Rsh(curveN, 1)
+	s := new(big.Int).SetBytes(sig[32:64])
+	if s.Cmp(halfN) <= 0 {
+		return sig
 	}
+	s.Sub(curveN, s)
+	result := make([]byte, 64)
+	copy(result[:32], sig[:32])
+	sBytes := s.Bytes()
+	copy(result[64-len(sBytes):], sBytes)
+	return result
+}
 
-	txHash = msgHash
-	log.Info(b.ChainConfig.BlockChain+" DcrmSignTransaction success", "keyID", keyID, "txhash", txHash, "nonce", signedTx.(HashableStdTx).Sequence)
-	return signedTx, txHash, err
+func (b *Bridge) getDcrmPubKeySecp256k1(pairID string) (pubkey secp256k1.PubKeySecp256k1, err error) {
+	pubBytes, err := b.getSignerPubKey(b.GetTokenConfig(pairID))
+	if err != nil {
+		return pubkey, err
+	}
+	copy(pubkey[:], pubBytes)
+	return pubkey, nil
 }
 
 // SignTransaction sign tx with pairID
 func (b *Bridge) SignTransaction(rawTx interface{}, pairID string) (signedTx interface{}, txHash string, err error) {
-	privKey := b.GetTokenConfig(pairID).GetDcrmAddressPrivateKey()
-	return b.SignTransactionWithPrivateKey(rawTx, privKey)
+	keySigner := b.GetTokenConfig(pairID).GetDcrmAddressSigner()
+	if keySigner == nil {
+		return nil, "", tokens.ErrMissingSigner
+	}
+	return b.SignTransactionWithSigner(rawTx, keySigner)
 }
 
 // SignTransactionWithPrivateKey sign tx with ECDSA private key
 func (b *Bridge) SignTransactionWithPrivateKey(rawTx interface{}, privKey *ecdsa.PrivateKey) (signedTx interface{}, txHash string, err error) {
+	return b.SignTransactionWithSigner(rawTx, signer.NewLocalSigner(privKey))
+}
+
+// signWithSigner sign sha256 hash of msg, returns signature in [R || S] format
+func signWithSigner(keySigner signer.Signer, msg []byte) ([]byte, error) {
+	hash := sha256.Sum256(msg)
+	signature, err := keySigner.SignHash(hash[:])
+	if err != nil {
+		return nil, err
+	}
+	return signature[:64], nil
+}
+
+// SignTransactionWithSigner sign tx with signer
+func (b *Bridge) SignTransactionWithSigner(rawTx interface{}, keySigner signer.Signer) (signedTx interface{}, txHash string, err error) {
+	if protoTx, ok := rawTx.(*ProtoSignContent); ok {
+		signature, errf := signWithSigner(keySigner, protoTx.SignBytes())
+		if errf != nil {
+			return nil, "", errf
+		}
+		protoSignedTx := &ProtoSignedTx{
+			ProtoSignContent: *protoTx,
+			Signature:        signature,
+		}
+		return protoSignedTx, protoSignedTx.TxHash(), nil
+	}
+
 	// rawTx is of type authtypes.StdSignDoc
 	tx, ok := rawTx.(StdSignContent)
 	if !ok {
@@ -170,17 +246,13 @@ func (b *Bridge) SignTransactionWithPrivateKey(rawTx interface{}, privKey *ecdsa
 	}
 
 	signBytes := tx.SignBytes()
-
-	var privBytes [32]byte
-	btcecpriv := btcec.PrivateKey(*privKey)
-	copy(privBytes[:], btcecpriv.Serialize()[:33])
-	priv := secp256k1.PrivKeySecp256k1(privBytes)
-	signature, err := priv.Sign(signBytes)
+	signature, err := signWithSigner(keySigner, signBytes)
 	if err != nil {
 		return nil, "", err
 	}
 
-	pub := priv.PubKey()
+	var pub secp256k1.PubKeySecp256k1
+	copy(pub[:], (*btcec.PublicKey)(keySigner.PublicKey()).SerializeCompressed())
 
 	stdsig := authtypes.StdSignature{
 		PubKey:    pub,
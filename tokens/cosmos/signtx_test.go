@@ -0,0 +1,68 @@
+package cosmos
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+)
+
+func TestVerifyTransactionWithArgs(t *testing.T) {
+	b := newTestBridge(t, newTestAddress(1).String())
+	dcrm, bind, other := newTestAddress(5), newTestAddress(2), newTestAddress(3)
+	tokens.GetTokenConfig("atom", false).DcrmAddress = dcrm.String()
+
+	// swapin fee 10% is charged by source token
+	srcToken := tokens.GetTokenConfig("atom", true)
+	feeRate, maxFee := 0.1, 1000.0
+	srcToken.SwapFeeRate = &feeRate
+	srcToken.MaximumSwapFee = &maxFee
+	srcToken.CalcAndStoreValue()
+
+	args := &tokens.BuildTxArgs{
+		SwapInfo:    tokens.SwapInfo{PairID: "atom", SwapType: tokens.SwapinType, Bind: bind.String()},
+		From:        dcrm.String(),
+		OriginValue: big.NewInt(1000),
+	}
+	if swapValue := args.GetSwapValue(); swapValue.Int64() != 900 {
+		t.Fatalf("swap value mismatch, have %v want %v", swapValue, 900)
+	}
+
+	// ibc denom contains upper case letters, which is invalid coin denom in sdk v0.39
+	tests := []struct {
+		name     string
+		from, to sdk.AccAddress
+		coins    sdk.Coins
+		ok       bool
+	}{
+		{"swap value", dcrm, bind, sdk.NewCoins(sdk.NewInt64Coin("uatom", 900)), true},
+		{"ibc denom", dcrm, bind, sdk.Coins{{Denom: testAtomIBCDenom, Amount: sdk.NewInt(900)}}, true},
+		{"less than swap value", dcrm, bind, sdk.NewCoins(sdk.NewInt64Coin("uatom", 899)), false},
+		{"more than swap value", dcrm, bind, sdk.NewCoins(sdk.NewInt64Coin("uatom", 901)), false},
+		{"origin value", dcrm, bind, sdk.NewCoins(sdk.NewInt64Coin("uatom", 1000)), false},
+		{"other sender", other, bind, sdk.NewCoins(sdk.NewInt64Coin("uatom", 900)), false},
+		{"other receiver", dcrm, other, sdk.NewCoins(sdk.NewInt64Coin("uatom", 900)), false},
+		{"other denom", dcrm, bind, sdk.NewCoins(sdk.NewInt64Coin("uosmo", 900)), false},
+		{"multiple coins", dcrm, bind, sdk.Coins{sdk.NewInt64Coin("uatom", 900), {Denom: testAtomIBCDenom, Amount: sdk.NewInt(1)}}, false},
+	}
+	for _, test := range tests {
+		tx := StdSignContent{Msgs: []sdk.Msg{NewMsgSend(test.from, test.to, test.coins)}}
+		if err := b.verifyTransactionWithArgs(tx, args); (err == nil) != test.ok {
+			t.Errorf("%v: verify legacy tx result mismatch, err %v", test.name, err)
+		}
+		protoTx := &ProtoSignContent{Msgs: []ProtoMsg{&ProtoMsgSend{
+			FromAddress: test.from.String(),
+			ToAddress:   test.to.String(),
+			Amount:      test.coins,
+		}}}
+		if err := b.verifyProtoTransactionWithArgs(protoTx, args); (err == nil) != test.ok {
+			t.Errorf("%v: verify proto tx result mismatch, err %v", test.name, err)
+		}
+	}
+
+	msg := NewMsgSend(dcrm, bind, sdk.NewCoins(sdk.NewInt64Coin("uatom", 900)))
+	if err := b.verifyTransactionWithArgs(StdSignContent{Msgs: []sdk.Msg{msg, msg}}, args); err == nil {
+		t.Error("tx with multiple msgs should fail")
+	}
+}
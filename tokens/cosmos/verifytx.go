@@ -16,12 +16,18 @@ import (
 
 // VerifyMsgHash verify msg hash
 func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHash []string) (err error) {
-	tx, ok := rawTx.(StdSignContent)
-	if !ok {
+	var txHash string
+	switch tx := rawTx.(type) {
+	case StdSignContent:
+		txHash = tx.Hash()
+	case *ProtoSignContent:
+		txHash = tx.Hash()
+	default:
 		return errors.New("raw tx type assertion error")
 	}
-
-	txHash := tx.Hash()
+	if len(msgHash) != 1 {
+		return tokens.ErrWrongCountOfMsgHashes
+	}
 	if strings.EqualFold(txHash, msgHash[0]) == true {
 		return nil
 	}
@@ -30,128 +36,181 @@ func (b *Bridge) VerifyMsgHash(rawTx interface{}, msgHash []string) (err error)
 
 // VerifyTransaction impl
 func (b *Bridge) VerifyTransaction(pairID, txHash string, allowUnstable bool) (*tokens.TxSwapInfo, error) {
-	if !b.IsSrc {
-		return nil, tokens.ErrBridgeDestinationNotSupported
+	swapInfos, errs := b.VerifyTransactionSwaps(pairID, txHash, allowUnstable)
+	if len(swapInfos) == 0 {
+		log.Warn("No such swapInfo")
+		return nil, nil
 	}
-	swapInfos, errs := b.verifySwapinTxWithHash(pairID, txHash, allowUnstable)
-	// swapinfos have already aggregated
-	for i, swapInfo := range swapInfos {
-		if strings.EqualFold(swapInfo.PairID, pairID) {
-			return swapInfo, errs[i]
+	return swapInfos[0], errs[0]
+}
+
+// VerifyTransactionSwaps impl tokens.MultiSwapVerifier
+// one tx may contain several swaps of a pair with different bind addresses
+func (b *Bridge) VerifyTransactionSwaps(pairID, txHash string, allowUnstable bool) (swapInfos []*tokens.TxSwapInfo, errs []error) {
+	allSwapInfos, allErrs := b.verifyDepositTxWithHash(txHash, allowUnstable)
+	for i, swapInfo := range allSwapInfos {
+		if swapInfo != nil && strings.EqualFold(swapInfo.PairID, pairID) {
+			swapInfos = append(swapInfos, swapInfo)
+			errs = append(errs, allErrs[i])
 		}
 	}
-	log.Warn("No such swapInfo")
-	return nil, nil
+	return swapInfos, errs
 }
 
-func (b *Bridge) verifySwapinTx(txresp sdk.TxResponse, allowUnstable bool) (swapInfos []*tokens.TxSwapInfo, errs []error) {
-	swapInfos = make([]*tokens.TxSwapInfo, 0)
-	swapInfoMap := make(map[string][]*tokens.TxSwapInfo)
+// verifyDepositTx verify deposit tx.
+// on source chain deposits are swapins, on destination chain deposits are
+// swapouts (coins are sent back to deposit address and the memo is the
+// receiver address on source chain).
+func (b *Bridge) verifyDepositTx(txresp *sdk.TxResponse, allowUnstable bool) (swapInfos []*tokens.TxSwapInfo, errs []error) {
+	swapInfos, errs = b.verifyDepositMsgs(txresp)
+	for _, swapInfo := range swapInfos {
+		swapInfo.Height = uint64(txresp.Height)
+	}
+	return swapInfos, errs
+}
+
+func (b *Bridge) verifyDepositTxWithHash(txHash string, allowUnstable bool) (swapInfos []*tokens.TxSwapInfo, errs []error) {
+	if b.IsProtobufTx() {
+		// tx logs are required to verify ibc packets
+		txresp, err := b.getTxResponseProto(txHash)
+		if err != nil || txresp.Code != 0 {
+			log.Debug("[verifyDeposit] "+b.ChainConfig.BlockChain+" Bridge::GetTransaction fail", "tx", txHash, "err", err)
+			return nil, []error{tokens.ErrTxNotStable}
+		}
+		return b.verifyDepositTx(txresp, allowUnstable)
+	}
+	tx, err := b.GetTransaction(txHash)
+	if err != nil {
+		log.Debug("[verifyDeposit] "+b.ChainConfig.BlockChain+" Bridge::GetTransaction fail", "tx", txHash, "err", err)
+		errs = []error{tokens.ErrTxNotStable}
+		return nil, errs
+	}
+	cosmostx, ok := tx.(sdk.Tx)
+	if !ok {
+		log.Debug("[verifyDeposit] "+b.ChainConfig.BlockChain+" Bridge::Transacton is of wrong type", "tx", txHash)
+		return nil, []error{errors.New("Tx is of wrong type")}
+	}
+	return b.verifyDepositMsgs(&sdk.TxResponse{TxHash: txHash, Tx: cosmostx})
+}
+
+// verifyDepositMsgs walks all msgs of tx, and registers one swap for
+// outputs to deposit address with the same pairID and bind address
+// (bank msgs bind to tx memo, ibc packets bind to packet memo).
+// swaps are identified by txid, pairID and bind address, so outputs of the
+// same swap are summed into its value, and its From is the sender of the
+// first summed output. multisend has no mapping from inputs to outputs,
+// its first input address is taken as sender of all its outputs.
+//
+//nolint:gocyclo // keep msgs walking as whole
+func (b *Bridge) verifyDepositMsgs(txresp *sdk.TxResponse) (swapInfos []*tokens.TxSwapInfo, errs []error) {
 	txid := strings.ToLower(txresp.TxHash)
 	cosmostx := txresp.Tx
+	swapInfos = make([]*tokens.TxSwapInfo, 0)
+	swapInfoMap := make(map[string][]*tokens.TxSwapInfo)
+	swapKeys := make([]string, 0)
 
 	// get bind address from memo
-	bindaddress, ok := b.GetBindAddressFromMemo(cosmostx)
-	if !ok {
-		return swapInfos, []error{fmt.Errorf("Cannot get bind address")}
-	}
-	if err := b.checkSwapinBindAddress(bindaddress); err != nil {
-		errs = []error{err}
-		return swapInfos, errs
+	memoBind, hasMemoBind := b.GetBindAddressFromMemo(cosmostx)
+
+	addDeposit := func(from, to, bind string, coin sdk.Coin) {
+		pairID, err := b.getPairID(coin)
+		if err != nil {
+			return
+		}
+		tokenCfg := b.GetTokenConfig(pairID)
+		if tokenCfg == nil {
+			return
+		}
+		if b.EqualAddress(to, tokenCfg.DepositAddress) == false {
+			return
+		}
+		swapInfo := &tokens.TxSwapInfo{}
+		swapInfo.PairID = pairID
+		swapInfo.From = from
+		swapInfo.To = tokenCfg.DepositAddress
+		swapInfo.Bind = bind
+		swapInfo.Value = coin.Amount.BigInt()
+		key := pairID + ":" + bind
+		if _, exist := swapInfoMap[key]; !exist {
+			swapKeys = append(swapKeys, key)
+		}
+		swapInfoMap[key] = append(swapInfoMap[key], swapInfo)
 	}
 
 	// check every msg
 	// if type is bank/send or bank/multisend, check every coin in every output
+	// if type is ibc/recv_packet, check the transferred coin of packet
 	// add to swapinfo
 	msgs := cosmostx.GetMsgs()
-	for _, msg := range msgs {
+	for i, msg := range msgs {
 		if err := msg.ValidateBasic(); err != nil {
 			continue
 		}
-		msgtype := msg.Type()
-		if msgtype == TypeMsgSend {
-			// MsgSend
+		switch msg.Type() {
+		case TypeMsgSend:
 			msgsend, ok := msg.(MsgSend)
-			if !ok {
+			if !ok || !hasMemoBind {
 				continue
 			}
-
 			for _, coin := range msgsend.Amount {
-				pairID, err := b.getPairID(coin)
-				if err != nil {
-					continue
-				}
-				tokenCfg := b.GetTokenConfig(pairID)
-				if tokenCfg == nil {
-					continue
-				}
-				if b.EqualAddress(msgsend.ToAddress.String(), tokenCfg.DepositAddress) == false {
-					continue
-				}
-				swapInfo := &tokens.TxSwapInfo{}
-				swapInfo.PairID = pairID
-				swapInfo.To = tokenCfg.DepositAddress
-				swapInfo.Bind = bindaddress
-				swapInfo.From = bindaddress
-				swapInfo.Value = coin.Amount.BigInt()
-				if swapInfoMap[pairID] == nil {
-					swapInfoMap[pairID] = make([]*tokens.TxSwapInfo, 0)
-				}
-				swapInfoMap[pairID] = append(swapInfoMap[pairID], swapInfo)
+				addDeposit(msgsend.FromAddress.String(), msgsend.ToAddress.String(), memoBind, coin)
 			}
-
-		} else if msgtype == TypeMsgMultiSend {
-			// MsgMultisend
+		case TypeMsgMultiSend:
 			msgmultisend, ok := msg.(MsgMultiSend)
-			if !ok {
+			if !ok || !hasMemoBind || len(msgmultisend.Inputs) == 0 {
 				continue
 			}
+			from := msgmultisend.Inputs[0].Address.String()
 			for _, output := range msgmultisend.Outputs {
 				for _, coin := range output.Coins {
-					pairID, err := b.getPairID(coin)
-					if err != nil {
-						continue
-					}
-					tokenCfg := b.GetTokenConfig(pairID)
-					if tokenCfg == nil {
-						continue
-					}
-					if b.EqualAddress(output.Address.String(), tokenCfg.DepositAddress) == false {
-						continue
-					}
-					swapInfo := &tokens.TxSwapInfo{}
-					swapInfo.PairID = pairID
-					swapInfo.To = tokenCfg.DepositAddress
-					swapInfo.Bind = bindaddress
-					swapInfo.From = bindaddress
-					swapInfo.Value = coin.Amount.BigInt()
-					if swapInfoMap[pairID] == nil {
-						swapInfoMap[pairID] = make([]*tokens.TxSwapInfo, 0)
-					}
-					swapInfoMap[pairID] = append(swapInfoMap[pairID], swapInfo)
+					addDeposit(from, output.Address.String(), memoBind, coin)
 				}
 			}
-		} else {
-			continue
+		case TypeMsgRecvPacket:
+			msgrecv, ok := msg.(MsgRecvPacket)
+			if !ok {
+				continue
+			}
+			data, coin, err := msgrecv.GetTransferData()
+			if err != nil {
+				log.Debug("ignore ibc packet", "txid", txid, "sequence", msgrecv.Packet.Sequence, "err", err)
+				continue
+			}
+			if !isRecvPacketSuccess(txresp.Logs, i, data.Receiver) {
+				log.Debug("ignore unsuccessful ibc packet", "txid", txid, "sequence", msgrecv.Packet.Sequence)
+				continue
+			}
+			bind, ok := b.getBindAddress(data.Memo)
+			if !ok {
+				continue
+			}
+			addDeposit(data.Sender, data.Receiver, bind, coin)
 		}
 	}
 
-	// aggregate by pairID
-	for k, v := range swapInfoMap {
-		if len(v) < 1 {
-			continue
-		}
+	if len(swapKeys) == 0 && !hasMemoBind {
+		return swapInfos, []error{fmt.Errorf("Cannot get bind address")}
+	}
+
+	// aggregate by pairID and bind address
+	for _, key := range swapKeys {
+		v := swapInfoMap[key]
 		aggSwapInfo := &tokens.TxSwapInfo{}
-		aggSwapInfo.PairID = k
+		aggSwapInfo.PairID = v[0].PairID
+		aggSwapInfo.Hash = txid
+		aggSwapInfo.From = v[0].From
 		aggSwapInfo.To = v[0].To
 		aggSwapInfo.Bind = v[0].Bind
-		aggSwapInfo.Hash = txid
 		aggSwapInfo.Value = big.NewInt(0)
 		for _, swapInfo := range v {
 			aggSwapInfo.Value = new(big.Int).Add(aggSwapInfo.Value, swapInfo.Value)
 		}
+		err := b.checkBindAddress(aggSwapInfo.Bind)
+		if err == nil && !tokens.CheckSwapValue(aggSwapInfo.PairID, aggSwapInfo.Value, b.IsSrc) {
+			err = tokens.ErrTxWithWrongValue
+		}
 		swapInfos = append(swapInfos, aggSwapInfo)
-		errs = append(errs, nil)
+		errs = append(errs, err)
 	}
 
 	return swapInfos, errs
@@ -163,161 +222,61 @@ var NotSupportedCoinErr = errors.New("coin not supported")
 // getPairID returns pairID corresponding to given coin
 // returns error when coin type not supported
 func (b *Bridge) getPairID(coin sdk.Coin) (string, error) {
-	for k, v := range b.SupportedCoins {
-		if strings.EqualFold(v.Denom, coin.Denom) {
-			return strings.ToLower(k), nil
-		}
+	if pairID, ok := b.findPairID(coin.Denom); ok {
+		return pairID, nil
 	}
 	// if not exists, reload coins and find it
 	b.LoadCoins()
-	for k, v := range b.SupportedCoins {
-		if strings.EqualFold(v.Denom, coin.Denom) {
-			return strings.ToLower(k), nil
-		}
+	if pairID, ok := b.findPairID(coin.Denom); ok {
+		return pairID, nil
 	}
 	return "", NotSupportedCoinErr
 }
 
-func (b *Bridge) verifySwapinTxWithHash(pairID, txHash string, allowUnstable bool) (swapInfos []*tokens.TxSwapInfo, errs []error) {
-	txid := strings.ToLower(txHash)
-	tx, err := b.GetTransaction(txHash)
-	if err != nil {
-		log.Debug("[verifySwapin] "+b.ChainConfig.BlockChain+" Bridge::GetTransaction fail", "tx", txHash, "err", err)
-		errs = []error{tokens.ErrTxNotStable}
-		return nil, errs
-	}
-	cosmostx, ok := tx.(sdk.Tx)
-	if !ok {
-		log.Debug("[verifySwapin] "+b.ChainConfig.BlockChain+" Bridge::Transacton is of wrong type", "tx", txHash)
-		return nil, []error{errors.New("Tx is of wrong type")}
-	}
-	swapInfos = make([]*tokens.TxSwapInfo, 0)
-	swapInfoMap := make(map[string][]*tokens.TxSwapInfo)
-
-	// get bind address from memo
-	bindaddress, ok := b.GetBindAddressFromMemo(cosmostx)
-	if !ok {
-		return swapInfos, []error{fmt.Errorf("Cannot get bind address")}
-	}
-	if err := b.checkSwapinBindAddress(bindaddress); err != nil {
-		errs = []error{err}
-		return swapInfos, errs
-	}
-
-	// check every msg
-	// if type is bank/send or bank/multisend, check every coin in every output
-	// add to swapinfo
-	msgs := cosmostx.GetMsgs()
-	for _, msg := range msgs {
-		if err := msg.ValidateBasic(); err != nil {
-			continue
-		}
-		msgtype := msg.Type()
-		if msgtype == TypeMsgSend {
-			// MsgSend
-			msgsend, ok := msg.(MsgSend)
-			if !ok {
-				continue
-			}
-
-			for _, coin := range msgsend.Amount {
-				pairID, err := b.getPairID(coin)
-				if err != nil {
-					continue
-				}
-				tokenCfg := b.GetTokenConfig(pairID)
-				if tokenCfg == nil {
-					continue
-				}
-				if b.EqualAddress(msgsend.ToAddress.String(), tokenCfg.DepositAddress) == false {
-					continue
-				}
-				swapInfo := &tokens.TxSwapInfo{}
-				swapInfo.PairID = pairID
-				swapInfo.To = tokenCfg.DepositAddress
-				swapInfo.Bind = bindaddress
-				swapInfo.From = bindaddress
-				swapInfo.Value = coin.Amount.BigInt()
-				if swapInfoMap[pairID] == nil {
-					swapInfoMap[pairID] = make([]*tokens.TxSwapInfo, 0)
-				}
-				swapInfoMap[pairID] = append(swapInfoMap[pairID], swapInfo)
-			}
-
-		} else if msgtype == TypeMsgMultiSend {
-			// MsgMultisend
-			msgmultisend, ok := msg.(MsgMultiSend)
-			if !ok {
-				continue
-			}
-			for _, output := range msgmultisend.Outputs {
-				for _, coin := range output.Coins {
-					pairID, err := b.getPairID(coin)
-					if err != nil {
-						continue
-					}
-					tokenCfg := b.GetTokenConfig(pairID)
-					if tokenCfg == nil {
-						continue
-					}
-					if b.EqualAddress(output.Address.String(), tokenCfg.DepositAddress) == false {
-						continue
-					}
-					swapInfo := &tokens.TxSwapInfo{}
-					swapInfo.PairID = pairID
-					swapInfo.To = tokenCfg.DepositAddress
-					swapInfo.Bind = bindaddress
-					swapInfo.From = bindaddress
-					swapInfo.Value = coin.Amount.BigInt()
-					if swapInfoMap[pairID] == nil {
-						swapInfoMap[pairID] = make([]*tokens.TxSwapInfo, 0)
-					}
-					swapInfoMap[pairID] = append(swapInfoMap[pairID], swapInfo)
-				}
-			}
-		} else {
-			continue
+func (b *Bridge) findPairID(denom string) (string, bool) {
+	for k, v := range b.SupportedCoins {
+		if strings.EqualFold(v.Denom, denom) {
+			return strings.ToLower(k), true
 		}
 	}
-
-	// aggregate by pairID
-	for k, v := range swapInfoMap {
-		if len(v) < 1 {
-			continue
-		}
-		aggSwapInfo := &tokens.TxSwapInfo{}
-		aggSwapInfo.PairID = k
-		aggSwapInfo.Hash = txid
-		aggSwapInfo.To = v[0].To
-		aggSwapInfo.Bind = v[0].Bind
-		// aggSwapInfo.TxId = v[0].TxId
-		aggSwapInfo.Value = big.NewInt(0)
-		for _, swapInfo := range v {
-			aggSwapInfo.Value = new(big.Int).Add(aggSwapInfo.Value, swapInfo.Value)
+	if strings.HasPrefix(denom, IBCDenomPrefix) {
+		// ibc denom hash is upper case hex
+		denom = IBCDenomPrefix + strings.ToUpper(denom[len(IBCDenomPrefix):])
+		if name, ok := b.IBCDenoms[denom]; ok {
+			return strings.ToLower(name), true
 		}
-		swapInfos = append(swapInfos, aggSwapInfo)
-		errs = append(errs, nil)
 	}
-
-	return swapInfos, errs
+	return "", false
 }
 
 // GetBindAddressFromMemo get tx memo from an sdk.Tx
+// the memo must be a valid address of the counterpart chain
 func (b *Bridge) GetBindAddressFromMemo(tx sdk.Tx) (address string, ok bool) {
 	authtx, ok := tx.(authtypes.StdTx)
 	if !ok {
 		log.Warn("GetBindAddressFromMemo: Tx is not auth StdTx", "Tx", tx)
 		return "", false
 	}
-	memo := authtx.Memo
-	dstBridge := tokens.DstBridge
-	if ok = dstBridge.IsValidAddress(memo); ok {
-		memo = strings.ToLower(memo)
-		return memo, ok
-	} else {
+	return b.getBindAddress(authtx.Memo)
+}
+
+func (b *Bridge) getBindAddress(memo string) (address string, ok bool) {
+	memo = strings.TrimSpace(memo)
+	if !tokens.GetCrossChainBridge(!b.IsSrc).IsValidAddress(memo) {
 		log.Warn("GetBindAddressFromMemo: memo is not a valid address", "memo", memo)
 		return "", false
 	}
+	if b.IsSrc {
+		memo = strings.ToLower(memo)
+	}
+	return memo, true
+}
+
+func (b *Bridge) checkBindAddress(bindAddr string) error {
+	if b.IsSrc {
+		return b.checkSwapinBindAddress(bindAddr)
+	}
+	return b.checkSwapoutBindAddress(bindAddr)
 }
 
 func (b *Bridge) checkSwapinBindAddress(bindAddr string) error {
@@ -330,3 +289,11 @@ func (b *Bridge) checkSwapinBindAddress(bindAddr string) error {
 	}
 	return nil
 }
+
+func (b *Bridge) checkSwapoutBindAddress(bindAddr string) error {
+	if !tokens.SrcBridge.IsValidAddress(bindAddr) {
+		log.Warn("wrong bind address in swapout", "bind", bindAddr)
+		return tokens.ErrTxWithWrongMemo
+	}
+	return nil
+}
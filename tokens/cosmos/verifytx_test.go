@@ -0,0 +1,257 @@
+package cosmos
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	sdk "github.com/cosmos/cosmos-sdk/types"
+	authtypes "github.com/cosmos/cosmos-sdk/x/auth/types"
+)
+
+// ATOM on osmosis, received through channel-0 of transfer port
+const testAtomIBCDenom = "ibc/27394FB092D2ECCD56123C74F36E4C1F926001CEADA9CA97EA622B25F41E5EB2"
+
+func newTestAddress(b byte) sdk.AccAddress {
+	addr := make([]byte, 20)
+	for i := range addr {
+		addr[i] = b
+	}
+	return addr
+}
+
+func newTestToken(depositAddress string, denomTraces []string) *tokens.TokenConfig {
+	decimals := uint8(6)
+	maxSwap, minSwap, bigValue := 1000.0, 0.0, 100.0
+	feeRate, minFee, maxFee := 0.0, 0.0, 0.0
+	token := &tokens.TokenConfig{
+		ID:                "ATOM",
+		Unit:              "uatom",
+		Decimals:          &decimals,
+		DepositAddress:    depositAddress,
+		IBCDenomTraces:    denomTraces,
+		MaximumSwap:       &maxSwap,
+		MinimumSwap:       &minSwap,
+		BigValueThreshold: &bigValue,
+		SwapFeeRate:       &feeRate,
+		MinimumSwapFee:    &minFee,
+		MaximumSwapFee:    &maxFee,
+	}
+	token.CalcAndStoreValue()
+	return token
+}
+
+// newTestBridge returns destination cosmos bridge of cosmos to cosmos pair 'atom'
+func newTestBridge(t *testing.T, depositAddress string) *Bridge {
+	oldPairsConfig := tokens.GetTokenPairsConfig()
+	oldSrcBridge, oldDstBridge := tokens.SrcBridge, tokens.DstBridge
+	t.Cleanup(func() {
+		tokens.SetTokenPairsConfig(oldPairsConfig, false)
+		tokens.SrcBridge, tokens.DstBridge = oldSrcBridge, oldDstBridge
+	})
+
+	tokens.SetTokenPairsConfig(map[string]*tokens.TokenPairConfig{
+		"atom": {
+			PairID:    "atom",
+			SrcToken:  newTestToken(newTestAddress(9).String(), nil),
+			DestToken: newTestToken(depositAddress, []string{"transfer/channel-0/uatom"}),
+		},
+	}, false)
+	b := NewCrossChainBridge(false)
+	b.LoadCoins()
+	tokens.SrcBridge = NewCrossChainBridge(true)
+	tokens.DstBridge = b
+	return b
+}
+
+func newTestRecvPacket(t *testing.T, denom, amount, receiver, memo string) MsgRecvPacket {
+	data, err := json.Marshal(&FungibleTokenPacketData{
+		Denom:    denom,
+		Amount:   amount,
+		Sender:   "cosmos1sender",
+		Receiver: receiver,
+		Memo:     memo,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return MsgRecvPacket{
+		Packet: Packet{
+			Sequence:           1,
+			SourcePort:         "transfer",
+			SourceChannel:      "channel-141",
+			DestinationPort:    "transfer",
+			DestinationChannel: "channel-0",
+			Data:               data,
+		},
+		Signer: newTestAddress(8).String(),
+	}
+}
+
+func newTestAckLogs(msgIndex uint16, receiver, success string) sdk.ABCIMessageLogs {
+	return sdk.ABCIMessageLogs{{
+		MsgIndex: msgIndex,
+		Events: sdk.StringEvents{{
+			Type: EventTypeFungibleTokenPacket,
+			Attributes: []sdk.Attribute{
+				{Key: "module", Value: "transfer"},
+				{Key: AttributeKeyReceiver, Value: receiver},
+				{Key: AttributeKeyAckSuccess, Value: success},
+			},
+		}},
+	}}
+}
+
+func TestVerifyMultiSendDeposit(t *testing.T) {
+	deposit := newTestAddress(1)
+	b := newTestBridge(t, deposit.String())
+	bind := newTestAddress(2).String()
+	from, other := newTestAddress(3), newTestAddress(4)
+
+	msg := MsgMultiSend{
+		Inputs: []Input{
+			{Address: from, Coins: sdk.NewCoins(sdk.NewInt64Coin("uatom", 330))},
+			{Address: other, Coins: sdk.NewCoins(sdk.NewInt64Coin("uatom", 20), sdk.NewInt64Coin("uother", 40))},
+		},
+		Outputs: []Output{
+			{Address: deposit, Coins: sdk.NewCoins(sdk.NewInt64Coin("uatom", 100))},
+			{Address: other, Coins: sdk.NewCoins(sdk.NewInt64Coin("uatom", 50))},
+			{Address: deposit, Coins: sdk.NewCoins(sdk.NewInt64Coin("uatom", 200), sdk.NewInt64Coin("uother", 40))},
+		},
+	}
+	txresp := &sdk.TxResponse{
+		TxHash: "ABCD",
+		Tx:     authtypes.StdTx{Msgs: []sdk.Msg{msg}, Memo: bind},
+	}
+	swapInfos, errs := b.verifyDepositMsgs(txresp)
+	if len(swapInfos) != 1 || len(errs) != 1 {
+		t.Fatalf("multisend outputs of the same bind should be one swap, have %v swaps", len(swapInfos))
+	}
+	swapInfo := swapInfos[0]
+	if errs[0] != nil {
+		t.Fatalf("verify multisend deposit failed, %v", errs[0])
+	}
+	if swapInfo.PairID != "atom" || swapInfo.Hash != "abcd" || swapInfo.Bind != bind {
+		t.Errorf("multisend swap info mismatch, have %+v", swapInfo)
+	}
+	if swapInfo.Value.Int64() != 300 {
+		t.Errorf("multisend swap value should sum outputs to deposit address, have %v want %v", swapInfo.Value, 300)
+	}
+	if swapInfo.From != from.String() || swapInfo.To != deposit.String() {
+		t.Errorf("multisend swap from %v to %v mismatch", swapInfo.From, swapInfo.To)
+	}
+}
+
+func TestVerifyRecvPacketDeposit(t *testing.T) {
+	deposit := newTestAddress(1).String()
+	b := newTestBridge(t, deposit)
+	bind := newTestAddress(2).String()
+
+	tests := []struct {
+		name   string
+		denom  string
+		logs   sdk.ABCIMessageLogs
+		amount int64
+	}{
+		{"success", "uatom", newTestAckLogs(0, deposit, "true"), 500},
+		{"ack failed", "uatom", newTestAckLogs(0, deposit, "false"), 0},
+		{"no ack", "uatom", nil, 0},
+		{"ack of other msg", "uatom", newTestAckLogs(1, deposit, "true"), 0},
+		{"unknown denom trace", "uosmo", newTestAckLogs(0, deposit, "true"), 0},
+	}
+	for _, test := range tests {
+		msg := newTestRecvPacket(t, test.denom, "500", deposit, bind)
+		txresp := &sdk.TxResponse{
+			TxHash: "ABCD",
+			Tx:     authtypes.StdTx{Msgs: []sdk.Msg{msg}},
+			Logs:   test.logs,
+		}
+		swapInfos, errs := b.verifyDepositMsgs(txresp)
+		if test.amount == 0 {
+			if len(swapInfos) != 0 {
+				t.Errorf("%v: recv packet should not be deposit, have %+v", test.name, swapInfos[0])
+			}
+			continue
+		}
+		if len(swapInfos) != 1 || errs[0] != nil {
+			t.Errorf("%v: verify recv packet deposit failed, swaps %v errs %v", test.name, len(swapInfos), errs)
+			continue
+		}
+		if swapInfos[0].Value.Int64() != test.amount || swapInfos[0].Bind != bind || swapInfos[0].From != "cosmos1sender" {
+			t.Errorf("%v: recv packet swap info mismatch, have %+v", test.name, swapInfos[0])
+		}
+	}
+}
+
+func TestIBCDenom(t *testing.T) {
+	if denom := IBCDenom("transfer/channel-0/uatom"); denom != testAtomIBCDenom {
+		t.Errorf("ibc denom mismatch, have %v want %v", denom, testAtomIBCDenom)
+	}
+
+	packet := &Packet{
+		SourcePort:         "transfer",
+		SourceChannel:      "channel-141",
+		DestinationPort:    "transfer",
+		DestinationChannel: "channel-0",
+	}
+	tests := []struct {
+		denom string
+		want  string
+	}{
+		{"uatom", testAtomIBCDenom},             // sent from source chain
+		{"transfer/channel-141/uatom", "uatom"}, // returning to origin chain
+		{"transfer/channel-141/transfer/channel-5/uosmo", IBCDenom("transfer/channel-5/uosmo")}, // returning one hop
+		{"transfer/channel-9/uatom", IBCDenom("transfer/channel-0/transfer/channel-9/uatom")},   // forwarded
+	}
+	for _, test := range tests {
+		if have := packet.ReceivedDenom(test.denom); have != test.want {
+			t.Errorf("received denom of %v mismatch, have %v want %v", test.denom, have, test.want)
+		}
+	}
+
+	b := newTestBridge(t, newTestAddress(1).String())
+	for _, denom := range []string{"uatom", testAtomIBCDenom, "ibc/27394fb092d2eccd56123c74f36e4c1f926001ceada9ca97ea622b25f41e5eb2"} {
+		if pairID, ok := b.findPairID(denom); !ok || pairID != "atom" {
+			t.Errorf("find pairID of denom %v mismatch, have %v %v", denom, pairID, ok)
+		}
+	}
+	if _, ok := b.findPairID(IBCDenom("transfer/channel-1/uatom")); ok {
+		t.Error("ibc denom of unconfigured trace should not be supported")
+	}
+}
+
+func TestIsRecvPacketSuccess(t *testing.T) {
+	receiver := newTestAddress(1).String()
+	tests := []struct {
+		logs sdk.ABCIMessageLogs
+		want bool
+	}{
+		{newTestAckLogs(0, receiver, "true"), true},
+		{newTestAckLogs(0, receiver, "false"), false},
+		{newTestAckLogs(0, newTestAddress(2).String(), "true"), false},
+		{newTestAckLogs(1, receiver, "true"), false},
+		{sdk.ABCIMessageLogs{{Events: sdk.StringEvents{{
+			Type: "recv_packet",
+			Attributes: []sdk.Attribute{
+				{Key: AttributeKeyReceiver, Value: receiver},
+				{Key: AttributeKeyAckSuccess, Value: "true"},
+			},
+		}}}}, false},
+		{nil, false},
+	}
+	for i, test := range tests {
+		if have := isRecvPacketSuccess(test.logs, 0, receiver); have != test.want {
+			t.Errorf("test %v: recv packet success mismatch, have %v want %v", i, have, test.want)
+		}
+	}
+}
+
+func TestAppendUniqueTxs(t *testing.T) {
+	exist := make(map[string]struct{})
+	txs := appendUniqueTxs(nil, []sdk.TxResponse{{TxHash: "AA"}, {TxHash: "BB"}}, exist)
+	// tx with both send and recv packet msgs is searched twice
+	txs = appendUniqueTxs(txs, []sdk.TxResponse{{TxHash: "aa"}, {TxHash: "CC"}, {TxHash: "CC"}}, exist)
+	if len(txs) != 3 || txs[0].TxHash != "AA" || txs[1].TxHash != "BB" || txs[2].TxHash != "CC" {
+		t.Errorf("unique txs mismatch, have %v", txs)
+	}
+}
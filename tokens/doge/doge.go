@@ -0,0 +1,74 @@
+// Package doge implements the dogecoin bridge on the btc code path.
+package doge
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+)
+
+const (
+	netMainnet = "mainnet"
+	netTestnet = "testnet"
+)
+
+// MainNetParams dogecoin mainnet params (no segwit)
+var MainNetParams = chaincfg.Params{
+	Name:        netMainnet,
+	Net:         0xc0c0c0c0,
+	DefaultPort: "22556",
+
+	PubKeyHashAddrID: 0x1e, // starts with D
+	ScriptHashAddrID: 0x16, // starts with 9 or A
+	PrivateKeyID:     0x9e, // starts with 6 (uncompressed) or Q (compressed)
+
+	HDPrivateKeyID: [4]byte{0x02, 0xfa, 0xc3, 0x98}, // starts with dgpv
+	HDPublicKeyID:  [4]byte{0x02, 0xfa, 0xca, 0xfd}, // starts with dgub
+
+	HDCoinType: 3,
+}
+
+// TestNetParams dogecoin testnet params (no segwit)
+var TestNetParams = chaincfg.Params{
+	Name:        netTestnet,
+	Net:         0xfcc1b7dc,
+	DefaultPort: "44556",
+
+	PubKeyHashAddrID: 0x71, // starts with n
+	ScriptHashAddrID: 0xc4, // starts with 2
+	PrivateKeyID:     0xf1,
+
+	HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94}, // starts with tprv
+	HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // starts with tpub
+
+	HDCoinType: 1,
+}
+
+// Descriptor doge chain descriptor
+var Descriptor = &btc.ChainDescriptor{
+	Name:   "Dogecoin",
+	Symbol: "DOGE",
+	Networks: map[string]*chaincfg.Params{
+		netMainnet: &MainNetParams,
+		netTestnet: &TestNetParams,
+	},
+	DefaultNetwork: netMainnet,
+
+	SigHashType: txscript.SigHashAll,
+
+	// MAX_MONEY of dogecoin core
+	MaxAmount: 10e9 * btcutil.SatoshiPerBitcoin,
+
+	// dogecoin requires much higher fees in satoshi unit
+	MinRelayFee:       1e6,
+	MaxMinRelayFee:    1e8,
+	MinRelayFeePerKb:  1e6,
+	MaxRelayFeePerKb:  1e8,
+	EstimateFeeBlocks: 6,
+}
+
+// NewCrossChainBridge new doge bridge
+func NewCrossChainBridge(isSrc bool) *btc.Bridge {
+	return btc.NewUtxoChainBridge(isSrc, Descriptor)
+}
@@ -0,0 +1,94 @@
+package doge
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/btcsuite/btcutil"
+)
+
+func newTestBridge(netID string) *btc.Bridge {
+	b := NewCrossChainBridge(true)
+	b.ChainConfig = &tokens.ChainConfig{NetID: netID}
+	return b
+}
+
+func TestDecodeAddress(t *testing.T) {
+	b := newTestBridge(netMainnet)
+	tests := []struct {
+		addr   string
+		hash   string
+		isP2sh bool
+	}{
+		{"DH5yaieqoZN36fDVciNyRueRGvGLR3mr7L", "830a7420e63d76244ff7cbd1c248e94c14463259", false},
+		{"DBXu2kgc3xtvCUWFcxFE3r9hEYgmuaaCyD", "4620b70031f0e9437e374a2100934fba49110460", false},
+		{"A8c3xNz2mqsDLFwv5KL5fpH12QEwDaoTXo", "b15b9098e14dc3c48b4f0a6ac548c66771d30f54", true},
+	}
+	for _, test := range tests {
+		address, err := b.DecodeAddress(test.addr)
+		if err != nil {
+			t.Errorf("decode %v failed, %v", test.addr, err)
+			continue
+		}
+		if hash := hex.EncodeToString(address.ScriptAddress()); hash != test.hash {
+			t.Errorf("decode %v hash mismatch, have %v want %v", test.addr, hash, test.hash)
+		}
+		if b.IsP2shAddress(test.addr) != test.isP2sh || b.IsP2pkhAddress(test.addr) == test.isP2sh {
+			t.Errorf("address type of %v mismatch, isP2sh %v", test.addr, test.isP2sh)
+		}
+		if !b.IsCanonicalAddress(test.addr) {
+			t.Errorf("address %v should be canonical", test.addr)
+		}
+	}
+
+	invalids := []string{
+		"1BgGZ9tcN4rm9KBzDn7KprQz87SZ26SAMH",         // btc mainnet
+		"DH5yaieqoZN36fDVciNyRueRGvGLR3mr7M",         // wrong checksum
+		"nesRpRaAbTDmZHwmzBkLd2AtF7Z9L9z5S2",         // doge testnet
+		"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", // segwit
+	}
+	for _, addr := range invalids {
+		if b.IsValidAddress(addr) {
+			t.Errorf("address %v should be invalid on mainnet", addr)
+		}
+	}
+
+	if !newTestBridge(netTestnet).IsValidAddress("nesRpRaAbTDmZHwmzBkLd2AtF7Z9L9z5S2") {
+		t.Error("doge testnet address should be valid on testnet")
+	}
+}
+
+func TestNewAddressPubKeyHash(t *testing.T) {
+	// compressed public key of private key 1
+	pkData, _ := hex.DecodeString("0279be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798")
+	tests := []struct {
+		netID string
+		want  string
+	}{
+		{netMainnet, "DFpN6QqFfUm3gKNaxN6tNcab1FArL9cZLE"},
+		{netTestnet, "nesRpRaAbTDmZHwmzBkLd2AtF7Z9L9z5S2"},
+	}
+	for _, test := range tests {
+		address, err := newTestBridge(test.netID).NewAddressPubKeyHash(pkData)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if address.EncodeAddress() != test.want {
+			t.Errorf("%v address mismatch, have %v want %v", test.netID, address.EncodeAddress(), test.want)
+		}
+	}
+}
+
+func TestDescriptor(t *testing.T) {
+	if Descriptor.IsForkIDSigHash() {
+		t.Error("dogecoin should not use forkid sighash")
+	}
+	if Descriptor.MaxAmount <= btcutil.MaxSatoshi {
+		t.Errorf("dogecoin max amount %v should exceed btc max amount", Descriptor.MaxAmount)
+	}
+	if params := Descriptor.GetNetParams("custom"); params != &MainNetParams {
+		t.Errorf("unknown net id should use %v params, have %v", netMainnet, params.Name)
+	}
+}
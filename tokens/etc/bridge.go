@@ -13,10 +13,14 @@ import (
 
 const (
 	netMainnet = "mainnet"
-	netKotti   = "kotti"
-	netMordor  = "mordor"
 )
 
+// chain ids of supported networks
+var chainIDs = map[string]uint64{
+	netMainnet: 61,
+	// testnets kotti (6) and mordor (63) are not supported
+}
+
 // Bridge etc bridge inherit from eth bridge
 type Bridge struct {
 	*eth.Bridge
@@ -37,9 +41,7 @@ func (b *Bridge) SetChainAndGateway(chainCfg *tokens.ChainConfig, gatewayCfg *to
 // VerifyChainID verify chain id
 func (b *Bridge) VerifyChainID() {
 	networkID := strings.ToLower(b.ChainConfig.NetID)
-	switch networkID {
-	case netMainnet:
-	default:
+	if _, exist := chainIDs[networkID]; !exist {
 		log.Fatalf("unsupported etc network: %v", b.ChainConfig.NetID)
 	}
 
@@ -58,25 +60,9 @@ func (b *Bridge) VerifyChainID() {
 		time.Sleep(3 * time.Second)
 	}
 
-	panicMismatchChainID := func() {
-		log.Fatalf("gateway chainID %v is not %v", chainID, b.ChainConfig.NetID)
-	}
-
-	switch networkID {
-	case netMainnet:
-		if chainID.Uint64() != 61 {
-			panicMismatchChainID()
-		}
-	case netKotti:
-		if chainID.Uint64() != 6 {
-			panicMismatchChainID()
-		}
-	case netMordor:
-		if chainID.Uint64() != 63 {
-			panicMismatchChainID()
-		}
-	default:
-		log.Fatalf("unsupported etc network %v", networkID)
+	err = eth.CheckChainID(b.ChainConfig.NetID, chainID, chainIDs)
+	if err != nil {
+		log.Fatalf("%v", err)
 	}
 
 	b.SignerChainID = chainID
@@ -85,6 +71,11 @@ func (b *Bridge) VerifyChainID() {
 	log.Info("VerifyChainID succeed", "networkID", networkID, "chainID", chainID)
 }
 
+// CheckChainAndGateway impl ConfigChecker
+func (b *Bridge) CheckChainAndGateway(chainCfg *tokens.ChainConfig, gatewayCfg *tokens.GatewayConfig, online bool) error {
+	return b.CheckChainAndGatewayWith(chainCfg, gatewayCfg, online, chainIDs, b.GetSignerChainID)
+}
+
 // GetSignerChainID override
 func (b *Bridge) GetSignerChainID() (*big.Int, error) {
 	networkID, err := b.NetworkID()
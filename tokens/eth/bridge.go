@@ -18,6 +18,13 @@ const (
 	netCustom  = "custom"
 )
 
+// chain ids of supported networks, zero means any chain id
+var chainIDs = map[string]uint64{
+	netMainnet: 1,
+	netRinkeby: 4,
+	netCustom:  0,
+}
+
 // Bridge eth bridge
 type Bridge struct {
 	*tokens.CrossChainBridgeBase
@@ -50,10 +57,7 @@ func (b *Bridge) Init() {
 // VerifyChainID verify chain id
 func (b *Bridge) VerifyChainID() {
 	networkID := strings.ToLower(b.ChainConfig.NetID)
-	switch networkID {
-	case netMainnet, netRinkeby:
-	case netCustom:
-	default:
+	if _, exist := chainIDs[networkID]; !exist {
 		log.Fatalf("unsupported ethereum network: %v", b.ChainConfig.NetID)
 	}
 
@@ -72,22 +76,9 @@ func (b *Bridge) VerifyChainID() {
 		time.Sleep(3 * time.Second)
 	}
 
-	panicMismatchChainID := func() {
-		log.Fatalf("gateway chainID %v is not %v", chainID, b.ChainConfig.NetID)
-	}
-
-	switch networkID {
-	case netMainnet:
-		if chainID.Uint64() != 1 {
-			panicMismatchChainID()
-		}
-	case netRinkeby:
-		if chainID.Uint64() != 4 {
-			panicMismatchChainID()
-		}
-	case netCustom:
-	default:
-		log.Fatalf("unsupported ethereum network %v", networkID)
+	err = CheckChainID(b.ChainConfig.NetID, chainID, chainIDs)
+	if err != nil {
+		log.Fatalf("%v", err)
 	}
 
 	b.SignerChainID = chainID
@@ -96,24 +87,83 @@ func (b *Bridge) VerifyChainID() {
 	log.Info("VerifyChainID succeed", "networkID", networkID, "chainID", chainID)
 }
 
+// CheckChainID check gateway chain id is the one of network
+func CheckChainID(netID string, chainID *big.Int, netChainIDs map[string]uint64) error {
+	wantChainID, exist := netChainIDs[strings.ToLower(netID)]
+	if !exist {
+		return fmt.Errorf("unsupported network %v", netID)
+	}
+	if wantChainID != 0 && chainID.Uint64() != wantChainID {
+		return fmt.Errorf("gateway chainID %v is not %v", chainID, netID)
+	}
+	return nil
+}
+
+// CheckChainAndGateway impl ConfigChecker
+func (b *Bridge) CheckChainAndGateway(chainCfg *tokens.ChainConfig, gatewayCfg *tokens.GatewayConfig, online bool) error {
+	return b.CheckChainAndGatewayWith(chainCfg, gatewayCfg, online, chainIDs, b.GetSignerChainID)
+}
+
+// CheckChainAndGatewayWith check chain and gateway config with chain ids of
+// supported networks, and chain id getter of gateway (for eth-like chains)
+func (b *Bridge) CheckChainAndGatewayWith(chainCfg *tokens.ChainConfig, gatewayCfg *tokens.GatewayConfig, online bool,
+	netChainIDs map[string]uint64, getChainID func() (*big.Int, error)) error {
+	b.CrossChainBridgeBase.SetChainAndGateway(chainCfg, gatewayCfg)
+	wantChainID, exist := netChainIDs[strings.ToLower(chainCfg.NetID)]
+	if !exist {
+		return fmt.Errorf("unsupported %v network %v", chainCfg.BlockChain, chainCfg.NetID)
+	}
+	// chain id is used when checking address (eg. checksum of RSK address)
+	b.SignerChainID = new(big.Int).SetUint64(wantChainID)
+	if !online {
+		return nil
+	}
+	chainID, err := getChainID()
+	if err != nil {
+		return fmt.Errorf("can not get gateway chainID: %w", err)
+	}
+	err = CheckChainID(chainCfg.NetID, chainID, netChainIDs)
+	if err != nil {
+		return err
+	}
+	b.SignerChainID = chainID
+	b.Signer = types.MakeSigner("EIP155", chainID)
+	return nil
+}
+
 // VerifyTokenConfig verify token config
-func (b *Bridge) VerifyTokenConfig(tokenCfg *tokens.TokenConfig) (err error) {
+func (b *Bridge) VerifyTokenConfig(tokenCfg *tokens.TokenConfig) error {
+	return b.verifyTokenConfig(tokenCfg, true, true)
+}
+
+// CheckTokenConfig impl ConfigChecker, contract decimals and code are checked if online
+func (b *Bridge) CheckTokenConfig(tokenCfg *tokens.TokenConfig, online bool) error {
+	return b.verifyTokenConfig(tokenCfg, online, false)
+}
+
+func (b *Bridge) verifyTokenConfig(tokenCfg *tokens.TokenConfig, online, retry bool) (err error) {
 	if !b.IsValidAddress(tokenCfg.DcrmAddress) {
 		return fmt.Errorf("invalid dcrm address: %v", tokenCfg.DcrmAddress)
 	}
-	if b.IsSrc && !b.IsValidAddress(tokenCfg.DepositAddress) {
+	if (b.IsSrc || tokenCfg.IsLockRelease) && !b.IsValidAddress(tokenCfg.DepositAddress) {
 		return fmt.Errorf("invalid deposit address: %v", tokenCfg.DepositAddress)
 	}
+	if tokenCfg.FeeTreasury != "" && !b.IsValidAddress(tokenCfg.FeeTreasury) {
+		return fmt.Errorf("invalid fee treasury: %v", tokenCfg.FeeTreasury)
+	}
 	if tokenCfg.IsDelegateContract {
+		if !online {
+			return nil
+		}
 		return b.verifyDelegateContract(tokenCfg)
 	}
 
-	err = b.verifyDecimals(tokenCfg)
+	err = b.verifyDecimals(tokenCfg, online, retry)
 	if err != nil {
 		return err
 	}
 
-	err = b.verifyContractAddress(tokenCfg)
+	err = b.verifyContractAddress(tokenCfg, online)
 	if err != nil {
 		return err
 	}
@@ -137,7 +187,7 @@ func (b *Bridge) verifyDelegateContract(tokenCfg *tokens.TokenConfig) error {
 	return nil
 }
 
-func (b *Bridge) verifyDecimals(tokenCfg *tokens.TokenConfig) error {
+func (b *Bridge) verifyDecimals(tokenCfg *tokens.TokenConfig, online, retry bool) error {
 	configedDecimals := *tokenCfg.Decimals
 	switch strings.ToUpper(tokenCfg.Symbol) {
 	case "ETH", "FSN":
@@ -147,7 +197,7 @@ func (b *Bridge) verifyDecimals(tokenCfg *tokens.TokenConfig) error {
 		log.Info(tokenCfg.Symbol+" verify decimals success", "decimals", configedDecimals)
 	}
 
-	if tokenCfg.IsErc20() {
+	if online && tokenCfg.IsErc20() {
 		for {
 			decimals, err := b.GetErc20Decimals(tokenCfg.ContractAddress)
 			if err == nil {
@@ -157,6 +207,9 @@ func (b *Bridge) verifyDecimals(tokenCfg *tokens.TokenConfig) error {
 				log.Info(tokenCfg.Symbol+" verify decimals success", "decimals", configedDecimals)
 				break
 			}
+			if !retry {
+				return fmt.Errorf("get erc20 decimals failed: %w", err)
+			}
 			log.Error("get erc20 decimals failed", "err", err)
 			time.Sleep(3 * time.Second)
 		}
@@ -164,25 +217,28 @@ func (b *Bridge) verifyDecimals(tokenCfg *tokens.TokenConfig) error {
 	return nil
 }
 
-func (b *Bridge) verifyContractAddress(tokenCfg *tokens.TokenConfig) error {
-	if tokenCfg.ContractAddress != "" {
-		if !b.IsValidAddress(tokenCfg.ContractAddress) {
-			return fmt.Errorf("invalid contract address: %v", tokenCfg.ContractAddress)
-		}
-		switch {
-		case !b.IsSrc:
-			if err := b.VerifyMbtcContractAddress(tokenCfg.ContractAddress); err != nil {
-				return fmt.Errorf("wrong contract address: %v, %v", tokenCfg.ContractAddress, err)
-			}
-		case tokenCfg.IsErc20():
-			if err := b.VerifyErc20ContractAddress(tokenCfg.ContractAddress, tokenCfg.ContractCodeHash, tokenCfg.IsProxyErc20()); err != nil {
-				return fmt.Errorf("wrong contract address: %v, %v", tokenCfg.ContractAddress, err)
-			}
-		default:
-			return fmt.Errorf("unsupported type of contract address '%v' in source chain, please assign SrcToken.ID (eg. ERC20) in config file", tokenCfg.ContractAddress)
-		}
-		log.Info("verify contract address pass", "address", tokenCfg.ContractAddress)
+func (b *Bridge) verifyContractAddress(tokenCfg *tokens.TokenConfig, online bool) (err error) {
+	if tokenCfg.ContractAddress == "" {
+		return nil
+	}
+	if !b.IsValidAddress(tokenCfg.ContractAddress) {
+		return fmt.Errorf("invalid contract address: %v", tokenCfg.ContractAddress)
+	}
+	if b.IsSrc && !tokenCfg.IsErc20() {
+		return fmt.Errorf("unsupported type of contract address '%v' in source chain, please assign SrcToken.ID (eg. ERC20) in config file", tokenCfg.ContractAddress)
+	}
+	if !online {
+		return nil
+	}
+	if b.IsSrc || tokenCfg.IsLockRelease {
+		err = b.VerifyErc20ContractAddress(tokenCfg.ContractAddress, tokenCfg.ContractCodeHash, tokenCfg.IsProxyErc20())
+	} else {
+		err = b.VerifyMbtcContractAddress(tokenCfg.ContractAddress)
+	}
+	if err != nil {
+		return fmt.Errorf("wrong contract address: %v, %v", tokenCfg.ContractAddress, err)
 	}
+	log.Info("verify contract address pass", "address", tokenCfg.ContractAddress)
 	return nil
 }
 
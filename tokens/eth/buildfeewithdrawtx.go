@@ -0,0 +1,81 @@
+package eth
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// BuildFeeWithdrawTx build tx of withdrawing collected swap fee (OriginValue) to fee treasury,
+// mint on destination chain, or transfer from dcrm address on source chain.
+// nonce is allocated in the same way as swap tx to not conflict with swaps.
+func (b *Bridge) BuildFeeWithdrawTx(args *tokens.BuildTxArgs) (rawTx interface{}, err error) {
+	tokenCfg := b.GetTokenConfig(args.PairID)
+	if tokenCfg == nil {
+		return nil, tokens.ErrUnknownPairID
+	}
+	if tokenCfg.FeeTreasury == "" {
+		return nil, tokens.ErrNoFeeTreasury
+	}
+	if (b.IsSrc && args.SwapType != tokens.SwapoutType) || (!b.IsSrc && args.SwapType != tokens.SwapinType) {
+		return nil, tokens.ErrBuildSwapTxInWrongEndpoint
+	}
+	amount := args.OriginValue
+	if amount == nil || amount.Sign() <= 0 {
+		return nil, errors.New("wrong fee withdraw amount")
+	}
+
+	treasury := common.HexToAddress(tokenCfg.FeeTreasury)
+	args.From = tokenCfg.DcrmAddress
+	args.Bind = tokenCfg.FeeTreasury
+	args.Value = nil
+
+	var input []byte
+	switch {
+	case b.IsSrc && !tokenCfg.IsErc20():
+		args.To = tokenCfg.FeeTreasury
+		args.Value = new(big.Int).Set(amount)
+	case b.IsSrc || tokenCfg.IsLockRelease:
+		input = PackDataWithFuncHash(erc20CodeParts["transfer"], treasury, amount)
+		args.To = tokenCfg.ContractAddress
+		err = b.checkVaultBalance(tokenCfg, amount)
+	default:
+		input = PackDataWithFuncHash(getSwapinFuncHash(), common.HexToHash(args.SwapID), treasury, amount)
+		args.To = tokenCfg.ContractAddress
+		if tokenCfg.IsDelegateContract {
+			err = b.checkBalance(tokenCfg.DelegateToken, tokenCfg.ContractAddress, amount)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	extra, err := b.setDefaults(args)
+	if err != nil {
+		return nil, err
+	}
+
+	// build as non swap tx, as the value is not calculated from swap value
+	buildArgs := *args
+	buildArgs.SwapType = tokens.NoSwapType
+	return b.buildTx(&buildArgs, extra, input)
+}
+
+// GetTxFee get network fee paid by tx (gas used * gas price)
+func (b *Bridge) GetTxFee(txHash string) (*big.Int, error) {
+	receipt, _, err := b.GetTransactionReceipt(txHash)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := b.GetTransactionByHash(txHash)
+	if err != nil {
+		return nil, err
+	}
+	if receipt.GasUsed == nil || tx.Price == nil {
+		return nil, errors.New("tx without gas info")
+	}
+	fee := new(big.Int).SetUint64(uint64(*receipt.GasUsed))
+	return fee.Mul(fee, tx.Price.ToInt()), nil
+}
@@ -15,6 +15,9 @@ func (b *Bridge) buildSwapinTxInput(args *tokens.BuildTxArgs) error {
 	if token == nil {
 		return tokens.ErrUnknownPairID
 	}
+	if token.IsLockRelease {
+		return b.buildErc20SwapinTxInput(args, token)
+	}
 	funcHash := getSwapinFuncHash()
 	txHash := common.HexToHash(args.SwapID)
 	address := common.HexToAddress(args.Bind)
@@ -22,7 +25,7 @@ func (b *Bridge) buildSwapinTxInput(args *tokens.BuildTxArgs) error {
 		log.Warn("swapin to wrong address", "address", args.Bind)
 		return errors.New("can not swapin to empty or invalid address")
 	}
-	amount := tokens.CalcSwappedValue(pairID, args.OriginValue, true)
+	amount := args.GetSwapValue()
 
 	input := PackDataWithFuncHash(funcHash, txHash, address, amount)
 	args.Input = &input // input
@@ -34,3 +37,22 @@ func (b *Bridge) buildSwapinTxInput(args *tokens.BuildTxArgs) error {
 	}
 	return b.checkBalance(token.DelegateToken, token.ContractAddress, amount)
 }
+
+// build input for calling erc20 `transfer(address to, uint256 value)`
+// to release locked token from the vault (lock/release mode)
+func (b *Bridge) buildErc20SwapinTxInput(args *tokens.BuildTxArgs, token *tokens.TokenConfig) error {
+	funcHash := erc20CodeParts["transfer"]
+	address := common.HexToAddress(args.Bind)
+	if address == (common.Address{}) || !common.IsHexAddress(args.Bind) {
+		log.Warn("swapin to wrong address", "address", args.Bind)
+		return errors.New("can not swapin to empty or invalid address")
+	}
+	amount := args.GetSwapValue()
+
+	input := PackDataWithFuncHash(funcHash, address, amount)
+	args.Input = &input // input
+
+	args.To = token.ContractAddress // to
+
+	return b.checkVaultBalance(token, amount)
+}
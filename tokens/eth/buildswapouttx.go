@@ -20,12 +20,12 @@ func (b *Bridge) buildErc20SwapoutTxInput(args *tokens.BuildTxArgs) (err error)
 		log.Warn("swapout to wrong address", "address", args.Bind)
 		return errors.New("can not swapout to empty or invalid address")
 	}
-	amount := tokens.CalcSwappedValue(pairID, args.OriginValue, false)
+	amount := args.GetSwapValue()
 
 	input := PackDataWithFuncHash(funcHash, address, amount)
 	args.Input = &input // input
 
 	args.To = token.ContractAddress // to
 
-	return b.checkBalance(token.ContractAddress, token.DcrmAddress, amount)
+	return b.checkVaultBalance(token, amount)
 }
@@ -99,7 +99,7 @@ func (b *Bridge) buildTx(args *tokens.BuildTxArgs, extra *tokens.EthExtraArgs, i
 			return nil, tokens.ErrUnknownPairID
 		}
 		if !tokenCfg.IsErc20() {
-			value = tokens.CalcSwappedValue(pairID, args.OriginValue, false)
+			value = args.GetSwapValue()
 		}
 	}
 
@@ -151,7 +151,10 @@ func (b *Bridge) setDefaults(args *tokens.BuildTxArgs) (extra *tokens.EthExtraAr
 	}
 	if args.Extra == nil || args.Extra.EthExtra == nil {
 		extra = &tokens.EthExtraArgs{}
-		args.Extra = &tokens.AllExtras{EthExtra: extra}
+		if args.Extra == nil {
+			args.Extra = &tokens.AllExtras{}
+		}
+		args.Extra.EthExtra = extra
 	} else {
 		extra = args.Extra.EthExtra
 	}
@@ -202,6 +205,19 @@ func (b *Bridge) getGasPrice() (price *big.Int, err error) {
 	return nil, err
 }
 
+// EstimateNetworkFee impl NetworkFeeEstimator (gas price * expected gas)
+func (b *Bridge) EstimateNetworkFee(pairID string, expectedTxCost uint64) (*big.Int, error) {
+	gasPrice, err := b.getGasPrice()
+	if err != nil {
+		return nil, err
+	}
+	if tokenCfg := b.GetTokenConfig(pairID); tokenCfg != nil && tokenCfg.PlusGasPricePercentage > 0 {
+		gasPrice.Mul(gasPrice, big.NewInt(int64(100+tokenCfg.PlusGasPricePercentage)))
+		gasPrice.Div(gasPrice, big.NewInt(100))
+	}
+	return gasPrice.Mul(gasPrice, new(big.Int).SetUint64(expectedTxCost)), nil
+}
+
 func (b *Bridge) adjustSwapGasPrice(pairID string, extra *tokens.EthExtraArgs) error {
 	tokenCfg := b.GetTokenConfig(pairID)
 	if tokenCfg == nil {
@@ -270,3 +286,25 @@ func (b *Bridge) checkBalance(token, account string, amount *big.Int) (err error
 	}
 	return err
 }
+
+// checkVaultBalance check the erc20 vault (dcrm address) has enough
+// locked token to release the swapped amount
+func (b *Bridge) checkVaultBalance(token *tokens.TokenConfig, amount *big.Int) (err error) {
+	var balance *big.Int
+	for i := 0; i < retryRPCCount; i++ {
+		balance, err = b.GetTokenBalance(ERC20TokenType, token.ContractAddress, token.DcrmAddress)
+		if err == nil {
+			break
+		}
+		time.Sleep(retryRPCInterval)
+	}
+	if err != nil {
+		log.Warn("get vault balance error", "token", token.ContractAddress, "vault", token.DcrmAddress, "err", err)
+		return err
+	}
+	if balance.Cmp(amount) < 0 {
+		log.Warn("vault balance is not enough", "token", token.ContractAddress, "vault", token.DcrmAddress, "balance", balance, "amount", amount)
+		return tokens.ErrInsufficientVaultBalance
+	}
+	return nil
+}
@@ -101,6 +101,24 @@ func (b *Bridge) GetBlockByNumber(number *big.Int) (*types.RPCBlock, error) {
 	return nil, err
 }
 
+// GetBlockByNumberWithTxs call eth_getBlockByNumber with full transactions
+func (b *Bridge) GetBlockByNumberWithTxs(number *big.Int) (*types.RPCBlockWithTxs, error) {
+	gateway := b.GatewayConfig
+	var result *types.RPCBlockWithTxs
+	var err error
+	for _, apiAddress := range gateway.APIAddress {
+		url := apiAddress
+		err = client.RPCPost(&result, url, "eth_getBlockByNumber", types.ToBlockNumArg(number), true)
+		if err == nil && result != nil {
+			return result, nil
+		}
+	}
+	if result == nil {
+		return nil, errors.New("block not found")
+	}
+	return nil, err
+}
+
 // GetTransactionByHash call eth_getTransactionByHash
 func (b *Bridge) GetTransactionByHash(txHash string) (*types.RPCTransaction, error) {
 	gateway := b.GatewayConfig
@@ -214,6 +232,63 @@ func (b *Bridge) GetLogs(filterQuery *types.FilterQuery) (result []*types.RPCLog
 	return nil, err
 }
 
+var callTracerConfig = map[string]string{"tracer": "callTracer"}
+
+// DebugTraceTransaction call debug_traceTransaction with callTracer
+func (b *Bridge) DebugTraceTransaction(txHash string) (result *types.RPCCallFrame, err error) {
+	gateway := b.GatewayConfig
+	for _, apiAddress := range gateway.APIAddress {
+		url := apiAddress
+		err = client.RPCPost(&result, url, "debug_traceTransaction", txHash, callTracerConfig)
+		if err == nil && result != nil {
+			return result, nil
+		}
+	}
+	if result == nil && err == nil {
+		err = errors.New("trace not found")
+	}
+	return nil, err
+}
+
+// DebugTraceBlockByNumber call debug_traceBlockByNumber with callTracer
+func (b *Bridge) DebugTraceBlockByNumber(number *big.Int) (result []*types.RPCTxTraceResult, err error) {
+	gateway := b.GatewayConfig
+	for _, apiAddress := range gateway.APIAddress {
+		url := apiAddress
+		err = client.RPCPost(&result, url, "debug_traceBlockByNumber", types.ToBlockNumArg(number), callTracerConfig)
+		if err == nil {
+			return result, nil
+		}
+	}
+	return nil, err
+}
+
+// TraceTransaction call trace_transaction
+func (b *Bridge) TraceTransaction(txHash string) (result []*types.RPCParityTrace, err error) {
+	gateway := b.GatewayConfig
+	for _, apiAddress := range gateway.APIAddress {
+		url := apiAddress
+		err = client.RPCPost(&result, url, "trace_transaction", txHash)
+		if err == nil {
+			return result, nil
+		}
+	}
+	return nil, err
+}
+
+// TraceBlock call trace_block
+func (b *Bridge) TraceBlock(number *big.Int) (result []*types.RPCParityTrace, err error) {
+	gateway := b.GatewayConfig
+	for _, apiAddress := range gateway.APIAddress {
+		url := apiAddress
+		err = client.RPCPost(&result, url, "trace_block", types.ToBlockNumArg(number))
+		if err == nil {
+			return result, nil
+		}
+	}
+	return nil, err
+}
+
 // GetPoolNonce call eth_getTransactionCount
 func (b *Bridge) GetPoolNonce(address, height string) (uint64, error) {
 	account := common.HexToAddress(address)
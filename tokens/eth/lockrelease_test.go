@@ -0,0 +1,207 @@
+package eth
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/common/hexutil"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+const (
+	testToken = "0x5555555555555555555555555555555555555555"
+	testVault = "0x6666666666666666666666666666666666666666"
+)
+
+// testGateway serves json rpc requests with raw json results by method
+type testGateway struct {
+	*httptest.Server
+	lock     sync.Mutex
+	requests []*testRequest
+}
+
+type testRequest struct {
+	ID     int               `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func newTestGateway(t *testing.T, results map[string]string) *testGateway {
+	gateway := &testGateway{}
+	gateway.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req testRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode gateway request failed, %v", err)
+		}
+		gateway.lock.Lock()
+		gateway.requests = append(gateway.requests, &req)
+		gateway.lock.Unlock()
+		result, exist := results[req.Method]
+		if !exist {
+			t.Errorf("unexpected gateway request %v", req.Method)
+			result = "null"
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":%v}`, req.ID, result)
+	}))
+	t.Cleanup(gateway.Close)
+	return gateway
+}
+
+// getRequests get requests of method
+func (g *testGateway) getRequests(method string) (requests []*testRequest) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	for _, req := range g.requests {
+		if req.Method == method {
+			requests = append(requests, req)
+		}
+	}
+	return requests
+}
+
+// newTestBalanceGateway serves `eth_call` of erc20 `balanceOf` with the given balance
+func newTestBalanceGateway(t *testing.T, balance int64) *testGateway {
+	return newTestGateway(t, map[string]string{
+		"eth_call": `"` + common.BigToHash(big.NewInt(balance)).Hex() + `"`,
+	})
+}
+
+func newTestLockReleaseToken(isLockRelease bool) *tokens.TokenConfig {
+	decimals := uint8(0)
+	maxSwap, minSwap, bigValue := 1e6, 1.0, 1e6
+	feeRate, minFee, maxFee := 0.0, 0.0, 0.0
+	token := &tokens.TokenConfig{
+		ID:                "ERC20",
+		ContractAddress:   testToken,
+		DcrmAddress:       testVault,
+		DepositAddress:    testDeposit,
+		IsLockRelease:     isLockRelease,
+		Decimals:          &decimals,
+		MaximumSwap:       &maxSwap,
+		MinimumSwap:       &minSwap,
+		BigValueThreshold: &bigValue,
+		SwapFeeRate:       &feeRate,
+		MinimumSwapFee:    &minFee,
+		MaximumSwapFee:    &maxFee,
+	}
+	token.CalcAndStoreValue()
+	return token
+}
+
+// newTestLockReleaseBridge returns destination bridge of lock/release pair 'lock'
+func newTestLockReleaseBridge(t *testing.T, gateway string) *Bridge {
+	oldPairsConfig := tokens.GetTokenPairsConfig()
+	oldSrcBridge := tokens.SrcBridge
+	t.Cleanup(func() {
+		tokens.SetTokenPairsConfig(oldPairsConfig, false)
+		tokens.SrcBridge = oldSrcBridge
+	})
+	tokens.SetTokenPairsConfig(map[string]*tokens.TokenPairConfig{
+		"lock": {PairID: "lock", SrcToken: newTestLockReleaseToken(false), DestToken: newTestLockReleaseToken(true)},
+	}, false)
+	srcBridge := NewCrossChainBridge(true)
+	srcBridge.SignerChainID = big.NewInt(1)
+	tokens.SrcBridge = srcBridge
+
+	b := NewCrossChainBridge(false)
+	b.CrossChainBridgeBase.SetChainAndGateway(
+		&tokens.ChainConfig{BlockChain: "ETH"},
+		&tokens.GatewayConfig{APIAddress: []string{gateway}},
+	)
+	return b
+}
+
+func newTestTransferReceipt(from, to string, value int64) *types.RPCTxReceipt {
+	fromAddr := common.HexToAddress(from)
+	contract := common.HexToAddress(testToken)
+	data := hexutil.Bytes(common.BigToHash(big.NewInt(value)).Bytes())
+	return &types.RPCTxReceipt{
+		From:      &fromAddr,
+		Recipient: &contract,
+		Logs: []*types.RPCLog{{
+			Address: &contract,
+			Topics: []common.Hash{
+				common.BytesToHash(erc20CodeParts["LogTransfer"]),
+				common.HexToAddress(from).Hash(),
+				common.HexToAddress(to).Hash(),
+			},
+			Data: &data,
+		}},
+	}
+}
+
+func TestBuildLockReleaseSwapinTxInput(t *testing.T) {
+	srv := newTestBalanceGateway(t, 1000)
+	b := newTestLockReleaseBridge(t, srv.URL)
+
+	args := &tokens.BuildTxArgs{
+		SwapInfo:    tokens.SwapInfo{PairID: "lock", SwapType: tokens.SwapinType, Bind: testAddr1},
+		OriginValue: big.NewInt(600),
+	}
+	if err := b.buildSwapinTxInput(args); err != nil {
+		t.Fatalf("build lock/release swapin failed, %v", err)
+	}
+	if args.To != testToken {
+		t.Errorf("release tx should call token contract, have %v", args.To)
+	}
+	wantInput := PackDataWithFuncHash(erc20CodeParts["transfer"], common.HexToAddress(testAddr1), big.NewInt(600))
+	if args.Input == nil || common.ToHex(*args.Input) != common.ToHex(wantInput) {
+		t.Errorf("release tx input mismatch, have %x want %x", args.Input, wantInput)
+	}
+	_, to, value, err := ParseErc20SwapinTxInput(args.Input, testAddr1)
+	if err != nil || !common.IsEqualIgnoreCase(to, testAddr1) || value.Int64() != 600 {
+		t.Errorf("parse release tx input mismatch, to %v value %v err %v", to, value, err)
+	}
+
+	args.OriginValue = big.NewInt(1001)
+	if err := b.buildSwapinTxInput(args); err != tokens.ErrInsufficientVaultBalance {
+		t.Errorf("build release tx error mismatch, have %v want %v", err, tokens.ErrInsufficientVaultBalance)
+	}
+}
+
+func TestVerifyLockReleaseSwapoutTx(t *testing.T) {
+	b := newTestLockReleaseBridge(t, "")
+
+	tests := []struct {
+		name    string
+		receipt *types.RPCTxReceipt
+		err     error
+	}{
+		{"transfer to deposit address", newTestTransferReceipt(testAddr1, testDeposit, 100), nil},
+		{"transfer to other address", newTestTransferReceipt(testAddr1, testAddr2, 100), tokens.ErrTxWithWrongReceiver},
+		{"deposit address to itself", newTestTransferReceipt(testDeposit, testDeposit, 100), tokens.ErrTxWithWrongSender},
+		{"too small value", newTestTransferReceipt(testAddr1, testDeposit, 0), tokens.ErrTxWithWrongValue},
+	}
+	for _, test := range tests {
+		swapInfos, errs := b.verifySwapoutTxWithReceipt(&tokens.TxSwapInfo{Hash: testTxHash1}, test.receipt)
+		if !tokens.ShouldRegisterSwapForError(test.err) {
+			if len(swapInfos) != 0 {
+				t.Errorf("%v: swap should not be registered, have %+v", test.name, swapInfos[0])
+			}
+			continue
+		}
+		if len(swapInfos) != 1 || len(errs) != 1 {
+			t.Errorf("%v: verify result count mismatch, have %v", test.name, len(swapInfos))
+			continue
+		}
+		if errs[0] != test.err {
+			t.Errorf("%v: verify error mismatch, have %v want %v", test.name, errs[0], test.err)
+			continue
+		}
+		if test.err != nil {
+			continue
+		}
+		swapInfo := swapInfos[0]
+		if swapInfo.PairID != "lock" || swapInfo.Bind != testAddr1 || swapInfo.To != testDeposit ||
+			swapInfo.TxTo != testToken || swapInfo.Value.Int64() != 100 {
+			t.Errorf("%v: swap info mismatch, have %+v", test.name, swapInfo)
+		}
+	}
+}
@@ -1,7 +1,6 @@
 package eth
 
 import (
-	"context"
 	"fmt"
 	"math/big"
 	"sync"
@@ -9,13 +8,17 @@ import (
 
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+	"github.com/anyswap/CrossChain-Bridge/types"
 )
 
 var (
-	quickSyncFinish  bool
 	quickSyncWorkers = uint64(4)
 
+	// scan head blocks one by one if behind no more than this value,
+	// otherwise add quick sync job to catch up the behind blocks
 	maxScanHeight          = uint64(100)
+	quickSyncSaveInterval  = uint64(10)
+	scanLogsBatch          = uint64(100)
 	retryIntervalInScanJob = 3 * time.Second
 	restIntervalInScanJob  = 3 * time.Second
 )
@@ -42,9 +45,6 @@ func (b *Bridge) getStartAndLatestHeight() (start, latest uint64) {
 	if start < initialHeight {
 		start = initialHeight
 	}
-	if start+maxScanHeight < latest {
-		start = latest - maxScanHeight
-	}
 	return start, latest
 }
 
@@ -54,115 +54,175 @@ func (b *Bridge) StartChainTransactionScanJob() {
 	log.Infof("[scanchain] start %v scan chain job", chainName)
 
 	start, latest := b.getStartAndLatestHeight()
-	_ = tools.UpdateLatestScanInfo(b.IsSrc, start)
 	log.Infof("[scanchain] start %v scan chain loop from %v latest=%v", chainName, start, latest)
 
+	// resume unfinished quick sync ranges, then catch up from latest scan height
+	for i, r := range tools.GetPendingScanRanges(b.IsSrc) {
+		go b.quickSyncRange(uint64(i+1), r, nil)
+	}
 	if latest > start {
-		go b.quickSync(context.Background(), nil, start, latest+1)
-	} else {
-		quickSyncFinish = true
+		b.quickSync(start, latest+1)
 	}
+	_ = tools.UpdateLatestScanInfo(b.IsSrc, latest)
 
 	stable := latest
 	errorSubject := fmt.Sprintf("[scanchain] get %v block failed", chainName)
 	scanSubject := fmt.Sprintf("[scanchain] scanned %v block", chainName)
 
 	scannedBlocks := tools.NewCachedScannedBlocks(67)
-	var quickSyncCtx context.Context
-	var quickSyncCancel context.CancelFunc
 	for {
 		latest = tools.LoopGetLatestBlockNumber(b)
 		if stable+maxScanHeight < latest {
-			if quickSyncCancel != nil {
-				select {
-				case <-quickSyncCtx.Done():
-				default:
-					log.Warn("cancel quick sync range", "stable", stable, "latest", latest)
-					quickSyncCancel()
-				}
-			}
-			quickSyncCtx, quickSyncCancel = context.WithCancel(context.Background())
-			go b.quickSync(quickSyncCtx, quickSyncCancel, stable+1, latest)
+			// catch up behind blocks concurrently, keep scanning the head
+			b.quickSync(stable+1, latest)
 			stable = latest
 		}
+		var filter *swapLogsFilter
+		if b.ChainConfig.ScanLogs {
+			// build once every round, as token pairs config may be reloaded
+			filter = b.getSwapLogsFilter()
+		}
 		for h := stable; h <= latest; {
-			block, err := b.GetBlockByNumber(new(big.Int).SetUint64(h))
+			blockHash, txs, err := b.scanHeadBlock(h, filter, scannedBlocks)
 			if err != nil {
 				log.Error(errorSubject, "height", h, "err", err)
 				time.Sleep(retryIntervalInScanJob)
 				continue
 			}
-			blockHash := block.Hash.String()
-			if scannedBlocks.IsBlockScanned(blockHash) {
-				h++
-				continue
-			}
-			for _, tx := range block.Transactions {
-				b.processTransaction(tx.String())
+			if blockHash != "" {
+				log.Info(scanSubject, "blockHash", blockHash, "height", h, "txs", txs)
 			}
-			scannedBlocks.CacheScannedBlock(blockHash, h)
-			log.Info(scanSubject, "blockHash", blockHash, "height", h, "txs", len(block.Transactions))
 			h++
 		}
 		stable = latest
-		if quickSyncFinish {
-			_ = tools.UpdateLatestScanInfo(b.IsSrc, stable)
-		}
+		_ = tools.UpdateLatestScanInfo(b.IsSrc, stable)
 		time.Sleep(restIntervalInScanJob)
 	}
 }
 
-func (b *Bridge) quickSync(ctx context.Context, cancel context.CancelFunc, start, end uint64) {
-	chainName := b.ChainConfig.BlockChain
-	log.Printf("[scanchain] begin %v syncRange job. start=%v end=%v", chainName, start, end)
-	count := end - start
-	workers := quickSyncWorkers
-	if count < 10 {
-		workers = 1
-	}
-	step := count / workers
-	wg := new(sync.WaitGroup)
-	wg.Add(int(workers))
-	for i := uint64(0); i < workers; i++ {
-		wstt := start + i*step
-		wend := start + (i+1)*step
-		if i+1 == workers {
-			wend = end
+// scanHeadBlock scan head block, return empty block hash if scanned before.
+// in logs scanning mode, the block is fetched with txs only if native deposits
+// are filtered, and the fetched block is reused to find native deposits.
+func (b *Bridge) scanHeadBlock(height uint64, filter *swapLogsFilter, scannedBlocks *tools.CachedScannedBlocks) (blockHash string, txs int, err error) {
+	number := new(big.Int).SetUint64(height)
+	var block *types.RPCBlock
+	var blockWithTxs *types.RPCBlockWithTxs
+	if filter != nil && len(filter.nativeDeposits) > 0 {
+		blockWithTxs, err = b.GetBlockByNumberWithTxs(number)
+		if err == nil {
+			blockHash, txs = blockWithTxs.Hash.String(), len(blockWithTxs.Transactions)
 		}
-		go b.quickSyncRange(ctx, i+1, wstt, wend, wg)
+	} else {
+		block, err = b.GetBlockByNumber(number)
+		if err == nil {
+			blockHash, txs = block.Hash.String(), len(block.Transactions)
+		}
+	}
+	if err != nil {
+		return "", 0, err
 	}
-	wg.Wait()
-	if cancel != nil {
-		cancel()
+	if scannedBlocks.IsBlockScanned(blockHash) {
+		return "", 0, nil
+	}
+	if filter != nil {
+		err = b.scanBlockByLogs(filter, height, blockWithTxs)
 	} else {
-		quickSyncFinish = true
+		err = b.scanBlockTransactions(height, block)
+	}
+	if err != nil {
+		return "", 0, err
+	}
+	scannedBlocks.CacheScannedBlock(blockHash, height)
+	return blockHash, txs, nil
+}
+
+// scanBlockTransactions scan every transaction in block,
+// and internal transfers to deposit addresses if trace is enabled
+func (b *Bridge) scanBlockTransactions(height uint64, block *types.RPCBlock) error {
+	err := b.processInternalDeposits(height)
+	if err != nil {
+		return err
+	}
+	for _, tx := range block.Transactions {
+		b.processTransaction(tx.String())
 	}
-	log.Printf("[scanchain] finish %v syncRange job. start=%v end=%v", chainName, start, end)
+	return nil
 }
 
-func (b *Bridge) quickSyncRange(ctx context.Context, idx, start, end uint64, wg *sync.WaitGroup) {
-	defer wg.Done()
+// quickSync scan blocks [start, end) with workers in background,
+// the ranges of workers are checkpointed before return
+func (b *Bridge) quickSync(start, end uint64) {
 	chainName := b.ChainConfig.BlockChain
+	log.Printf("[scanchain] begin %v syncRange job. start=%v end=%v", chainName, start, end)
+	ranges := tools.NewScanRanges(b.IsSrc, start, end, quickSyncWorkers)
+	wg := new(sync.WaitGroup)
+	wg.Add(len(ranges))
+	for i, r := range ranges {
+		go b.quickSyncRange(uint64(i+1), r, wg)
+	}
+	go func() {
+		wg.Wait()
+		log.Printf("[scanchain] finish %v syncRange job. start=%v end=%v", chainName, start, end)
+	}()
+}
+
+func (b *Bridge) quickSyncRange(idx uint64, r *tools.ScanRange, wg *sync.WaitGroup) {
+	if wg != nil {
+		defer wg.Done()
+	}
+	chainName := b.ChainConfig.BlockChain
+	start, end := r.Next, r.End
 	log.Printf("[scanchain] id=%v begin %v syncRange start=%v end=%v", idx, chainName, start, end)
 
+	if b.ChainConfig.ScanLogs {
+		b.quickSyncRangeByLogs(idx, r)
+		return
+	}
+
 	for h := start; h < end; {
-		select {
-		case <-ctx.Done():
-			break
-		default:
-		}
 		block, err := b.GetBlockByNumber(new(big.Int).SetUint64(h))
 		if err != nil {
 			log.Errorf("[scanchain] id=%v get %v block failed at height %v. err=%v", idx, chainName, h, err)
 			time.Sleep(retryIntervalInScanJob)
 			continue
 		}
-		for _, tx := range block.Transactions {
-			b.processTransaction(tx.String())
+		err = b.scanBlockTransactions(h, block)
+		if err != nil {
+			log.Errorf("[scanchain] id=%v scan %v block failed at height %v. err=%v", idx, chainName, h, err)
+			time.Sleep(retryIntervalInScanJob)
+			continue
 		}
 		log.Tracef("[scanchain] id=%v scanned %v block, height=%v hash=%v txs=%v", idx, chainName, h, block.Hash.String(), len(block.Transactions))
 		h++
+		if (h-start)%quickSyncSaveInterval == 0 {
+			r.UpdateProgress(h)
+		}
+	}
+	r.Finish()
+
+	log.Printf("[scanchain] id=%v finish %v syncRange start=%v end=%v", idx, chainName, start, end)
+}
+
+func (b *Bridge) quickSyncRangeByLogs(idx uint64, r *tools.ScanRange) {
+	chainName := b.ChainConfig.BlockChain
+	start, end := r.Next, r.End
+	filter := b.getSwapLogsFilter()
+	for h := start; h < end; {
+		h2 := h + scanLogsBatch
+		if h2 > end {
+			h2 = end
+		}
+		err := b.scanBlocksByLogs(filter, h, h2-1)
+		if err != nil {
+			log.Errorf("[scanchain] id=%v scan %v logs failed in range [%v, %v). err=%v", idx, chainName, h, h2, err)
+			time.Sleep(retryIntervalInScanJob)
+			continue
+		}
+		log.Tracef("[scanchain] id=%v scanned %v logs in range [%v, %v)", idx, chainName, h, h2)
+		h = h2
+		r.UpdateProgress(h)
 	}
+	r.Finish()
 
 	log.Printf("[scanchain] id=%v finish %v syncRange start=%v end=%v", idx, chainName, start, end)
 }
@@ -0,0 +1,159 @@
+package eth
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+// swapLogsFilter filter of swap logs and native deposits
+type swapLogsFilter struct {
+	queries        []*types.FilterQuery
+	nativeDeposits map[string]struct{}
+}
+
+type addressSet map[common.Address]struct{}
+
+func (s addressSet) add(address string) {
+	s[common.HexToAddress(address)] = struct{}{}
+}
+
+func (s addressSet) addresses() []common.Address {
+	result := make([]common.Address, 0, len(s))
+	for address := range s {
+		result = append(result, address)
+	}
+	return result
+}
+
+func (s addressSet) hashes() []common.Hash {
+	result := make([]common.Hash, 0, len(s))
+	for address := range s {
+		result = append(result, address.Hash())
+	}
+	return result
+}
+
+// getSwapLogsFilter get filter from token pairs config.
+// deposits of erc20 are filtered by 'Transfer' logs to deposit addresses,
+// deposits of native coin are filtered by txs to deposit addresses
+// (and internal transfers to deposit addresses if trace is enabled),
+// and swapouts of mintable tokens are filtered by 'LogSwapout' logs.
+func (b *Bridge) getSwapLogsFilter() *swapLogsFilter {
+	filter := &swapLogsFilter{
+		nativeDeposits: make(map[string]struct{}),
+	}
+	erc20Contracts := make(addressSet)
+	depositAddrs := make(addressSet)
+	swapoutContracts := make(addressSet)
+	for _, pairCfg := range tokens.GetTokenPairsConfig() {
+		token := pairCfg.SrcToken
+		if !b.IsSrc {
+			token = pairCfg.DestToken
+		}
+		switch {
+		case token.IsReleaseFromVault(b.IsSrc):
+			erc20Contracts.add(token.ContractAddress)
+			depositAddrs.add(token.DepositAddress)
+		case b.IsSrc:
+			filter.nativeDeposits[strings.ToLower(token.DepositAddress)] = struct{}{}
+		default:
+			swapoutContracts.add(token.ContractAddress)
+		}
+	}
+	if len(erc20Contracts) > 0 {
+		transferTopic := common.BytesToHash(erc20CodeParts["LogTransfer"])
+		filter.queries = append(filter.queries, &types.FilterQuery{
+			Addresses: erc20Contracts.addresses(),
+			Topics:    [][]common.Hash{{transferTopic}, nil, depositAddrs.hashes()},
+		})
+	}
+	if len(swapoutContracts) > 0 {
+		swapoutTopic := common.BytesToHash(getLogSwapoutTopic())
+		filter.queries = append(filter.queries, &types.FilterQuery{
+			Addresses: swapoutContracts.addresses(),
+			Topics:    [][]common.Hash{{swapoutTopic}},
+		})
+	}
+	return filter
+}
+
+// newTxProcessor process every tx once
+func (b *Bridge) newTxProcessor() func(txid string) {
+	processed := make(map[string]struct{})
+	return func(txid string) {
+		if _, exist := processed[txid]; exist {
+			return
+		}
+		processed[txid] = struct{}{}
+		b.processTransaction(txid)
+	}
+}
+
+// scanBlocksByLogs scan swaps in blocks [from, to] by logs
+func (b *Bridge) scanBlocksByLogs(filter *swapLogsFilter, from, to uint64) error {
+	process := b.newTxProcessor()
+	err := b.scanSwapLogs(filter, from, to, process)
+	if err != nil || len(filter.nativeDeposits) == 0 {
+		return err
+	}
+	for h := from; h <= to; h++ {
+		var block *types.RPCBlockWithTxs
+		block, err = b.GetBlockByNumberWithTxs(new(big.Int).SetUint64(h))
+		if err != nil {
+			return err
+		}
+		err = b.scanNativeDeposits(filter, h, block, process)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scanBlockByLogs scan swaps in the fetched block by logs,
+// block with txs is required only if native deposits are filtered
+func (b *Bridge) scanBlockByLogs(filter *swapLogsFilter, height uint64, block *types.RPCBlockWithTxs) error {
+	process := b.newTxProcessor()
+	err := b.scanSwapLogs(filter, height, height, process)
+	if err != nil || len(filter.nativeDeposits) == 0 {
+		return err
+	}
+	return b.scanNativeDeposits(filter, height, block, process)
+}
+
+func (b *Bridge) scanSwapLogs(filter *swapLogsFilter, from, to uint64, process func(string)) error {
+	for _, query := range filter.queries {
+		rangeQuery := *query
+		rangeQuery.FromBlock = new(big.Int).SetUint64(from)
+		rangeQuery.ToBlock = new(big.Int).SetUint64(to)
+		logs, err := b.GetLogs(&rangeQuery)
+		if err != nil {
+			return err
+		}
+		for _, rlog := range logs {
+			if rlog.TxHash == nil || (rlog.Removed != nil && *rlog.Removed) {
+				continue
+			}
+			process(rlog.TxHash.String())
+		}
+		log.Trace("[scanchain] scan swap logs", "from", from, "to", to, "logs", len(logs))
+	}
+	return nil
+}
+
+func (b *Bridge) scanNativeDeposits(filter *swapLogsFilter, height uint64, block *types.RPCBlockWithTxs, process func(string)) error {
+	for _, tx := range block.Transactions {
+		if tx.Recipient == nil || tx.Hash == nil {
+			continue
+		}
+		if _, exist := filter.nativeDeposits[strings.ToLower(tx.Recipient.String())]; exist {
+			process(tx.Hash.String())
+		}
+	}
+	return b.processInternalDeposits(height)
+}
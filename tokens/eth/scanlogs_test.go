@@ -0,0 +1,195 @@
+package eth
+
+import (
+	"encoding/json"
+	"reflect"
+	"sort"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+const (
+	testErc20    = "0xa000000000000000000000000000000000000001"
+	testMintable = "0xa000000000000000000000000000000000000002"
+	testDeposit2 = "0xd000000000000000000000000000000000000002"
+)
+
+func sortedAddresses(addresses ...string) []common.Address {
+	result := make([]common.Address, 0, len(addresses))
+	for _, address := range addresses {
+		result = append(result, common.HexToAddress(address))
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Hex() < result[j].Hex() })
+	return result
+}
+
+func sortedHashes(addresses ...string) []common.Hash {
+	result := make([]common.Hash, 0, len(addresses))
+	for _, address := range sortedAddresses(addresses...) {
+		result = append(result, address.Hash())
+	}
+	return result
+}
+
+// normalizeFilterQuery sort addresses and topics of query built from map
+func normalizeFilterQuery(query *types.FilterQuery) {
+	sort.Slice(query.Addresses, func(i, j int) bool { return query.Addresses[i].Hex() < query.Addresses[j].Hex() })
+	for _, topics := range query.Topics {
+		sort.Slice(topics, func(i, j int) bool { return topics[i].Hex() < topics[j].Hex() })
+	}
+}
+
+func TestGetSwapLogsFilter(t *testing.T) {
+	oldPairsConfig := tokens.GetTokenPairsConfig()
+	defer tokens.SetTokenPairsConfig(oldPairsConfig, false)
+
+	transferTopic := common.BytesToHash(erc20CodeParts["LogTransfer"])
+	swapoutTopic := common.BytesToHash(getLogSwapoutTopic())
+	erc20Token := &tokens.TokenConfig{ID: "ERC20", ContractAddress: testErc20, DepositAddress: testDeposit}
+	nativeToken := &tokens.TokenConfig{DepositAddress: testDeposit2}
+	mintableToken := &tokens.TokenConfig{ContractAddress: testMintable}
+	lockReleaseToken := &tokens.TokenConfig{ID: "ERC20", ContractAddress: testErc20, DepositAddress: testDeposit2, IsLockRelease: true}
+
+	tests := []struct {
+		name    string
+		isSrc   bool
+		pairs   map[string]*tokens.TokenPairConfig
+		queries []*types.FilterQuery
+		natives map[string]struct{}
+	}{
+		{
+			name:  "erc20 deposit",
+			isSrc: true,
+			pairs: map[string]*tokens.TokenPairConfig{
+				"erc20": {PairID: "erc20", SrcToken: erc20Token, DestToken: mintableToken},
+			},
+			queries: []*types.FilterQuery{{
+				Addresses: sortedAddresses(testErc20),
+				Topics:    [][]common.Hash{{transferTopic}, nil, sortedHashes(testDeposit)},
+			}},
+		},
+		{
+			name:  "native deposit",
+			isSrc: true,
+			pairs: map[string]*tokens.TokenPairConfig{
+				"eth": {PairID: "eth", SrcToken: nativeToken, DestToken: mintableToken},
+			},
+			natives: map[string]struct{}{testDeposit2: {}},
+		},
+		{
+			name:  "mintable swapout",
+			isSrc: false,
+			pairs: map[string]*tokens.TokenPairConfig{
+				"eth": {PairID: "eth", SrcToken: nativeToken, DestToken: mintableToken},
+			},
+			queries: []*types.FilterQuery{{
+				Addresses: sortedAddresses(testMintable),
+				Topics:    [][]common.Hash{{swapoutTopic}},
+			}},
+		},
+		{
+			name:  "lock/release swapout",
+			isSrc: false,
+			pairs: map[string]*tokens.TokenPairConfig{
+				"lock": {PairID: "lock", SrcToken: nativeToken, DestToken: lockReleaseToken},
+			},
+			queries: []*types.FilterQuery{{
+				Addresses: sortedAddresses(testErc20),
+				Topics:    [][]common.Hash{{transferTopic}, nil, sortedHashes(testDeposit2)},
+			}},
+		},
+		{
+			name:  "mixed swapouts",
+			isSrc: false,
+			pairs: map[string]*tokens.TokenPairConfig{
+				"eth":  {PairID: "eth", SrcToken: nativeToken, DestToken: mintableToken},
+				"lock": {PairID: "lock", SrcToken: nativeToken, DestToken: lockReleaseToken},
+			},
+			queries: []*types.FilterQuery{
+				{
+					Addresses: sortedAddresses(testErc20),
+					Topics:    [][]common.Hash{{transferTopic}, nil, sortedHashes(testDeposit2)},
+				},
+				{
+					Addresses: sortedAddresses(testMintable),
+					Topics:    [][]common.Hash{{swapoutTopic}},
+				},
+			},
+		},
+		{
+			name:  "mixed deposits",
+			isSrc: true,
+			pairs: map[string]*tokens.TokenPairConfig{
+				"erc20": {PairID: "erc20", SrcToken: erc20Token, DestToken: mintableToken},
+				"eth":   {PairID: "eth", SrcToken: nativeToken, DestToken: mintableToken},
+			},
+			queries: []*types.FilterQuery{{
+				Addresses: sortedAddresses(testErc20),
+				Topics:    [][]common.Hash{{transferTopic}, nil, sortedHashes(testDeposit)},
+			}},
+			natives: map[string]struct{}{testDeposit2: {}},
+		},
+	}
+	for _, test := range tests {
+		tokens.SetTokenPairsConfig(test.pairs, false)
+		b := NewCrossChainBridge(test.isSrc)
+		filter := b.getSwapLogsFilter()
+		for _, query := range filter.queries {
+			normalizeFilterQuery(query)
+		}
+		if !reflect.DeepEqual(filter.queries, test.queries) {
+			t.Errorf("%v: filter queries mismatch, have %v want %v", test.name, filter.queries, test.queries)
+		}
+		if test.natives == nil {
+			test.natives = make(map[string]struct{})
+		}
+		if !reflect.DeepEqual(filter.nativeDeposits, test.natives) {
+			t.Errorf("%v: native deposits mismatch, have %v want %v", test.name, filter.nativeDeposits, test.natives)
+		}
+	}
+}
+
+func TestScanBlocksByLogsSkipRemoved(t *testing.T) {
+	logs := `[
+{"address":"` + testErc20 + `","topics":[],"transactionHash":"` + testTxHash1 + `","removed":false},
+{"address":"` + testErc20 + `","topics":[],"transactionHash":"` + testTxHash2 + `","removed":true},
+{"address":"` + testErc20 + `","topics":[]},
+{"address":"` + testErc20 + `","topics":[],"transactionHash":"` + testTxHash1 + `"}
+]`
+	// processed txs are not found, so no swap is registered
+	gateway := newTestGateway(t, map[string]string{
+		"eth_getLogs":              logs,
+		"eth_getTransactionByHash": "null",
+	})
+	b := NewCrossChainBridge(true)
+	b.CrossChainBridgeBase.SetChainAndGateway(
+		&tokens.ChainConfig{BlockChain: "ETH"},
+		&tokens.GatewayConfig{APIAddress: []string{gateway.URL}},
+	)
+	filter := &swapLogsFilter{
+		queries:        []*types.FilterQuery{{Addresses: sortedAddresses(testErc20)}},
+		nativeDeposits: make(map[string]struct{}),
+	}
+	if err := b.scanBlocksByLogs(filter, 100, 110); err != nil {
+		t.Fatal(err)
+	}
+
+	// removed logs and logs without tx hash are skipped, and tx is processed once
+	var processed []string
+	for _, req := range gateway.getRequests("eth_getTransactionByHash") {
+		var txHash string
+		if len(req.Params) != 1 || json.Unmarshal(req.Params[0], &txHash) != nil {
+			t.Fatalf("wrong get transaction params %s", req.Params)
+		}
+		processed = append(processed, txHash)
+	}
+	if want := []string{testTxHash1}; !reflect.DeepEqual(processed, want) {
+		t.Errorf("processed txs mismatch, have %v want %v", processed, want)
+	}
+	if reqs := gateway.getRequests("eth_getLogs"); len(reqs) != 1 {
+		t.Errorf("get logs requests count mismatch, have %v want %v", len(reqs), 1)
+	}
+}
@@ -4,7 +4,9 @@ import (
 	"errors"
 	"fmt"
 
+	"github.com/anyswap/CrossChain-Bridge/common/hexutil"
 	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tools/rlp"
 	"github.com/anyswap/CrossChain-Bridge/types"
 )
 
@@ -25,3 +27,30 @@ func (b *Bridge) SendTransaction(signedTx interface{}) (txHash string, err error
 	//#log.Trace("SendTransaction success", "raw", tx.RawStr())
 	return txHash, nil
 }
+
+// SerializeSignedTx serialize signed tx to rlp encoded hex string
+func (b *Bridge) SerializeSignedTx(signedTx interface{}) (string, error) {
+	tx, ok := signedTx.(*types.Transaction)
+	if !ok {
+		return "", errors.New("wrong signed transaction type")
+	}
+	data, err := rlp.EncodeToBytes(tx)
+	if err != nil {
+		return "", err
+	}
+	return hexutil.Encode(data), nil
+}
+
+// DeserializeSignedTx deserialize signed tx from rlp encoded hex string
+func (b *Bridge) DeserializeSignedTx(data string) (signedTx interface{}, err error) {
+	txData, err := hexutil.Decode(data)
+	if err != nil {
+		return nil, err
+	}
+	tx := new(types.Transaction)
+	err = rlp.DecodeBytes(txData, tx)
+	if err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
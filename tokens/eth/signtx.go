@@ -12,6 +12,7 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+	"github.com/anyswap/CrossChain-Bridge/tools/signer"
 	"github.com/anyswap/CrossChain-Bridge/types"
 )
 
@@ -94,18 +95,31 @@ func (b *Bridge) DcrmSignTransaction(rawTx interface{}, args *tokens.BuildTxArgs
 
 // SignTransaction sign tx with pairID
 func (b *Bridge) SignTransaction(rawTx interface{}, pairID string) (signTx interface{}, txHash string, err error) {
-	privKey := b.GetTokenConfig(pairID).GetDcrmAddressPrivateKey()
-	return b.SignTransactionWithPrivateKey(rawTx, privKey)
+	keySigner := b.GetTokenConfig(pairID).GetDcrmAddressSigner()
+	if keySigner == nil {
+		return nil, "", tokens.ErrMissingSigner
+	}
+	return b.SignTransactionWithSigner(rawTx, keySigner)
 }
 
 // SignTransactionWithPrivateKey sign tx with ECDSA private key
 func (b *Bridge) SignTransactionWithPrivateKey(rawTx interface{}, privKey *ecdsa.PrivateKey) (signTx interface{}, txHash string, err error) {
+	return b.SignTransactionWithSigner(rawTx, signer.NewLocalSigner(privKey))
+}
+
+// SignTransactionWithSigner sign tx with signer
+func (b *Bridge) SignTransactionWithSigner(rawTx interface{}, keySigner signer.Signer) (signTx interface{}, txHash string, err error) {
 	tx, ok := rawTx.(*types.Transaction)
 	if !ok {
 		return nil, "", errors.New("wrong raw tx param")
 	}
 
-	signedTx, err := types.SignTx(tx, b.Signer, privKey)
+	signature, err := keySigner.SignHash(b.Signer.Hash(tx).Bytes())
+	if err != nil {
+		return nil, "", fmt.Errorf("sign tx failed, %v", err)
+	}
+
+	signedTx, err := tx.WithSignature(b.Signer, signature)
 	if err != nil {
 		return nil, "", fmt.Errorf("sign tx failed, %v", err)
 	}
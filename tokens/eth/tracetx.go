@@ -0,0 +1,240 @@
+package eth
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/common/hexutil"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+// internalTransfer native coin transfer in internal call of contract
+type internalTransfer struct {
+	From  string
+	To    string
+	Value *big.Int
+}
+
+func (b *Bridge) isTraceEnabled() bool {
+	return b.ChainConfig.TraceAPI != ""
+}
+
+// getInternalTransfers get internal transfers of tx (top level call is excluded)
+func (b *Bridge) getInternalTransfers(txHash string) ([]*internalTransfer, error) {
+	switch b.ChainConfig.TraceAPI {
+	case tokens.TraceAPIDebug:
+		frame, err := b.DebugTraceTransaction(txHash)
+		if err != nil {
+			return nil, err
+		}
+		return getCallFrameTransfers(frame), nil
+	case tokens.TraceAPIParity:
+		traces, err := b.TraceTransaction(txHash)
+		if err != nil {
+			return nil, err
+		}
+		return getParityTraceTransfers(traces)[common.HexToHash(txHash).String()], nil
+	default:
+		return nil, nil
+	}
+}
+
+// getBlockInternalTransfers get internal transfers of txs in block, key is tx hash
+func (b *Bridge) getBlockInternalTransfers(height uint64) (map[string][]*internalTransfer, error) {
+	number := new(big.Int).SetUint64(height)
+	switch b.ChainConfig.TraceAPI {
+	case tokens.TraceAPIDebug:
+		results, err := b.DebugTraceBlockByNumber(number)
+		if err != nil {
+			return nil, err
+		}
+		var block *types.RPCBlock
+		result := make(map[string][]*internalTransfer)
+		for i, res := range results {
+			if res.Result == nil || res.Error != "" {
+				continue
+			}
+			transfers := getCallFrameTransfers(res.Result)
+			if len(transfers) == 0 {
+				continue
+			}
+			txHash := res.TxHash
+			if txHash == nil { // old geth has no 'txHash' in result
+				if block == nil {
+					block, err = b.GetBlockByNumber(number)
+					if err != nil {
+						return nil, err
+					}
+				}
+				if i >= len(block.Transactions) {
+					continue
+				}
+				txHash = block.Transactions[i]
+			}
+			result[txHash.String()] = transfers
+		}
+		return result, nil
+	case tokens.TraceAPIParity:
+		traces, err := b.TraceBlock(number)
+		if err != nil {
+			return nil, err
+		}
+		return getParityTraceTransfers(traces), nil
+	default:
+		return nil, nil
+	}
+}
+
+// getCallFrameTransfers walk call frames of 'callTracer',
+// frames with error are reverted together with their sub calls.
+func getCallFrameTransfers(frame *types.RPCCallFrame) (transfers []*internalTransfer) {
+	if frame == nil || frame.Error != "" {
+		return nil
+	}
+	var walk func(calls []*types.RPCCallFrame)
+	walk = func(calls []*types.RPCCallFrame) {
+		for _, call := range calls {
+			if call == nil || call.Error != "" {
+				continue
+			}
+			switch strings.ToUpper(call.Type) {
+			case "CALL", "SELFDESTRUCT":
+				transfers = appendInternalTransfer(transfers, call.From, call.To, call.Value)
+			}
+			walk(call.Calls)
+		}
+	}
+	walk(frame.Calls)
+	return transfers
+}
+
+// getParityTraceTransfers get internal transfers from parity traces, key is tx hash.
+// traces are in depth first order, so reverted parents are always met before their sub traces.
+func getParityTraceTransfers(traces []*types.RPCParityTrace) map[string][]*internalTransfer {
+	result := make(map[string][]*internalTransfer)
+	reverted := make(map[string][][]int)
+	for _, trace := range traces {
+		if trace.TransactionHash == nil || trace.Action == nil {
+			continue // block and uncle rewards
+		}
+		txHash := trace.TransactionHash.String()
+		if trace.Error != "" {
+			reverted[txHash] = append(reverted[txHash], trace.TraceAddress)
+			continue
+		}
+		if len(trace.TraceAddress) == 0 || isTraceReverted(trace.TraceAddress, reverted[txHash]) {
+			continue
+		}
+		action := trace.Action
+		switch {
+		case trace.Type == "call" && action.CallType == "call":
+			result[txHash] = appendInternalTransfer(result[txHash], action.From, action.To, action.Value)
+		case trace.Type == "suicide":
+			result[txHash] = appendInternalTransfer(result[txHash], action.Address, action.RefundAddress, action.Balance)
+		}
+	}
+	for txHash, transfers := range result {
+		if len(transfers) == 0 {
+			delete(result, txHash)
+		}
+	}
+	return result
+}
+
+func isTraceReverted(traceAddress []int, revertedAddresses [][]int) bool {
+	for _, reverted := range revertedAddresses {
+		if len(reverted) > len(traceAddress) {
+			continue
+		}
+		isPrefix := true
+		for i, pos := range reverted {
+			if traceAddress[i] != pos {
+				isPrefix = false
+				break
+			}
+		}
+		if isPrefix {
+			return true
+		}
+	}
+	return false
+}
+
+func appendInternalTransfer(transfers []*internalTransfer, from, to *common.Address, value *hexutil.Big) []*internalTransfer {
+	if from == nil || to == nil || value == nil || value.ToInt().Sign() <= 0 {
+		return transfers
+	}
+	return append(transfers, &internalTransfer{
+		From:  strings.ToLower(from.String()),
+		To:    strings.ToLower(to.String()),
+		Value: value.ToInt(),
+	})
+}
+
+// getInternalDeposit sum values of internal transfers to deposit address.
+// the deposit is credited to the sender, so transfers from different senders
+// are ambiguous and 'ErrTxWithWrongSender' is returned (with the total value).
+func getInternalDeposit(transfers []*internalTransfer, depositAddress string) (from string, value *big.Int, err error) {
+	value = big.NewInt(0)
+	for _, transfer := range transfers {
+		if !common.IsEqualIgnoreCase(transfer.To, depositAddress) {
+			continue
+		}
+		switch {
+		case from == "":
+			from = transfer.From
+		case !common.IsEqualIgnoreCase(from, transfer.From):
+			err = tokens.ErrTxWithWrongSender
+		}
+		value.Add(value, transfer.Value)
+	}
+	return from, value, err
+}
+
+// getNativeDepositPairs get pairIDs of native coin deposits, key is deposit address
+func getNativeDepositPairs() map[string][]string {
+	result := make(map[string][]string)
+	for pairID, pairCfg := range tokens.GetTokenPairsConfig() {
+		token := pairCfg.SrcToken
+		if token.IsErc20() {
+			continue
+		}
+		depositAddress := strings.ToLower(token.DepositAddress)
+		result[depositAddress] = append(result[depositAddress], pairID)
+	}
+	return result
+}
+
+// processInternalDeposits register swapins of internal native transfers to deposit addresses in block
+func (b *Bridge) processInternalDeposits(height uint64) error {
+	if !b.IsSrc || !b.isTraceEnabled() {
+		return nil
+	}
+	depositPairs := getNativeDepositPairs()
+	if len(depositPairs) == 0 {
+		return nil
+	}
+	blockTransfers, err := b.getBlockInternalTransfers(height)
+	if err != nil {
+		return err
+	}
+	for txHash, transfers := range blockTransfers {
+		processed := make(map[string]struct{})
+		for _, transfer := range transfers {
+			if _, exist := processed[transfer.To]; exist {
+				continue
+			}
+			processed[transfer.To] = struct{}{}
+			for _, pairID := range depositPairs[transfer.To] {
+				swapInfo, errf := b.verifySwapinTxWithPairID(pairID, txHash, true)
+				log.Debug("[scanchain] found internal deposit", "txid", txHash, "pairID", pairID, "err", errf)
+				tools.RegisterSwapin(txHash, []*tokens.TxSwapInfo{swapInfo}, []error{errf})
+			}
+		}
+	}
+	return nil
+}
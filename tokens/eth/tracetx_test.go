@@ -0,0 +1,217 @@
+package eth
+
+import (
+	"encoding/json"
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/types"
+)
+
+const (
+	testAddr1   = "0x1111111111111111111111111111111111111111"
+	testAddr2   = "0x2222222222222222222222222222222222222222"
+	testDeposit = "0x3333333333333333333333333333333333333333"
+	testAddr4   = "0x4444444444444444444444444444444444444444"
+
+	testTxHash1 = "0x1000000000000000000000000000000000000000000000000000000000000001"
+	testTxHash2 = "0x2000000000000000000000000000000000000000000000000000000000000002"
+)
+
+func newTestTransfer(from, to string, value int64) *internalTransfer {
+	return &internalTransfer{From: from, To: to, Value: big.NewInt(value)}
+}
+
+func TestGetCallFrameTransfers(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame string
+		want  []*internalTransfer
+	}{
+		{
+			name:  "top level call is excluded",
+			frame: `{"type":"CALL","from":"` + testAddr1 + `","to":"` + testDeposit + `","value":"0x64"}`,
+		},
+		{
+			name:  "reverted tx",
+			frame: `{"type":"CALL","from":"` + testAddr4 + `","to":"` + testAddr2 + `","error":"execution reverted","calls":[{"type":"CALL","from":"` + testAddr2 + `","to":"` + testDeposit + `","value":"0x64"}]}`,
+		},
+		{
+			name: "nested calls, reverted sub call and its children are skipped",
+			frame: `{"type":"CALL","from":"` + testAddr4 + `","to":"` + testAddr2 + `","calls":[` +
+				`{"type":"CALL","from":"` + testAddr2 + `","to":"` + testDeposit + `","value":"0x64","calls":[` +
+				`{"type":"CALL","from":"` + testDeposit + `","to":"` + testAddr1 + `","value":"0x1"}]},` +
+				`{"type":"CALL","from":"` + testAddr2 + `","to":"` + testAddr1 + `","value":"0x5","error":"out of gas","calls":[` +
+				`{"type":"CALL","from":"` + testAddr1 + `","to":"` + testDeposit + `","value":"0x7"}]},` +
+				`{"type":"STATICCALL","from":"` + testAddr2 + `","to":"` + testAddr1 + `"},` +
+				`{"type":"DELEGATECALL","from":"` + testAddr2 + `","to":"` + testAddr1 + `","value":"0x9"},` +
+				`{"type":"CALL","from":"` + testAddr2 + `","to":"` + testAddr1 + `","value":"0x0"},` +
+				`{"type":"SELFDESTRUCT","from":"` + testAddr1 + `","to":"` + testDeposit + `","value":"0x8"}]}`,
+			want: []*internalTransfer{
+				newTestTransfer(testAddr2, testDeposit, 100),
+				newTestTransfer(testDeposit, testAddr1, 1),
+				newTestTransfer(testAddr1, testDeposit, 8),
+			},
+		},
+	}
+	for _, test := range tests {
+		var frame types.RPCCallFrame
+		if err := json.Unmarshal([]byte(test.frame), &frame); err != nil {
+			t.Fatalf("%v: decode call frame failed: %v", test.name, err)
+		}
+		if have := getCallFrameTransfers(&frame); !reflect.DeepEqual(have, test.want) {
+			t.Errorf("%v: have %v want %v", test.name, have, test.want)
+		}
+	}
+}
+
+func TestGetParityTraceTransfers(t *testing.T) {
+	traces := `[
+{"type":"call","action":{"callType":"call","from":"` + testAddr4 + `","to":"` + testAddr2 + `","value":"0x0"},"traceAddress":[],"transactionHash":"` + testTxHash1 + `"},
+{"type":"call","action":{"callType":"call","from":"` + testAddr2 + `","to":"` + testDeposit + `","value":"0x64"},"traceAddress":[0],"transactionHash":"` + testTxHash1 + `"},
+{"type":"call","action":{"callType":"call","from":"` + testAddr2 + `","to":"` + testAddr1 + `","value":"0x5"},"error":"Reverted","traceAddress":[1],"transactionHash":"` + testTxHash1 + `"},
+{"type":"call","action":{"callType":"call","from":"` + testAddr1 + `","to":"` + testDeposit + `","value":"0x7"},"traceAddress":[1,0],"transactionHash":"` + testTxHash1 + `"},
+{"type":"call","action":{"callType":"delegatecall","from":"` + testAddr2 + `","to":"` + testAddr1 + `","value":"0x9"},"traceAddress":[2],"transactionHash":"` + testTxHash1 + `"},
+{"type":"suicide","action":{"address":"` + testAddr1 + `","refundAddress":"` + testDeposit + `","balance":"0x8"},"traceAddress":[3],"transactionHash":"` + testTxHash1 + `"},
+{"type":"call","action":{"callType":"call","from":"` + testAddr4 + `","to":"` + testDeposit + `","value":"0x64"},"traceAddress":[],"transactionHash":"` + testTxHash2 + `"},
+{"type":"reward","action":{"author":"` + testAddr4 + `","value":"0x1bc16d674ec80000"},"traceAddress":[]}
+]`
+	var parityTraces []*types.RPCParityTrace
+	if err := json.Unmarshal([]byte(traces), &parityTraces); err != nil {
+		t.Fatalf("decode parity traces failed: %v", err)
+	}
+	want := map[string][]*internalTransfer{
+		testTxHash1: {
+			newTestTransfer(testAddr2, testDeposit, 100),
+			newTestTransfer(testAddr1, testDeposit, 8),
+		},
+	}
+	if have := getParityTraceTransfers(parityTraces); !reflect.DeepEqual(have, want) {
+		t.Errorf("have %v want %v", have, want)
+	}
+}
+
+func TestIsTraceReverted(t *testing.T) {
+	reverted := [][]int{{1}, {2, 0}}
+	tests := []struct {
+		traceAddress []int
+		want         bool
+	}{
+		{[]int{0}, false},
+		{[]int{1}, true},
+		{[]int{1, 0, 3}, true},
+		{[]int{2}, false},
+		{[]int{2, 0}, true},
+		{[]int{2, 1}, false},
+		{[]int{10}, false},
+	}
+	for _, test := range tests {
+		if have := isTraceReverted(test.traceAddress, reverted); have != test.want {
+			t.Errorf("trace address %v: have %v want %v", test.traceAddress, have, test.want)
+		}
+	}
+	if isTraceReverted([]int{0}, nil) {
+		t.Errorf("no reverted traces, but trace is reverted")
+	}
+}
+
+func TestGetInternalDeposit(t *testing.T) {
+	tests := []struct {
+		name      string
+		transfers []*internalTransfer
+		wantFrom  string
+		wantValue int64
+		wantErr   error
+	}{
+		{
+			name: "no deposit",
+			transfers: []*internalTransfer{
+				newTestTransfer(testAddr1, testAddr2, 100),
+			},
+		},
+		{
+			name: "deposits of the same sender are summed",
+			transfers: []*internalTransfer{
+				newTestTransfer(testAddr1, testDeposit, 100),
+				newTestTransfer(testAddr1, testAddr2, 50),
+				newTestTransfer(testAddr1, "0x3333333333333333333333333333333333333333", 20),
+			},
+			wantFrom:  testAddr1,
+			wantValue: 120,
+		},
+		{
+			name: "deposits of different senders",
+			transfers: []*internalTransfer{
+				newTestTransfer(testAddr1, testDeposit, 100),
+				newTestTransfer(testAddr2, testDeposit, 30),
+			},
+			wantFrom:  testAddr1,
+			wantValue: 130,
+			wantErr:   tokens.ErrTxWithWrongSender,
+		},
+	}
+	for _, test := range tests {
+		from, value, err := getInternalDeposit(test.transfers, "0x3333333333333333333333333333333333333333")
+		if from != test.wantFrom || value.Int64() != test.wantValue || err != test.wantErr {
+			t.Errorf("%v: have (%v, %v, %v) want (%v, %v, %v)", test.name, from, value, err, test.wantFrom, test.wantValue, test.wantErr)
+		}
+	}
+}
+
+func TestVerifyInternalSwapin(t *testing.T) {
+	tests := []struct {
+		name  string
+		frame string
+		bind  string
+		value int64
+		err   error
+	}{
+		{
+			name: "deposit from contract",
+			frame: `{"type":"CALL","from":"` + testAddr4 + `","to":"` + testAddr2 + `","calls":[` +
+				`{"type":"CALL","from":"` + testAddr2 + `","to":"` + testDeposit + `","value":"0x64"}]}`,
+			bind:  testAddr2,
+			value: 100,
+			err:   tokens.ErrBindAddrIsContract,
+		},
+		{
+			name: "deposits from different contracts",
+			frame: `{"type":"CALL","from":"` + testAddr4 + `","to":"` + testAddr2 + `","calls":[` +
+				`{"type":"CALL","from":"` + testAddr2 + `","to":"` + testDeposit + `","value":"0x64"},` +
+				`{"type":"CALL","from":"` + testAddr2 + `","to":"` + testAddr1 + `","value":"0x0","calls":[` +
+				`{"type":"CALL","from":"` + testAddr1 + `","to":"` + testDeposit + `","value":"0x1"}]}]}`,
+			bind:  testAddr4,
+			value: 101,
+			err:   tokens.ErrTxWithWrongSender,
+		},
+		{
+			name: "no deposit",
+			frame: `{"type":"CALL","from":"` + testAddr4 + `","to":"` + testAddr2 + `","calls":[` +
+				`{"type":"CALL","from":"` + testAddr2 + `","to":"` + testAddr1 + `","value":"0x64"}]}`,
+			bind: testAddr4,
+			err:  tokens.ErrTxWithWrongReceiver,
+		},
+	}
+	token := &tokens.TokenConfig{DepositAddress: testDeposit}
+	for _, test := range tests {
+		srv := newTestGateway(t, map[string]string{"debug_traceTransaction": test.frame})
+		b := NewCrossChainBridge(true)
+		b.CrossChainBridgeBase.SetChainAndGateway(
+			&tokens.ChainConfig{BlockChain: "ETH", TraceAPI: tokens.TraceAPIDebug},
+			&tokens.GatewayConfig{APIAddress: []string{srv.URL}},
+		)
+		swapInfo := &tokens.TxSwapInfo{Hash: testTxHash1, From: testAddr4, Bind: testAddr4}
+		err := b.verifyInternalSwapin(swapInfo, token)
+		if err != test.err {
+			t.Errorf("%v: verify error mismatch, have %v want %v", test.name, err, test.err)
+		}
+		if swapInfo.Bind != test.bind {
+			t.Errorf("%v: bind mismatch, have %v want %v", test.name, swapInfo.Bind, test.bind)
+		}
+		if test.value != 0 && (swapInfo.Value == nil || swapInfo.Value.Int64() != test.value) {
+			t.Errorf("%v: value mismatch, have %v want %v", test.name, swapInfo.Value, test.value)
+		}
+	}
+}
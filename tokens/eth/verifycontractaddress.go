@@ -149,7 +149,7 @@ func InitExtCodePartsWithFlag(isMbtc bool) {
 }
 
 func isMbtcSwapout() bool {
-	return btc.BridgeInstance != nil
+	return btc.GetSrcBridge() != nil
 }
 
 func getSwapinFuncHash() []byte {
@@ -27,9 +27,14 @@ func (b *Bridge) verifySwapoutTxWithPairID(pairID, txHash string, allowUnstable
 		return swapInfo, err
 	}
 
-	if !allowUnstable || receipt != nil {
+	switch {
+	case token.IsLockRelease && (!allowUnstable || receipt != nil):
+		err = b.verifyErc20SwapoutTxReceipt(swapInfo, receipt, token)
+	case token.IsLockRelease:
+		err = b.verifyErc20SwapoutRawTx(swapInfo, token)
+	case !allowUnstable || receipt != nil:
 		err = b.verifySwapoutTxReceipt(swapInfo, receipt, token)
-	} else {
+	default:
 		err = b.verifySwapoutRawTx(swapInfo, token)
 	}
 	if err != nil {
@@ -143,6 +148,15 @@ func (b *Bridge) verifySwapoutTxWithReceipt(commonInfo *tokens.TxSwapInfo, recei
 
 		swapInfo.PairID = pairID // PairID
 
+		if token.IsLockRelease {
+			err := b.verifyErc20SwapoutTxReceipt(swapInfo, receipt, token)
+			if err == nil {
+				err = b.checkSwapoutInfo(swapInfo)
+			}
+			addSwapInfoConsiderError(swapInfo, err, &swapInfos, &errs)
+			continue
+		}
+
 		bindAddress, value, err := parseSwapoutTxLogs(receipt.Logs, token.ContractAddress)
 		if err != nil {
 			if err != tokens.ErrSwapoutLogNotFound {
@@ -224,6 +238,15 @@ func (b *Bridge) verifySwapoutTxUnstable(txHash string) (swapInfos []*tokens.TxS
 
 		swapInfo.PairID = pairID // PairID
 
+		if token.IsLockRelease {
+			err = b.verifyErc20SwapoutRawTx(swapInfo, token)
+			if err == nil {
+				err = b.checkSwapoutInfo(swapInfo)
+			}
+			addSwapInfoConsiderError(swapInfo, err, &swapInfos, &errs)
+			continue
+		}
+
 		input := (*[]byte)(tx.Payload)
 		bindAddress, value, err := ParseSwapoutTxInput(input)
 		if err != nil {
@@ -252,6 +275,30 @@ func (b *Bridge) verifySwapoutTxUnstable(txHash string) (swapInfos []*tokens.TxS
 	return swapInfos, errs
 }
 
+// verifyErc20SwapoutTxReceipt verify erc20 transfer to deposit address (lock/release mode)
+func (b *Bridge) verifyErc20SwapoutTxReceipt(swapInfo *tokens.TxSwapInfo, receipt *types.RPCTxReceipt, token *tokens.TokenConfig) error {
+	err := b.verifyErc20SwapinTxReceipt(swapInfo, receipt, token)
+	if err != nil {
+		return err
+	}
+	if swapInfo.Bind == swapInfo.To {
+		return tokens.ErrTxWithWrongSender
+	}
+	return nil
+}
+
+// verifyErc20SwapoutRawTx verify erc20 transfer input to deposit address (lock/release mode)
+func (b *Bridge) verifyErc20SwapoutRawTx(swapInfo *tokens.TxSwapInfo, token *tokens.TokenConfig) error {
+	err := b.verifySwapinRawTx(swapInfo, token)
+	if err != nil {
+		return err
+	}
+	if swapInfo.Bind == swapInfo.To {
+		return tokens.ErrTxWithWrongSender
+	}
+	return nil
+}
+
 func (b *Bridge) checkSwapoutInfo(swapInfo *tokens.TxSwapInfo) error {
 	if !tokens.CheckSwapValue(swapInfo.PairID, swapInfo.Value, b.IsSrc) {
 		return tokens.ErrTxWithWrongValue
@@ -111,16 +111,19 @@ func (b *Bridge) verifySwapinTxWithPairID(pairID, txHash string, allowUnstable b
 	}
 
 	txRecipient := strings.ToLower(tx.Recipient.String())
-	if !common.IsEqualIgnoreCase(txRecipient, token.DepositAddress) {
-		return swapInfo, tokens.ErrTxWithWrongReceiver
-	}
-
 	swapInfo.TxTo = txRecipient                       // TxTo
 	swapInfo.To = txRecipient                         // To
 	swapInfo.From = strings.ToLower(tx.From.String()) // From
 	swapInfo.Bind = swapInfo.From                     // Bind
 	swapInfo.Value = tx.Amount.ToInt()                // Value
 
+	if !common.IsEqualIgnoreCase(txRecipient, token.DepositAddress) {
+		err = b.verifyInternalSwapin(swapInfo, token)
+		if err != nil {
+			return swapInfo, err
+		}
+	}
+
 	err = b.checkSwapinInfo(swapInfo)
 	if err != nil {
 		return swapInfo, err
@@ -192,6 +195,36 @@ func (b *Bridge) verifySwapinTx(txHash string, allowUnstable bool) (swapInfos []
 	return swapInfos, errs
 }
 
+// verifyInternalSwapin verify internal native transfers to deposit address by tracing tx.
+// internal transfers are always sent by contracts (contract wallets or forwarders),
+// the depositor who should receive the swapped token can not be derived from them,
+// so internal swapins are registered with 'ErrBindAddrIsContract' for manual handling.
+func (b *Bridge) verifyInternalSwapin(swapInfo *tokens.TxSwapInfo, token *tokens.TokenConfig) error {
+	if !b.isTraceEnabled() {
+		return tokens.ErrTxWithWrongReceiver
+	}
+	transfers, err := b.getInternalTransfers(swapInfo.Hash)
+	if err != nil {
+		log.Debug("[verifySwapin] "+b.ChainConfig.BlockChain+" Bridge::getInternalTransfers fail", "tx", swapInfo.Hash, "err", err)
+		return tokens.ErrRPCQueryError
+	}
+	from, value, err := getInternalDeposit(transfers, token.DepositAddress)
+	if from == "" {
+		return tokens.ErrTxWithWrongReceiver
+	}
+	swapInfo.To = strings.ToLower(token.DepositAddress) // To
+	swapInfo.Value = value                              // Value
+	if err != nil {
+		// keep tx sender as bind, the swap needs manual handling
+		log.Warn("[verifySwapin] internal deposits from different senders", "tx", swapInfo.Hash, "pairID", swapInfo.PairID)
+		return err
+	}
+	swapInfo.From = from // From
+	swapInfo.Bind = from // Bind
+	log.Warn("[verifySwapin] internal deposit from contract", "tx", swapInfo.Hash, "pairID", swapInfo.PairID, "from", from)
+	return tokens.ErrBindAddrIsContract
+}
+
 func addSwapInfoConsiderError(swapInfo *tokens.TxSwapInfo, err error, swapInfos *[]*tokens.TxSwapInfo, errs *[]error) {
 	if !tokens.ShouldRegisterSwapForError(err) {
 		return
@@ -0,0 +1,274 @@
+package tokens
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+const (
+	defaultFeePolicyCacheSeconds = 60
+	defaultFeePolicyTolerance    = 0.2
+)
+
+var (
+	errEstimateSwapFee = errors.New("can not estimate dynamic swap fee")
+
+	dynamicMinSwapFees    = make(map[string]*dynamicMinSwapFee)
+	dynamicMinSwapFeeLock sync.Mutex
+)
+
+// FeePolicyConfig dynamic fee policy of token which charges swap fee,
+// the minimum swap fee is raised to cover the network fee of the swap tx,
+// which is estimated from the current gas price (or fee per kb) of the paying chain
+// and converted to this token by the price feed.
+type FeePolicyConfig struct {
+	ExpectedTxCost uint64  // expected gas (eth like chain) or size in bytes (btc like chain) of swap tx
+	NativeSymbol   string  // price feed symbol of native coin which pays network fee
+	NativeDecimals *uint8  // decimals of native coin which pays network fee
+	FeeSymbol      string  `json:",omitempty"` // price feed symbol of this token, default to 'Symbol'
+	Multiplier     float64 `json:",omitempty"` // multiplier of network fee, default to 1
+	CacheSeconds   int64   `json:",omitempty"` // cache estimated minimum swap fee for seconds, default to 60
+	Tolerance      float64 `json:",omitempty"` // max relative difference of swap fee snapshot to the one estimated by oracle, default to 0.2
+}
+
+// SwapFeeSchedule effective swap fee schedule (in whole unit)
+type SwapFeeSchedule struct {
+	SwapFeeRate           float64
+	MinimumSwapFee        float64 // static or dynamic minimum swap fee, the larger one
+	MaximumSwapFee        float64
+	DynamicMinimumSwapFee float64 `json:",omitempty"`
+	UpdateTime            int64   `json:",omitempty"` // when dynamic minimum swap fee is estimated
+}
+
+// NetworkFeeEstimator estimate network fee (in smallest unit of native coin) of swap tx
+// with expected cost (gas for eth like chain, size in bytes for btc like chain)
+type NetworkFeeEstimator interface {
+	EstimateNetworkFee(pairID string, expectedTxCost uint64) (*big.Int, error)
+}
+
+type dynamicMinSwapFee struct {
+	fee        *big.Int
+	updateTime int64
+}
+
+// CheckConfig check fee policy config
+func (c *FeePolicyConfig) CheckConfig() error {
+	if c.ExpectedTxCost == 0 {
+		return errors.New("fee policy must config 'ExpectedTxCost'")
+	}
+	if c.NativeSymbol == "" {
+		return errors.New("fee policy must config 'NativeSymbol'")
+	}
+	if c.NativeDecimals == nil {
+		return errors.New("fee policy must config 'NativeDecimals'")
+	}
+	if c.Multiplier < 0 {
+		return errors.New("fee policy 'Multiplier' must be non-negative")
+	}
+	if c.CacheSeconds < 0 {
+		return errors.New("fee policy 'CacheSeconds' must be non-negative")
+	}
+	if c.Tolerance < 0 || c.Tolerance >= 1 {
+		return errors.New("fee policy 'Tolerance' must be in range [0,1)")
+	}
+	return nil
+}
+
+func (c *FeePolicyConfig) getMultiplier() float64 {
+	if c.Multiplier == 0 {
+		return 1
+	}
+	return c.Multiplier
+}
+
+func (c *FeePolicyConfig) getCacheSeconds() int64 {
+	if c.CacheSeconds == 0 {
+		return defaultFeePolicyCacheSeconds
+	}
+	return c.CacheSeconds
+}
+
+func (c *FeePolicyConfig) getTolerance() float64 {
+	if c.Tolerance == 0 {
+		return defaultFeePolicyTolerance
+	}
+	return c.Tolerance
+}
+
+// HasFeePolicy whether swap fee of token is priced by fee policy
+func HasFeePolicy(pairID string, isSrc bool) bool {
+	token := GetTokenConfig(pairID, isSrc)
+	return token != nil && token.FeePolicy != nil
+}
+
+// GetDynamicMinSwapFee get cached dynamic minimum swap fee, or estimate it if expired.
+// returns nil if no fee policy or estimation failed and no cached one.
+// fee of swapin is charged in source token and paid on destination chain,
+// and vice versa (same as 'isSrc' of CalcSwappedValue).
+func GetDynamicMinSwapFee(pairID string, isSrc bool) (fee *big.Int, updateTime int64) {
+	token := GetTokenConfig(pairID, isSrc)
+	if token == nil || token.FeePolicy == nil {
+		return nil, 0
+	}
+	key := strings.ToLower(fmt.Sprintf("%v:%v", pairID, isSrc))
+	now := time.Now().Unix()
+
+	dynamicMinSwapFeeLock.Lock()
+	cached := dynamicMinSwapFees[key]
+	dynamicMinSwapFeeLock.Unlock()
+
+	if cached != nil && now-cached.updateTime < token.FeePolicy.getCacheSeconds() {
+		return cached.fee, cached.updateTime
+	}
+
+	estimated, err := estimateMinSwapFee(pairID, token, isSrc)
+	if err != nil {
+		log.Warn("estimate dynamic minimum swap fee failed", "pairID", pairID, "isSrc", isSrc, "err", err)
+		if cached != nil {
+			return cached.fee, cached.updateTime
+		}
+		return nil, 0
+	}
+	log.Info("estimate dynamic minimum swap fee", "pairID", pairID, "isSrc", isSrc, "fee", estimated)
+
+	dynamicMinSwapFeeLock.Lock()
+	dynamicMinSwapFees[key] = &dynamicMinSwapFee{fee: estimated, updateTime: now}
+	dynamicMinSwapFeeLock.Unlock()
+	return estimated, now
+}
+
+// estimateMinSwapFee fee = network fee / 10^NativeDecimals * native price / token price * multiplier * 10^Decimals
+func estimateMinSwapFee(pairID string, token *TokenConfig, isSrc bool) (*big.Int, error) {
+	policy := token.FeePolicy
+	payBridge := GetCrossChainBridge(!isSrc)
+	estimator, ok := payBridge.(NetworkFeeEstimator)
+	if !ok {
+		return nil, errors.New("network fee estimation is not supported")
+	}
+	networkFee, err := estimator.EstimateNetworkFee(pairID, policy.ExpectedTxCost)
+	if err != nil {
+		return nil, err
+	}
+
+	feeSymbol := policy.FeeSymbol
+	if feeSymbol == "" {
+		feeSymbol = token.Symbol
+	}
+	priceRatio := 1.0
+	if !strings.EqualFold(feeSymbol, policy.NativeSymbol) {
+		if priceFeed == nil {
+			return nil, errNoPriceFeed
+		}
+		nativePrice, errp := priceFeed.GetPrice(policy.NativeSymbol)
+		if errp != nil {
+			return nil, errp
+		}
+		tokenPrice, errp := priceFeed.GetPrice(feeSymbol)
+		if errp != nil {
+			return nil, errp
+		}
+		priceRatio = nativePrice / tokenPrice
+	}
+
+	nativeFee := FromBits(networkFee, *policy.NativeDecimals)
+	return ToBits(nativeFee*priceRatio*policy.getMultiplier(), *token.Decimals), nil
+}
+
+// GetSwapFeeSchedule get effective swap fee schedule
+func GetSwapFeeSchedule(pairID string, isSrc bool) *SwapFeeSchedule {
+	token := GetTokenConfig(pairID, isSrc)
+	if token == nil {
+		return nil
+	}
+	schedule := &SwapFeeSchedule{
+		SwapFeeRate:    *token.SwapFeeRate,
+		MinimumSwapFee: *token.MinimumSwapFee,
+		MaximumSwapFee: *token.MaximumSwapFee,
+	}
+	if dynamicFee, updateTime := GetDynamicMinSwapFee(pairID, isSrc); dynamicFee != nil {
+		schedule.DynamicMinimumSwapFee = FromBits(dynamicFee, *token.Decimals)
+		schedule.UpdateTime = updateTime
+		minSwapFee := FromBits(token.getMinSwapFee(dynamicFee), *token.Decimals)
+		if minSwapFee > schedule.MinimumSwapFee {
+			schedule.MinimumSwapFee = minSwapFee
+		}
+	}
+	return schedule
+}
+
+// getMinSwapFee the larger one of static and dynamic minimum swap fee, not exceed maximum swap fee
+func (c *TokenConfig) getMinSwapFee(dynamicFee *big.Int) *big.Int {
+	minSwapFee := c.minSwapFee
+	if dynamicFee != nil && dynamicFee.Cmp(minSwapFee) > 0 {
+		minSwapFee = dynamicFee
+	}
+	if minSwapFee.Cmp(c.maxSwapFee) > 0 {
+		minSwapFee = c.maxSwapFee
+	}
+	return minSwapFee
+}
+
+// calcSwapFee calc swap fee in range [minimum swap fee, maximum swap fee]
+func (c *TokenConfig) calcSwapFee(value, dynamicMinFee *big.Int) *big.Int {
+	if *c.SwapFeeRate == 0.0 {
+		return big.NewInt(0)
+	}
+
+	feeRateMul1e18 := new(big.Int).SetUint64(uint64(*c.SwapFeeRate * 1e18))
+	swapFee := new(big.Int).Mul(value, feeRateMul1e18)
+	swapFee.Div(swapFee, big.NewInt(1e18))
+
+	minSwapFee := c.getMinSwapFee(dynamicMinFee)
+	if swapFee.Cmp(minSwapFee) < 0 {
+		swapFee = minSwapFee
+	} else if swapFee.Cmp(c.maxSwapFee) > 0 {
+		swapFee = c.maxSwapFee
+	}
+	return swapFee
+}
+
+// VerifySwapFeeSnapshot verify swap fee snapshotted by swap initiator, the charged
+// swap fee must be within the fee policy tolerance of the one estimated by this node,
+// and must be the static swap fee if there is no fee policy.
+func VerifySwapFeeSnapshot(pairID string, value, swapFee *big.Int, isSrc bool) error {
+	token := GetTokenConfig(pairID, isSrc)
+	if token == nil {
+		return ErrUnknownPairID
+	}
+	if swapFee == nil {
+		return nil // static swap fee is charged
+	}
+	charged := token.getSnapshotSwapFee(value, swapFee)
+	expected := token.calcSwapFee(value, nil)
+	tolerance := 0.0
+	if token.FeePolicy != nil {
+		dynamicMinFee, _ := GetDynamicMinSwapFee(pairID, isSrc)
+		if dynamicMinFee == nil {
+			return errEstimateSwapFee
+		}
+		expected = token.calcSwapFee(value, dynamicMinFee)
+		tolerance = token.FeePolicy.getTolerance()
+	}
+	diff := new(big.Int).Sub(charged, expected)
+	diff.Abs(diff)
+	maxDiff, _ := new(big.Float).Mul(new(big.Float).SetInt(expected), big.NewFloat(tolerance)).Int(nil)
+	if diff.Cmp(maxDiff) > 0 {
+		return fmt.Errorf("%w, charged %v expected %v tolerance %v", ErrSwapFeeOutOfTolerance, charged, expected, tolerance)
+	}
+	return nil
+}
+
+// clearDynamicMinSwapFee clear cached dynamic minimum swap fee of pair (eg. its config is reloaded)
+func clearDynamicMinSwapFee(pairID string) {
+	dynamicMinSwapFeeLock.Lock()
+	defer dynamicMinSwapFeeLock.Unlock()
+	for _, isSrc := range []bool{true, false} {
+		delete(dynamicMinSwapFees, strings.ToLower(fmt.Sprintf("%v:%v", pairID, isSrc)))
+	}
+}
@@ -0,0 +1,146 @@
+package tokens
+
+import (
+	"math/big"
+	"testing"
+	"time"
+)
+
+func newTestFeeToken(feeRate, minFee, maxFee float64) *TokenConfig {
+	decimals := uint8(8)
+	maxSwap, minSwap, bigValue := 1000.0, 0.0, 100.0
+	token := &TokenConfig{
+		Decimals:          &decimals,
+		MaximumSwap:       &maxSwap,
+		MinimumSwap:       &minSwap,
+		BigValueThreshold: &bigValue,
+		SwapFeeRate:       &feeRate,
+		MinimumSwapFee:    &minFee,
+		MaximumSwapFee:    &maxFee,
+	}
+	token.CalcAndStoreValue()
+	return token
+}
+
+func TestCalcSwappedValueWithFee(t *testing.T) {
+	SetTokenPairsConfig(map[string]*TokenPairConfig{
+		"test": {PairID: "test", SrcToken: newTestFeeToken(0.001, 0.0001, 0.01)},
+	}, false)
+	defer SetTokenPairsConfig(nil, false)
+
+	value := ToBits(1, 8)
+	staticSwapped := CalcSwappedValue("test", value, true) // fee 0.001
+	cases := []struct {
+		swapFee *big.Int
+		want    *big.Int
+	}{
+		{nil, staticSwapped},
+		{ToBits(0.0005, 8), staticSwapped},                          // lower than static fee
+		{ToBits(0.005, 8), ToBits(0.995, 8)},                        // raised by fee policy
+		{ToBits(0.05, 8), new(big.Int).Sub(value, ToBits(0.01, 8))}, // limited by maximum swap fee
+	}
+	for i, c := range cases {
+		if got := CalcSwappedValueWithFee("test", value, c.swapFee, true); got.Cmp(c.want) != 0 {
+			t.Errorf("case %v: have %v want %v", i, got, c.want)
+		}
+	}
+
+	*GetTokenConfig("test", true).SwapFeeRate = 0
+	if got := CalcSwappedValueWithFee("test", value, ToBits(0.005, 8), true); got.Cmp(value) != 0 {
+		t.Errorf("swap fee should be zero if fee rate is zero, have %v", got)
+	}
+}
+
+func TestStaticPriceFeed(t *testing.T) {
+	feed := NewStaticPriceFeed(map[string]float64{"eth": 2000, "BTC": 40000})
+	if price, err := feed.GetPrice("ETH"); err != nil || price != 2000 {
+		t.Errorf("get price of ETH failed, price %v err %v", price, err)
+	}
+	if _, err := feed.GetPrice("FSN"); err == nil {
+		t.Error("get price of unknown symbol should fail")
+	}
+}
+
+func TestVerifySwapFeeSnapshot(t *testing.T) {
+	nativeDecimals := uint8(18)
+	token := newTestFeeToken(0.001, 0.0001, 0.01)
+	SetTokenPairsConfig(map[string]*TokenPairConfig{
+		"test": {PairID: "test", SrcToken: token},
+	}, false)
+	defer func() {
+		SetTokenPairsConfig(nil, false)
+		clearDynamicMinSwapFee("test")
+	}()
+
+	value := ToBits(1, 8) // static fee 0.001
+	if err := VerifySwapFeeSnapshot("test", value, nil, true); err != nil {
+		t.Errorf("verify nil snapshot failed, %v", err)
+	}
+	if err := VerifySwapFeeSnapshot("test", value, ToBits(0.0005, 8), true); err != nil {
+		t.Errorf("snapshot lower than static fee charges static fee, %v", err)
+	}
+	if err := VerifySwapFeeSnapshot("test", value, ToBits(0.002, 8), true); err == nil {
+		t.Error("snapshot larger than static fee should fail without fee policy")
+	}
+
+	token.FeePolicy = &FeePolicyConfig{ExpectedTxCost: 21000, NativeSymbol: "ETH", NativeDecimals: &nativeDecimals}
+	if err := VerifySwapFeeSnapshot("test", value, ToBits(0.005, 8), true); err != errEstimateSwapFee {
+		t.Errorf("verify snapshot without estimation, have err %v want %v", err, errEstimateSwapFee)
+	}
+
+	dynamicMinSwapFeeLock.Lock()
+	dynamicMinSwapFees["test:true"] = &dynamicMinSwapFee{fee: ToBits(0.005, 8), updateTime: time.Now().Unix()}
+	dynamicMinSwapFeeLock.Unlock()
+
+	cases := []struct {
+		swapFee float64
+		ok      bool
+	}{
+		{0.005, true},
+		{0.0041, true},  // within default tolerance 0.2
+		{0.0059, true},  // within default tolerance 0.2
+		{0.0039, false}, // too low
+		{0.0061, false}, // too high
+		{0.05, false},   // limited by maximum swap fee, still too high
+	}
+	for _, c := range cases {
+		err := VerifySwapFeeSnapshot("test", value, ToBits(c.swapFee, 8), true)
+		if (err == nil) != c.ok {
+			t.Errorf("verify snapshot %v, have err %v want ok %v", c.swapFee, err, c.ok)
+		}
+	}
+
+	token.FeePolicy.Tolerance = 0.5
+	if err := VerifySwapFeeSnapshot("test", value, ToBits(0.007, 8), true); err != nil {
+		t.Errorf("snapshot within configed tolerance should pass, %v", err)
+	}
+}
+
+func TestCheckSwapValueWithStaticFee(t *testing.T) {
+	nativeDecimals := uint8(18)
+	token := newTestFeeToken(0.001, 0.0001, 0.01)
+	token.FeePolicy = &FeePolicyConfig{ExpectedTxCost: 21000, NativeSymbol: "ETH", NativeDecimals: &nativeDecimals}
+	SetTokenPairsConfig(map[string]*TokenPairConfig{
+		"test": {PairID: "test", SrcToken: token},
+	}, false)
+	defer func() {
+		SetTokenPairsConfig(nil, false)
+		clearDynamicMinSwapFee("test")
+	}()
+
+	// dynamic fee is raised by network fee spike
+	dynamicMinSwapFeeLock.Lock()
+	dynamicMinSwapFees["test:true"] = &dynamicMinSwapFee{fee: ToBits(0.01, 8), updateTime: time.Now().Unix()}
+	dynamicMinSwapFeeLock.Unlock()
+
+	value := ToBits(0.005, 8)
+	if CalcSwappedValue("test", value, true).Sign() != 0 {
+		t.Fatal("swapped value should be zero with dynamic fee")
+	}
+	if !CheckSwapValue("test", value, true) {
+		t.Error("deposit should be checked with static fee")
+	}
+	if CheckSwapValue("test", ToBits(0.0001, 8), true) {
+		t.Error("deposit not enough for static fee should be wrong value")
+	}
+}
@@ -18,6 +18,14 @@ const (
 	netCustom  = "custom"
 )
 
+// chain ids of supported networks, zero means any chain id
+var chainIDs = map[string]uint64{
+	netMainnet: 32659,
+	netTestnet: 46688,
+	netDevnet:  55555,
+	netCustom:  0,
+}
+
 // Bridge fsn bridge inherit from eth bridge
 type Bridge struct {
 	*eth.Bridge
@@ -38,10 +46,7 @@ func (b *Bridge) SetChainAndGateway(chainCfg *tokens.ChainConfig, gatewayCfg *to
 // VerifyChainID verify chain id
 func (b *Bridge) VerifyChainID() {
 	networkID := strings.ToLower(b.ChainConfig.NetID)
-	switch networkID {
-	case netMainnet, netTestnet, netDevnet:
-	case netCustom:
-	default:
+	if _, exist := chainIDs[networkID]; !exist {
 		log.Fatalf("unsupported fusion network: %v", b.ChainConfig.NetID)
 	}
 
@@ -60,26 +65,9 @@ func (b *Bridge) VerifyChainID() {
 		time.Sleep(3 * time.Second)
 	}
 
-	panicMismatchChainID := func() {
-		log.Fatalf("gateway chainID %v is not %v", chainID, b.ChainConfig.NetID)
-	}
-
-	switch networkID {
-	case netMainnet:
-		if chainID.Uint64() != 32659 {
-			panicMismatchChainID()
-		}
-	case netTestnet:
-		if chainID.Uint64() != 46688 {
-			panicMismatchChainID()
-		}
-	case netDevnet:
-		if chainID.Uint64() != 55555 {
-			panicMismatchChainID()
-		}
-	case netCustom:
-	default:
-		log.Fatalf("unsupported fusion network %v", networkID)
+	err = eth.CheckChainID(b.ChainConfig.NetID, chainID, chainIDs)
+	if err != nil {
+		log.Fatalf("%v", err)
 	}
 
 	b.SignerChainID = chainID
@@ -87,3 +75,8 @@ func (b *Bridge) VerifyChainID() {
 
 	log.Info("VerifyChainID succeed", "networkID", networkID, "chainID", chainID)
 }
+
+// CheckChainAndGateway impl ConfigChecker
+func (b *Bridge) CheckChainAndGateway(chainCfg *tokens.ChainConfig, gatewayCfg *tokens.GatewayConfig, online bool) error {
+	return b.CheckChainAndGatewayWith(chainCfg, gatewayCfg, online, chainIDs, b.GetSignerChainID)
+}
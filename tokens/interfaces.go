@@ -20,6 +20,11 @@ var (
 	ErrBuildSwapTxInWrongEndpoint    = errors.New("build swap in/out tx in wrong endpoint")
 	ErrTxBeforeInitialHeight         = errors.New("transaction before initial block height")
 	ErrAddressIsInBlacklist          = errors.New("address is in black list")
+	ErrInsufficientVaultBalance      = errors.New("insufficient vault balance")
+	ErrSwapFeeOutOfTolerance         = errors.New("swap fee snapshot is out of tolerance")
+	ErrMissingSigner                 = errors.New("missing signer of dcrm address")
+	ErrNoFeeTreasury                 = errors.New("no fee treasury is configed")
+	ErrFeeWithdrawNotSupported       = errors.New("fee withdraw not supported")
 
 	ErrTodo = errors.New("developing: TODO")
 
@@ -100,6 +105,12 @@ type CrossChainBridge interface {
 	GetTokenSupply(tokenType, tokenAddress string) (*big.Int, error)
 }
 
+// MultiSwapVerifier interface (for chains whose tx may contain
+// several swaps of the same pair with different bind addresses)
+type MultiSwapVerifier interface {
+	VerifyTransactionSwaps(pairID, txHash string, allowUnstable bool) ([]*TxSwapInfo, []error)
+}
+
 // NonceSetter interface (for eth-like)
 type NonceSetter interface {
 	GetTxBlockInfo(txHash string) (blockHeight, blockTime uint64)
@@ -109,3 +120,35 @@ type NonceSetter interface {
 	IncreaseNonce(pairID string, value uint64)
 	InitNonces(nonces map[string]uint64)
 }
+
+// UtxoReserver interface (for utxo chains which reserve the selected utxos
+// when building swap tx, the reservation is released if the tx is not sent)
+type UtxoReserver interface {
+	ReleaseUtxos(args *BuildTxArgs)
+}
+
+// SignedTxSerializer interface (for chains whose signed tx can be
+// persisted in outbox and rebroadcasted after restart)
+type SignedTxSerializer interface {
+	SerializeSignedTx(signedTx interface{}) (string, error)
+	DeserializeSignedTx(data string) (signedTx interface{}, err error)
+}
+
+// FeeWithdrawer interface (for chains which support withdrawing the collected
+// swap fee to fee treasury, the fee of swapin is minted on destination chain,
+// and the fee of swapout is transferred from dcrm address on source chain)
+type FeeWithdrawer interface {
+	BuildFeeWithdrawTx(args *BuildTxArgs) (rawTx interface{}, err error)
+}
+
+// TxFeeGetter interface (for chains which can report the network fee paid by tx)
+type TxFeeGetter interface {
+	GetTxFee(txHash string) (*big.Int, error)
+}
+
+// ConfigChecker interface (for checking chain and token config without
+// retrying or exiting, gateway is queried only if online)
+type ConfigChecker interface {
+	CheckChainAndGateway(chainCfg *ChainConfig, gatewayCfg *GatewayConfig, online bool) error
+	CheckTokenConfig(tokenCfg *TokenConfig, online bool) error
+}
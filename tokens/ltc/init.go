@@ -1,109 +0,0 @@
-package ltc
-
-import (
-	"github.com/anyswap/CrossChain-Bridge/log"
-	"github.com/anyswap/CrossChain-Bridge/tokens"
-	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
-)
-
-var (
-	cfgMinRelayFee       int64  = 400
-	cfgMinRelayFeePerKb  int64  = 2000
-	cfgMaxRelayFeePerKb  int64  = 500000
-	cfgPlusFeePercentage uint64 = 0
-	cfgEstimateFeeBlocks        = 6
-
-	cfgFromPublicKey string
-
-	cfgUtxoAggregateMinCount  = 20
-	cfgUtxoAggregateMinValue  = uint64(1000000)
-	cfgUtxoAggregateToAddress string
-)
-
-// Init init ltc extra
-func Init(btcExtra *tokens.BtcExtraConfig) {
-	if btc.BridgeInstance == nil {
-		return
-	}
-
-	if btcExtra == nil {
-		log.Fatal("Ltc bridge must config 'BtcExtra'")
-	}
-
-	initFromPublicKey()
-	initRelayFee(btcExtra)
-	initAggregate(btcExtra)
-}
-
-func initFromPublicKey() {
-	if len(tokens.GetTokenPairsConfig()) != 1 {
-		log.Fatalf("Ltc bridge does not support multiple tokens")
-	}
-
-	pairCfg, exist := tokens.GetTokenPairsConfig()[PairID]
-	if !exist {
-		log.Fatalf("Ltc bridge must have pairID %v", PairID)
-	}
-
-	cfgFromPublicKey = pairCfg.SrcToken.DcrmPubkey
-	_, err := btc.BridgeInstance.GetCompressedPublicKey(cfgFromPublicKey, true)
-	if err != nil {
-		log.Fatal("wrong ltc dcrm public key", "err", err)
-	}
-}
-
-func initRelayFee(btcExtra *tokens.BtcExtraConfig) {
-	if btcExtra.MinRelayFee > 0 {
-		cfgMinRelayFee = btcExtra.MinRelayFee
-		maxMinRelayFee, _ := newAmount(0.001)
-		minRelayFee := ltcAmountType(cfgMinRelayFee)
-		if minRelayFee > maxMinRelayFee {
-			log.Fatal("BtcMinRelayFee is too large", "value", minRelayFee, "max", maxMinRelayFee)
-		}
-	}
-
-	if btcExtra.EstimateFeeBlocks > 0 {
-		cfgEstimateFeeBlocks = btcExtra.EstimateFeeBlocks
-		if cfgEstimateFeeBlocks > 25 {
-			log.Fatal("EstimateFeeBlocks is too large, must <= 25")
-		}
-	}
-
-	if btcExtra.PlusFeePercentage > 0 {
-		cfgPlusFeePercentage = btcExtra.PlusFeePercentage
-		if cfgPlusFeePercentage > 5000 {
-			log.Fatal("PlusFeePercentage is too large, must <= 5000")
-		}
-	}
-
-	if btcExtra.MaxRelayFeePerKb > 0 {
-		cfgMaxRelayFeePerKb = btcExtra.MaxRelayFeePerKb
-	}
-
-	if btcExtra.MinRelayFeePerKb > 0 {
-		cfgMinRelayFeePerKb = btcExtra.MinRelayFeePerKb
-	}
-
-	if cfgMinRelayFeePerKb > cfgMaxRelayFeePerKb {
-		log.Fatal("MinRelayFeePerKb is larger than MaxRelayFeePerKb", "min", cfgMinRelayFeePerKb, "max", cfgMaxRelayFeePerKb)
-	}
-
-	log.Info("Init Btc extra", "MinRelayFee", cfgMinRelayFee, "MinRelayFeePerKb", cfgMinRelayFeePerKb, "MaxRelayFeePerKb", cfgMaxRelayFeePerKb, "PlusFeePercentage", cfgPlusFeePercentage)
-}
-
-func initAggregate(btcExtra *tokens.BtcExtraConfig) {
-	if btcExtra.UtxoAggregateMinCount > 0 {
-		cfgUtxoAggregateMinCount = btcExtra.UtxoAggregateMinCount
-	}
-
-	if btcExtra.UtxoAggregateMinValue > 0 {
-		cfgUtxoAggregateMinValue = btcExtra.UtxoAggregateMinValue
-	}
-
-	cfgUtxoAggregateToAddress = btcExtra.UtxoAggregateToAddress
-	if !btc.BridgeInstance.IsValidAddress(cfgUtxoAggregateToAddress) {
-		log.Fatal("wrong utxo aggregate to address", "toAddress", cfgUtxoAggregateToAddress)
-	}
-
-	log.Info("Init Btc extra", "UtxoAggregateMinCount", cfgUtxoAggregateMinCount, "UtxoAggregateMinValue", cfgUtxoAggregateMinValue, "UtxoAggregateToAddress", cfgUtxoAggregateToAddress)
-}
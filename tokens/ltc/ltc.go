@@ -0,0 +1,93 @@
+// Package ltc implements the litecoin bridge on the btc code path.
+package ltc
+
+import (
+	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcd/txscript"
+	"github.com/btcsuite/btcutil"
+)
+
+const (
+	netMainnet  = "mainnet"
+	netTestnet4 = "testnet4"
+)
+
+// MainNetParams litecoin mainnet params, ref. ltcd chaincfg
+var MainNetParams = chaincfg.Params{
+	Name:        netMainnet,
+	Net:         0xdbb6c0fb,
+	DefaultPort: "9333",
+
+	Bech32HRPSegwit: "ltc",
+
+	PubKeyHashAddrID:        0x30, // starts with L
+	ScriptHashAddrID:        0x32, // starts with M
+	PrivateKeyID:            0xB0, // starts with 6 (uncompressed) or T (compressed)
+	WitnessPubKeyHashAddrID: 0x06, // starts with p2
+	WitnessScriptHashAddrID: 0x0A, // starts with 7Xh
+
+	HDPrivateKeyID: [4]byte{0x04, 0x88, 0xad, 0xe4}, // starts with xprv
+	HDPublicKeyID:  [4]byte{0x04, 0x88, 0xb2, 0x1e}, // starts with xpub
+
+	HDCoinType: 2,
+}
+
+// TestNet4Params litecoin testnet4 params, ref. ltcd chaincfg
+var TestNet4Params = chaincfg.Params{
+	Name:        netTestnet4,
+	Net:         0xf1c8d2fd,
+	DefaultPort: "19335",
+
+	Bech32HRPSegwit: "tltc",
+
+	PubKeyHashAddrID:        0x6f, // starts with m or n
+	ScriptHashAddrID:        0x3a, // starts with Q
+	PrivateKeyID:            0xef, // starts with 9 (uncompressed) or c (compressed)
+	WitnessPubKeyHashAddrID: 0x52, // starts with QW
+	WitnessScriptHashAddrID: 0x31, // starts with T7n
+
+	HDPrivateKeyID: [4]byte{0x04, 0x35, 0x83, 0x94}, // starts with tprv
+	HDPublicKeyID:  [4]byte{0x04, 0x35, 0x87, 0xcf}, // starts with tpub
+
+	HDCoinType: 1,
+}
+
+// Descriptor ltc chain descriptor.
+// the ltc electrs gateway returns addresses in btc mainnet format,
+// so addresses are converted when calling the gateway api.
+var Descriptor = &btc.ChainDescriptor{
+	Name:   "Litecoin",
+	Symbol: "LTC",
+	Networks: map[string]*chaincfg.Params{
+		netMainnet:  &MainNetParams,
+		netTestnet4: &TestNet4Params,
+	},
+	DefaultNetwork: netMainnet,
+
+	APINetParams: &chaincfg.MainNetParams,
+
+	SigHashType: txscript.SigHashAll,
+
+	MaxAmount: 84e6 * btcutil.SatoshiPerBitcoin,
+
+	MinRelayFee:       400,
+	MaxMinRelayFee:    100000,
+	MinRelayFeePerKb:  2000,
+	MaxRelayFeePerKb:  500000,
+	EstimateFeeBlocks: 6,
+}
+
+func init() {
+	// register to decode bech32 segwit addresses of litecoin
+	for _, params := range []*chaincfg.Params{&MainNetParams, &TestNet4Params} {
+		if err := chaincfg.Register(params); err != nil {
+			panic(err)
+		}
+	}
+}
+
+// NewCrossChainBridge new ltc bridge
+func NewCrossChainBridge(isSrc bool) *btc.Bridge {
+	return btc.NewUtxoChainBridge(isSrc, Descriptor)
+}
@@ -7,6 +7,8 @@ import (
 	"io/ioutil"
 	"os"
 	"strings"
+	"sync"
+	"sync/atomic"
 
 	"github.com/BurntSushi/toml"
 	"github.com/anyswap/CrossChain-Bridge/common"
@@ -16,7 +18,12 @@ import (
 var (
 	tokenPairsConfigDirectory string
 
-	tokenPairsConfig map[string]*TokenPairConfig
+	// load pairs config from other source (eg. database) instead of pairs dir
+	tokenPairsLoader func() (map[string]*TokenPairConfig, error)
+
+	// copy on write, so readers need no lock
+	tokenPairsConfig atomic.Value // map[string]*TokenPairConfig
+	pairsConfigLock  sync.Mutex   // serialize modifications of pairs config
 )
 
 // TokenPairConfig pair config
@@ -52,17 +59,22 @@ func SetTokenPairsConfig(pairsConfig map[string]*TokenPairConfig, check bool) {
 			log.Fatalf("check token pairs config error: %v", err)
 		}
 	}
-	tokenPairsConfig = pairsConfig
+	tokenPairsConfig.Store(pairsConfig)
 }
 
-// GetTokenPairsConfig get token pairs config
+// GetTokenPairsConfig get token pairs config, it must not be modified
 func GetTokenPairsConfig() map[string]*TokenPairConfig {
-	return tokenPairsConfig
+	return getTokenPairsConfig()
+}
+
+func getTokenPairsConfig() map[string]*TokenPairConfig {
+	pairsConfig, _ := tokenPairsConfig.Load().(map[string]*TokenPairConfig)
+	return pairsConfig
 }
 
 // GetTokenPairConfig get token pair config
 func GetTokenPairConfig(pairID string) *TokenPairConfig {
-	pairCfg, exist := tokenPairsConfig[strings.ToLower(pairID)]
+	pairCfg, exist := getTokenPairsConfig()[strings.ToLower(pairID)]
 	if !exist {
 		log.Warn("GetTokenPairConfig: pairID not exist", "pairID", pairID)
 		return nil
@@ -72,14 +84,15 @@ func GetTokenPairConfig(pairID string) *TokenPairConfig {
 
 // IsTokenPairExist is token pair exist
 func IsTokenPairExist(pairID string) bool {
-	_, exist := tokenPairsConfig[strings.ToLower(pairID)]
+	_, exist := getTokenPairsConfig()[strings.ToLower(pairID)]
 	return exist
 }
 
 // GetAllPairIDs get all pairIDs
 func GetAllPairIDs() []string {
-	pairIDs := make([]string, 0, len(tokenPairsConfig))
-	for _, pairCfg := range tokenPairsConfig {
+	pairsConfig := getTokenPairsConfig()
+	pairIDs := make([]string, 0, len(pairsConfig))
+	for _, pairCfg := range pairsConfig {
 		pairIDs = append(pairIDs, strings.ToLower(pairCfg.PairID))
 	}
 	return pairIDs
@@ -87,7 +100,7 @@ func GetAllPairIDs() []string {
 
 // FindTokenConfig find by (tx to) address
 func FindTokenConfig(address string, isSrc bool) (configs []*TokenConfig, pairIDs []string) {
-	for _, pairCfg := range tokenPairsConfig {
+	for _, pairCfg := range getTokenPairsConfig() {
 		var tokenCfg *TokenConfig
 		if isSrc {
 			tokenCfg = pairCfg.SrcToken
@@ -112,7 +125,7 @@ func FindTokenConfig(address string, isSrc bool) (configs []*TokenConfig, pairID
 
 // GetTokenConfig get token config
 func GetTokenConfig(pairID string, isSrc bool) *TokenConfig {
-	pairCfg, exist := tokenPairsConfig[strings.ToLower(pairID)]
+	pairCfg, exist := getTokenPairsConfig()[strings.ToLower(pairID)]
 	if !exist {
 		log.Warn("GetTokenConfig: pairID not exist", "pairID", pairID)
 		return nil
@@ -123,9 +136,16 @@ func GetTokenConfig(pairID string, isSrc bool) *TokenConfig {
 	return pairCfg.DestToken
 }
 
+// IsSwapFeeNativeCoin is swap fee collected in native coin which also pays network fee,
+// swapin fee is collected on destination chain, swapout fee on source chain
+func IsSwapFeeNativeCoin(pairID string, isSwapin bool) bool {
+	tokenCfg := GetTokenConfig(pairID, !isSwapin)
+	return tokenCfg != nil && tokenCfg.ContractAddress == ""
+}
+
 // GetTokenConfigsByDirection get token configs by direction
 func GetTokenConfigsByDirection(pairID string, isSwapin bool) (fromTokenConfig, toTokenConfig *TokenConfig) {
-	pairCfg, exist := tokenPairsConfig[strings.ToLower(pairID)]
+	pairCfg, exist := getTokenPairsConfig()[strings.ToLower(pairID)]
 	if !exist {
 		log.Warn("GetTokenConfigs: pairID not exist", "pairID", pairID)
 		return nil, nil
@@ -165,21 +185,10 @@ func checkTokenPairsConfig(pairsConfig map[string]*TokenPairConfig) (err error)
 		}
 		dstContractsMap[dstContract] = struct{}{}
 		// check config
-		err = tokenPair.CheckConfig()
-		if err != nil {
-			return err
-		}
-		err = SrcBridge.VerifyTokenConfig(tokenPair.SrcToken)
+		err = checkTokenPairConfig(tokenPair)
 		if err != nil {
 			return err
 		}
-		err = DstBridge.VerifyTokenConfig(tokenPair.DestToken)
-		if err != nil {
-			return err
-		}
-		if *tokenPair.SrcToken.Decimals != *tokenPair.DestToken.Decimals {
-			return fmt.Errorf("decimals of pair are not equal, src %v, dest %v", *tokenPair.SrcToken.Decimals, *tokenPair.DestToken.Decimals)
-		}
 	}
 	if nonContractSrcCount > 1 {
 		return fmt.Errorf("only support one non-contract token swapin")
@@ -187,6 +196,25 @@ func checkTokenPairsConfig(pairsConfig map[string]*TokenPairConfig) (err error)
 	return nil
 }
 
+func checkTokenPairConfig(tokenPair *TokenPairConfig) (err error) {
+	err = tokenPair.CheckConfig()
+	if err != nil {
+		return err
+	}
+	err = SrcBridge.VerifyTokenConfig(tokenPair.SrcToken)
+	if err != nil {
+		return err
+	}
+	err = DstBridge.VerifyTokenConfig(tokenPair.DestToken)
+	if err != nil {
+		return err
+	}
+	if *tokenPair.SrcToken.Decimals != *tokenPair.DestToken.Decimals {
+		return fmt.Errorf("decimals of pair are not equal, src %v, dest %v", *tokenPair.SrcToken.Decimals, *tokenPair.DestToken.Decimals)
+	}
+	return nil
+}
+
 // CheckConfig check token pair config
 func (c *TokenPairConfig) CheckConfig() (err error) {
 	if c.PairID == "" {
@@ -209,15 +237,37 @@ func (c *TokenPairConfig) CheckConfig() (err error) {
 	return nil
 }
 
+// SetTokenPairsLoader load token pairs config by loader instead of from pairs dir
+func SetTokenPairsLoader(loader func() (map[string]*TokenPairConfig, error)) {
+	tokenPairsLoader = loader
+}
+
 // LoadTokenPairsConfig load token pairs config
 func LoadTokenPairsConfig(check bool) {
-	pairsConfig, err := LoadTokenPairsConfigInDir(tokenPairsConfigDirectory, check)
+	pairsConfig, err := loadTokenPairsConfigs(check)
 	if err != nil {
 		log.Fatal("load token pair config error", "err", err)
 	}
 	SetTokenPairsConfig(pairsConfig, false)
 }
 
+func loadTokenPairsConfigs(check bool) (map[string]*TokenPairConfig, error) {
+	if tokenPairsLoader == nil {
+		return LoadTokenPairsConfigInDir(tokenPairsConfigDirectory, check)
+	}
+	pairsConfig, err := tokenPairsLoader()
+	if err != nil {
+		return nil, err
+	}
+	if check {
+		err = checkTokenPairsConfig(pairsConfig)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return pairsConfig, nil
+}
+
 // LoadTokenPairsConfigInDir load token pairs config
 func LoadTokenPairsConfigInDir(dir string, check bool) (map[string]*TokenPairConfig, error) {
 	fileInfoList, err := ioutil.ReadDir(dir)
@@ -262,6 +312,22 @@ func loadTokenPairConfig(configFile string) (config *TokenPairConfig, err error)
 	if _, err := toml.DecodeFile(configFile, &config); err != nil {
 		return nil, fmt.Errorf("toml decode file error: %v", err)
 	}
+	logTokenPairConfig(config)
+	log.Println("finish load token pair config file", configFile)
+	return config, nil
+}
+
+// ParseTokenPairConfig parse token pair config of toml content
+func ParseTokenPairConfig(content string) (config *TokenPairConfig, err error) {
+	config = &TokenPairConfig{}
+	if _, err := toml.Decode(content, &config); err != nil {
+		return nil, fmt.Errorf("toml decode error: %v", err)
+	}
+	logTokenPairConfig(config)
+	return config, nil
+}
+
+func logTokenPairConfig(config *TokenPairConfig) {
 	var bs []byte
 	if log.JSONFormat {
 		bs, _ = json.Marshal(config)
@@ -269,12 +335,13 @@ func loadTokenPairConfig(configFile string) (config *TokenPairConfig, err error)
 		bs, _ = json.MarshalIndent(config, "", "  ")
 	}
 	log.Tracef("load token pair finished. %v", string(bs))
-	log.Println("finish load token pair config file", configFile)
-	return config, nil
 }
 
 // AddPairConfig add pair config dynamically
 func AddPairConfig(configFile string) (pairConfig *TokenPairConfig, err error) {
+	pairsConfigLock.Lock()
+	defer pairsConfigLock.Unlock()
+
 	pairConfig, err = loadTokenPairConfig(configFile)
 	if err != nil {
 		return nil, err
@@ -283,27 +350,21 @@ func AddPairConfig(configFile string) (pairConfig *TokenPairConfig, err error) {
 	if err != nil {
 		return nil, err
 	}
+	pairsConfig := copyTokenPairsConfig()
 	// use all small case to identify
-	tokenPairsConfig[strings.ToLower(pairConfig.PairID)] = pairConfig
+	pairsConfig[strings.ToLower(pairConfig.PairID)] = pairConfig
+	tokenPairsConfig.Store(pairsConfig)
 	log.Info("add pair config success", "pairID", pairConfig.PairID, "configFile", configFile)
 	return pairConfig, nil
 }
 
 func checkAddTokenPairsConfig(pairConfig *TokenPairConfig) (err error) {
-	err = pairConfig.CheckConfig()
-	if err != nil {
-		return err
-	}
-	err = SrcBridge.VerifyTokenConfig(pairConfig.SrcToken)
-	if err != nil {
-		return err
-	}
-	err = DstBridge.VerifyTokenConfig(pairConfig.DestToken)
+	err = checkTokenPairConfig(pairConfig)
 	if err != nil {
 		return err
 	}
 	pairID := strings.ToLower(pairConfig.PairID)
-	if _, exist := tokenPairsConfig[pairID]; exist {
+	if _, exist := getTokenPairsConfig()[pairID]; exist {
 		return fmt.Errorf("pairID '%v' already exist", pairID)
 	}
 	srcContract := strings.ToLower(pairConfig.SrcToken.ContractAddress)
@@ -311,7 +372,7 @@ func checkAddTokenPairsConfig(pairConfig *TokenPairConfig) (err error) {
 		return fmt.Errorf("source contract address is empty")
 	}
 	dstContract := strings.ToLower(pairConfig.DestToken.ContractAddress)
-	for _, tokenPair := range tokenPairsConfig {
+	for _, tokenPair := range getTokenPairsConfig() {
 		if strings.EqualFold(srcContract, tokenPair.SrcToken.ContractAddress) {
 			return fmt.Errorf("source contract '%v' already exist", srcContract)
 		}
@@ -0,0 +1,74 @@
+package tokens
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+)
+
+// PairsConfigHash hash of token pairs config, to verify all nodes use the same config
+type PairsConfigHash struct {
+	Hash  string            `json:"hash"`
+	Pairs map[string]string `json:"pairs"` // pairID to hash of pair config
+}
+
+// Hash hash of pair config, calced from its json encoding,
+// so local secrets (eg. keystore) and calced values are not included.
+func (c *TokenPairConfig) Hash() common.Hash {
+	pair := *c
+	if c.SrcToken != nil {
+		srcToken := *c.SrcToken
+		srcToken.FeeSchedule = nil
+		pair.SrcToken = &srcToken
+	}
+	if c.DestToken != nil {
+		destToken := *c.DestToken
+		destToken.FeeSchedule = nil
+		pair.DestToken = &destToken
+	}
+	data, _ := json.Marshal(&pair)
+	return common.Keccak256Hash(data)
+}
+
+// GetPairsConfigHash get hash of current pairs config
+func GetPairsConfigHash() *PairsConfigHash {
+	return CalcPairsConfigHash(GetTokenPairsConfig())
+}
+
+// CalcPairsConfigHash calc hash of pairs config
+func CalcPairsConfigHash(pairsConfig map[string]*TokenPairConfig) *PairsConfigHash {
+	result := &PairsConfigHash{Pairs: make(map[string]string, len(pairsConfig))}
+	pairIDs := make([]string, 0, len(pairsConfig))
+	for _, pairCfg := range pairsConfig {
+		pairID := strings.ToLower(pairCfg.PairID)
+		result.Pairs[pairID] = pairCfg.Hash().String()
+		pairIDs = append(pairIDs, pairID)
+	}
+	sort.Strings(pairIDs)
+	var sb strings.Builder
+	for _, pairID := range pairIDs {
+		fmt.Fprintf(&sb, "%v=%v\n", pairID, result.Pairs[pairID])
+	}
+	result.Hash = common.Keccak256Hash([]byte(sb.String())).String()
+	return result
+}
+
+// DiffPairsConfigHash get pairIDs whose config hash are different
+// (including missing on either side), sorted by pairID
+func DiffPairsConfigHash(local, remote *PairsConfigHash) (pairIDs []string) {
+	for pairID, hash := range local.Pairs {
+		if remote.Pairs[pairID] != hash {
+			pairIDs = append(pairIDs, pairID)
+		}
+	}
+	for pairID := range remote.Pairs {
+		if _, exist := local.Pairs[pairID]; !exist {
+			pairIDs = append(pairIDs, pairID)
+		}
+	}
+	sort.Strings(pairIDs)
+	return pairIDs
+}
@@ -0,0 +1,25 @@
+package tokens
+
+import (
+	"testing"
+)
+
+func TestPairsConfigHash(t *testing.T) {
+	pair := newTestPair("test", 0.001)
+	hash := pair.Hash()
+
+	pair.SrcToken.FeeSchedule = &SwapFeeSchedule{SwapFeeRate: 0.001}
+	pair.SrcToken.DcrmAddressKeyStore = "/path/to/keystore"
+	if pair.Hash() != hash {
+		t.Error("hash should not include fee schedule and secrets")
+	}
+
+	local := CalcPairsConfigHash(map[string]*TokenPairConfig{"test": pair})
+	remote := CalcPairsConfigHash(map[string]*TokenPairConfig{"test": newTestPair("test", 0.002)})
+	if local.Hash == remote.Hash {
+		t.Error("hash of different pairs config should be different")
+	}
+	if diffs := DiffPairsConfigHash(local, remote); len(diffs) != 1 || diffs[0] != "test" {
+		t.Errorf("wrong mismatched pairs %v", diffs)
+	}
+}
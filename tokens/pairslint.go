@@ -0,0 +1,116 @@
+package tokens
+
+import (
+	"fmt"
+	"io/ioutil"
+	"sort"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+)
+
+// PairConfigIssue problem found when checking token pairs config
+type PairConfigIssue struct {
+	File    string `json:"file,omitempty"`
+	PairID  string `json:"pairid,omitempty"`
+	Message string `json:"message"`
+}
+
+// PairsConfigChecker check token pairs config without retrying or exiting.
+// token config is checked by bridge of the endpoint if its checker is not nil,
+// and the gateway of the endpoint is queried if online.
+type PairsConfigChecker struct {
+	SrcChecker ConfigChecker
+	DstChecker ConfigChecker
+	SrcOnline  bool
+	DstOnline  bool
+
+	issues []*PairConfigIssue
+}
+
+func (c *PairsConfigChecker) addIssue(file, pairID, format string, args ...interface{}) {
+	c.issues = append(c.issues, &PairConfigIssue{
+		File:    file,
+		PairID:  pairID,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// CheckDir check every token pair config file in dir, return all the issues
+// (instead of the first one) and the successfully loaded pairs config
+func (c *PairsConfigChecker) CheckDir(dir string) (pairsConfig map[string]*TokenPairConfig, issues []*PairConfigIssue) {
+	c.issues = nil
+	fileInfoList, err := ioutil.ReadDir(dir)
+	if err != nil {
+		c.addIssue("", "", "read token pairs dir failed: %v", err)
+		return nil, c.issues
+	}
+	pairsConfig = make(map[string]*TokenPairConfig)
+	pairFiles := make(map[string]string)
+	for _, info := range fileInfoList {
+		fileName := info.Name()
+		if info.IsDir() || !strings.HasSuffix(fileName, ".toml") {
+			continue
+		}
+		pairCfg, errf := loadTokenPairConfig(common.AbsolutePath(dir, fileName))
+		if errf != nil {
+			c.addIssue(fileName, "", "%v", errf)
+			continue
+		}
+		if pairCfg.PairID == "" {
+			c.addIssue(fileName, "", "tokenPair must config nonempty 'PairID'")
+			continue
+		}
+		pairID := strings.ToLower(pairCfg.PairID)
+		if other, exist := pairFiles[pairID]; exist {
+			c.addIssue(fileName, pairCfg.PairID, "duplicate pairID, also in file %v", other)
+			continue
+		}
+		pairFiles[pairID] = fileName
+		if c.checkPairConfig(fileName, pairCfg) {
+			pairsConfig[pairID] = pairCfg
+		}
+	}
+	if err = checkPairsUniqueness(pairsConfig); err != nil {
+		c.addIssue("", "", "%v", err)
+	}
+	sort.SliceStable(c.issues, func(i, j int) bool { return c.issues[i].File < c.issues[j].File })
+	return pairsConfig, c.issues
+}
+
+func (c *PairsConfigChecker) checkPairConfig(fileName string, pairCfg *TokenPairConfig) (ok bool) {
+	pairID := pairCfg.PairID
+	issuesCount := len(c.issues)
+	endpoints := []struct {
+		name    string
+		isSrc   bool
+		cfg     *TokenConfig
+		checker ConfigChecker
+		online  bool
+	}{
+		{"SrcToken", true, pairCfg.SrcToken, c.SrcChecker, c.SrcOnline},
+		{"DestToken", false, pairCfg.DestToken, c.DstChecker, c.DstOnline},
+	}
+	for _, token := range endpoints {
+		if token.cfg == nil {
+			c.addIssue(fileName, pairID, "tokenPair must config '%v'", token.name)
+			continue
+		}
+		if err := token.cfg.CheckConfig(token.isSrc); err != nil {
+			c.addIssue(fileName, pairID, "%v: %v", token.name, err)
+			continue
+		}
+		if token.checker == nil {
+			continue
+		}
+		if err := token.checker.CheckTokenConfig(token.cfg, token.online); err != nil {
+			c.addIssue(fileName, pairID, "%v: %v", token.name, err)
+		}
+	}
+	if len(c.issues) == issuesCount &&
+		*pairCfg.SrcToken.Decimals != *pairCfg.DestToken.Decimals {
+		c.addIssue(fileName, pairID, "decimals of pair are not equal, src %v, dest %v",
+			*pairCfg.SrcToken.Decimals, *pairCfg.DestToken.Decimals)
+	}
+	return len(c.issues) == issuesCount
+}
@@ -0,0 +1,53 @@
+package tokens
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestPairsConfigCheckerCheckDir(t *testing.T) {
+	dir, err := ioutil.TempDir("", "pairslint")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	files := map[string]string{
+		"a.toml":     "PairID = \"ETH\"\n[SrcToken]\n[DestToken]\n",
+		"b.toml":     "PairID = \"eth\"\n[SrcToken]\n[DestToken]\n",
+		"c.toml":     "PairID = \n",
+		"d.toml":     "[SrcToken]\n",
+		"readme.txt": "not a pair config",
+	}
+	for name, content := range files {
+		if err = ioutil.WriteFile(filepath.Join(dir, name), []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	checker := &PairsConfigChecker{}
+	pairsConfig, issues := checker.CheckDir(dir)
+	if len(pairsConfig) != 0 {
+		t.Errorf("no pair should pass the checking, have %v", len(pairsConfig))
+	}
+	want := map[string]string{
+		"a.toml": "token must config 'Decimals'",
+		"b.toml": "duplicate pairID, also in file a.toml",
+		"c.toml": "toml decode file error",
+		"d.toml": "must config nonempty 'PairID'",
+	}
+	found := make(map[string]bool)
+	for _, issue := range issues {
+		if wantMsg, exist := want[issue.File]; exist && strings.Contains(issue.Message, wantMsg) {
+			found[issue.File] = true
+		}
+	}
+	for file, wantMsg := range want {
+		if !found[file] {
+			t.Errorf("missing issue of %v '%v', issues %v", file, wantMsg, issues)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package tokens
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+// pair config change actions
+const (
+	PairConfigAdd    = "add"
+	PairConfigUpdate = "update"
+	PairConfigRemove = "remove"
+)
+
+// PairConfigChange change of pair config
+type PairConfigChange struct {
+	PairID string   `json:"pairid"`
+	Action string   `json:"action"`
+	Diffs  []string `json:"diffs,omitempty"`
+}
+
+// String format change of pair config
+func (c *PairConfigChange) String() string {
+	if len(c.Diffs) == 0 {
+		return fmt.Sprintf("%v %v", c.Action, c.PairID)
+	}
+	return fmt.Sprintf("%v %v: %v", c.Action, c.PairID, strings.Join(c.Diffs, ", "))
+}
+
+func copyTokenPairsConfig() map[string]*TokenPairConfig {
+	oldPairsConfig := getTokenPairsConfig()
+	pairsConfig := make(map[string]*TokenPairConfig, len(oldPairsConfig)+1)
+	for pairID, pairCfg := range oldPairsConfig {
+		pairsConfig[pairID] = pairCfg
+	}
+	return pairsConfig
+}
+
+// ReloadPairsConfig reload pairs config in token pairs dir (or by loader),
+// new pairs are added, changed pairs are updated in place, missing pairs are removed.
+// all changes are validated before applied, and nothing is applied if dryRun.
+func ReloadPairsConfig(dryRun bool) (changes []*PairConfigChange, err error) {
+	pairsConfigLock.Lock()
+	defer pairsConfigLock.Unlock()
+
+	newPairsConfig, err := loadTokenPairsConfigs(false)
+	if err != nil {
+		return nil, err
+	}
+	oldPairsConfig := getTokenPairsConfig()
+	changes = DiffPairsConfig(oldPairsConfig, newPairsConfig)
+	err = checkReloadPairsConfig(oldPairsConfig, newPairsConfig, changes)
+	if err != nil {
+		return changes, err
+	}
+	if dryRun || len(changes) == 0 {
+		return changes, nil
+	}
+
+	changed := make(map[string]struct{}, len(changes))
+	for _, change := range changes {
+		changed[change.PairID] = struct{}{}
+		clearDynamicMinSwapFee(change.PairID)
+		log.Info("reload pair config", "change", change.String())
+	}
+	// keep unchanged ones, as they may have loaded states (eg. signer)
+	for pairID, pairCfg := range oldPairsConfig {
+		if _, exist := changed[pairID]; !exist {
+			newPairsConfig[pairID] = pairCfg
+		}
+	}
+	tokenPairsConfig.Store(newPairsConfig)
+	return changes, nil
+}
+
+func checkReloadPairsConfig(oldPairsConfig, newPairsConfig map[string]*TokenPairConfig, changes []*PairConfigChange) (err error) {
+	for _, change := range changes {
+		newPair := newPairsConfig[change.PairID]
+		switch change.Action {
+		case PairConfigAdd:
+			if newPair.SrcToken == nil || newPair.SrcToken.ContractAddress == "" {
+				return fmt.Errorf("add pair %v: source contract address is empty", change.PairID)
+			}
+		case PairConfigUpdate:
+			err = checkUpdatePairConfig(oldPairsConfig[change.PairID], newPair)
+		default:
+			continue
+		}
+		if err == nil {
+			err = checkTokenPairConfig(newPair)
+		}
+		if err != nil {
+			return fmt.Errorf("%v pair %v: %w", change.Action, change.PairID, err)
+		}
+	}
+	return checkPairsUniqueness(newPairsConfig)
+}
+
+// checkPairsUniqueness check contracts are not shared between pairs
+func checkPairsUniqueness(pairsConfig map[string]*TokenPairConfig) error {
+	srcContractsMap := make(map[string]string)
+	dstContractsMap := make(map[string]string)
+	nonContractSrcCount := 0
+	for pairID, tokenPair := range pairsConfig {
+		if tokenPair.SrcToken == nil || tokenPair.DestToken == nil {
+			continue
+		}
+		srcContract := strings.ToLower(tokenPair.SrcToken.ContractAddress)
+		if srcContract == "" {
+			nonContractSrcCount++
+		} else if other, exist := srcContractsMap[srcContract]; exist {
+			return fmt.Errorf("duplicate source contract '%v' of pair %v and %v", srcContract, other, pairID)
+		}
+		srcContractsMap[srcContract] = pairID
+		dstContract := strings.ToLower(tokenPair.DestToken.ContractAddress)
+		if other, exist := dstContractsMap[dstContract]; exist {
+			return fmt.Errorf("duplicate destination contract '%v' of pair %v and %v", dstContract, other, pairID)
+		}
+		dstContractsMap[dstContract] = pairID
+	}
+	if nonContractSrcCount > 1 {
+		return fmt.Errorf("only support one non-contract token swapin")
+	}
+	return nil
+}
+
+// checkUpdatePairConfig identity of token can not be updated in place,
+// remove and add the pair instead.
+func checkUpdatePairConfig(oldPair, newPair *TokenPairConfig) error {
+	if newPair.SrcToken == nil || newPair.DestToken == nil {
+		return fmt.Errorf("must config both 'SrcToken' and 'DestToken'")
+	}
+	if err := checkUpdateTokenConfig(oldPair.SrcToken, newPair.SrcToken); err != nil {
+		return fmt.Errorf("SrcToken: %w", err)
+	}
+	if err := checkUpdateTokenConfig(oldPair.DestToken, newPair.DestToken); err != nil {
+		return fmt.Errorf("DestToken: %w", err)
+	}
+	return nil
+}
+
+func checkUpdateTokenConfig(oldToken, newToken *TokenConfig) error {
+	identities := []struct {
+		name     string
+		old, new interface{}
+	}{
+		{"ID", oldToken.ID, newToken.ID},
+		{"Decimals", oldToken.Decimals, newToken.Decimals},
+		{"ContractAddress", strings.ToLower(oldToken.ContractAddress), strings.ToLower(newToken.ContractAddress)},
+		{"DepositAddress", oldToken.DepositAddress, newToken.DepositAddress},
+		{"DcrmAddress", strings.ToLower(oldToken.DcrmAddress), strings.ToLower(newToken.DcrmAddress)},
+		{"DcrmPubkey", oldToken.DcrmPubkey, newToken.DcrmPubkey},
+		{"IsLockRelease", oldToken.IsLockRelease, newToken.IsLockRelease},
+		{"IsDelegateContract", oldToken.IsDelegateContract, newToken.IsDelegateContract},
+		{"Unit", oldToken.Unit, newToken.Unit},
+	}
+	for _, item := range identities {
+		if !reflect.DeepEqual(item.old, item.new) {
+			return fmt.Errorf("'%v' can not be updated in place", item.name)
+		}
+	}
+	return nil
+}
+
+// DiffPairsConfig diff pairs config, changes are sorted by pairID
+func DiffPairsConfig(oldPairsConfig, newPairsConfig map[string]*TokenPairConfig) (changes []*PairConfigChange) {
+	return diffPairsConfig(oldPairsConfig, newPairsConfig, true)
+}
+
+// DiffPublicPairsConfig diff pairs config without secret fields (eg. config got from api)
+func DiffPublicPairsConfig(oldPairsConfig, newPairsConfig map[string]*TokenPairConfig) (changes []*PairConfigChange) {
+	return diffPairsConfig(oldPairsConfig, newPairsConfig, false)
+}
+
+func diffPairsConfig(oldPairsConfig, newPairsConfig map[string]*TokenPairConfig, withSecrets bool) (changes []*PairConfigChange) {
+	for pairID, newPair := range newPairsConfig {
+		oldPair, exist := oldPairsConfig[pairID]
+		if !exist {
+			changes = append(changes, &PairConfigChange{PairID: pairID, Action: PairConfigAdd})
+			continue
+		}
+		diffs := diffTokenConfig("SrcToken", oldPair.SrcToken, newPair.SrcToken, withSecrets)
+		diffs = append(diffs, diffTokenConfig("DestToken", oldPair.DestToken, newPair.DestToken, withSecrets)...)
+		if len(diffs) > 0 {
+			changes = append(changes, &PairConfigChange{PairID: pairID, Action: PairConfigUpdate, Diffs: diffs})
+		}
+	}
+	for pairID := range oldPairsConfig {
+		if _, exist := newPairsConfig[pairID]; !exist {
+			changes = append(changes, &PairConfigChange{PairID: pairID, Action: PairConfigRemove})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].PairID < changes[j].PairID })
+	return changes
+}
+
+// diffTokenConfig diff json fields, and secret fields without showing values
+func diffTokenConfig(prefix string, oldToken, newToken *TokenConfig, withSecrets bool) (diffs []string) {
+	oldFields := toJSONFields(oldToken)
+	newFields := toJSONFields(newToken)
+	keys := make([]string, 0, len(oldFields)+len(newFields))
+	for key := range oldFields {
+		keys = append(keys, key)
+	}
+	for key := range newFields {
+		if _, exist := oldFields[key]; !exist {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		oldVal, newVal := oldFields[key], newFields[key]
+		if !reflect.DeepEqual(oldVal, newVal) {
+			diffs = append(diffs, fmt.Sprintf("%v.%v: %v -> %v", prefix, key, formatJSONField(oldVal), formatJSONField(newVal)))
+		}
+	}
+	if !withSecrets || oldToken == nil || newToken == nil {
+		return diffs
+	}
+	secrets := []struct {
+		name     string
+		old, new interface{}
+	}{
+		{"DcrmPubkey", oldToken.DcrmPubkey, newToken.DcrmPubkey},
+		{"DcrmAddressKeyStore", oldToken.DcrmAddressKeyStore, newToken.DcrmAddressKeyStore},
+		{"DcrmAddressPassword", oldToken.DcrmAddressPassword, newToken.DcrmAddressPassword},
+		{"DcrmAddressKeyFile", oldToken.DcrmAddressKeyFile, newToken.DcrmAddressKeyFile},
+		{"DcrmAddressSigner", oldToken.DcrmAddressSigner, newToken.DcrmAddressSigner},
+	}
+	for _, item := range secrets {
+		if !reflect.DeepEqual(item.old, item.new) {
+			diffs = append(diffs, fmt.Sprintf("%v.%v: changed", prefix, item.name))
+		}
+	}
+	return diffs
+}
+
+func toJSONFields(token *TokenConfig) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if token == nil {
+		return fields
+	}
+	data, err := json.Marshal(token)
+	if err == nil {
+		_ = json.Unmarshal(data, &fields)
+	}
+	return fields
+}
+
+func formatJSONField(val interface{}) string {
+	if val == nil {
+		return "<nil>"
+	}
+	data, err := json.Marshal(val)
+	if err != nil {
+		return fmt.Sprintf("%v", val)
+	}
+	return string(data)
+}
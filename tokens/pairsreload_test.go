@@ -0,0 +1,79 @@
+package tokens
+
+import (
+	"testing"
+)
+
+func newTestPair(pairID string, feeRate float64) *TokenPairConfig {
+	return &TokenPairConfig{
+		PairID:    pairID,
+		SrcToken:  newTestFeeToken(feeRate, 0.0001, 0.01),
+		DestToken: newTestFeeToken(0, 0, 0),
+	}
+}
+
+func TestDiffPairsConfig(t *testing.T) {
+	oldPairs := map[string]*TokenPairConfig{
+		"keep":   newTestPair("keep", 0.001),
+		"update": newTestPair("update", 0.001),
+		"remove": newTestPair("remove", 0.001),
+	}
+	newPairs := map[string]*TokenPairConfig{
+		"keep":   newTestPair("keep", 0.001),
+		"update": newTestPair("update", 0.002),
+		"add":    newTestPair("add", 0.001),
+	}
+	changes := DiffPairsConfig(oldPairs, newPairs)
+	want := []string{
+		"add add",
+		"remove remove",
+		"update update: SrcToken.SwapFeeRate: 0.001 -> 0.002",
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("wrong number of changes, have %v want %v", changes, want)
+	}
+	for i, change := range changes {
+		if change.String() != want[i] {
+			t.Errorf("change %v: have '%v' want '%v'", i, change, want[i])
+		}
+	}
+}
+
+func TestCheckUpdatePairConfig(t *testing.T) {
+	oldPair := newTestPair("test", 0.001)
+	newPair := newTestPair("test", 0.002)
+	if err := checkUpdatePairConfig(oldPair, newPair); err != nil {
+		t.Errorf("update fee rate should be allowed, err %v", err)
+	}
+	newPair.DestToken.DcrmAddress = "0x0000000000000000000000000000000000000001"
+	if err := checkUpdatePairConfig(oldPair, newPair); err == nil {
+		t.Error("update dcrm address in place should fail")
+	}
+}
+
+// run with -race, pairs config is replaced when readers are accessing it
+func TestReplacePairsConfigConcurrently(t *testing.T) {
+	defer SetTokenPairsConfig(nil, false)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 100; i++ {
+			SetTokenPairsConfig(map[string]*TokenPairConfig{
+				"test": newTestPair("test", 0.001),
+			}, false)
+		}
+	}()
+	for {
+		select {
+		case <-done:
+			if len(GetAllPairIDs()) != 1 || GetTokenConfig("test", true) == nil {
+				t.Fatal("pairs config is not replaced")
+			}
+			return
+		default:
+			_ = GetAllPairIDs()
+			_ = IsTokenPairExist("test")
+		}
+	}
+}
@@ -0,0 +1,168 @@
+package tokens
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+)
+
+// price feed types
+const (
+	PriceFeedStatic = "static"
+	PriceFeedFile   = "file"
+)
+
+var (
+	priceFeed PriceFeed
+
+	errNoPriceFeed = errors.New("no price feed is configed")
+)
+
+// PriceFeed provides prices of coins in the same quote currency (eg. USD)
+type PriceFeed interface {
+	GetPrice(symbol string) (float64, error)
+}
+
+// PriceFeedConfig price feed config
+type PriceFeedConfig struct {
+	Type   string             // static or file
+	Prices map[string]float64 `toml:",omitempty" json:",omitempty"` // symbol to price of static price feed
+	File   string             `toml:",omitempty" json:",omitempty"` // json file of symbol to price, reloaded when modified
+}
+
+// CheckConfig check price feed config
+func (c *PriceFeedConfig) CheckConfig() error {
+	switch c.Type {
+	case PriceFeedStatic:
+		if len(c.Prices) == 0 {
+			return errors.New("static price feed must config 'Prices'")
+		}
+		for symbol, price := range c.Prices {
+			if price <= 0 {
+				return fmt.Errorf("static price feed has wrong price %v of %v", price, symbol)
+			}
+		}
+	case PriceFeedFile:
+		if c.File == "" {
+			return errors.New("file price feed must config 'File'")
+		}
+	default:
+		return fmt.Errorf("unknown price feed type '%v'", c.Type)
+	}
+	return nil
+}
+
+// InitPriceFeed init price feed used by fee policy
+func InitPriceFeed(config *PriceFeedConfig) error {
+	if config == nil {
+		return nil
+	}
+	if err := config.CheckConfig(); err != nil {
+		return err
+	}
+	switch config.Type {
+	case PriceFeedStatic:
+		SetPriceFeed(NewStaticPriceFeed(config.Prices))
+	case PriceFeedFile:
+		feed := NewFilePriceFeed(config.File)
+		if err := feed.reload(); err != nil {
+			return err
+		}
+		SetPriceFeed(feed)
+	}
+	return nil
+}
+
+// SetPriceFeed set price feed
+func SetPriceFeed(feed PriceFeed) {
+	priceFeed = feed
+}
+
+// GetPriceFeed get price feed
+func GetPriceFeed() PriceFeed {
+	return priceFeed
+}
+
+// StaticPriceFeed price feed with fixed prices
+type StaticPriceFeed struct {
+	prices map[string]float64
+}
+
+// NewStaticPriceFeed new static price feed
+func NewStaticPriceFeed(prices map[string]float64) *StaticPriceFeed {
+	feed := &StaticPriceFeed{prices: make(map[string]float64, len(prices))}
+	for symbol, price := range prices {
+		feed.prices[strings.ToUpper(symbol)] = price
+	}
+	return feed
+}
+
+// GetPrice impl PriceFeed interface
+func (f *StaticPriceFeed) GetPrice(symbol string) (float64, error) {
+	return getPriceFromMap(f.prices, symbol)
+}
+
+// FilePriceFeed price feed of json file which maps symbol to price,
+// the file is reloaded when modified, so it can be updated by external tools
+type FilePriceFeed struct {
+	file string
+
+	lock    sync.RWMutex
+	modTime int64
+	prices  map[string]float64
+}
+
+// NewFilePriceFeed new file price feed
+func NewFilePriceFeed(file string) *FilePriceFeed {
+	return &FilePriceFeed{file: file}
+}
+
+// GetPrice impl PriceFeed interface
+func (f *FilePriceFeed) GetPrice(symbol string) (float64, error) {
+	if err := f.reload(); err != nil {
+		return 0, err
+	}
+	f.lock.RLock()
+	defer f.lock.RUnlock()
+	return getPriceFromMap(f.prices, symbol)
+}
+
+func (f *FilePriceFeed) reload() error {
+	info, err := os.Stat(f.file)
+	if err != nil {
+		return err
+	}
+	modTime := info.ModTime().UnixNano()
+	f.lock.RLock()
+	unchanged := f.prices != nil && f.modTime == modTime
+	f.lock.RUnlock()
+	if unchanged {
+		return nil
+	}
+
+	data, err := ioutil.ReadFile(f.file)
+	if err != nil {
+		return err
+	}
+	var prices map[string]float64
+	if err = json.Unmarshal(data, &prices); err != nil {
+		return fmt.Errorf("wrong price feed file %v, %w", f.file, err)
+	}
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.prices = NewStaticPriceFeed(prices).prices
+	f.modTime = modTime
+	return nil
+}
+
+func getPriceFromMap(prices map[string]float64, symbol string) (float64, error) {
+	price, exist := prices[strings.ToUpper(symbol)]
+	if !exist || price <= 0 {
+		return 0, fmt.Errorf("no price of %v", symbol)
+	}
+	return price, nil
+}
@@ -51,7 +51,9 @@ func (b *Bridge) BeforeConfig() {
 	b.SupportedCoins["EUR"] = cosmos.CosmosCoin{"ueur", 6}
 	b.SupportedCoins["GBP"] = cosmos.CosmosCoin{"ugbp", 6}
 	b.SupportedCoins["UMNT"] = cosmos.CosmosCoin{"umnt", 6}*/
-	tokens.IsSwapoutToStringAddress = true
+	if b.IsSrc {
+		tokens.IsSwapoutToStringAddress = true
+	}
 }
 
 // AfterConfig run after loading bridge and token config
@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/dcrm"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+)
+
+// ScanRange is a range of blocks quick synced by one worker.
+// blocks in [Start, Next) are scanned, and [Next, End) are pending.
+// progress is saved in database by swap server, and in the scan state
+// file by oracle, and the pending ranges are resumed when restart,
+// so no block is skipped however far behind the scanner is.
+// if progress can not be saved, latest scan height is not advanced
+// past the unfinished ranges, so they are scanned again after restart.
+type ScanRange struct {
+	Key   string
+	Start uint64
+	End   uint64
+	Next  uint64
+
+	isSrc bool
+}
+
+var (
+	// unfinished ranges in process, Next of ranges is guarded by the lock
+	activeScanRanges     = make(map[*ScanRange]struct{})
+	activeScanRangesLock sync.Mutex
+)
+
+func isScanRangeInDB() bool {
+	return dcrm.IsSwapServer() && mongodb.HasSession()
+}
+
+func isScanRangePersistent() bool {
+	return isScanRangeInDB() || localScanState != nil
+}
+
+func addActiveScanRange(r *ScanRange) {
+	activeScanRangesLock.Lock()
+	activeScanRanges[r] = struct{}{}
+	activeScanRangesLock.Unlock()
+}
+
+// limitLatestScanHeight limit height to the lowest pending height of unfinished ranges
+func limitLatestScanHeight(isSrc bool, height uint64) uint64 {
+	activeScanRangesLock.Lock()
+	defer activeScanRangesLock.Unlock()
+	for r := range activeScanRanges {
+		if r.isSrc == isSrc && r.Next < height {
+			height = r.Next
+		}
+	}
+	return height
+}
+
+// NewScanRanges split blocks [start, end) into ranges for `workers` workers
+func NewScanRanges(isSrc bool, start, end, workers uint64) []*ScanRange {
+	if start >= end {
+		return nil
+	}
+	count := end - start
+	if workers == 0 || count < 10 {
+		workers = 1
+	}
+	step := count / workers
+	ranges := make([]*ScanRange, 0, workers)
+	for i := uint64(0); i < workers; i++ {
+		wstt := start + i*step
+		wend := start + (i+1)*step
+		if i+1 == workers {
+			wend = end
+		}
+		r := &ScanRange{Start: wstt, End: wend, Next: wstt, isSrc: isSrc}
+		switch {
+		case isScanRangeInDB():
+			mr, err := mongodb.AddScanRange(isSrc, wstt, wend)
+			if err != nil {
+				log.Warn("add scan range failed", "isSrc", isSrc, "start", wstt, "end", wend, "err", err)
+			} else {
+				r.Key = mr.Key
+			}
+		case localScanState != nil:
+			key, err := localScanState.addRange(isSrc, wstt, wend)
+			if err != nil {
+				log.Warn("add scan range failed", "isSrc", isSrc, "start", wstt, "end", wend, "err", err)
+			}
+			r.Key = key
+		}
+		addActiveScanRange(r)
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// GetPendingScanRanges get unfinished ranges saved in database or scan state file
+func GetPendingScanRanges(isSrc bool) []*ScanRange {
+	var ranges []*ScanRange
+	switch {
+	case isScanRangeInDB():
+		mrs, err := mongodb.FindScanRanges(isSrc)
+		if err != nil {
+			log.Warn("find pending scan ranges failed", "isSrc", isSrc, "err", err)
+			return nil
+		}
+		ranges = make([]*ScanRange, 0, len(mrs))
+		for _, mr := range mrs {
+			ranges = append(ranges, &ScanRange{Key: mr.Key, Start: mr.Start, End: mr.End, Next: mr.Next})
+		}
+	case localScanState != nil:
+		ranges = localScanState.getRanges(isSrc)
+	default:
+		return nil
+	}
+	pendings := make([]*ScanRange, 0, len(ranges))
+	for _, r := range ranges {
+		r.isSrc = isSrc
+		if r.Next >= r.End {
+			r.Finish()
+			continue
+		}
+		addActiveScanRange(r)
+		pendings = append(pendings, r)
+	}
+	return pendings
+}
+
+// UpdateProgress record blocks before `next` are scanned
+func (r *ScanRange) UpdateProgress(next uint64) {
+	activeScanRangesLock.Lock()
+	r.Next = next
+	activeScanRangesLock.Unlock()
+	if r.Key == "" {
+		return
+	}
+	if isScanRangeInDB() {
+		_ = mongodb.UpdateScanRange(r.Key, next)
+	} else if localScanState != nil {
+		_ = localScanState.updateRange(r.Key, next)
+	}
+}
+
+// Finish remove the finished range from database or scan state file
+func (r *ScanRange) Finish() {
+	activeScanRangesLock.Lock()
+	r.Next = r.End
+	delete(activeScanRanges, r)
+	activeScanRangesLock.Unlock()
+	if r.Key == "" {
+		return
+	}
+	if isScanRangeInDB() {
+		_ = mongodb.RemoveScanRange(r.Key)
+	} else if localScanState != nil {
+		_ = localScanState.removeRange(r.Key)
+	}
+}
@@ -0,0 +1,137 @@
+package tools
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func finishScanRanges(ranges []*ScanRange) {
+	for _, r := range ranges {
+		r.Finish()
+	}
+}
+
+func TestNewScanRanges(t *testing.T) {
+	tests := []struct {
+		start, end, workers uint64
+		count               int
+	}{
+		{100, 200, 4, 4},
+		{100, 203, 4, 4},
+		{100, 105, 4, 1}, // too few blocks
+		{100, 200, 0, 1},
+		{100, 100, 4, 0},
+		{200, 100, 4, 0},
+	}
+	for _, test := range tests {
+		ranges := NewScanRanges(true, test.start, test.end, test.workers)
+		if len(ranges) != test.count {
+			t.Errorf("split [%v, %v) by %v workers, have %v ranges want %v", test.start, test.end, test.workers, len(ranges), test.count)
+			finishScanRanges(ranges)
+			continue
+		}
+		next := test.start
+		for _, r := range ranges {
+			if r.Start != next || r.Next != r.Start || r.End <= r.Start {
+				t.Errorf("split [%v, %v): range [%v, %v) next %v is not continuous", test.start, test.end, r.Start, r.End, r.Next)
+			}
+			next = r.End
+		}
+		if test.count > 0 && next != test.end {
+			t.Errorf("split [%v, %v): ranges end at %v", test.start, test.end, next)
+		}
+		finishScanRanges(ranges)
+	}
+}
+
+func TestLimitLatestScanHeight(t *testing.T) {
+	ranges := NewScanRanges(true, 100, 200, 4)
+	defer finishScanRanges(ranges)
+
+	if height := limitLatestScanHeight(true, 300); height != 100 {
+		t.Errorf("latest scan height should not pass unfinished ranges, have %v want %v", height, 100)
+	}
+	if height := limitLatestScanHeight(false, 300); height != 300 {
+		t.Errorf("ranges of other chain should not limit height, have %v want %v", height, 300)
+	}
+
+	ranges[0].UpdateProgress(110)
+	if height := limitLatestScanHeight(true, 300); height != 110 {
+		t.Errorf("limited height should follow progress, have %v want %v", height, 110)
+	}
+	ranges[0].Finish()
+	ranges[1].Finish()
+	if height := limitLatestScanHeight(true, 300); height != ranges[2].Start {
+		t.Errorf("finished ranges should not limit height, have %v want %v", height, ranges[2].Start)
+	}
+	ranges[2].Finish()
+	ranges[3].Finish()
+	if height := limitLatestScanHeight(true, 300); height != 300 {
+		t.Errorf("height should not be limited after all ranges finished, have %v want %v", height, 300)
+	}
+
+	// scan info is not advanced past unfinished ranges if they are not persisted
+	if isScanRangePersistent() {
+		t.Fatal("scan ranges should not be persistent without scan state")
+	}
+	ranges = NewScanRanges(true, 400, 500, 1)
+	if height := limitLatestScanHeight(true, 600); height != 400 {
+		t.Errorf("limited height mismatch, have %v want %v", height, 400)
+	}
+	finishScanRanges(ranges)
+}
+
+func TestResumeScanRanges(t *testing.T) {
+	ledgerFile := filepath.Join(t.TempDir(), "swapledger.jsonl")
+	if err := InitSwapLedger(ledgerFile); err != nil {
+		t.Fatal(err)
+	}
+	defer CloseSwapLedger()
+
+	ranges := NewScanRanges(false, 100, 200, 4)
+	if len(ranges) != 4 {
+		t.Fatalf("split ranges count mismatch, have %v want %v", len(ranges), 4)
+	}
+	ranges[0].UpdateProgress(110)
+	ranges[1].Finish()
+	ranges[2].UpdateProgress(ranges[2].End) // scanned, but not finished when crashed
+	srcRanges := NewScanRanges(true, 1000, 1005, 4)
+
+	// the latest scan height is saved without limit
+	if err := UpdateLatestScanInfo(false, 300); err != nil {
+		t.Fatal(err)
+	}
+
+	// restart with pending ranges kept in scan state file
+	activeScanRangesLock.Lock()
+	activeScanRanges = make(map[*ScanRange]struct{})
+	activeScanRangesLock.Unlock()
+	CloseSwapLedger()
+	if err := InitSwapLedger(ledgerFile); err != nil {
+		t.Fatal(err)
+	}
+	if height := GetLatestScanHeight(false); height != 300 {
+		t.Errorf("latest scan height mismatch, have %v want %v", height, 300)
+	}
+
+	pendings := GetPendingScanRanges(false)
+	if len(pendings) != 2 {
+		t.Fatalf("pending ranges count mismatch, have %v want %v", len(pendings), 2)
+	}
+	if r := pendings[0]; r.Start != 100 || r.Next != 110 || r.End != ranges[0].End {
+		t.Errorf("pending range mismatch, have [%v, %v) next %v", r.Start, r.End, r.Next)
+	}
+	if r := pendings[1]; r.Start != ranges[3].Start || r.Next != r.Start || r.End != 200 {
+		t.Errorf("pending range mismatch, have [%v, %v) next %v", r.Start, r.End, r.Next)
+	}
+	finishScanRanges(pendings)
+
+	if pendings = GetPendingScanRanges(false); len(pendings) != 0 {
+		t.Errorf("finished ranges should be removed, have %v pendings", len(pendings))
+	}
+	pendings = GetPendingScanRanges(true)
+	if len(pendings) != 1 || pendings[0].Start != srcRanges[0].Start {
+		t.Errorf("pending ranges of source chain mismatch, have %v", pendings)
+	}
+	finishScanRanges(pendings)
+}
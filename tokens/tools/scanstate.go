@@ -0,0 +1,141 @@
+package tools
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/log"
+)
+
+// scanState scan progress of oracle kept in a file next to the swap ledger,
+// so oracle resumes scanning from its own height and unfinished quick sync
+// ranges after outage, instead of the latest scan height of swap server
+// which may skip unobserved swaps.
+type scanState struct {
+	lock sync.Mutex
+	file string
+
+	SrcHeight uint64 `json:"srcheight"`
+	DstHeight uint64 `json:"dstheight"`
+
+	// unfinished quick sync ranges, key is src/dst + start
+	Ranges map[string]*scanStateRange `json:"ranges,omitempty"`
+}
+
+type scanStateRange struct {
+	IsSrc bool   `json:"issrc"`
+	Start uint64 `json:"start"`
+	End   uint64 `json:"end"`
+	Next  uint64 `json:"next"`
+}
+
+var localScanState *scanState
+
+func getScanStateFile(ledgerFile string) string {
+	return ledgerFile + ".scanstate"
+}
+
+func initScanState(stateFile string) error {
+	s := &scanState{file: stateFile, Ranges: make(map[string]*scanStateRange)}
+	if common.FileExist(stateFile) {
+		data, err := ioutil.ReadFile(stateFile)
+		if err != nil {
+			return err
+		}
+		if err = json.Unmarshal(data, s); err != nil {
+			return err
+		}
+		if s.Ranges == nil {
+			s.Ranges = make(map[string]*scanStateRange)
+		}
+	}
+	localScanState = s
+	log.Info("init scan state success", "file", stateFile, "srcHeight", s.SrcHeight, "dstHeight", s.DstHeight, "ranges", len(s.Ranges))
+	return nil
+}
+
+// save must be called with lock held, write to temp file
+// and rename it, so the state file is never partially written
+func (s *scanState) save() error {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmpFile := s.file + ".tmp"
+	if err = ioutil.WriteFile(tmpFile, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmpFile, s.file)
+}
+
+func (s *scanState) getHeight(isSrc bool) uint64 {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if isSrc {
+		return s.SrcHeight
+	}
+	return s.DstHeight
+}
+
+func (s *scanState) setHeight(isSrc bool, height uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if isSrc {
+		s.SrcHeight = height
+	} else {
+		s.DstHeight = height
+	}
+	return s.save()
+}
+
+func getScanStateRangeKey(isSrc bool, start uint64) string {
+	if isSrc {
+		return fmt.Sprintf("src:%v", start)
+	}
+	return fmt.Sprintf("dst:%v", start)
+}
+
+func (s *scanState) addRange(isSrc bool, start, end uint64) (string, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	key := getScanStateRangeKey(isSrc, start)
+	s.Ranges[key] = &scanStateRange{IsSrc: isSrc, Start: start, End: end, Next: start}
+	return key, s.save()
+}
+
+func (s *scanState) updateRange(key string, next uint64) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	r, exist := s.Ranges[key]
+	if !exist {
+		return fmt.Errorf("scan range %v not found", key)
+	}
+	r.Next = next
+	return s.save()
+}
+
+func (s *scanState) removeRange(key string) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	delete(s.Ranges, key)
+	return s.save()
+}
+
+// getRanges get unfinished ranges sorted by start
+func (s *scanState) getRanges(isSrc bool) []*ScanRange {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	ranges := make([]*ScanRange, 0, len(s.Ranges))
+	for key, r := range s.Ranges {
+		if r.IsSrc == isSrc {
+			ranges = append(ranges, &ScanRange{Key: key, Start: r.Start, End: r.End, Next: r.Next})
+		}
+	}
+	sort.Slice(ranges, func(i, j int) bool { return ranges[i].Start < ranges[j].Start })
+	return ranges
+}
@@ -0,0 +1,393 @@
+package tools
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/common/hexutil"
+	"github.com/anyswap/CrossChain-Bridge/dcrm"
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+// swap ledger errors
+var (
+	ErrSwapLedgerDisabled = errors.New("swap ledger is not enabled")
+	ErrSwapNotObserved    = errors.New("swap is not observed by this oracle")
+)
+
+// LedgerKindFeeWithdraw kind of fee withdraw ledger records
+const LedgerKindFeeWithdraw = "feewithdraw"
+
+// LedgerSwap is a swap observed by oracle scanning,
+// and endorsed if oracle agrees the dcrm sign of it.
+// fee withdraw agreed by oracle is recorded with kind 'feewithdraw',
+// whose txid is the withdraw id, bind is the fee treasury, value is the amount.
+type LedgerSwap struct {
+	Kind        string   `json:"kind,omitempty"`
+	TxID        string   `json:"txid"`
+	PairID      string   `json:"pairid"`
+	Bind        string   `json:"bind"`
+	IsSwapin    bool     `json:"isswapin"`
+	Value       string   `json:"value,omitempty"`
+	SwapFee     string   `json:"swapfee,omitempty"` // fee deducted from value, set when endorsed
+	VerifyError string   `json:"verifyerror,omitempty"`
+	ObservedAt  int64    `json:"observedat"`
+	EndorsedAt  int64    `json:"endorsedat,omitempty"`
+	KeyID       string   `json:"keyid,omitempty"`
+	MsgHash     []string `json:"msghash,omitempty"`
+}
+
+// SwapAttestation signed list of swaps endorsed by oracle
+type SwapAttestation struct {
+	Identifier string        `json:"identifier"`
+	Oracle     string        `json:"oracle"`
+	PairID     string        `json:"pairid,omitempty"`
+	Since      int64         `json:"since"`
+	Timestamp  int64         `json:"timestamp"`
+	Swaps      []*LedgerSwap `json:"swaps"`
+	Signature  string        `json:"signature,omitempty"`
+}
+
+type swapLedger struct {
+	lock  sync.RWMutex
+	swaps map[string]*LedgerSwap
+	file  *os.File
+}
+
+var ledger *swapLedger
+
+func getLedgerKey(txid, pairID, bind string, isSwapin bool) string {
+	return strings.ToLower(fmt.Sprintf("%v:%v:%v:%v", txid, pairID, bind, isSwapin))
+}
+
+func getFeeWithdrawLedgerKey(withdrawID, pairID string, isSwapin bool) string {
+	return strings.ToLower(fmt.Sprintf("%v:%v:%v:%v", LedgerKindFeeWithdraw, withdrawID, pairID, isSwapin))
+}
+
+func (swap *LedgerSwap) key() string {
+	if swap.Kind == LedgerKindFeeWithdraw {
+		return getFeeWithdrawLedgerKey(swap.TxID, swap.PairID, swap.IsSwapin)
+	}
+	return getLedgerKey(swap.TxID, swap.PairID, swap.Bind, swap.IsSwapin)
+}
+
+// InitSwapLedger init swap ledger from file, and append new records to it.
+// the scan height of oracle is kept in a state file next to it.
+func InitSwapLedger(ledgerFile string) error {
+	l := &swapLedger{swaps: make(map[string]*LedgerSwap)}
+	if common.FileExist(ledgerFile) {
+		if err := l.load(ledgerFile); err != nil {
+			return err
+		}
+	}
+	file, err := os.OpenFile(ledgerFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	if err = initScanState(getScanStateFile(ledgerFile)); err != nil {
+		_ = file.Close()
+		return err
+	}
+	l.file = file
+	ledger = l
+	log.Info("init swap ledger success", "file", ledgerFile, "swaps", len(l.swaps))
+	return nil
+}
+
+// load records, the last record of a swap overwrites the former ones
+func (l *swapLedger) load(ledgerFile string) error {
+	file, err := os.Open(ledgerFile)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		swap := &LedgerSwap{}
+		if err = json.Unmarshal(scanner.Bytes(), swap); err != nil {
+			return fmt.Errorf("wrong swap ledger record at line %v: %v", line, err)
+		}
+		l.swaps[swap.key()] = swap
+	}
+	return scanner.Err()
+}
+
+// save must be called with lock held
+func (l *swapLedger) save(swap *LedgerSwap) {
+	data, err := json.Marshal(swap)
+	if err == nil {
+		_, err = l.file.Write(append(data, '\n'))
+	}
+	if err != nil {
+		log.Error("save swap ledger record failed", "txid", swap.TxID, "pairID", swap.PairID, "bind", swap.Bind, "err", err)
+	}
+}
+
+// CloseSwapLedger close ledger file and disable swap ledger
+func CloseSwapLedger() {
+	if ledger == nil {
+		return
+	}
+	ledger.lock.Lock()
+	_ = ledger.file.Close()
+	ledger.lock.Unlock()
+	ledger = nil
+	localScanState = nil
+}
+
+// IsSwapLedgerEnabled is swap ledger enabled
+func IsSwapLedgerEnabled() bool {
+	return ledger != nil
+}
+
+// ObserveSwap add swap found in scanning to ledger
+func ObserveSwap(isSwapin bool, txid string, swapInfo *tokens.TxSwapInfo, verifyError error) {
+	if ledger == nil {
+		return
+	}
+	key := getLedgerKey(txid, swapInfo.PairID, swapInfo.Bind, isSwapin)
+	swap := &LedgerSwap{
+		TxID:       txid,
+		PairID:     swapInfo.PairID,
+		Bind:       swapInfo.Bind,
+		IsSwapin:   isSwapin,
+		ObservedAt: time.Now().Unix(),
+	}
+	if swapInfo.Value != nil {
+		swap.Value = swapInfo.Value.String()
+	}
+	if verifyError != nil {
+		swap.VerifyError = verifyError.Error()
+	}
+
+	ledger.lock.Lock()
+	defer ledger.lock.Unlock()
+	if old, exist := ledger.swaps[key]; exist &&
+		old.VerifyError == swap.VerifyError && old.Value == swap.Value {
+		return
+	}
+	ledger.swaps[key] = swap
+	ledger.save(swap)
+}
+
+// IsSwapObserved is swap observed by this oracle,
+// and verified without error with the same value
+func IsSwapObserved(txid, pairID, bind string, isSwapin bool, value *big.Int) bool {
+	if ledger == nil || value == nil {
+		return false
+	}
+	ledger.lock.RLock()
+	defer ledger.lock.RUnlock()
+	swap, exist := ledger.swaps[getLedgerKey(txid, pairID, bind, isSwapin)]
+	if !exist || swap.VerifyError != "" {
+		return false
+	}
+	observedValue, ok := new(big.Int).SetString(swap.Value, 0)
+	return ok && observedValue.Cmp(value) == 0
+}
+
+// EndorseSwap mark swap as endorsed when agree its dcrm sign,
+// swapFee is the fee snapshot of the signed swap (nil if not exist)
+func EndorseSwap(txid, pairID, bind string, isSwapin bool, keyID string, msgHash []string, swapFee *big.Int) {
+	if ledger == nil {
+		return
+	}
+	key := getLedgerKey(txid, pairID, bind, isSwapin)
+
+	ledger.lock.Lock()
+	defer ledger.lock.Unlock()
+	old, exist := ledger.swaps[key]
+	if !exist {
+		return
+	}
+	swap := *old
+	swap.EndorsedAt = time.Now().Unix()
+	swap.KeyID = keyID
+	swap.MsgHash = msgHash
+	swap.SwapFee = calcLedgerSwapFee(&swap, swapFee)
+	ledger.swaps[key] = &swap
+	ledger.save(&swap)
+}
+
+func calcLedgerSwapFee(swap *LedgerSwap, swapFee *big.Int) string {
+	value, ok := new(big.Int).SetString(swap.Value, 0)
+	if !ok || tokens.GetTokenConfig(swap.PairID, swap.IsSwapin) == nil {
+		return ""
+	}
+	swapValue := tokens.CalcSwappedValueWithFee(swap.PairID, value, swapFee, swap.IsSwapin)
+	return new(big.Int).Sub(value, swapValue).String()
+}
+
+// GetFeeWithdraw get fee withdraw agreed by this oracle, nil if not exist
+func GetFeeWithdraw(withdrawID, pairID string, isSwapin bool) *LedgerSwap {
+	if ledger == nil {
+		return nil
+	}
+	ledger.lock.RLock()
+	defer ledger.lock.RUnlock()
+	return ledger.swaps[getFeeWithdrawLedgerKey(withdrawID, pairID, isSwapin)]
+}
+
+// AcceptFeeWithdraw record fee withdraw when agree its dcrm sign
+func AcceptFeeWithdraw(withdrawID, pairID, treasury string, isSwapin bool, amount *big.Int, keyID string, msgHash []string) {
+	if ledger == nil || amount == nil {
+		return
+	}
+	now := time.Now().Unix()
+	withdraw := &LedgerSwap{
+		Kind:       LedgerKindFeeWithdraw,
+		TxID:       withdrawID,
+		PairID:     pairID,
+		Bind:       treasury,
+		IsSwapin:   isSwapin,
+		Value:      amount.String(),
+		ObservedAt: now,
+		EndorsedAt: now,
+		KeyID:      keyID,
+		MsgHash:    msgHash,
+	}
+
+	ledger.lock.Lock()
+	defer ledger.lock.Unlock()
+	ledger.swaps[withdraw.key()] = withdraw
+	ledger.save(withdraw)
+}
+
+// GetWithdrawableFee get fee of swaps endorsed by this oracle minus fee withdraws agreed by it.
+// network fee is not known to oracle and is not deducted.
+func GetWithdrawableFee(pairID string, isSwapin bool) (*big.Int, error) {
+	if ledger == nil {
+		return nil, ErrSwapLedgerDisabled
+	}
+	collected := big.NewInt(0)
+	withdrawn := big.NewInt(0)
+	ledger.lock.RLock()
+	for _, swap := range ledger.swaps {
+		if swap.IsSwapin != isSwapin || !strings.EqualFold(swap.PairID, pairID) {
+			continue
+		}
+		switch swap.Kind {
+		case LedgerKindFeeWithdraw:
+			if amount, ok := new(big.Int).SetString(swap.Value, 0); ok {
+				withdrawn.Add(withdrawn, amount)
+			}
+		case "":
+			if swap.EndorsedAt == 0 {
+				continue
+			}
+			if fee, ok := new(big.Int).SetString(swap.SwapFee, 0); ok {
+				collected.Add(collected, fee)
+			}
+		}
+	}
+	ledger.lock.RUnlock()
+	withdrawable := collected.Sub(collected, withdrawn)
+	if withdrawable.Sign() < 0 {
+		withdrawable.SetUint64(0)
+	}
+	return withdrawable, nil
+}
+
+// GetEndorsedSwaps get swaps endorsed since timestamp (filter by pairID if not empty)
+func GetEndorsedSwaps(since int64, pairID string) ([]*LedgerSwap, error) {
+	if ledger == nil {
+		return nil, ErrSwapLedgerDisabled
+	}
+	ledger.lock.RLock()
+	result := make([]*LedgerSwap, 0)
+	for _, swap := range ledger.swaps {
+		if swap.Kind != "" || swap.EndorsedAt == 0 || swap.EndorsedAt < since {
+			continue
+		}
+		if pairID != "" && !strings.EqualFold(swap.PairID, pairID) {
+			continue
+		}
+		result = append(result, swap)
+	}
+	ledger.lock.RUnlock()
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].EndorsedAt != result[j].EndorsedAt {
+			return result[i].EndorsedAt < result[j].EndorsedAt
+		}
+		return result[i].TxID < result[j].TxID
+	})
+	return result, nil
+}
+
+func (att *SwapAttestation) signHash() ([]byte, error) {
+	content := *att
+	content.Signature = ""
+	data, err := json.Marshal(&content)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.Keccak256(data), nil
+}
+
+// MakeSwapAttestation make attestation of endorsed swaps signed by dcrm node keystore
+func MakeSwapAttestation(since int64, pairID string) (*SwapAttestation, error) {
+	swaps, err := GetEndorsedSwaps(since, pairID)
+	if err != nil {
+		return nil, err
+	}
+	att := &SwapAttestation{
+		Identifier: params.GetIdentifier(),
+		Oracle:     dcrm.GetDefaultDcrmUser().String(),
+		PairID:     pairID,
+		Since:      since,
+		Timestamp:  time.Now().Unix(),
+		Swaps:      swaps,
+	}
+	err = att.sign(dcrm.SignWithNodeKey)
+	if err != nil {
+		return nil, err
+	}
+	return att, nil
+}
+
+func (att *SwapAttestation) sign(signFn func(hash []byte) ([]byte, error)) error {
+	hash, err := att.signHash()
+	if err != nil {
+		return err
+	}
+	signature, err := signFn(hash)
+	if err != nil {
+		return err
+	}
+	att.Signature = hexutil.Encode(signature)
+	return nil
+}
+
+// VerifySwapAttestation verify attestation is signed by its oracle
+func VerifySwapAttestation(att *SwapAttestation) error {
+	signature, err := hexutil.Decode(att.Signature)
+	if err != nil {
+		return err
+	}
+	hash, err := att.signHash()
+	if err != nil {
+		return err
+	}
+	pubKey, err := crypto.SigToPub(hash, signature)
+	if err != nil {
+		return err
+	}
+	signer := crypto.PubkeyToAddress(*pubKey)
+	if !strings.EqualFold(signer.String(), att.Oracle) {
+		return fmt.Errorf("attestation signer mismatch, have %v want %v", signer.String(), att.Oracle)
+	}
+	return nil
+}
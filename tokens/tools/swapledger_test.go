@@ -0,0 +1,164 @@
+package tools
+
+import (
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+func TestWithdrawableFee(t *testing.T) {
+	ledgerFile := filepath.Join(t.TempDir(), "swapledger.jsonl")
+	if err := InitSwapLedger(ledgerFile); err != nil {
+		t.Fatal(err)
+	}
+	defer CloseSwapLedger()
+	for _, swap := range []*LedgerSwap{
+		{TxID: "tx1", PairID: "ETH", Bind: "bind", IsSwapin: true, Value: "10000", SwapFee: "100", EndorsedAt: 1},
+		{TxID: "tx2", PairID: "eth", Bind: "bind", IsSwapin: true, Value: "20000", SwapFee: "200", EndorsedAt: 2},
+		{TxID: "tx3", PairID: "eth", Bind: "bind", IsSwapin: true, Value: "30000", SwapFee: "300"}, // not endorsed
+		{TxID: "tx4", PairID: "eth", Bind: "bind", IsSwapin: false, Value: "40000", SwapFee: "400", EndorsedAt: 4},
+		{TxID: "tx5", PairID: "btc", Bind: "bind", IsSwapin: true, Value: "50000", SwapFee: "500", EndorsedAt: 5},
+	} {
+		ledger.swaps[swap.key()] = swap
+		ledger.save(swap)
+	}
+	AcceptFeeWithdraw("withdraw1", "eth", "treasury", true, big.NewInt(120), "keyid", []string{"msghash"})
+
+	checkWithdrawable := func(isSwapin bool, want int64) {
+		withdrawable, err := GetWithdrawableFee("ETH", isSwapin)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if withdrawable.Int64() != want {
+			t.Errorf("withdrawable fee of isSwapin %v mismatch, have %v want %v", isSwapin, withdrawable, want)
+		}
+	}
+	checkWithdrawable(true, 180)
+	checkWithdrawable(false, 400)
+
+	withdraw := GetFeeWithdraw("WITHDRAW1", "eth", true)
+	if withdraw == nil || withdraw.Value != "120" || withdraw.Bind != "treasury" {
+		t.Fatalf("accepted fee withdraw mismatch, have %+v", withdraw)
+	}
+	if GetFeeWithdraw("withdraw1", "eth", false) != nil {
+		t.Fatal("fee withdraw of other swap type should not exist")
+	}
+
+	swaps, err := GetEndorsedSwaps(0, "eth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(swaps) != 3 {
+		t.Fatalf("endorsed swaps should not include fee withdraws, have %v want %v", len(swaps), 3)
+	}
+
+	// fee withdraws are kept after restart
+	AcceptFeeWithdraw("withdraw2", "eth", "treasury", true, big.NewInt(80), "keyid", []string{"msghash"})
+	_ = ledger.file.Close()
+	if err := InitSwapLedger(ledgerFile); err != nil {
+		t.Fatal(err)
+	}
+	checkWithdrawable(true, 100)
+}
+
+func TestObserveSwap(t *testing.T) {
+	ledgerFile := filepath.Join(t.TempDir(), "swapledger.jsonl")
+	if err := InitSwapLedger(ledgerFile); err != nil {
+		t.Fatal(err)
+	}
+	defer CloseSwapLedger()
+
+	swapInfo := &tokens.TxSwapInfo{PairID: "ETH", Bind: "bind", Value: big.NewInt(1000)}
+	ObserveSwap(true, "tx1", swapInfo, tokens.ErrRPCQueryError)
+	if IsSwapObserved("tx1", "eth", "bind", true, big.NewInt(1000)) {
+		t.Fatal("swap observed with verify error should not be signed")
+	}
+
+	ObserveSwap(true, "tx1", swapInfo, nil)
+	tests := []struct {
+		txid     string
+		isSwapin bool
+		value    *big.Int
+		observed bool
+	}{
+		{"tx1", true, big.NewInt(1000), true},
+		{"TX1", true, big.NewInt(1000), true},
+		{"tx1", true, big.NewInt(999), false},
+		{"tx1", true, nil, false},
+		{"tx1", false, big.NewInt(1000), false},
+		{"tx2", true, big.NewInt(1000), false},
+	}
+	for i, test := range tests {
+		if IsSwapObserved(test.txid, "eth", "bind", test.isSwapin, test.value) != test.observed {
+			t.Errorf("test %v: swap observed mismatch, want %v", i, test.observed)
+		}
+	}
+
+	// value change with the same verify error is recorded
+	ObserveSwap(true, "tx1", &tokens.TxSwapInfo{PairID: "ETH", Bind: "bind", Value: big.NewInt(2000)}, nil)
+	if IsSwapObserved("tx1", "eth", "bind", true, big.NewInt(1000)) ||
+		!IsSwapObserved("tx1", "eth", "bind", true, big.NewInt(2000)) {
+		t.Fatal("swap value change should be observed")
+	}
+
+	// observed swaps and scan height are kept after restart
+	if err := UpdateLatestScanInfo(true, 12345); err != nil {
+		t.Fatal(err)
+	}
+	CloseSwapLedger()
+	if err := InitSwapLedger(ledgerFile); err != nil {
+		t.Fatal(err)
+	}
+	if !IsSwapObserved("tx1", "eth", "bind", true, big.NewInt(2000)) {
+		t.Fatal("observed swap should be loaded after restart")
+	}
+	if height := GetLatestScanHeight(true); height != 12345 {
+		t.Fatalf("scan height should be loaded after restart, have %v want %v", height, 12345)
+	}
+}
+
+func TestSwapAttestation(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	att := &SwapAttestation{
+		Identifier: "test",
+		Oracle:     crypto.PubkeyToAddress(key.PublicKey).String(),
+		Since:      1,
+		Timestamp:  2,
+		Swaps: []*LedgerSwap{
+			{TxID: "tx1", PairID: "eth", Bind: "bind", IsSwapin: true, Value: "1000", EndorsedAt: 2},
+		},
+	}
+	err = att.sign(func(hash []byte) ([]byte, error) { return crypto.Sign(hash, key) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = VerifySwapAttestation(att); err != nil {
+		t.Fatalf("verify attestation failed, %v", err)
+	}
+
+	tampered := *att
+	tampered.Swaps = []*LedgerSwap{
+		{TxID: "tx1", PairID: "eth", Bind: "bind", IsSwapin: true, Value: "2000", EndorsedAt: 2},
+	}
+	if err = VerifySwapAttestation(&tampered); err == nil {
+		t.Error("verify tampered attestation should fail")
+	}
+
+	tampered = *att
+	tampered.Oracle = "0x0000000000000000000000000000000000000001"
+	if err = VerifySwapAttestation(&tampered); err == nil {
+		t.Error("verify attestation of other oracle should fail")
+	}
+
+	errSign := errors.New("sign failed")
+	if err = att.sign(func([]byte) ([]byte, error) { return nil, errSign }); !errors.Is(err, errSign) {
+		t.Errorf("sign attestation error mismatch, have %v want %v", err, errSign)
+	}
+}
@@ -67,8 +67,9 @@ func registerSwap(isSwapin bool, txid string, swapInfos []*tokens.TxSwapInfo, ve
 		}
 		pairID := swapInfo.PairID
 		bind := swapInfo.Bind
+		ObserveSwap(isSwapin, txid, swapInfo, verifyError)
 		if IsSwapExist(txid, pairID, bind, isSwapin) {
-			return
+			continue
 		}
 		isServer := dcrm.IsSwapServer()
 		log.Info("[scan] register swap", "pairID", pairID, "isSwapin", isSwapin, "isServer", isServer, "tx", txid, "bind", bind)
@@ -127,6 +128,7 @@ func RegisterP2shSwapin(txid string, swapInfo *tokens.TxSwapInfo, verifyError er
 	if !tokens.ShouldRegisterSwapForError(verifyError) {
 		return
 	}
+	ObserveSwap(true, txid, swapInfo, verifyError)
 	isServer := dcrm.IsSwapServer()
 	log.Info("[scan] register p2sh swapin", "isServer", isServer, "tx", txid)
 	bind := swapInfo.Bind
@@ -180,8 +182,15 @@ func GetP2shBindAddress(p2shAddress string) (bindAddress string) {
 	return ""
 }
 
-// GetLatestScanHeight get latest scanned block height
+// GetLatestScanHeight get latest scanned block height,
+// oracle with swap ledger resumes from its own scan height if exist.
 func GetLatestScanHeight(isSrc bool) uint64 {
+	if localScanState != nil {
+		if height := localScanState.getHeight(isSrc); height != 0 {
+			log.Info("GetLatestScanHeight from scan state", "isSrc", isSrc, "height", height)
+			return height
+		}
+	}
 	if mongodb.HasSession() {
 		for {
 			latestInfo, err := mongodb.FindLatestScanInfo(isSrc)
@@ -218,11 +227,22 @@ func LoopGetLatestBlockNumber(b tokens.CrossChainBridge) uint64 {
 	}
 }
 
-// UpdateLatestScanInfo update latest scan info
+// UpdateLatestScanInfo update latest scan info,
+// height is limited by unfinished quick sync ranges if they are not persisted
 func UpdateLatestScanInfo(isSrc bool, height uint64) error {
+	if !isScanRangePersistent() {
+		height = limitLatestScanHeight(isSrc, height)
+	}
 	if dcrm.IsSwapServer() {
 		return mongodb.UpdateLatestScanInfo(isSrc, height)
 	}
+	if localScanState != nil {
+		err := localScanState.setHeight(isSrc, height)
+		if err != nil {
+			log.Warn("update scan state failed", "isSrc", isSrc, "height", height, "err", err)
+		}
+		return err
+	}
 	return nil
 }
 
@@ -8,8 +8,8 @@ import (
 	"strings"
 
 	"github.com/anyswap/CrossChain-Bridge/common"
-	"github.com/anyswap/CrossChain-Bridge/tools"
 	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+	"github.com/anyswap/CrossChain-Bridge/tools/signer"
 )
 
 // BtcExtraConfig used to build swpout to btc tx
@@ -23,6 +23,18 @@ type BtcExtraConfig struct {
 	UtxoAggregateMinCount  int
 	UtxoAggregateMinValue  uint64
 	UtxoAggregateToAddress string
+
+	P2shPairID string `json:",omitempty"`
+
+	// coin selection strategy: bnb (default), largestfirst, consolidate
+	CoinSelection        string `json:",omitempty"`
+	ConsolidateFeePerKb  int64  `json:",omitempty"`
+	ConsolidateMaxInputs int    `json:",omitempty"`
+
+	// aggregate only if relay fee per kb is not greater than this (0 means no limit)
+	AggregateMaxFeePerKb int64 `json:",omitempty"`
+	// consolidate deposit address utxos not greater than this value (0 means disabled)
+	AggregateDustMaxValue uint64 `json:",omitempty"`
 }
 
 // ChainConfig struct
@@ -33,7 +45,9 @@ type ChainConfig struct {
 	InitialHeight  *uint64
 	EnableScan     bool
 	EnableScanPool bool
-	ScanReceipt    bool `json:",omitempty"`
+	ScanReceipt    bool   `json:",omitempty"`
+	ScanLogs       bool   `json:",omitempty"`
+	TraceAPI       string `json:",omitempty"`
 
 	MaxGasPriceFluctPercent uint64 `json:",omitempty"`
 	WaitTimeToReplace       int64  // seconds
@@ -50,7 +64,16 @@ type GatewayConfig struct {
 
 // GatewayExtras struct
 type GatewayExtras struct {
-	BlockExtra *BlockExtraArgs
+	BlockExtra  *BlockExtraArgs
+	CosmosExtra *CosmosExtraArgs
+}
+
+// CosmosExtraArgs struct
+type CosmosExtraArgs struct {
+	// use protobuf tx (SIGN_MODE_DIRECT) and grpc-gateway api (cosmos-sdk v0.40+)
+	EnableProtobufTx bool
+	// grpc-gateway api addresses, use APIAddress if not configed
+	GRPCGatewayAPIs []string `json:",omitempty"`
 }
 
 // BlockExtraArgs struct
@@ -88,7 +111,13 @@ type TokenConfig struct {
 	PlusGasPricePercentage uint64 `json:",omitempty"`
 	DisableSwap            bool
 	IsDelegateContract     bool
-	DelegateToken          string `json:",omitempty"`
+	DelegateToken          string           `json:",omitempty"`
+	IsLockRelease          bool             `json:",omitempty"` // dest token is native erc20 locked in DcrmAddress
+	FeeTreasury            string           `json:",omitempty"` // collected swap fee is withdrawn to this address
+	FeePolicy              *FeePolicyConfig `json:",omitempty"` // dynamic minimum swap fee
+
+	// effective fee schedule, only filled in api result
+	FeeSchedule *SwapFeeSchedule `toml:"-" json:",omitempty"`
 
 	DefaultGasLimit uint64 `json:",omitempty"`
 
@@ -96,7 +125,9 @@ type TokenConfig struct {
 	DcrmAddressKeyStore string `json:"-"`
 	DcrmAddressPassword string `json:"-"`
 	DcrmAddressKeyFile  string `json:"-"`
-	dcrmAddressPriKey   *ecdsa.PrivateKey
+	// or use remote signing service or pkcs11 token instead
+	DcrmAddressSigner *signer.Config `json:"-"`
+	dcrmAddressSigner signer.Signer
 
 	// calced value
 	maxSwap          *big.Int
@@ -105,7 +136,8 @@ type TokenConfig struct {
 	minSwapFee       *big.Int
 	bigValThreshhold *big.Int
 
-	Unit string // Cosmos coin unit denom
+	Unit           string   // Cosmos coin unit denom
+	IBCDenomTraces []string `json:",omitempty"` // Cosmos accepted ibc denom traces (eg. 'transfer/channel-0/uatom')
 }
 
 // IsErc20 return if token is erc20
@@ -113,11 +145,26 @@ func (c *TokenConfig) IsErc20() bool {
 	return strings.EqualFold(c.ID, "ERC20") || c.IsProxyErc20()
 }
 
+// IsReleaseFromVault return if swap to this token is released by
+// transferring erc20 token from the vault held by dcrm address
+func (c *TokenConfig) IsReleaseFromVault(isSrc bool) bool {
+	if isSrc {
+		return c.IsErc20()
+	}
+	return c.IsLockRelease
+}
+
 // IsProxyErc20 return if token is proxy contract of erc20
 func (c *TokenConfig) IsProxyErc20() bool {
 	return strings.EqualFold(c.ID, "ProxyERC20")
 }
 
+// TraceAPI of eth like chains to detect internal transfers
+const (
+	TraceAPIDebug  = "debug"  // geth 'debug_traceTransaction' with 'callTracer'
+	TraceAPIParity = "parity" // openethereum/erigon 'trace_transaction' and 'trace_block'
+)
+
 // SwapType type
 type SwapType uint32
 
@@ -220,6 +267,16 @@ func (args *BuildTxArgs) GetExtraArgs() *BuildTxArgs {
 	}
 }
 
+// GetSwapValue get swapped value of origin value,
+// with the swap fee snapshotted in extra args if exist
+func (args *BuildTxArgs) GetSwapValue() *big.Int {
+	var swapFee *big.Int
+	if args.Extra != nil {
+		swapFee = args.Extra.SwapFee
+	}
+	return CalcSwappedValueWithFee(args.PairID, args.OriginValue, swapFee, args.SwapType == SwapinType)
+}
+
 // GetTxNonce get tx nonce
 func (args *BuildTxArgs) GetTxNonce() uint64 {
 	if args.Extra != nil && args.Extra.EthExtra != nil && args.Extra.EthExtra.Nonce != nil {
@@ -232,6 +289,7 @@ func (args *BuildTxArgs) GetTxNonce() uint64 {
 type AllExtras struct {
 	BtcExtra *BtcExtraArgs `json:"btcExtra,omitempty"`
 	EthExtra *EthExtraArgs `json:"ethExtra,omitempty"`
+	SwapFee  *big.Int      `json:"swapFee,omitempty"` // snapshot of swap fee priced by fee policy
 }
 
 // EthExtraArgs struct
@@ -276,6 +334,11 @@ func (c *ChainConfig) CheckConfig() error {
 	if c.InitialHeight == nil {
 		return errors.New("token must config 'InitialHeight'")
 	}
+	switch c.TraceAPI {
+	case "", TraceAPIDebug, TraceAPIParity:
+	default:
+		return fmt.Errorf("wrong 'TraceAPI' %v (must be '%v' or '%v' if configed)", c.TraceAPI, TraceAPIDebug, TraceAPIParity)
+	}
 	return nil
 }
 
@@ -312,6 +375,14 @@ func (c *TokenConfig) CheckConfig(isSrc bool) error {
 	if *c.SwapFeeRate == 0.0 && *c.MinimumSwapFee > 0.0 {
 		return errors.New("wrong token config, MinimumSwapFee should be 0 if SwapFeeRate is 0")
 	}
+	if c.FeePolicy != nil {
+		if *c.SwapFeeRate == 0.0 {
+			return errors.New("wrong token config, FeePolicy should not be configed if SwapFeeRate is 0")
+		}
+		if err := c.FeePolicy.CheckConfig(); err != nil {
+			return err
+		}
+	}
 	maxPlusGasPricePercentage := uint64(10000)
 	if c.PlusGasPricePercentage > maxPlusGasPricePercentage {
 		return errors.New("too large 'PlusGasPricePercentage' value")
@@ -331,6 +402,17 @@ func (c *TokenConfig) CheckConfig(isSrc bool) error {
 	if isSrc && c.IsErc20() && c.ContractAddress == "" {
 		return errors.New("token must config 'ContractAddress' for ERC20 in source chain")
 	}
+	if !isSrc && c.IsLockRelease {
+		if !c.IsErc20() {
+			return errors.New("token must config 'ID' to ERC20 if 'IsLockRelease' is true")
+		}
+		if c.DepositAddress == "" {
+			return errors.New("token must config 'DepositAddress' if 'IsLockRelease' is true")
+		}
+		if c.IsDelegateContract {
+			return errors.New("token can not config both 'IsLockRelease' and 'IsDelegateContract'")
+		}
+	}
 	if isSrc && c.IsProxyErc20() && c.ContractCodeHash == "" {
 		return errors.New("token must config 'ContractCodeHash' for ProxyERC20 in source chain")
 	}
@@ -344,7 +426,7 @@ func (c *TokenConfig) CheckConfig(isSrc bool) error {
 	}
 	// calc value and store
 	c.CalcAndStoreValue()
-	err := c.LoadDcrmAddressPrivateKey()
+	err := c.LoadDcrmAddressSigner()
 	if err != nil {
 		return err
 	}
@@ -361,31 +443,43 @@ func (c *TokenConfig) CalcAndStoreValue() {
 	c.bigValThreshhold = ToBits(*c.BigValueThreshold+smallBiasValue, *c.Decimals)
 }
 
-// GetDcrmAddressPrivateKey get private key
-func (c *TokenConfig) GetDcrmAddressPrivateKey() *ecdsa.PrivateKey {
-	return c.dcrmAddressPriKey
+// GetDcrmAddressSigner get signer of dcrm address (nil if signed by dcrm)
+func (c *TokenConfig) GetDcrmAddressSigner() signer.Signer {
+	return c.dcrmAddressSigner
 }
 
-// LoadDcrmAddressPrivateKey load private key
-func (c *TokenConfig) LoadDcrmAddressPrivateKey() error {
-	if c.DcrmAddressKeyFile != "" {
-		priKey, err := crypto.LoadECDSA(c.DcrmAddressKeyFile)
-		if err != nil {
-			return fmt.Errorf("wrong private key, %v", err)
+// LoadDcrmAddressSigner load signer of dcrm address
+func (c *TokenConfig) LoadDcrmAddressSigner() error {
+	var signerCfg *signer.Config
+	switch {
+	case c.DcrmAddressSigner != nil:
+		cfg := *c.DcrmAddressSigner
+		if cfg.PublicKey == "" {
+			cfg.PublicKey = c.DcrmPubkey
+		}
+		signerCfg = &cfg
+	case c.DcrmAddressKeyFile != "":
+		signerCfg = &signer.Config{
+			Type:    signer.TypeLocal,
+			KeyFile: c.DcrmAddressKeyFile,
 		}
-		c.dcrmAddressPriKey = priKey
-	} else if c.DcrmAddressKeyStore != "" {
-		key, err := tools.LoadKeyStore(c.DcrmAddressKeyStore, c.DcrmAddressPassword)
+	case c.DcrmAddressKeyStore != "":
+		signerCfg = &signer.Config{
+			Type:         signer.TypeLocal,
+			KeyStore:     c.DcrmAddressKeyStore,
+			PasswordFile: c.DcrmAddressPassword,
+		}
+	}
+	if signerCfg != nil {
+		keySigner, err := signer.NewSigner(signerCfg)
 		if err != nil {
 			return err
 		}
-		c.dcrmAddressPriKey = key.PrivateKey
-	}
-	if c.dcrmAddressPriKey != nil {
-		keyAddr := crypto.PubkeyToAddress(c.dcrmAddressPriKey.PublicKey)
+		keyAddr := keySigner.Address()
 		if !strings.EqualFold(keyAddr.String(), c.DcrmAddress) {
-			return fmt.Errorf("dcrm address %v and its keystore address %v is not match", c.DcrmAddress, keyAddr.String())
+			return fmt.Errorf("dcrm address %v and its signer address %v is not match", c.DcrmAddress, keyAddr.String())
 		}
+		c.dcrmAddressSigner = keySigner
 	} else {
 		if c.DcrmPubkey == "" {
 			return fmt.Errorf("token must config 'DcrmPubkey'")
@@ -402,7 +496,7 @@ func (c *TokenConfig) VerifyDcrmPublicKey() error {
 	if !common.IsHexAddress(c.DcrmAddress) {
 		return nil
 	}
-	if c.dcrmAddressPriKey != nil && c.DcrmPubkey == "" {
+	if c.dcrmAddressSigner != nil && c.DcrmPubkey == "" {
 		return nil
 	}
 	// ETH like address
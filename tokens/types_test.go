@@ -0,0 +1,25 @@
+package tokens
+
+import "testing"
+
+func TestIsReleaseFromVault(t *testing.T) {
+	tests := []struct {
+		id            string
+		isLockRelease bool
+		isSrc         bool
+		want          bool
+	}{
+		{"ERC20", false, true, true},
+		{"ProxyERC20", false, true, true},
+		{"", false, true, false},
+		{"ERC20", false, false, false},
+		{"ERC20", true, false, true},
+		{"", true, false, true},
+	}
+	for _, test := range tests {
+		token := &TokenConfig{ID: test.id, IsLockRelease: test.isLockRelease}
+		if have := token.IsReleaseFromVault(test.isSrc); have != test.want {
+			t.Errorf("token '%v' lockRelease %v isSrc %v: release from vault mismatch, have %v want %v", test.id, test.isLockRelease, test.isSrc, have, test.want)
+		}
+	}
+}
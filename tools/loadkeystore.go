@@ -3,22 +3,22 @@ package tools
 import (
 	"fmt"
 	"io/ioutil"
-	"strings"
 
 	"github.com/anyswap/CrossChain-Bridge/tools/keystore"
+	"github.com/anyswap/CrossChain-Bridge/tools/secrets"
 )
 
-// LoadKeyStore load keystore from keyfile and passfile
+// LoadKeyStore load keystore from keyfile and passfile,
+// passfile is password file path or secret reference (eg. 'env:NAME')
 func LoadKeyStore(keyfile, passfile string) (*keystore.Key, error) {
 	keyjson, err := ioutil.ReadFile(keyfile)
 	if err != nil {
 		return nil, fmt.Errorf("read keystore fail %v", err)
 	}
-	passdata, err := ioutil.ReadFile(passfile)
+	passwd, err := secrets.GetPassword(passfile)
 	if err != nil {
 		return nil, fmt.Errorf("read password fail %v", err)
 	}
-	passwd := strings.TrimSpace(string(passdata))
 	key, err := keystore.DecryptKey(keyjson, passwd)
 	if err != nil {
 		return nil, fmt.Errorf("decrypt key fail %v", err)
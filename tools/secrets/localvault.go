@@ -0,0 +1,185 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sort"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	localVaultVersion = 1
+
+	vaultScryptN     = 1 << 15
+	vaultScryptR     = 8
+	vaultScryptP     = 1
+	vaultScryptDKLen = 32
+)
+
+var errWrongVaultPassword = errors.New("could not decrypt local vault with given password")
+
+// localVaultFile encrypted local vault file, secrets are stored as json object
+// which is encrypted by aes-256-gcm with key derived from password by scrypt
+type localVaultFile struct {
+	Version    int    `json:"version"`
+	ScryptN    int    `json:"n"`
+	ScryptR    int    `json:"r"`
+	ScryptP    int    `json:"p"`
+	Salt       string `json:"salt"`
+	Nonce      string `json:"nonce"`
+	CipherText string `json:"ciphertext"`
+}
+
+// LocalVault secrets stored in encrypted local vault file
+type LocalVault struct {
+	file     string
+	password string
+
+	secrets map[string]string
+	lock    sync.RWMutex
+}
+
+// NewLocalVault new empty local vault, it is written to file when saved
+func NewLocalVault(file, password string) (*LocalVault, error) {
+	if common.FileExist(file) {
+		return nil, fmt.Errorf("local vault file %v already exist", file)
+	}
+	return &LocalVault{
+		file:     file,
+		password: password,
+		secrets:  make(map[string]string),
+	}, nil
+}
+
+// LoadLocalVault load and decrypt local vault file
+func LoadLocalVault(file, password string) (*LocalVault, error) {
+	data, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("read local vault fail %v", err)
+	}
+	var vf localVaultFile
+	if err = json.Unmarshal(data, &vf); err != nil {
+		return nil, fmt.Errorf("decode local vault fail %v", err)
+	}
+	if vf.Version != localVaultVersion {
+		return nil, fmt.Errorf("unsupported local vault version %v", vf.Version)
+	}
+	salt, nonce, cipherText := common.FromHex(vf.Salt), common.FromHex(vf.Nonce), common.FromHex(vf.CipherText)
+	aead, err := newVaultCipher(password, salt, vf.ScryptN, vf.ScryptR, vf.ScryptP)
+	if err != nil {
+		return nil, err
+	}
+	if len(nonce) != aead.NonceSize() {
+		return nil, errors.New("wrong nonce size of local vault")
+	}
+	plainText, err := aead.Open(nil, nonce, cipherText, nil)
+	if err != nil {
+		return nil, errWrongVaultPassword
+	}
+	secrets := make(map[string]string)
+	if err = json.Unmarshal(plainText, &secrets); err != nil {
+		return nil, fmt.Errorf("decode local vault secrets fail %v", err)
+	}
+	return &LocalVault{
+		file:     file,
+		password: password,
+		secrets:  secrets,
+	}, nil
+}
+
+func newVaultCipher(password string, salt []byte, n, r, p int) (cipher.AEAD, error) {
+	derivedKey, err := scrypt.Key([]byte(password), salt, n, r, p, vaultScryptDKLen)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(derivedKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// GetSecret impl Provider
+func (v *LocalVault) GetSecret(name string) (string, error) {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	value, exist := v.secrets[name]
+	if !exist {
+		return "", fmt.Errorf("secret '%v' not found in local vault", name)
+	}
+	return value, nil
+}
+
+// SetSecret set secret (not saved until Save is called)
+func (v *LocalVault) SetSecret(name, value string) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	v.secrets[name] = value
+}
+
+// RemoveSecret remove secret (not saved until Save is called)
+func (v *LocalVault) RemoveSecret(name string) bool {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+	_, exist := v.secrets[name]
+	delete(v.secrets, name)
+	return exist
+}
+
+// Names get sorted names of secrets
+func (v *LocalVault) Names() []string {
+	v.lock.RLock()
+	defer v.lock.RUnlock()
+	names := make([]string, 0, len(v.secrets))
+	for name := range v.secrets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Save encrypt and write local vault file, with new salt and nonce
+func (v *LocalVault) Save() error {
+	v.lock.RLock()
+	plainText, err := json.Marshal(v.secrets)
+	v.lock.RUnlock()
+	if err != nil {
+		return err
+	}
+	salt := make([]byte, 32)
+	if _, err = io.ReadFull(rand.Reader, salt); err != nil {
+		return err
+	}
+	aead, err := newVaultCipher(v.password, salt, vaultScryptN, vaultScryptR, vaultScryptP)
+	if err != nil {
+		return err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return err
+	}
+	vf := &localVaultFile{
+		Version:    localVaultVersion,
+		ScryptN:    vaultScryptN,
+		ScryptR:    vaultScryptR,
+		ScryptP:    vaultScryptP,
+		Salt:       hex.EncodeToString(salt),
+		Nonce:      hex.EncodeToString(nonce),
+		CipherText: hex.EncodeToString(aead.Seal(nil, nonce, plainText, nil)),
+	}
+	data, err := json.MarshalIndent(vf, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(v.file, data, 0600)
+}
@@ -0,0 +1,167 @@
+// Package secrets provides secrets (eg. keystore passwords, db credentials)
+// by reference in form of 'scheme:name' from pluggable providers.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strings"
+	"sync"
+)
+
+// secret reference schemes
+const (
+	SchemeEnv        = "env"        // env:NAME
+	SchemeFile       = "file"       // file:/path/to/file
+	SchemeLocalVault = "localvault" // localvault:name
+	SchemeVault      = "vault"      // vault:secret/data/bridge#field
+)
+
+// Provider provides secret by name
+type Provider interface {
+	GetSecret(name string) (string, error)
+}
+
+// Config secrets providers config
+type Config struct {
+	// encrypted local vault file, and the reference of its password
+	LocalVaultFile     string
+	LocalVaultPassword string `json:"-"`
+
+	// hashicorp vault compatible http api, and the reference of its token
+	VaultAddress string
+	VaultToken   string `json:"-"`
+}
+
+var (
+	providers = map[string]Provider{
+		SchemeEnv:  envProvider{},
+		SchemeFile: fileProvider{},
+	}
+	providersLock sync.RWMutex
+
+	knownSchemes = []string{SchemeEnv, SchemeFile, SchemeLocalVault, SchemeVault}
+)
+
+// CheckConfig check secrets config
+func (c *Config) CheckConfig() error {
+	if c.LocalVaultFile != "" && c.LocalVaultPassword == "" {
+		return errors.New("secrets must config 'LocalVaultPassword' if 'LocalVaultFile' is configed")
+	}
+	if c.VaultAddress != "" && c.VaultToken == "" {
+		return errors.New("secrets must config 'VaultToken' if 'VaultAddress' is configed")
+	}
+	return nil
+}
+
+// Init init secrets providers of local vault and vault api
+func Init(cfg *Config) error {
+	if cfg == nil {
+		return nil
+	}
+	if err := cfg.CheckConfig(); err != nil {
+		return err
+	}
+	if cfg.LocalVaultFile != "" {
+		password, err := GetSecret(cfg.LocalVaultPassword)
+		if err != nil {
+			return fmt.Errorf("get local vault password failed: %w", err)
+		}
+		vault, err := LoadLocalVault(cfg.LocalVaultFile, password)
+		if err != nil {
+			return err
+		}
+		RegisterProvider(SchemeLocalVault, vault)
+	}
+	if cfg.VaultAddress != "" {
+		token, err := GetSecret(cfg.VaultToken)
+		if err != nil {
+			return fmt.Errorf("get vault token failed: %w", err)
+		}
+		RegisterProvider(SchemeVault, NewVaultProvider(cfg.VaultAddress, token))
+	}
+	return nil
+}
+
+// RegisterProvider register provider of scheme
+func RegisterProvider(scheme string, provider Provider) {
+	providersLock.Lock()
+	defer providersLock.Unlock()
+	providers[scheme] = provider
+}
+
+func getProvider(scheme string) (Provider, error) {
+	providersLock.RLock()
+	defer providersLock.RUnlock()
+	provider, exist := providers[scheme]
+	if !exist {
+		return nil, fmt.Errorf("secrets provider '%v' is not configed", scheme)
+	}
+	return provider, nil
+}
+
+// splitReference split reference into scheme and name,
+// scheme is empty if the reference is not in form of 'scheme:name'
+func splitReference(ref string) (scheme, name string) {
+	parts := strings.SplitN(ref, ":", 2)
+	if len(parts) != 2 {
+		return "", ref
+	}
+	for _, known := range knownSchemes {
+		if parts[0] == known {
+			return parts[0], parts[1]
+		}
+	}
+	return "", ref
+}
+
+// GetSecret get secret of reference, reference without known scheme
+// is the secret itself (eg. plain password in config file)
+func GetSecret(ref string) (string, error) {
+	return getSecret(ref, "")
+}
+
+// GetPassword get password of reference, reference without known scheme
+// is the path of password file (eg. '--password' flag, 'PasswordFile' config)
+func GetPassword(ref string) (string, error) {
+	return getSecret(ref, SchemeFile)
+}
+
+func getSecret(ref, defaultScheme string) (string, error) {
+	scheme, name := splitReference(ref)
+	if scheme == "" {
+		if defaultScheme == "" {
+			return ref, nil
+		}
+		scheme = defaultScheme
+	}
+	provider, err := getProvider(scheme)
+	if err != nil {
+		return "", err
+	}
+	return provider.GetSecret(name)
+}
+
+type envProvider struct{}
+
+// GetSecret get secret from environment variable
+func (envProvider) GetSecret(name string) (string, error) {
+	value, exist := os.LookupEnv(name)
+	if !exist {
+		return "", fmt.Errorf("environment variable '%v' is not set", name)
+	}
+	return value, nil
+}
+
+type fileProvider struct{}
+
+// GetSecret get secret from file content (spaces trimmed)
+func (fileProvider) GetSecret(name string) (string, error) {
+	data, err := ioutil.ReadFile(name)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
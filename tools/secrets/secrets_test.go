@@ -0,0 +1,141 @@
+package secrets
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetSecret(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	passFile := filepath.Join(dir, "password.txt")
+	if err = ioutil.WriteFile(passFile, []byte(" filepass\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	os.Setenv("SECRETS_TEST_PASSWORD", "envpass")
+	defer os.Unsetenv("SECRETS_TEST_PASSWORD")
+
+	tests := []struct {
+		ref        string
+		isPassword bool
+		want       string
+		wantErr    bool
+	}{
+		{"env:SECRETS_TEST_PASSWORD", false, "envpass", false},
+		{"env:SECRETS_TEST_NOT_SET", false, "", true},
+		{"file:" + passFile, false, "filepass", false},
+		{passFile, true, "filepass", false},
+		{"plain:pass", false, "plain:pass", false},
+		{"vault:secret/data/bridge#dbpass", false, "", true},
+	}
+	for _, test := range tests {
+		get := GetSecret
+		if test.isPassword {
+			get = GetPassword
+		}
+		have, err := get(test.ref)
+		if (err != nil) != test.wantErr {
+			t.Errorf("get secret '%v': unexpected error %v", test.ref, err)
+			continue
+		}
+		if have != test.want {
+			t.Errorf("get secret '%v': have '%v' want '%v'", test.ref, have, test.want)
+		}
+	}
+}
+
+func TestLocalVault(t *testing.T) {
+	dir, err := ioutil.TempDir("", "secrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	vaultFile := filepath.Join(dir, "secrets.vault")
+
+	vault, err := NewLocalVault(vaultFile, "vaultpass")
+	if err != nil {
+		t.Fatal(err)
+	}
+	vault.SetSecret("dbpass", "s3cret")
+	if err = vault.Save(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = LoadLocalVault(vaultFile, "wrongpass"); err != errWrongVaultPassword {
+		t.Errorf("load with wrong password: have error %v want %v", err, errWrongVaultPassword)
+	}
+
+	os.Setenv("SECRETS_TEST_VAULT_PASSWORD", "vaultpass")
+	defer os.Unsetenv("SECRETS_TEST_VAULT_PASSWORD")
+	err = Init(&Config{
+		LocalVaultFile:     vaultFile,
+		LocalVaultPassword: "env:SECRETS_TEST_VAULT_PASSWORD",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if have, err := GetSecret("localvault:dbpass"); err != nil || have != "s3cret" {
+		t.Errorf("get local vault secret: have '%v' error %v", have, err)
+	}
+	if _, err := GetSecret("localvault:missing"); err == nil {
+		t.Error("get missing local vault secret should fail")
+	}
+}
+
+func TestVaultProvider(t *testing.T) {
+	secretsData := map[string]interface{}{
+		"/v1/secret/data/bridge": map[string]interface{}{ // kv version 2
+			"data": map[string]interface{}{"dbpass": "kv2pass"},
+		},
+		"/v1/kv/bridge": map[string]interface{}{ // kv version 1
+			"dbpass": "kv1pass",
+		},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "roottoken" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		data, exist := secretsData[r.URL.Path]
+		if !exist {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"data": data})
+	}))
+	defer server.Close()
+
+	provider := NewVaultProvider(server.URL+"/", "roottoken")
+	tests := []struct {
+		name    string
+		want    string
+		wantErr bool
+	}{
+		{"secret/data/bridge#dbpass", "kv2pass", false},
+		{"kv/bridge#dbpass", "kv1pass", false},
+		{"kv/bridge#missing", "", true},
+		{"kv/missing#dbpass", "", true},
+		{"kv/bridge", "", true},
+	}
+	for _, test := range tests {
+		have, err := provider.GetSecret(test.name)
+		if (err != nil) != test.wantErr {
+			t.Errorf("get vault secret '%v': unexpected error %v", test.name, err)
+			continue
+		}
+		if have != test.want {
+			t.Errorf("get vault secret '%v': have '%v' want '%v'", test.name, have, test.want)
+		}
+	}
+
+	if _, err := NewVaultProvider(server.URL, "badtoken").GetSecret("kv/bridge#dbpass"); err == nil {
+		t.Error("get vault secret with bad token should fail")
+	}
+}
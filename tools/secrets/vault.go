@@ -0,0 +1,62 @@
+package secrets
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+)
+
+const vaultRequestTimeout = 10 // seconds
+
+// VaultProvider get secrets from hashicorp vault compatible http api.
+// secret name is in form of 'path#field', eg. 'secret/data/bridge#dbpassword'
+// reads field 'dbpassword' of kv secret at '<address>/v1/secret/data/bridge'.
+// both kv version 1 and version 2 (which nests fields in 'data') are supported.
+type VaultProvider struct {
+	address string
+	token   string
+}
+
+// vaultResponse response of reading secret
+type vaultResponse struct {
+	Data map[string]interface{} `json:"data"`
+}
+
+// NewVaultProvider new vault provider
+func NewVaultProvider(address, token string) *VaultProvider {
+	return &VaultProvider{
+		address: strings.TrimSuffix(address, "/"),
+		token:   token,
+	}
+}
+
+// GetSecret impl Provider
+func (p *VaultProvider) GetSecret(name string) (string, error) {
+	parts := strings.SplitN(name, "#", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return "", fmt.Errorf("wrong vault secret name '%v', should be 'path#field'", name)
+	}
+	path, field := strings.TrimPrefix(parts[0], "/"), parts[1]
+
+	url := fmt.Sprintf("%v/v1/%v", p.address, path)
+	headers := map[string]string{"X-Vault-Token": p.token}
+	var result vaultResponse
+	err := client.RPCGetRequest(&result, url, nil, headers, vaultRequestTimeout)
+	if err != nil {
+		return "", fmt.Errorf("read vault secret at '%v' failed: %w", path, err)
+	}
+	data := result.Data
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		data = nested // kv version 2
+	}
+	value, exist := data[field]
+	if !exist {
+		return "", fmt.Errorf("field '%v' not found in vault secret at '%v'", field, path)
+	}
+	str, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("field '%v' of vault secret at '%v' is not string", field, path)
+	}
+	return str, nil
+}
@@ -0,0 +1,60 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/tools"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+// LocalSigner sign with private key in memory
+type LocalSigner struct {
+	privKey *ecdsa.PrivateKey
+	address common.Address
+}
+
+// NewLocalSigner new local signer of private key
+func NewLocalSigner(privKey *ecdsa.PrivateKey) *LocalSigner {
+	return &LocalSigner{
+		privKey: privKey,
+		address: crypto.PubkeyToAddress(privKey.PublicKey),
+	}
+}
+
+// NewLocalSignerFromConfig new local signer from key file or keystore
+func NewLocalSignerFromConfig(cfg *Config) (*LocalSigner, error) {
+	switch {
+	case cfg.KeyFile != "":
+		privKey, err := crypto.LoadECDSA(cfg.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("wrong private key, %v", err)
+		}
+		return NewLocalSigner(privKey), nil
+	case cfg.KeyStore != "":
+		key, err := tools.LoadKeyStore(cfg.KeyStore, cfg.PasswordFile)
+		if err != nil {
+			return nil, err
+		}
+		return NewLocalSigner(key.PrivateKey), nil
+	default:
+		return nil, errors.New("local signer must config key file or keystore")
+	}
+}
+
+// Address impl Signer
+func (s *LocalSigner) Address() common.Address {
+	return s.address
+}
+
+// PublicKey impl Signer
+func (s *LocalSigner) PublicKey() *ecdsa.PublicKey {
+	return &s.privKey.PublicKey
+}
+
+// SignHash impl Signer
+func (s *LocalSigner) SignHash(hash []byte) ([]byte, error) {
+	return crypto.Sign(hash, s.privKey)
+}
@@ -0,0 +1,132 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+	"github.com/anyswap/CrossChain-Bridge/tools/secrets"
+	"github.com/btcsuite/btcd/btcec"
+)
+
+const (
+	defaultPKCS11Tool = "pkcs11-tool"
+
+	// pkcs11PinEnv environment variable to pass pin to 'pkcs11-tool',
+	// pin on command line is visible to other users by 'ps' and '/proc'.
+	pkcs11PinEnv = "BRIDGE_PKCS11_PIN"
+)
+
+// PKCS11Signer sign with key in pkcs11 token (HSM, smart card),
+// it calls OpenSC 'pkcs11-tool' (0.21+ which supports 'env:' pin)
+// to avoid linking cgo pkcs11 library.
+type PKCS11Signer struct {
+	tool    string
+	module  string
+	slot    string
+	keyID   string
+	pin     string
+	pubKey  *ecdsa.PublicKey
+	address common.Address
+}
+
+// NewPKCS11Signer new pkcs11 signer
+func NewPKCS11Signer(cfg *Config) (*PKCS11Signer, error) {
+	if cfg.PKCS11Module == "" || cfg.PKCS11KeyID == "" {
+		return nil, errors.New("pkcs11 signer must config module and key id")
+	}
+	pubKey, err := ParsePublicKey(cfg.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	s := &PKCS11Signer{
+		tool:    cfg.PKCS11Tool,
+		module:  cfg.PKCS11Module,
+		slot:    cfg.PKCS11Slot,
+		keyID:   cfg.PKCS11KeyID,
+		pubKey:  pubKey,
+		address: crypto.PubkeyToAddress(*pubKey),
+	}
+	if s.tool == "" {
+		s.tool = defaultPKCS11Tool
+	}
+	if cfg.PKCS11PinFile != "" {
+		s.pin, err = secrets.GetPassword(cfg.PKCS11PinFile)
+		if err != nil {
+			return nil, fmt.Errorf("read pkcs11 pin fail %v", err)
+		}
+	}
+	return s, nil
+}
+
+// Address impl Signer
+func (s *PKCS11Signer) Address() common.Address {
+	return s.address
+}
+
+// PublicKey impl Signer
+func (s *PKCS11Signer) PublicKey() *ecdsa.PublicKey {
+	return s.pubKey
+}
+
+// SignHash impl Signer
+func (s *PKCS11Signer) SignHash(hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, errWrongHashLength
+	}
+	dir, err := ioutil.TempDir("", "pkcs11sign")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(dir)
+
+	inputFile := filepath.Join(dir, "hash")
+	outputFile := filepath.Join(dir, "signature")
+	err = ioutil.WriteFile(inputFile, hash, 0600)
+	if err != nil {
+		return nil, err
+	}
+
+	args := []string{"--module", s.module}
+	if s.slot != "" {
+		args = append(args, "--slot", s.slot)
+	}
+	if s.pin != "" {
+		args = append(args, "--login", "--pin", "env:"+pkcs11PinEnv)
+	}
+	args = append(args,
+		"--sign", "--mechanism", "ECDSA", "--id", s.keyID,
+		"--input-file", inputFile, "--output-file", outputFile)
+
+	// #nosec G204 the program and args are from config
+	cmd := exec.Command(s.tool, args...)
+	if s.pin != "" {
+		cmd.Env = append(os.Environ(), pkcs11PinEnv+"="+s.pin)
+	}
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		return nil, fmt.Errorf("pkcs11 sign failed, %v, %v", err, strings.TrimSpace(string(output)))
+	}
+	sig, err := ioutil.ReadFile(outputFile)
+	if err != nil {
+		return nil, err
+	}
+	if len(sig) != 64 {
+		// signature in DER format
+		derSig, errf := btcec.ParseDERSignature(sig, btcec.S256())
+		if errf != nil {
+			return nil, fmt.Errorf("wrong pkcs11 signature, %v", errf)
+		}
+		sig = make([]byte, 64)
+		copy(sig[32-len(derSig.R.Bytes()):32], derSig.R.Bytes())
+		copy(sig[64-len(derSig.S.Bytes()):], derSig.S.Bytes())
+	}
+	return FinalizeSignature(hash, sig, s.pubKey)
+}
@@ -0,0 +1,87 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"errors"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/common/hexutil"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+const (
+	// RemoteSignHashMethod json-rpc method of remote signing service.
+	// params are address of signing key and the 32 bytes hash in hex,
+	// result is signature in hex of [R || S] or [R || S || V] format.
+	//
+	// it is a protocol of this project, not served by existing signers.
+	// Clef 'account_signData' and web3signer 'eth1/sign' hash the given
+	// data themselves (keccak256, with or without prefix), so they can not
+	// sign the sighash of utxo chains or sign doc hash of cosmos chains.
+	// a custom service (eg. an adaptor in front of HSM or KMS) which
+	// signs raw hash is required, request and response are like:
+	//
+	// --> {"jsonrpc":"2.0","id":1,"method":"signer_signHash","params":["0x<address>","0x<32 bytes hash>"]}
+	// <-- {"jsonrpc":"2.0","id":1,"result":"0x<64 or 65 bytes signature>"}
+	//
+	// signature is verified against the configed public key, and is
+	// normalized to low S form, so the service can return either form.
+	RemoteSignHashMethod = "signer_signHash"
+
+	defaultRemoteTimeout = 30 // seconds
+	remoteRequestID      = 1
+)
+
+// RemoteSigner sign with remote signing service (json-rpc over http),
+// the service must serve 'RemoteSignHashMethod'.
+type RemoteSigner struct {
+	url     string
+	timeout int
+	pubKey  *ecdsa.PublicKey
+	address common.Address
+}
+
+// NewRemoteSigner new remote signer
+func NewRemoteSigner(cfg *Config) (*RemoteSigner, error) {
+	if cfg.URL == "" {
+		return nil, errors.New("remote signer must config url")
+	}
+	pubKey, err := ParsePublicKey(cfg.PublicKey)
+	if err != nil {
+		return nil, err
+	}
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultRemoteTimeout
+	}
+	return &RemoteSigner{
+		url:     cfg.URL,
+		timeout: timeout,
+		pubKey:  pubKey,
+		address: crypto.PubkeyToAddress(*pubKey),
+	}, nil
+}
+
+// Address impl Signer
+func (s *RemoteSigner) Address() common.Address {
+	return s.address
+}
+
+// PublicKey impl Signer
+func (s *RemoteSigner) PublicKey() *ecdsa.PublicKey {
+	return s.pubKey
+}
+
+// SignHash impl Signer
+func (s *RemoteSigner) SignHash(hash []byte) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, errWrongHashLength
+	}
+	var result hexutil.Bytes
+	err := client.RPCPostWithTimeoutAndID(&result, s.timeout, remoteRequestID, s.url, RemoteSignHashMethod, s.address.String(), hexutil.Encode(hash))
+	if err != nil {
+		return nil, err
+	}
+	return FinalizeSignature(hash, result, s.pubKey)
+}
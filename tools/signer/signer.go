@@ -0,0 +1,124 @@
+// Package signer provides signers of secp256k1 keys which are not held by dcrm,
+// the key can be kept in local keystore, remote signing service, or hardware token.
+package signer
+
+import (
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+// signer types
+const (
+	TypeLocal  = "local"
+	TypeRemote = "remote"
+	TypePKCS11 = "pkcs11"
+)
+
+var (
+	secp256k1N     = crypto.S256().Params().N
+	secp256k1halfN = new(big.Int).Rsh(secp256k1N, 1)
+
+	errWrongHashLength = errors.New("hash is required to be exactly 32 bytes")
+)
+
+// Signer sign message hash with secp256k1 key
+type Signer interface {
+	// Address returns eth style address of the signing key
+	Address() common.Address
+	// PublicKey returns public key of the signing key
+	PublicKey() *ecdsa.PublicKey
+	// SignHash sign 32 bytes hash, returns 65 bytes signature in [R || S || V] format,
+	// where V is 0 or 1 and S is in the lower half of the curve order.
+	SignHash(hash []byte) ([]byte, error)
+}
+
+// Config signer config
+type Config struct {
+	Type string
+
+	// local signer
+	KeyFile      string // hex private key file
+	KeyStore     string
+	PasswordFile string
+
+	// remote signer
+	URL     string
+	Timeout int // seconds
+
+	// pkcs11 signer
+	PKCS11Tool    string // pkcs11-tool program, default is 'pkcs11-tool'
+	PKCS11Module  string // pkcs11 module library path
+	PKCS11Slot    string
+	PKCS11KeyID   string
+	PKCS11PinFile string
+
+	// public key of remote and pkcs11 signer (uncompressed or compressed hex)
+	PublicKey string
+}
+
+// NewSigner new signer from config
+func NewSigner(cfg *Config) (Signer, error) {
+	switch strings.ToLower(cfg.Type) {
+	case TypeLocal, "":
+		return NewLocalSignerFromConfig(cfg)
+	case TypeRemote:
+		return NewRemoteSigner(cfg)
+	case TypePKCS11:
+		return NewPKCS11Signer(cfg)
+	default:
+		return nil, fmt.Errorf("unknown signer type '%v'", cfg.Type)
+	}
+}
+
+// ParsePublicKey parse uncompressed or compressed public key in hex
+func ParsePublicKey(pubkeyHex string) (*ecdsa.PublicKey, error) {
+	pkBytes := common.FromHex(pubkeyHex)
+	switch len(pkBytes) {
+	case 65:
+		return crypto.UnmarshalPubkey(pkBytes)
+	case 33:
+		return crypto.DecompressPubkey(pkBytes)
+	default:
+		return nil, fmt.Errorf("wrong public key '%v'", pubkeyHex)
+	}
+}
+
+// FinalizeSignature make signature of [R || S] or [R || S || V] format
+// into canonical [R || S || V] format (low S and V of 0 or 1),
+// and verify it is signed by the public key.
+func FinalizeSignature(hash, sig []byte, pubKey *ecdsa.PublicKey) ([]byte, error) {
+	if len(hash) != 32 {
+		return nil, errWrongHashLength
+	}
+	if len(sig) != 64 && len(sig) != 65 {
+		return nil, fmt.Errorf("wrong signature length %v", len(sig))
+	}
+	r := new(big.Int).SetBytes(sig[:32])
+	s := new(big.Int).SetBytes(sig[32:64])
+	if r.Sign() == 0 || s.Sign() == 0 || r.Cmp(secp256k1N) >= 0 || s.Cmp(secp256k1N) >= 0 {
+		return nil, errors.New("wrong signature values")
+	}
+	if s.Cmp(secp256k1halfN) > 0 {
+		s.Sub(secp256k1N, s)
+	}
+	result := make([]byte, 65)
+	copy(result[32-len(r.Bytes()):32], r.Bytes())
+	copy(result[64-len(s.Bytes()):64], s.Bytes())
+
+	// recover V as S may be changed
+	want := crypto.PubkeyToAddress(*pubKey)
+	for v := byte(0); v < 2; v++ {
+		result[64] = v
+		pub, err := crypto.SigToPub(hash, result)
+		if err == nil && crypto.PubkeyToAddress(*pub) == want {
+			return result, nil
+		}
+	}
+	return nil, errors.New("signature is not signed by the signer key")
+}
@@ -0,0 +1,189 @@
+package signer
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/common/hexutil"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+var testHash = crypto.Keccak256([]byte("test signer"))
+
+func checkSignature(t *testing.T, s Signer, sig []byte) {
+	if len(sig) != 65 {
+		t.Fatalf("wrong signature length %v", len(sig))
+	}
+	pub, err := crypto.SigToPub(testHash, sig)
+	if err != nil {
+		t.Fatalf("recover error: %v", err)
+	}
+	if crypto.PubkeyToAddress(*pub) != s.Address() {
+		t.Errorf("signer mismatch: want %v have %v", s.Address().String(), crypto.PubkeyToAddress(*pub).String())
+	}
+	if !crypto.VerifySignature(crypto.CompressPubkey(s.PublicKey()), testHash, sig[:64]) {
+		t.Errorf("verify signature failed")
+	}
+}
+
+// toHighS make a valid but non-canonical signature in [R || S] format
+func toHighS(sig []byte) []byte {
+	s := new(big.Int).SetBytes(sig[32:64])
+	s.Sub(secp256k1N, s)
+	result := make([]byte, 64)
+	copy(result, sig[:32])
+	copy(result[64-len(s.Bytes()):], s.Bytes())
+	return result
+}
+
+func TestLocalSigner(t *testing.T) {
+	s := NewTestSigner()
+	sig, err := s.SignHash(testHash)
+	if err != nil {
+		t.Fatalf("sign error: %v", err)
+	}
+	checkSignature(t, s, sig)
+
+	if _, err = s.SignHash(testHash[:31]); err == nil {
+		t.Errorf("sign hash of wrong length without error")
+	}
+}
+
+func TestTestSigner(t *testing.T) {
+	s := NewTestSigner()
+	wantErr := errors.New("test error")
+	s.SetError(wantErr)
+	if _, err := s.SignHash(testHash); err != wantErr {
+		t.Errorf("want error %v, have %v", wantErr, err)
+	}
+	s.SetError(nil)
+	if _, err := s.SignHash(testHash); err != nil {
+		t.Errorf("sign error: %v", err)
+	}
+	hashes := s.SignedHashes()
+	if len(hashes) != 1 || !bytes.Equal(hashes[0], testHash) {
+		t.Errorf("wrong signed hashes %x", hashes)
+	}
+}
+
+func TestFinalizeSignature(t *testing.T) {
+	s := NewTestSigner()
+	sig, _ := s.SignHash(testHash)
+
+	highS := toHighS(sig)
+	result, err := FinalizeSignature(testHash, highS, s.PublicKey())
+	if err != nil {
+		t.Fatalf("finalize error: %v", err)
+	}
+	if !bytes.Equal(result, sig) {
+		t.Errorf("finalize mismatch: want %x have %x", sig, result)
+	}
+
+	other := NewTestSigner()
+	if _, err = FinalizeSignature(testHash, sig, other.PublicKey()); err == nil {
+		t.Errorf("finalize signature of other key without error")
+	}
+	if _, err = FinalizeSignature(testHash, sig[:63], s.PublicKey()); err == nil {
+		t.Errorf("finalize signature of wrong length without error")
+	}
+}
+
+func TestRemoteSigner(t *testing.T) {
+	key := NewTestSigner()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     int
+			Method string
+			Params []string
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request error: %v", err)
+		}
+		if req.Method != RemoteSignHashMethod || len(req.Params) != 2 || req.Params[0] != key.Address().String() {
+			t.Errorf("wrong request %+v", req)
+		}
+		sig, _ := key.SignHash(hexutil.MustDecode(req.Params[1]))
+		// remote service may return signature of [R || S] with high S
+		result := hexutil.Encode(toHighS(sig))
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result})
+	}))
+	defer server.Close()
+
+	s, err := NewSigner(&Config{
+		Type:      TypeRemote,
+		URL:       server.URL,
+		PublicKey: hexutil.Encode(crypto.FromECDSAPub(key.PublicKey())),
+	})
+	if err != nil {
+		t.Fatalf("new remote signer error: %v", err)
+	}
+	sig, err := s.SignHash(testHash)
+	if err != nil {
+		t.Fatalf("remote sign error: %v", err)
+	}
+	checkSignature(t, s, sig)
+}
+
+func TestPKCS11Signer(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("fake pkcs11-tool is shell script")
+	}
+	key := NewTestSigner()
+	sig, _ := key.SignHash(testHash)
+
+	dir, err := ioutil.TempDir("", "pkcs11test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	pinFile := filepath.Join(dir, "pin")
+	if err = ioutil.WriteFile(pinFile, []byte("123456\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	// fake pkcs11-tool copies prepared signature to the output file,
+	// it fails if pin is not passed by environment variable
+	sigFile := filepath.Join(dir, "prepared")
+	if err = ioutil.WriteFile(sigFile, toHighS(sig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	tool := filepath.Join(dir, "pkcs11-tool")
+	script := "#!/bin/sh\n" +
+		"while [ $# -gt 0 ]; do\n" +
+		"  if [ \"$1\" = \"--pin\" ] && [ \"$2\" != \"env:" + pkcs11PinEnv + "\" ]; then echo \"pin in args\"; exit 1; fi\n" +
+		"  if [ \"$1\" = \"--output-file\" ]; then cp " + sigFile + " \"$2\"; fi\n" +
+		"  shift\n" +
+		"done\n" +
+		"[ \"$" + pkcs11PinEnv + "\" = \"123456\" ] || { echo \"wrong pin\"; exit 1; }\n"
+	// #nosec G306 the fake tool must be executable
+	if err = ioutil.WriteFile(tool, []byte(script), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := NewSigner(&Config{
+		Type:          TypePKCS11,
+		PKCS11Tool:    tool,
+		PKCS11Module:  "/usr/lib/softhsm/libsofthsm2.so",
+		PKCS11KeyID:   "01",
+		PKCS11PinFile: pinFile,
+		PublicKey:     hexutil.Encode(crypto.CompressPubkey(key.PublicKey())),
+	})
+	if err != nil {
+		t.Fatalf("new pkcs11 signer error: %v", err)
+	}
+	result, err := s.SignHash(testHash)
+	if err != nil {
+		t.Fatalf("pkcs11 sign error: %v", err)
+	}
+	checkSignature(t, s, result)
+}
@@ -0,0 +1,58 @@
+package signer
+
+import (
+	"crypto/ecdsa"
+	"sync"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/tools/crypto"
+)
+
+// TestSigner is a stand-in signer for tests, it signs with random key
+// in memory, records the signed hashes, and can be made to fail.
+type TestSigner struct {
+	*LocalSigner
+
+	lock   sync.Mutex
+	hashes [][]byte
+	err    error
+}
+
+// NewTestSigner new test signer with random key
+func NewTestSigner() *TestSigner {
+	privKey, err := crypto.GenerateKey()
+	if err != nil {
+		panic(err)
+	}
+	return &TestSigner{LocalSigner: NewLocalSigner(privKey)}
+}
+
+// SetError make the following signing return this error (nil to reset)
+func (s *TestSigner) SetError(err error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.err = err
+}
+
+// SignedHashes returns hashes signed by this signer
+func (s *TestSigner) SignedHashes() [][]byte {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	return append([][]byte{}, s.hashes...)
+}
+
+// SignHash impl Signer
+func (s *TestSigner) SignHash(hash []byte) ([]byte, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	s.hashes = append(s.hashes, common.CopyBytes(hash))
+	return s.LocalSigner.SignHash(hash)
+}
+
+// PrivateKey returns private key of test signer
+func (s *TestSigner) PrivateKey() *ecdsa.PrivateKey {
+	return s.privKey
+}
@@ -32,6 +32,14 @@ type RPCBlock struct {
 	Uncles          []*common.Hash  `json:"uncles"`
 }
 
+// RPCBlockWithTxs struct of block with full transactions
+type RPCBlockWithTxs struct {
+	Hash         *common.Hash      `json:"hash"`
+	Number       *hexutil.Big      `json:"number"`
+	Time         *hexutil.Big      `json:"timestamp"`
+	Transactions []*RPCTransaction `json:"transactions"`
+}
+
 // RPCTransaction struct
 type RPCTransaction struct {
 	Hash             *common.Hash    `json:"hash"`
@@ -128,3 +136,40 @@ func ToBlockNumArg(number *big.Int) string {
 	}
 	return hexutil.EncodeBig(number)
 }
+
+// RPCCallFrame call frame traced by geth 'callTracer'
+type RPCCallFrame struct {
+	Type  string          `json:"type"`
+	From  *common.Address `json:"from"`
+	To    *common.Address `json:"to"`
+	Value *hexutil.Big    `json:"value"`
+	Error string          `json:"error"`
+	Calls []*RPCCallFrame `json:"calls"`
+}
+
+// RPCTxTraceResult result of 'debug_traceBlockByNumber'
+type RPCTxTraceResult struct {
+	TxHash *common.Hash  `json:"txHash"`
+	Result *RPCCallFrame `json:"result"`
+	Error  string        `json:"error"`
+}
+
+// RPCTraceAction action of parity trace
+type RPCTraceAction struct {
+	CallType      string          `json:"callType"`
+	From          *common.Address `json:"from"`
+	To            *common.Address `json:"to"`
+	Value         *hexutil.Big    `json:"value"`
+	Address       *common.Address `json:"address"`
+	RefundAddress *common.Address `json:"refundAddress"`
+	Balance       *hexutil.Big    `json:"balance"`
+}
+
+// RPCParityTrace result item of 'trace_transaction' and 'trace_block'
+type RPCParityTrace struct {
+	Type            string          `json:"type"`
+	Action          *RPCTraceAction `json:"action"`
+	Error           string          `json:"error"`
+	TraceAddress    []int           `json:"traceAddress"`
+	TransactionHash *common.Hash    `json:"transactionHash"`
+}
@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 	"sync"
 	"time"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/anyswap/CrossChain-Bridge/params"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
 )
 
 var (
@@ -60,11 +63,13 @@ func acceptSign() {
 				continue
 			}
 			agreeResult := "AGREE"
-			err := verifySignInfo(info)
+			args, err := verifySignInfo(info)
 			switch err {
 			case errIdentifierMismatch,
 				errInitiatorMismatch,
 				errWrongMsgContext,
+				errPairConfigMismatch,
+				tools.ErrSwapNotObserved,
 				tokens.ErrUnknownPairID,
 				tokens.ErrNoBtcBridge,
 				tokens.ErrTxNotStable,
@@ -83,39 +88,81 @@ func acceptSign() {
 			} else {
 				logWorker("accept", "accept sign job finish", "keyID", keyID, "result", agreeResult)
 				addAcceptSignHistory(keyID, agreeResult, info.MsgHash, info.MsgContext)
+				if agreeResult == "AGREE" {
+					endorseSignInfo(args, keyID, info.MsgHash)
+				}
 			}
 		}
 		time.Sleep(waitInterval)
 	}
 }
 
-func verifySignInfo(signInfo *dcrm.SignInfoData) error {
+// endorseSignInfo record agreed swap or fee withdraw in swap ledger
+func endorseSignInfo(args *tokens.BuildTxArgs, keyID string, msgHash []string) {
+	isSwapin := args.SwapType == tokens.SwapinType
+	switch args.Identifier {
+	case params.GetIdentifier():
+		var swapFee *big.Int
+		if args.Extra != nil {
+			swapFee = args.Extra.SwapFee
+		}
+		tools.EndorseSwap(args.SwapID, args.PairID, args.Bind, isSwapin, keyID, msgHash, swapFee)
+	case tokens.FeeWithdrawIdentifier:
+		tools.AcceptFeeWithdraw(args.SwapID, args.PairID, args.Bind, isSwapin, args.OriginValue, keyID, msgHash)
+	}
+}
+
+func verifySignInfo(signInfo *dcrm.SignInfoData) (*tokens.BuildTxArgs, error) {
 	if !params.IsDcrmInitiator(signInfo.Account) {
-		return errInitiatorMismatch
+		return nil, errInitiatorMismatch
 	}
 	msgHash := signInfo.MsgHash
 	msgContext := signInfo.MsgContext
 	if len(msgContext) != 1 {
-		return errWrongMsgContext
+		return nil, errWrongMsgContext
 	}
 	var args tokens.BuildTxArgs
 	err := json.Unmarshal([]byte(msgContext[0]), &args)
 	if err != nil {
-		return errWrongMsgContext
+		return nil, errWrongMsgContext
+	}
+	switch args.Identifier {
+	case params.GetIdentifier(), tokens.AggregateIdentifier, tokens.FeeWithdrawIdentifier:
+		if isPairConfigMismatched(strings.ToLower(args.PairID)) {
+			logWorkerWarn("accept", "ignore sign as pair config mismatch with server", "pairID", args.PairID, "txid", args.SwapID, "identifier", args.Identifier)
+			return nil, errPairConfigMismatch
+		}
 	}
 	switch args.Identifier {
 	case params.GetIdentifier():
 	case tokens.AggregateIdentifier:
-		if btc.BridgeInstance == nil {
-			return tokens.ErrNoBtcBridge
+		bridge := btc.GetBridgeByPairID(args.PairID)
+		if bridge == nil {
+			return nil, tokens.ErrNoBtcBridge
 		}
 		logWorker("accept", "verifySignInfo", "msgHash", msgHash, "msgContext", msgContext)
-		return btc.BridgeInstance.VerifyAggregateMsgHash(msgHash, &args)
+		return &args, bridge.VerifyAggregateMsgHash(msgHash, &args)
+	case tokens.FeeWithdrawIdentifier:
+		logWorker("accept", "verifySignInfo", "msgHash", msgHash, "msgContext", msgContext)
+		return &args, rebuildAndVerifyFeeWithdrawMsgHash(msgHash, &args)
 	default:
-		return errIdentifierMismatch
+		return nil, errIdentifierMismatch
 	}
 	logWorker("accept", "verifySignInfo", "msgHash", msgHash, "msgContext", msgContext)
-	return rebuildAndVerifyMsgHash(msgHash, &args)
+	return &args, rebuildAndVerifyMsgHash(msgHash, &args)
+}
+
+// checkSwapObserved only sign swaps observed by scanning of this oracle itself
+func checkSwapObserved(args *tokens.BuildTxArgs, swapInfo *tokens.TxSwapInfo) error {
+	if !tools.IsSwapLedgerEnabled() {
+		return nil
+	}
+	isSwapin := args.SwapType == tokens.SwapinType
+	if !tools.IsSwapObserved(args.SwapID, args.PairID, args.Bind, isSwapin, swapInfo.Value) {
+		logWorkerWarn("accept", "swap is not observed", "pairID", args.PairID, "txid", args.SwapID, "bind", args.Bind, "swaptype", args.SwapType, "value", swapInfo.Value)
+		return tools.ErrSwapNotObserved
+	}
+	return nil
 }
 
 func rebuildAndVerifyMsgHash(msgHash []string, args *tokens.BuildTxArgs) error {
@@ -142,6 +189,19 @@ func rebuildAndVerifyMsgHash(msgHash []string, args *tokens.BuildTxArgs) error {
 		return err
 	}
 
+	err = checkSwapObserved(args, swapInfo)
+	if err != nil {
+		return err
+	}
+
+	if args.Extra != nil && args.Extra.SwapFee != nil {
+		err = tokens.VerifySwapFeeSnapshot(args.PairID, swapInfo.Value, args.Extra.SwapFee, args.SwapType == tokens.SwapinType)
+		if err != nil {
+			logWorkerError("accept", "verify swap fee failed", err, "pairID", args.PairID, "txid", args.SwapID, "bind", args.Bind, "swaptype", args.SwapType)
+			return err
+		}
+	}
+
 	buildTxArgs := &tokens.BuildTxArgs{
 		SwapInfo:    args.SwapInfo,
 		From:        tokenCfg.DcrmAddress,
@@ -155,6 +215,74 @@ func rebuildAndVerifyMsgHash(msgHash []string, args *tokens.BuildTxArgs) error {
 	return dstBridge.VerifyMsgHash(rawTx, msgHash)
 }
 
+// rebuildAndVerifyFeeWithdrawMsgHash fee withdraw is initiated by admin,
+// and can only be sent to the configed fee treasury. the amount is checked
+// against the fee of swaps endorsed by this oracle minus fee withdraws agreed
+// by it (recorded in swap ledger), and the balance of dcrm address.
+func rebuildAndVerifyFeeWithdrawMsgHash(msgHash []string, args *tokens.BuildTxArgs) error {
+	var bridge tokens.CrossChainBridge
+	switch args.SwapType {
+	case tokens.SwapinType:
+		bridge = tokens.DstBridge
+	case tokens.SwapoutType:
+		bridge = tokens.SrcBridge
+	default:
+		return fmt.Errorf("unknown swap type %v", args.SwapType)
+	}
+	feeWithdrawer, ok := bridge.(tokens.FeeWithdrawer)
+	if !ok {
+		return tokens.ErrFeeWithdrawNotSupported
+	}
+	tokenCfg := bridge.GetTokenConfig(args.PairID)
+	if tokenCfg == nil {
+		return tokens.ErrUnknownPairID
+	}
+	if tokenCfg.FeeTreasury == "" {
+		return tokens.ErrNoFeeTreasury
+	}
+	if !strings.EqualFold(args.Bind, tokenCfg.FeeTreasury) {
+		return fmt.Errorf("fee treasury mismatch, have %v want %v", args.Bind, tokenCfg.FeeTreasury)
+	}
+	if err := verifyFeeWithdrawAmount(bridge, tokenCfg, msgHash, args); err != nil {
+		return err
+	}
+
+	buildTxArgs := &tokens.BuildTxArgs{
+		SwapInfo:    args.SwapInfo,
+		OriginValue: args.OriginValue,
+		Extra:       args.Extra,
+	}
+	rawTx, err := feeWithdrawer.BuildFeeWithdrawTx(buildTxArgs)
+	if err != nil {
+		return err
+	}
+	return bridge.VerifyMsgHash(rawTx, msgHash)
+}
+
+func verifyFeeWithdrawAmount(bridge tokens.CrossChainBridge, tokenCfg *tokens.TokenConfig, msgHash []string, args *tokens.BuildTxArgs) error {
+	amount := args.OriginValue
+	if amount == nil || amount.Sign() <= 0 || args.SwapID == "" {
+		return errWrongFeeWithdrawAmount
+	}
+	isSwapin := args.SwapType == tokens.SwapinType
+	if accepted := tools.GetFeeWithdraw(args.SwapID, args.PairID, isSwapin); accepted != nil {
+		// the same withdraw tx may be signed again
+		if accepted.Value == amount.String() && strings.Join(accepted.MsgHash, ",") == strings.Join(msgHash, ",") {
+			return nil
+		}
+		return errFeeWithdrawAccepted
+	}
+	withdrawable, err := tools.GetWithdrawableFee(args.PairID, isSwapin)
+	if err != nil {
+		return err
+	}
+	if amount.Cmp(withdrawable) > 0 {
+		logWorkerWarn("accept", "fee withdraw amount exceeds withdrawable", "pairID", args.PairID, "withdrawID", args.SwapID, "amount", amount, "withdrawable", withdrawable)
+		return errFeeWithdrawExceed
+	}
+	return checkFeeWithdrawBalance(bridge, tokenCfg, args.PairID, amount, isSwapin)
+}
+
 type acceptSignInfo struct {
 	keyID      string
 	result     string
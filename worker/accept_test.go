@@ -0,0 +1,52 @@
+package worker
+
+import (
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+	"github.com/anyswap/CrossChain-Bridge/tokens/tools"
+)
+
+func TestCheckSwapObserved(t *testing.T) {
+	args := &tokens.BuildTxArgs{
+		SwapInfo: tokens.SwapInfo{
+			PairID:   "eth",
+			SwapID:   "tx1",
+			SwapType: tokens.SwapinType,
+			Bind:     "bind",
+		},
+	}
+	swapInfo := &tokens.TxSwapInfo{PairID: "eth", Bind: "bind", Value: big.NewInt(1000)}
+
+	// not checked if swap ledger is disabled
+	if err := checkSwapObserved(args, swapInfo); err != nil {
+		t.Fatalf("check swap without ledger failed, %v", err)
+	}
+
+	if err := tools.InitSwapLedger(filepath.Join(t.TempDir(), "swapledger.jsonl")); err != nil {
+		t.Fatal(err)
+	}
+	defer tools.CloseSwapLedger()
+
+	if err := checkSwapObserved(args, swapInfo); err != tools.ErrSwapNotObserved {
+		t.Fatalf("unobserved swap check error mismatch, have %v want %v", err, tools.ErrSwapNotObserved)
+	}
+
+	tools.ObserveSwap(true, "tx1", swapInfo, nil)
+	if err := checkSwapObserved(args, swapInfo); err != nil {
+		t.Fatalf("check observed swap failed, %v", err)
+	}
+
+	// server sign request of other value or swap type is refused
+	otherValue := &tokens.TxSwapInfo{PairID: "eth", Bind: "bind", Value: big.NewInt(999)}
+	if err := checkSwapObserved(args, otherValue); err != tools.ErrSwapNotObserved {
+		t.Errorf("swap of other value check error mismatch, have %v want %v", err, tools.ErrSwapNotObserved)
+	}
+	swapout := *args
+	swapout.SwapType = tokens.SwapoutType
+	if err := checkSwapObserved(&swapout, swapInfo); err != tools.ErrSwapNotObserved {
+		t.Errorf("swap of other type check error mismatch, have %v want %v", err, tools.ErrSwapNotObserved)
+	}
+}
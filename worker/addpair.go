@@ -9,7 +9,7 @@ import (
 	"github.com/fsnotify/fsnotify"
 )
 
-// AddTokenPairDynamically add token pair dynamically
+// AddTokenPairDynamically add, update and remove token pair dynamically
 func AddTokenPairDynamically() {
 	pairsDir := tokens.GetTokenPairsDir()
 	if pairsDir == "" {
@@ -33,6 +33,8 @@ func AddTokenPairDynamically() {
 	ops := []fsnotify.Op{
 		fsnotify.Create,
 		fsnotify.Write,
+		fsnotify.Remove,
+		fsnotify.Rename,
 	}
 
 	for {
@@ -44,9 +46,9 @@ func AddTokenPairDynamically() {
 			log.Trace("fsnotify watch event", "event", ev)
 			for _, op := range ops {
 				if ev.Op&op == op {
-					err = addTokenPair(ev.Name)
+					err = reloadTokenPairs(ev.Name)
 					if err != nil {
-						log.Info("addTokenPair error", "configFile", ev.Name, "err", err)
+						log.Info("reloadTokenPairs error", "configFile", ev.Name, "err", err)
 					}
 					break
 				}
@@ -60,19 +62,42 @@ func AddTokenPairDynamically() {
 	}
 }
 
-func addTokenPair(fileName string) error {
+func reloadTokenPairs(fileName string) error {
 	if !strings.HasSuffix(fileName, ".toml") {
 		return nil
 	}
 	fileStat, err := os.Stat(fileName)
-	// ignore if file is not exist, or is directory, or is empty file
-	if err != nil || fileStat.IsDir() || fileStat.Size() == 0 {
+	// ignore if file is directory, or is empty file (maybe in writing)
+	if err == nil && (fileStat.IsDir() || fileStat.Size() == 0) {
 		return nil
 	}
-	pairConfig, err := tokens.AddPairConfig(fileName)
+	changes, err := ReloadTokenPairs(false)
 	if err != nil {
 		return err
 	}
-	log.Info("addTokenPair success", "configFile", fileName, "pairID", pairConfig.PairID)
+	for _, change := range changes {
+		log.Info("reloadTokenPairs success", "configFile", fileName, "change", change.String())
+	}
 	return nil
 }
+
+// ReloadTokenPairs reload token pairs config in token pairs dir,
+// and start or stop swap jobs of added or removed pairs.
+// changes are only checked but not applied if dryRun.
+func ReloadTokenPairs(dryRun bool) ([]*tokens.PairConfigChange, error) {
+	changes, err := tokens.ReloadPairsConfig(dryRun)
+	if err != nil || dryRun || !isSwapJobStarted() {
+		return changes, err
+	}
+	for _, change := range changes {
+		switch change.Action {
+		case tokens.PairConfigAdd:
+			if pairCfg := tokens.GetTokenPairConfig(change.PairID); pairCfg != nil {
+				AddSwapJob(pairCfg)
+			}
+		case tokens.PairConfigRemove:
+			RemoveSwapJob(change.PairID)
+		}
+	}
+	return changes, nil
+}
@@ -1,9 +1,12 @@
 package worker
 
 import (
+	"errors"
+	"fmt"
 	"time"
 
 	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc/electrs"
 )
@@ -11,84 +14,257 @@ import (
 var (
 	utxoPageLimit = 100
 
-	aggSumVal   uint64
-	aggAddrs    []string
-	aggUtxos    []*electrs.ElectUtxo
-	aggOffset   int
 	aggInterval = 10 * time.Minute
+
+	errAggregateNotSerialized = errors.New("signed tx is not serialized in aggregate")
 )
 
+// aggregator collects utxos of one pair in a round, and aggregates them
+// once the collected ones are enough
+type aggregator struct {
+	bridge        btc.BridgeInterface
+	pairID        string
+	kind          string
+	relayFeePerKb int64
+	collected     map[string]struct{} // shared in a round, include utxos spent by pending aggregate txs
+
+	sumVal uint64
+	addrs  []string
+	utxos  []*electrs.ElectUtxo
+}
+
 // StartAggregateJob aggregate job
 func StartAggregateJob() {
-	if btc.BridgeInstance == nil {
+	if len(btc.GetBridges()) == 0 {
 		return
 	}
 
+	lease := newJobLease("aggregate", true)
 	for loop := 1; ; loop++ {
+		lease.waitHeld()
 		logWorker("aggregate", "start aggregate job", "loop", loop)
-		doAggregateJob()
+		for _, bridge := range btc.GetBridges() {
+			collected := recoverPendingAggregates(bridge)
+			doAggregateJob(bridge, collected)
+		}
 		logWorker("aggregate", "finish aggregate job", "loop", loop)
 		time.Sleep(aggInterval)
 	}
 }
 
-func doAggregateJob() {
-	aggOffset = 0
+func doAggregateJob(bridge btc.BridgeInterface, collected map[string]struct{}) {
+	relayFeePerKb, isLowFee, err := bridge.GetAggregateRelayFee()
+	if err != nil {
+		logWorkerError("aggregate", "get aggregate relay fee failed", err)
+		return
+	}
+	if !isLowFee {
+		logWorker("aggregate", "wait for low fee window", "relayFeePerKb", relayFeePerKb)
+		return
+	}
+	aggregateP2shUtxos(bridge, relayFeePerKb, collected)
+	aggregateDepositDust(bridge, relayFeePerKb, collected)
+}
+
+// aggregateP2shUtxos p2sh addresses are all belong to the p2sh pair
+func aggregateP2shUtxos(bridge btc.BridgeInterface, relayFeePerKb int64, collected map[string]struct{}) {
+	agg := &aggregator{
+		bridge:        bridge,
+		pairID:        bridge.GetP2shPairID(),
+		kind:          mongodb.AggregateKindP2sh,
+		relayFeePerKb: relayFeePerKb,
+		collected:     collected,
+	}
+	offset := 0
 	for {
-		p2shAddrs, err := mongodb.FindP2shAddresses(aggOffset, utxoPageLimit)
+		p2shAddrs, err := mongodb.FindP2shAddresses(offset, utxoPageLimit)
 		if err != nil {
-			logWorkerError("aggregate", "FindP2shAddresses failed", err, "offset", aggOffset, "limit", utxoPageLimit)
+			logWorkerError("aggregate", "FindP2shAddresses failed", err, "offset", offset, "limit", utxoPageLimit)
 			time.Sleep(3 * time.Second)
 			continue
 		}
 		for _, p2shAddr := range p2shAddrs {
-			findUtxosAndAggregate(p2shAddr.P2shAddress)
+			if !bridge.IsValidAddress(p2shAddr.P2shAddress) {
+				continue // p2sh address of other utxo chain
+			}
+			findUtxos, _ := bridge.FindUtxos(p2shAddr.P2shAddress)
+			for _, utxo := range findUtxos {
+				agg.add(p2shAddr.P2shAddress, utxo)
+			}
 		}
 		if len(p2shAddrs) < utxoPageLimit {
 			break
 		}
-		aggOffset += utxoPageLimit
+		offset += utxoPageLimit
 	}
 }
 
-func findUtxosAndAggregate(addr string) {
-	findUtxos, _ := btc.BridgeInstance.FindUtxos(addr)
-	for _, utxo := range findUtxos {
-		if utxo.Value == nil || *utxo.Value == 0 {
+// aggregateDepositDust consolidate dust of deposit address,
+// only deposit address controlled by dcrm (same as dcrm address) can be signed
+func aggregateDepositDust(bridge btc.BridgeInterface, relayFeePerKb int64, collected map[string]struct{}) {
+	visited := make(map[string]struct{})
+	for _, pairID := range tokens.GetAllPairIDs() {
+		if btc.GetBridgeByPairID(pairID) != bridge {
+			continue
+		}
+		tokenCfg := bridge.GetTokenConfig(pairID)
+		if tokenCfg == nil || tokenCfg.DepositAddress == "" || tokenCfg.DepositAddress != tokenCfg.DcrmAddress {
+			continue
+		}
+		depositAddr := tokenCfg.DepositAddress
+		if _, exist := visited[depositAddr]; exist {
 			continue
 		}
-		if isUtxoExist(utxo) {
+		visited[depositAddr] = struct{}{}
+
+		dusts, err := bridge.FindDustUtxos(depositAddr, relayFeePerKb)
+		if err != nil {
+			logWorkerError("aggregate", "find dust utxos failed", err, "pairID", pairID, "address", depositAddr)
 			continue
 		}
-		logWorker("aggregate", "find utxo", "address", addr, "utxo", utxo.String())
+		agg := &aggregator{
+			bridge:        bridge,
+			pairID:        pairID,
+			kind:          mongodb.AggregateKindDeposit,
+			relayFeePerKb: relayFeePerKb,
+			collected:     collected,
+		}
+		for _, utxo := range dusts {
+			agg.add(depositAddr, utxo)
+		}
+	}
+}
+
+func getAggregateUtxoKey(txid string, vout uint32) string {
+	return fmt.Sprintf("%v:%v", txid, vout)
+}
+
+func (a *aggregator) add(addr string, utxo *electrs.ElectUtxo) {
+	if utxo.Txid == nil || utxo.Vout == nil || utxo.Value == nil || *utxo.Value == 0 {
+		return
+	}
+	key := getAggregateUtxoKey(*utxo.Txid, *utxo.Vout)
+	if _, exist := a.collected[key]; exist {
+		return
+	}
+	a.collected[key] = struct{}{}
+	logWorker("aggregate", "find utxo", "kind", a.kind, "address", addr, "txid", *utxo.Txid, "vout", *utxo.Vout, "value", *utxo.Value)
 
-		aggSumVal += *utxo.Value
-		aggAddrs = append(aggAddrs, addr)
-		aggUtxos = append(aggUtxos, utxo)
+	a.sumVal += *utxo.Value
+	a.addrs = append(a.addrs, addr)
+	a.utxos = append(a.utxos, utxo)
 
-		if btc.BridgeInstance.ShouldAggregate(len(aggUtxos), aggSumVal) {
-			aggregate()
+	if a.bridge.ShouldAggregate(len(a.utxos), a.sumVal) {
+		a.aggregate()
+	}
+}
+
+func (a *aggregator) aggregate() {
+	txHash, err := a.doAggregate()
+	if err != nil {
+		logWorkerError("aggregate", "aggregate utxos failed", err, "pairID", a.pairID, "kind", a.kind, "txHash", txHash)
+	} else {
+		logWorker("aggregate", "aggregate utxos succeed", "pairID", a.pairID, "kind", a.kind, "txHash", txHash, "utxos", len(a.utxos), "sumVal", a.sumVal, "relayFeePerKb", a.relayFeePerKb)
+	}
+	a.sumVal = 0
+	a.addrs = nil
+	a.utxos = nil
+}
+
+// doAggregate persist the signed aggregate tx before sending it,
+// if sending failed it's left pending and rebroadcast in the next round
+func (a *aggregator) doAggregate() (txHash string, err error) {
+	signedTx, txHash, err := a.bridge.BuildSignedAggregateTx(a.pairID, a.relayFeePerKb, a.addrs, a.utxos)
+	if err != nil {
+		return "", err
+	}
+	err = a.addAggregate(signedTx, txHash)
+	if err != nil {
+		return txHash, err
+	}
+	_, err = a.bridge.SendTransaction(signedTx)
+	if err != nil {
+		return txHash, err
+	}
+	return txHash, mongodb.UpdateAggregateStatus(txHash, mongodb.AggregateSent, "")
+}
+
+func (a *aggregator) addAggregate(signedTx interface{}, txHash string) error {
+	item := &mongodb.MgoAggregate{
+		Key:           txHash,
+		PairID:        a.pairID,
+		Kind:          a.kind,
+		Inputs:        make([]*mongodb.MgoAggregateInput, len(a.utxos)),
+		SumValue:      a.sumVal,
+		RelayFeePerKb: a.relayFeePerKb,
+	}
+	for i, utxo := range a.utxos {
+		item.Inputs[i] = &mongodb.MgoAggregateInput{
+			Address: a.addrs[i],
+			Txid:    *utxo.Txid,
+			Vout:    *utxo.Vout,
+			Value:   *utxo.Value,
 		}
 	}
+	if serializer, ok := a.bridge.(tokens.SignedTxSerializer); ok {
+		data, err := serializer.SerializeSignedTx(signedTx)
+		if err != nil {
+			return err
+		}
+		item.SignedTx = data
+	}
+	return mongodb.AddAggregate(item)
 }
 
-func isUtxoExist(utxo *electrs.ElectUtxo) bool {
-	for _, item := range aggUtxos {
-		if *item.Txid == *utxo.Txid && *item.Vout == *utxo.Vout {
-			return true
+// recoverPendingAggregates reconcile or rebroadcast pending aggregate txs of bridge,
+// returns the utxos spent by the ones which are still pending
+func recoverPendingAggregates(bridge btc.BridgeInterface) map[string]struct{} {
+	collected := make(map[string]struct{})
+	items, err := mongodb.FindPendingAggregates()
+	if err != nil {
+		logWorkerError("aggregate", "find pending aggregates error", err)
+		return collected
+	}
+	if len(items) > 0 {
+		logWorker("aggregate", "find pending aggregates to recover", "count", len(items))
+	}
+	for _, item := range items {
+		if pairBridge := btc.GetBridgeByPairID(item.PairID); pairBridge != nil && pairBridge != bridge {
+			continue
+		}
+		err = recoverAggregate(bridge, item)
+		if err == nil {
+			continue
+		}
+		logWorkerError("aggregate", "recover aggregate error", err, "txHash", item.Key, "pairID", item.PairID, "kind", item.Kind)
+		for _, input := range item.Inputs {
+			collected[getAggregateUtxoKey(input.Txid, input.Vout)] = struct{}{}
 		}
 	}
-	return false
+	return collected
 }
 
-func aggregate() {
-	txHash, err := btc.BridgeInstance.AggregateUtxos(aggAddrs, aggUtxos)
+func recoverAggregate(bridge btc.BridgeInterface, item *mongodb.MgoAggregate) error {
+	txHash := item.Key
+	if _, err := bridge.GetTransaction(txHash); err == nil {
+		return mongodb.UpdateAggregateStatus(txHash, mongodb.AggregateSent, "reconciled")
+	}
+	signedTx, err := getAggregateSignedTx(bridge, item)
+	if err == nil {
+		logWorker("aggregate", "rebroadcast aggregate tx", "txHash", txHash, "pairID", item.PairID, "kind", item.Kind)
+		_, err = bridge.SendTransaction(signedTx)
+	}
 	if err != nil {
-		logWorkerError("aggregate", "AggregateUtxos failed", err)
-	} else {
-		logWorker("aggregate", "AggregateUtxos succeed", "txHash", txHash, "utxos", len(aggUtxos), "sumVal", aggSumVal)
+		_ = mongodb.UpdateAggregateStatus(txHash, mongodb.AggregateFailed, err.Error())
+		return err
+	}
+	return mongodb.UpdateAggregateStatus(txHash, mongodb.AggregateSent, "rebroadcast")
+}
+
+func getAggregateSignedTx(bridge tokens.CrossChainBridge, item *mongodb.MgoAggregate) (interface{}, error) {
+	serializer, ok := bridge.(tokens.SignedTxSerializer)
+	if !ok || item.SignedTx == "" {
+		return nil, errAggregateNotSerialized
 	}
-	aggSumVal = 0
-	aggAddrs = nil
-	aggUtxos = nil
+	return serializer.DeserializeSignedTx(item.SignedTx)
 }
@@ -5,10 +5,10 @@ import (
 	"strings"
 	"time"
 
+	"github.com/anyswap/CrossChain-Bridge/log"
 	"github.com/anyswap/CrossChain-Bridge/mongodb"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tokens/btc"
-	"github.com/anyswap/CrossChain-Bridge/log"
 )
 
 // MatchTx struct
@@ -67,6 +67,7 @@ func updateSwapResult(txid, pairID, bind string, mtx *MatchTx) (err error) {
 	updates := &mongodb.SwapResultUpdateItems{
 		Status:    mongodb.MatchTxNotStable,
 		Timestamp: now(),
+		Actor:     mongodb.ActorSwap,
 	}
 	if mtx.SwapHeight == 0 {
 		updates.SwapTx = mtx.SwapTx
@@ -106,6 +107,23 @@ func updateSwapResult(txid, pairID, bind string, mtx *MatchTx) (err error) {
 	return err
 }
 
+// getOldSwapTxs get old swap txs after adding txHash,
+// return nil if there is no need to update old swap txs
+func getOldSwapTxs(res *mongodb.MgoSwapResult, txHash string) (oldSwapTxs []string) {
+	if len(res.OldSwapTxs) > 0 {
+		for _, oldSwapTx := range res.OldSwapTxs {
+			if oldSwapTx == txHash {
+				return nil
+			}
+		}
+		oldSwapTxs = res.OldSwapTxs
+		oldSwapTxs = append(oldSwapTxs, txHash)
+	} else if res.SwapTx != "" && txHash != res.SwapTx {
+		oldSwapTxs = []string{res.SwapTx, txHash}
+	}
+	return oldSwapTxs
+}
+
 func updateSwapResultHeight(swap *mongodb.MgoSwapResult, blockHeight, blockTime uint64, updateSwapTx bool) (err error) {
 	updates := &mongodb.SwapResultUpdateItems{
 		Status:    mongodb.KeepStatus,
@@ -177,7 +195,7 @@ func markSwapResultStable(txid, pairID, bind string, isSwapin bool) (err error)
 	status := mongodb.MatchTxStable
 	timestamp := now()
 	memo := "" // unchange
-	err = mongodb.UpdateSwapResultStatus(isSwapin, txid, pairID, bind, status, timestamp, memo)
+	err = mongodb.UpdateSwapResultStatus(isSwapin, txid, pairID, bind, status, timestamp, memo, mongodb.ActorStable)
 	if err != nil {
 		logWorkerError("stable", "markSwapResultStable", err, "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin)
 	} else {
@@ -190,7 +208,7 @@ func markSwapResultFailed(txid, pairID, bind string, isSwapin bool) (err error)
 	status := mongodb.MatchTxFailed
 	timestamp := now()
 	memo := "" // unchange
-	err = mongodb.UpdateSwapResultStatus(isSwapin, txid, pairID, bind, status, timestamp, memo)
+	err = mongodb.UpdateSwapResultStatus(isSwapin, txid, pairID, bind, status, timestamp, memo, mongodb.ActorStable)
 	if err != nil {
 		logWorkerError("stable", "markSwapResultFailed", err, "txid", txid, "pairID", pairID, "bind", bind, "isSwapin", isSwapin)
 	} else {
@@ -202,12 +220,17 @@ func markSwapResultFailed(txid, pairID, bind string, isSwapin bool) (err error)
 func verifySwapTransaction(bridge tokens.CrossChainBridge, pairID, txid, bind string, swapTxType tokens.SwapTxType) (swapInfo *tokens.TxSwapInfo, err error) {
 	switch swapTxType {
 	case tokens.P2shSwapinTx:
-		if btc.BridgeInstance == nil {
+		btcBridge := btc.GetBridgeByPairID(pairID)
+		if btcBridge == nil {
 			return nil, tokens.ErrNoBtcBridge
 		}
-		swapInfo, err = btc.BridgeInstance.VerifyP2shTransaction(pairID, txid, bind, false)
+		swapInfo, err = btcBridge.VerifyP2shTransaction(pairID, txid, bind, false)
 	default:
-		swapInfo, err = bridge.VerifyTransaction(pairID, txid, false)
+		if verifier, ok := bridge.(tokens.MultiSwapVerifier); ok {
+			swapInfo, err = verifySwapOfBind(verifier, pairID, txid, bind)
+		} else {
+			swapInfo, err = bridge.VerifyTransaction(pairID, txid, false)
+		}
 	}
 	if swapInfo == nil {
 		return nil, fmt.Errorf("empty swapinfo after verify tx")
@@ -218,7 +241,20 @@ func verifySwapTransaction(bridge tokens.CrossChainBridge, pairID, txid, bind st
 	return swapInfo, err
 }
 
-func sendSignedTransaction(bridge tokens.CrossChainBridge, signedTx interface{}, txid, pairID, bind string, isSwapin, isReplace bool) (err error) {
+func verifySwapOfBind(verifier tokens.MultiSwapVerifier, pairID, txid, bind string) (*tokens.TxSwapInfo, error) {
+	swapInfos, errs := verifier.VerifyTransactionSwaps(pairID, txid, false)
+	for i, swapInfo := range swapInfos {
+		if strings.EqualFold(swapInfo.Bind, bind) {
+			return swapInfo, errs[i]
+		}
+	}
+	if len(errs) > 0 && errs[0] != nil {
+		return nil, errs[0]
+	}
+	return nil, tokens.ErrBindAddressMismatch
+}
+
+func sendSignedTransaction(bridge tokens.CrossChainBridge, signedTx interface{}, swapTx, txid, pairID, bind string, isSwapin, isReplace bool) (err error) {
 	var (
 		txHash              string
 		retrySendTxCount    = 3
@@ -228,7 +264,6 @@ func sendSignedTransaction(bridge tokens.CrossChainBridge, signedTx interface{},
 		txHash, err = bridge.SendTransaction(signedTx)
 		log.Info("sendSignedTransaction", "txHash", txHash)
 		if txHash != "" {
-			resTxHash = txHash
 			if tx, _ := bridge.GetTransaction(txHash); tx != nil {
 				logWorker("sendtx", "send tx success", "txHash", txHash)
 				err = nil
@@ -239,15 +274,17 @@ func sendSignedTransaction(bridge tokens.CrossChainBridge, signedTx interface{},
 	}
 	if err != nil {
 		logWorkerError("sendtx", "update swap status to TxSwapFailed", err, "txid", txid, "bind", bind, "isSwapin", isSwapin)
-		_ = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxSwapFailed, now(), err.Error())
-		_ = mongodb.UpdateSwapResultStatus(isSwapin, txid, pairID, bind, mongodb.TxSwapFailed, now(), err.Error())
-		return resTxHash, err
+		_ = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxSwapFailed, now(), err.Error(), mongodb.ActorSwap)
+		_ = mongodb.UpdateSwapResultStatus(isSwapin, txid, pairID, bind, mongodb.TxSwapFailed, now(), err.Error(), mongodb.ActorSwap)
+		_ = mongodb.UpdateSwapOutboxStatus(swapTx, mongodb.OutboxFailed, err.Error())
+		return err
 	}
+	_ = mongodb.UpdateSwapOutboxStatus(swapTx, mongodb.OutboxSent, "")
 	if !isReplace {
 		if nonceSetter, ok := bridge.(tokens.NonceSetter); ok {
 			nonceSetter.IncreaseNonce(pairID, 1)
 		}
 	}
 	log.Info("sendSignedTransaction, return", "txHash", txHash)
-	return resTxHash, nil
+	return nil
 }
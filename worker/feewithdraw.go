@@ -0,0 +1,181 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/common"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+var (
+	feeWithdrawLock sync.Mutex
+
+	errFeeWithdrawBusy        = errors.New("fee withdraw is in progress by others")
+	errFeeWithdrawExceed      = errors.New("fee withdraw amount exceeds withdrawable")
+	errNoWithdrawableFee      = errors.New("no withdrawable fee")
+	errWrongFeeWithdrawAmount = errors.New("wrong fee withdraw amount")
+	errFeeWithdrawBalance     = errors.New("fee withdraw amount exceeds dcrm balance")
+	errFeeWithdrawAccepted    = errors.New("fee withdraw is already accepted")
+)
+
+func getFeeLedger(pairID string, from, to int64) (*mongodb.FeeLedger, error) {
+	return mongodb.GetFeeLedger(pairID, from, to,
+		tokens.IsSwapFeeNativeCoin(pairID, true),
+		tokens.IsSwapFeeNativeCoin(pairID, false))
+}
+
+// checkFeeWithdrawBalance check dcrm address has enough balance to withdraw fee,
+// fee minted on destination chain has no balance to check
+func checkFeeWithdrawBalance(resBridge tokens.CrossChainBridge, tokenCfg *tokens.TokenConfig, pairID string, amount *big.Int, isSwapin bool) error {
+	var balance *big.Int
+	var err error
+	switch {
+	case tokenCfg.IsReleaseFromVault(!isSwapin):
+		balance, err = resBridge.GetTokenBalance("ERC20", tokenCfg.ContractAddress, tokenCfg.DcrmAddress)
+	case !isSwapin:
+		balance, err = resBridge.GetBalance(tokenCfg.DcrmAddress)
+	default:
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if balance.Cmp(amount) < 0 {
+		logWorkerWarn("feewithdraw", "dcrm balance is not enough", "pairID", pairID, "dcrm", tokenCfg.DcrmAddress, "balance", balance, "amount", amount, "isSwapin", isSwapin)
+		return errFeeWithdrawBalance
+	}
+	return nil
+}
+
+// WithdrawFee withdraw collected fee of swapin (on destination chain)
+// or swapout (on source chain) to the configed fee treasury.
+// withdraw all withdrawable fee if amount is nil.
+func WithdrawFee(pairID string, isSwapin bool, amount *big.Int, actor string) (txHash string, err error) {
+	pairID = strings.ToLower(pairID)
+	resBridge := tokens.GetCrossChainBridge(!isSwapin)
+	feeWithdrawer, ok := resBridge.(tokens.FeeWithdrawer)
+	if !ok {
+		return "", tokens.ErrFeeWithdrawNotSupported
+	}
+	tokenCfg := resBridge.GetTokenConfig(pairID)
+	if tokenCfg == nil {
+		return "", tokens.ErrUnknownPairID
+	}
+	if tokenCfg.FeeTreasury == "" {
+		return "", tokens.ErrNoFeeTreasury
+	}
+	if amount != nil && amount.Sign() <= 0 {
+		return "", errWrongFeeWithdrawAmount
+	}
+
+	feeWithdrawLock.Lock()
+	defer feeWithdrawLock.Unlock()
+
+	if params.IsReplicaEnabled() {
+		name := "feewithdraw:" + pairID
+		owner := getReplicaName()
+		held, errf := mongodb.AcquireLease(name, owner, getLeaseTTL())
+		if !held {
+			if errf != nil {
+				return "", errf
+			}
+			return "", errFeeWithdrawBusy
+		}
+		defer func() { _ = mongodb.ReleaseLease(name, owner) }()
+	}
+
+	ledger, err := getFeeLedger(pairID, 0, 0)
+	if err != nil {
+		return "", err
+	}
+	ledgerSide := ledger.Swapout
+	if isSwapin {
+		ledgerSide = ledger.Swapin
+	}
+	withdrawable := ledgerSide.GetWithdrawable()
+	if withdrawable == nil || withdrawable.Sign() <= 0 {
+		return "", errNoWithdrawableFee
+	}
+	if amount == nil {
+		amount = withdrawable
+	} else if amount.Cmp(withdrawable) > 0 {
+		return "", errFeeWithdrawExceed
+	}
+	err = checkFeeWithdrawBalance(resBridge, tokenCfg, pairID, amount, isSwapin)
+	if err != nil {
+		return "", err
+	}
+
+	if nonceSetter, ok := resBridge.(tokens.NonceSetter); ok {
+		unlock, errf := lockSwapNonce(nonceSetter, pairID, tokenCfg.DcrmAddress, isSwapin)
+		if errf != nil {
+			return "", errf
+		}
+		defer unlock()
+	}
+
+	withdrawID := common.Keccak256Hash([]byte(fmt.Sprintf("%v:%v:%v:%v", pairID, isSwapin, amount, time.Now().UnixNano()))).Hex()
+	args := &tokens.BuildTxArgs{
+		SwapInfo: tokens.SwapInfo{
+			Identifier: tokens.FeeWithdrawIdentifier,
+			PairID:     pairID,
+			SwapID:     withdrawID,
+			SwapType:   getSwapType(isSwapin),
+			Bind:       tokenCfg.FeeTreasury,
+		},
+		OriginValue: amount,
+	}
+	logWorker("feewithdraw", "start to withdraw fee", "pairID", pairID, "isSwapin", isSwapin, "amount", amount, "treasury", tokenCfg.FeeTreasury, "actor", actor)
+
+	rawTx, err := feeWithdrawer.BuildFeeWithdrawTx(args)
+	if err != nil {
+		logWorkerError("feewithdraw", "build tx failed", err, "pairID", pairID, "isSwapin", isSwapin)
+		return "", err
+	}
+	signedTx, txHash, err := tokens.SignRawTransaction(resBridge, rawTx, args)
+	if err != nil {
+		logWorkerError("feewithdraw", "sign tx failed", err, "pairID", pairID, "isSwapin", isSwapin)
+		releaseReservedUtxos(resBridge, args)
+		return "", err
+	}
+
+	// record before sending, so the amount can not be withdrawn twice
+	key := mongodb.GetFeeLedgerKey(mongodb.FeeKindWithdraw, isSwapin, withdrawID)
+	err = mongodb.AddFeeLedgerEntry(&mongodb.MgoFeeLedgerEntry{
+		Key:      key,
+		PairID:   pairID,
+		Kind:     mongodb.FeeKindWithdraw,
+		IsSwapin: isSwapin,
+		TxID:     withdrawID,
+		SwapTx:   txHash,
+		Amount:   amount.String(),
+		Status:   mongodb.FeeWithdrawPending,
+		Actor:    actor,
+	})
+	if err != nil {
+		releaseReservedUtxos(resBridge, args)
+		return txHash, err
+	}
+
+	_, err = resBridge.SendTransaction(signedTx)
+	if err != nil {
+		logWorkerError("feewithdraw", "send tx failed", err, "pairID", pairID, "isSwapin", isSwapin, "txHash", txHash)
+		_ = mongodb.UpdateFeeWithdrawStatus(key, mongodb.FeeWithdrawFailed, "", err.Error())
+		releaseReservedUtxos(resBridge, args)
+		return txHash, err
+	}
+	_ = mongodb.UpdateFeeWithdrawStatus(key, mongodb.FeeWithdrawSent, "", "")
+	if nonceSetter, ok := resBridge.(tokens.NonceSetter); ok {
+		nonceSetter.IncreaseNonce(pairID, 1)
+		_ = mongodb.UpdateLatestSwapNonce(tokenCfg.DcrmAddress, isSwapin, args.GetTxNonce()+1)
+	}
+	logWorker("feewithdraw", "withdraw fee success", "pairID", pairID, "isSwapin", isSwapin, "amount", amount, "txHash", txHash)
+	return txHash, nil
+}
@@ -0,0 +1,232 @@
+package worker
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+var (
+	outboxRecoverDelay      = int64(600) // seconds, in case of sending in progress
+	restIntervalInOutboxJob = 10 * time.Minute
+
+	errOutboxNotSerialized = errors.New("signed tx is not serialized in outbox")
+)
+
+func addSwapOutbox(bridge tokens.CrossChainBridge, signedTx interface{}, txid, pairID, bind, from string, mtx *MatchTx, isReplace bool) error {
+	item := &mongodb.MgoSwapOutbox{
+		IsSwapin:  mtx.SwapType == tokens.SwapinType,
+		TxID:      txid,
+		PairID:    pairID,
+		Bind:      bind,
+		From:      strings.ToLower(from),
+		SwapTx:    mtx.SwapTx,
+		SwapValue: mtx.SwapValue,
+		SwapType:  uint32(mtx.SwapType),
+		SwapNonce: mtx.SwapNonce,
+		IsReplace: isReplace,
+	}
+	if serializer, ok := bridge.(tokens.SignedTxSerializer); ok {
+		data, err := serializer.SerializeSignedTx(signedTx)
+		if err != nil {
+			return err
+		}
+		item.SignedTx = data
+	}
+	return mongodb.AddSwapOutbox(item)
+}
+
+// StartOutboxJob recover pending swap outbox left by last run before
+// starting swap job, then recover pending ones left by crashed replicas.
+func StartOutboxJob() {
+	logWorker("outbox", "start swap outbox job")
+	startTime := now()
+	recoverPendingSwapOutbox(startTime)
+	go func() {
+		lease := newJobLease("outbox", true)
+		for {
+			restInJob(restIntervalInOutboxJob)
+			lease.waitHeld()
+			recoverPendingSwapOutbox(getSepTimeInFind(outboxRecoverDelay))
+		}
+	}()
+}
+
+func recoverPendingSwapOutbox(septime int64) {
+	items, err := mongodb.FindPendingSwapOutbox(septime)
+	if err != nil {
+		logWorkerError("outbox", "find pending swap outbox error", err)
+		return
+	}
+	if len(items) > 0 {
+		logWorker("outbox", "find pending swap outbox to recover", "count", len(items))
+	}
+	for _, item := range items {
+		err = recoverSwapOutbox(item)
+		switch err {
+		case nil, errSwapClaimedByOthers:
+		default:
+			logWorkerError("outbox", "recover swap outbox error", err, "swaptx", item.SwapTx, "pairID", item.PairID, "txid", item.TxID, "bind", item.Bind, "isSwapin", item.IsSwapin)
+		}
+	}
+}
+
+type outboxRecoverAction int
+
+const (
+	outboxReconcile   outboxRecoverAction = iota // sent, record it if not recorded
+	outboxRebroadcast                            // not sent, record it if not recorded and send again
+	outboxDiscard                                // not sent, and should not be sent
+)
+
+// recoverSwapOutbox reconcile database if the signed tx has been sent,
+// otherwise complete the interrupted record and send sequence if possible.
+func recoverSwapOutbox(item *mongodb.MgoSwapOutbox) error {
+	isSwapin := item.IsSwapin
+	txid, pairID, bind := item.TxID, item.PairID, item.Bind
+	swapTx := item.SwapTx
+
+	claim, err := keepSwapClaim(isSwapin, txid, pairID, bind)
+	if err != nil {
+		return err
+	}
+	defer claim.release()
+
+	res, err := mongodb.FindSwapResult(isSwapin, txid, pairID, bind)
+	if err != nil {
+		return err
+	}
+
+	resBridge := tokens.GetCrossChainBridge(!isSwapin)
+	isSent := isOutboxSwapTxSent(resBridge, swapTx)
+	var swap *mongodb.MgoSwap
+	if !isSent && !isSwapTxRecorded(res, swapTx) {
+		swap, err = mongodb.FindSwap(isSwapin, txid, pairID, bind)
+		if err != nil {
+			return err
+		}
+	}
+	action, memo := getOutboxRecoverAction(item, res, swap, isSent)
+
+	switch action {
+	case outboxDiscard:
+		return mongodb.UpdateSwapOutboxStatus(swapTx, mongodb.OutboxObsolete, memo)
+	case outboxReconcile:
+		logWorker("outbox", "reconcile sent swap tx", "swaptx", swapTx, "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "memo", memo)
+		if !isSwapTxRecorded(res, swapTx) {
+			err = recordSwapOutbox(item, res)
+			if err != nil {
+				return err
+			}
+		}
+		updateOutboxSwapNonce(resBridge, item)
+		return mongodb.UpdateSwapOutboxStatus(swapTx, mongodb.OutboxSent, "reconciled")
+	}
+
+	signedTx, err := getOutboxSignedTx(resBridge, item)
+	if err != nil {
+		_ = mongodb.UpdateSwapOutboxStatus(swapTx, mongodb.OutboxFailed, err.Error())
+		return err
+	}
+	if !isSwapTxRecorded(res, swapTx) {
+		err = recordSwapOutbox(item, res)
+		if err != nil {
+			return err
+		}
+	}
+	logWorker("outbox", "rebroadcast swap tx", "swaptx", swapTx, "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "memo", memo)
+	// do not increase nonce in memory, it's updated in database below
+	err = sendSignedTransaction(resBridge, signedTx, swapTx, txid, pairID, bind, isSwapin, true)
+	if err != nil {
+		return err
+	}
+	updateOutboxSwapNonce(resBridge, item)
+	return nil
+}
+
+func isOutboxSwapTxSent(bridge tokens.CrossChainBridge, swapTx string) bool {
+	_, err := bridge.GetTransaction(swapTx)
+	return err == nil
+}
+
+// isSwapTxReplaced swap tx is superseded by another tx in 'replaceswap'
+func isSwapTxReplaced(res *mongodb.MgoSwapResult, swapTx string) bool {
+	if res.SwapTx == swapTx {
+		return false
+	}
+	for _, oldSwapTx := range res.OldSwapTxs {
+		if oldSwapTx == swapTx {
+			return true
+		}
+	}
+	return false
+}
+
+func isSwapTxRecorded(res *mongodb.MgoSwapResult, swapTx string) bool {
+	return res.SwapTx == swapTx || isSwapTxReplaced(res, swapTx)
+}
+
+// getOutboxRecoverAction decide how to recover the outbox item,
+// swap is only used if swap tx is neither sent nor recorded.
+func getOutboxRecoverAction(item *mongodb.MgoSwapOutbox, res *mongodb.MgoSwapResult, swap *mongodb.MgoSwap, isSent bool) (action outboxRecoverAction, memo string) {
+	swapTx := item.SwapTx
+	switch {
+	case isSent && isSwapTxRecorded(res, swapTx):
+		return outboxReconcile, "recorded"
+	case isSent:
+		return outboxReconcile, "not recorded"
+	case isSwapTxReplaced(res, swapTx):
+		// the replacing tx with the same nonce is sent instead
+		return outboxDiscard, "replaced"
+	case res.SwapTx == swapTx:
+		if res.Status != mongodb.MatchTxNotStable || res.SwapHeight != 0 {
+			return outboxDiscard, "swap is settled"
+		}
+		return outboxRebroadcast, "recorded"
+	case item.IsReplace || item.SignedTx == "" ||
+		swap == nil || swap.Status != mongodb.TxNotSwapped ||
+		res.Status != mongodb.MatchTxEmpty:
+		return outboxDiscard, "not recorded"
+	default:
+		return outboxRebroadcast, "not recorded"
+	}
+}
+
+// recordSwapOutbox record swap tx in database as 'doSwap' or 'replaceSwap' does
+func recordSwapOutbox(item *mongodb.MgoSwapOutbox, res *mongodb.MgoSwapResult) error {
+	isSwapin := item.IsSwapin
+	txid, pairID, bind := item.TxID, item.PairID, item.Bind
+	if item.IsReplace {
+		return replaceSwapResult(res, item.SwapTx, isSwapin)
+	}
+	matchTx := &MatchTx{
+		SwapTx:     item.SwapTx,
+		OldSwapTxs: getOldSwapTxs(res, item.SwapTx),
+		SwapValue:  item.SwapValue,
+		SwapType:   tokens.SwapType(item.SwapType),
+		SwapNonce:  item.SwapNonce,
+	}
+	err := updateSwapResult(txid, pairID, bind, matchTx)
+	if err != nil {
+		return err
+	}
+	return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxProcessed, now(), "", mongodb.ActorSwap)
+}
+
+func getOutboxSignedTx(bridge tokens.CrossChainBridge, item *mongodb.MgoSwapOutbox) (interface{}, error) {
+	serializer, ok := bridge.(tokens.SignedTxSerializer)
+	if !ok || item.SignedTx == "" {
+		return nil, errOutboxNotSerialized
+	}
+	return serializer.DeserializeSignedTx(item.SignedTx)
+}
+
+func updateOutboxSwapNonce(bridge tokens.CrossChainBridge, item *mongodb.MgoSwapOutbox) {
+	if _, ok := bridge.(tokens.NonceSetter); !ok {
+		return
+	}
+	_ = mongodb.UpdateLatestSwapNonce(item.From, item.IsSwapin, item.SwapNonce+1)
+}
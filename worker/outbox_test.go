@@ -0,0 +1,101 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// fakeOutboxBridge bridge knows only the sent txs
+type fakeOutboxBridge struct {
+	tokens.CrossChainBridge
+	sent map[string]bool
+}
+
+func (b *fakeOutboxBridge) GetTransaction(txHash string) (interface{}, error) {
+	if b.sent[txHash] {
+		return txHash, nil
+	}
+	return nil, tokens.ErrTxNotFound
+}
+
+func TestGetOutboxRecoverAction(t *testing.T) {
+	bridge := &fakeOutboxBridge{sent: map[string]bool{"0xsent": true, "0xsentold": true}}
+	notSwapped := &mongodb.MgoSwap{Status: mongodb.TxNotSwapped}
+	emptyRes := &mongodb.MgoSwapResult{Status: mongodb.MatchTxEmpty}
+
+	tests := []struct {
+		name   string
+		item   *mongodb.MgoSwapOutbox
+		res    *mongodb.MgoSwapResult
+		swap   *mongodb.MgoSwap
+		action outboxRecoverAction
+	}{
+		{
+			name:   "recorded and sent",
+			item:   &mongodb.MgoSwapOutbox{SwapTx: "0xsent"},
+			res:    &mongodb.MgoSwapResult{SwapTx: "0xsent", Status: mongodb.MatchTxNotStable},
+			action: outboxReconcile,
+		},
+		{
+			name:   "not recorded and sent",
+			item:   &mongodb.MgoSwapOutbox{SwapTx: "0xsent", SignedTx: "0x01"},
+			res:    emptyRes,
+			swap:   notSwapped,
+			action: outboxReconcile,
+		},
+		{
+			name:   "recorded and not sent",
+			item:   &mongodb.MgoSwapOutbox{SwapTx: "0xunsent", SignedTx: "0x01"},
+			res:    &mongodb.MgoSwapResult{SwapTx: "0xunsent", Status: mongodb.MatchTxNotStable},
+			action: outboxRebroadcast,
+		},
+		{
+			name:   "recorded, not sent and settled",
+			item:   &mongodb.MgoSwapOutbox{SwapTx: "0xunsent", SignedTx: "0x01"},
+			res:    &mongodb.MgoSwapResult{SwapTx: "0xunsent", Status: mongodb.MatchTxNotStable, SwapHeight: 100},
+			action: outboxDiscard,
+		},
+		{
+			name:   "not recorded and not sent",
+			item:   &mongodb.MgoSwapOutbox{SwapTx: "0xunsent", SignedTx: "0x01"},
+			res:    emptyRes,
+			swap:   notSwapped,
+			action: outboxRebroadcast,
+		},
+		{
+			name:   "not recorded, not sent and not serialized",
+			item:   &mongodb.MgoSwapOutbox{SwapTx: "0xunsent"},
+			res:    emptyRes,
+			swap:   notSwapped,
+			action: outboxDiscard,
+		},
+		{
+			name:   "not recorded, not sent replacing tx",
+			item:   &mongodb.MgoSwapOutbox{SwapTx: "0xunsent", SignedTx: "0x01", IsReplace: true},
+			res:    &mongodb.MgoSwapResult{SwapTx: "0xold", Status: mongodb.MatchTxNotStable},
+			swap:   &mongodb.MgoSwap{Status: mongodb.TxProcessed},
+			action: outboxDiscard,
+		},
+		{
+			name:   "replaced and not sent",
+			item:   &mongodb.MgoSwapOutbox{SwapTx: "0xunsent", SignedTx: "0x01"},
+			res:    &mongodb.MgoSwapResult{SwapTx: "0xnew", OldSwapTxs: []string{"0xunsent", "0xnew"}, Status: mongodb.MatchTxNotStable},
+			action: outboxDiscard,
+		},
+		{
+			name:   "replaced and sent",
+			item:   &mongodb.MgoSwapOutbox{SwapTx: "0xsentold", SignedTx: "0x01"},
+			res:    &mongodb.MgoSwapResult{SwapTx: "0xnew", OldSwapTxs: []string{"0xsentold", "0xnew"}, Status: mongodb.MatchTxNotStable},
+			action: outboxReconcile,
+		},
+	}
+	for _, test := range tests {
+		isSent := isOutboxSwapTxSent(bridge, test.item.SwapTx)
+		action, memo := getOutboxRecoverAction(test.item, test.res, test.swap, isSent)
+		if action != test.action {
+			t.Errorf("%v: recover action mismatch, have %v (%v) want %v", test.name, action, memo, test.action)
+		}
+	}
+}
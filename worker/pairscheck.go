@@ -0,0 +1,64 @@
+package worker
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+var (
+	restIntervalInPairsCheckJob = 60 * time.Second
+
+	// pairs whose config hash mismatch with server
+	mismatchedPairs     = make(map[string]struct{})
+	mismatchedPairsLock sync.RWMutex
+
+	errPairConfigMismatch = errors.New("pair config mismatch with server")
+)
+
+// StartPairsConfigCheckJob oracle verify pairs config hash with server,
+// signs of mismatched pairs are ignored until the configs become the same.
+func StartPairsConfigCheckJob() {
+	if !params.IsVerifyPairsConfig() {
+		return
+	}
+	logWorker("pairs", "start pairs config check job")
+	for {
+		checkPairsConfigHash()
+		restInJob(restIntervalInPairsCheckJob)
+	}
+}
+
+func checkPairsConfigHash() {
+	var remote tokens.PairsConfigHash
+	err := client.RPCPost(&remote, params.ServerAPIAddress, "swap.GetPairsConfigHash")
+	if err != nil {
+		logWorkerError("pairs", "get pairs config hash from server failed", err)
+		return
+	}
+	local := tokens.GetPairsConfigHash()
+	mismatches := make(map[string]struct{})
+	for _, pairID := range tokens.DiffPairsConfigHash(local, &remote) {
+		mismatches[pairID] = struct{}{}
+		logWorkerError("pairs", "pair config mismatch with server", errPairConfigMismatch,
+			"pairID", pairID, "local", local.Pairs[pairID], "server", remote.Pairs[pairID])
+	}
+	if len(mismatches) == 0 {
+		logWorkerTrace("pairs", "pairs config match with server", "hash", local.Hash)
+	}
+
+	mismatchedPairsLock.Lock()
+	mismatchedPairs = mismatches
+	mismatchedPairsLock.Unlock()
+}
+
+func isPairConfigMismatched(pairID string) bool {
+	mismatchedPairsLock.RLock()
+	defer mismatchedPairsLock.RUnlock()
+	_, exist := mismatchedPairs[pairID]
+	return exist
+}
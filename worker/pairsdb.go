@@ -0,0 +1,219 @@
+package worker
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/anyswap/CrossChain-Bridge/admin"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// admin methods which push pair config versions into database
+const (
+	PushPairMethod   = "pushpair"
+	RemovePairMethod = "removepair"
+)
+
+var errPairConfigNotInDB = errors.New("token pairs config is not loaded from db")
+
+// pairVersionParams params of signed pair config admin calls, the signed
+// pairID, version and previous hash bind the call to one version of the pair.
+// params are [pairID, version, prevHash] with config content appended for pushpair.
+type pairVersionParams struct {
+	pairID   string
+	version  uint64
+	prevHash string
+	content  string
+}
+
+func parsePairVersionParams(method string, params []string) (*pairVersionParams, error) {
+	wantCount := 3
+	if method == PushPairMethod {
+		wantCount = 4
+	}
+	if len(params) != wantCount {
+		return nil, fmt.Errorf("wrong number of params, have %v want %v", len(params), wantCount)
+	}
+	version, err := strconv.ParseUint(params[1], 10, 64)
+	if err != nil || version == 0 {
+		return nil, fmt.Errorf("wrong pair config version '%v'", params[1])
+	}
+	p := &pairVersionParams{
+		pairID:   params[0],
+		version:  version,
+		prevHash: params[2],
+	}
+	if method == PushPairMethod {
+		p.content = params[3]
+	}
+	return p, nil
+}
+
+// PushPairConfig push new version of pair config into database, the pushed
+// version is applied by reloading pairs config. adminTx is the signed admin
+// call which carries the config content, it is stored to verify the version.
+func PushPairConfig(args []string, adminAddr, adminTx string) (*mongodb.MgoPairConfig, error) {
+	if !params.IsPairsConfigInDB() {
+		return nil, errPairConfigNotInDB
+	}
+	p, err := parsePairVersionParams(PushPairMethod, args)
+	if err != nil {
+		return nil, err
+	}
+	pairCfg, err := tokens.ParseTokenPairConfig(p.content)
+	if err != nil {
+		return nil, err
+	}
+	err = pairCfg.CheckConfig()
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(pairCfg.PairID, p.pairID) {
+		return nil, fmt.Errorf("pairID mismatch, signed %v config %v", p.pairID, pairCfg.PairID)
+	}
+	return addPairConfigVersion(&mongodb.MgoPairConfig{
+		PairID:   pairCfg.PairID,
+		Version:  p.version,
+		Config:   p.content,
+		Hash:     pairCfg.Hash().String(),
+		PrevHash: p.prevHash,
+		Admin:    adminAddr,
+		AdminTx:  adminTx,
+	})
+}
+
+// RemovePairConfig push a removed version of pair config into database
+func RemovePairConfig(args []string, adminAddr, adminTx string) (*mongodb.MgoPairConfig, error) {
+	if !params.IsPairsConfigInDB() {
+		return nil, errPairConfigNotInDB
+	}
+	p, err := parsePairVersionParams(RemovePairMethod, args)
+	if err != nil {
+		return nil, err
+	}
+	return addPairConfigVersion(&mongodb.MgoPairConfig{
+		PairID:   p.pairID,
+		Version:  p.version,
+		PrevHash: p.prevHash,
+		Removed:  true,
+		Admin:    adminAddr,
+		AdminTx:  adminTx,
+	})
+}
+
+func addPairConfigVersion(mp *mongodb.MgoPairConfig) (*mongodb.MgoPairConfig, error) {
+	latest, err := mongodb.FindLatestPairConfig(mp.PairID)
+	switch err {
+	case nil:
+	case mongodb.ErrItemNotFound:
+		latest = nil
+	default:
+		return nil, err
+	}
+	if mp.Removed && (latest == nil || latest.Removed) {
+		return nil, fmt.Errorf("pair %v is not exist or already removed", mp.PairID)
+	}
+	err = checkPairConfigVersion(latest, mp.Version, mp.PrevHash)
+	if err != nil {
+		return nil, err
+	}
+	err = mongodb.AddPairConfig(mp)
+	if err == mongodb.ErrItemIsDup {
+		return nil, fmt.Errorf("version %v of pair %v is pushed concurrently", mp.Version, mp.PairID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	logWorker("pairs", "push pair config version", "pairID", mp.PairID, "version", mp.Version, "hash", mp.Hash, "prevHash", mp.PrevHash, "removed", mp.Removed, "admin", mp.Admin)
+	return mp, nil
+}
+
+// checkPairConfigVersion check signed version and previous hash follow the
+// previous version (nil if not exist), so a signed call can not be replayed
+// onto another version of the pair.
+func checkPairConfigVersion(prev *mongodb.MgoPairConfig, version uint64, prevHash string) error {
+	wantVersion, wantPrevHash := uint64(1), ""
+	if prev != nil {
+		wantVersion, wantPrevHash = prev.Version+1, prev.Hash
+	}
+	if version != wantVersion {
+		return fmt.Errorf("pair config version mismatch, signed %v want %v", version, wantVersion)
+	}
+	if prevHash != wantPrevHash {
+		return fmt.Errorf("pair config previous hash mismatch, signed '%v' want '%v'", prevHash, wantPrevHash)
+	}
+	return nil
+}
+
+// LoadTokenPairsFromDB load latest versions of pairs config from database,
+// every version is verified by its signed admin call.
+func LoadTokenPairsFromDB() (map[string]*tokens.TokenPairConfig, error) {
+	versions, err := mongodb.FindLatestPairConfigs()
+	if err != nil {
+		return nil, err
+	}
+	pairsConfig := make(map[string]*tokens.TokenPairConfig, len(versions))
+	for _, mp := range versions {
+		err = verifyPairConfigVersion(mp)
+		if err != nil {
+			return nil, fmt.Errorf("verify version %v of pair %v failed, %w", mp.Version, mp.PairID, err)
+		}
+		if mp.Removed {
+			continue
+		}
+		pairCfg, err := tokens.ParseTokenPairConfig(mp.Config)
+		if err != nil {
+			return nil, fmt.Errorf("parse version %v of pair %v failed, %w", mp.Version, mp.PairID, err)
+		}
+		if pairCfg.Hash().String() != mp.Hash {
+			return nil, fmt.Errorf("hash mismatch of version %v of pair %v", mp.Version, mp.PairID)
+		}
+		pairsConfig[strings.ToLower(pairCfg.PairID)] = pairCfg
+	}
+	logWorker("pairs", "load token pairs config from db", "count", len(pairsConfig))
+	return pairsConfig, nil
+}
+
+func verifyPairConfigVersion(mp *mongodb.MgoPairConfig) error {
+	sender, args, err := admin.VerifyRecordedTransaction(mp.AdminTx)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(sender.String(), mp.Admin) {
+		return fmt.Errorf("admin mismatch, recorded %v signed by %v", mp.Admin, sender.String())
+	}
+	if !params.IsAdmin(sender.String()) {
+		return fmt.Errorf("%v is not admin", sender.String())
+	}
+	switch {
+	case mp.Removed && args.Method == RemovePairMethod:
+	case !mp.Removed && args.Method == PushPairMethod:
+	default:
+		return fmt.Errorf("wrong admin call method '%v'", args.Method)
+	}
+	p, err := parsePairVersionParams(args.Method, args.Params)
+	if err != nil {
+		return err
+	}
+	if !strings.EqualFold(p.pairID, mp.PairID) {
+		return errors.New("admin call pairID mismatch")
+	}
+	if p.version != mp.Version || p.prevHash != mp.PrevHash {
+		return errors.New("admin call version mismatch")
+	}
+	if p.content != mp.Config {
+		return errors.New("admin call config content mismatch")
+	}
+	if mp.Version == 1 {
+		return checkPairConfigVersion(nil, mp.Version, mp.PrevHash)
+	}
+	prev, err := mongodb.FindPairConfig(mp.PairID, mp.Version-1)
+	if err != nil {
+		return fmt.Errorf("find previous version failed, %w", err)
+	}
+	return checkPairConfigVersion(prev, mp.Version, mp.PrevHash)
+}
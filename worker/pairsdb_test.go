@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+)
+
+func TestParsePairVersionParams(t *testing.T) {
+	p, err := parsePairVersionParams(PushPairMethod, []string{"ETH", "3", "0xabcd", "content"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.pairID != "ETH" || p.version != 3 || p.prevHash != "0xabcd" || p.content != "content" {
+		t.Errorf("parsed pushpair params mismatch, have %+v", p)
+	}
+	p, err = parsePairVersionParams(RemovePairMethod, []string{"ETH", "4", ""})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if p.version != 4 || p.prevHash != "" || p.content != "" {
+		t.Errorf("parsed removepair params mismatch, have %+v", p)
+	}
+
+	invalids := []struct {
+		method string
+		params []string
+	}{
+		{PushPairMethod, []string{"content"}},
+		{PushPairMethod, []string{"ETH", "1", ""}},
+		{RemovePairMethod, []string{"ETH"}},
+		{RemovePairMethod, []string{"ETH", "1", "", "content"}},
+		{RemovePairMethod, []string{"ETH", "0", ""}},
+		{RemovePairMethod, []string{"ETH", "-1", ""}},
+		{RemovePairMethod, []string{"ETH", "0x1", ""}},
+	}
+	for _, test := range invalids {
+		if _, err := parsePairVersionParams(test.method, test.params); err == nil {
+			t.Errorf("%v params %q should be invalid", test.method, test.params)
+		}
+	}
+}
+
+func TestCheckPairConfigVersion(t *testing.T) {
+	prev := &mongodb.MgoPairConfig{PairID: "eth", Version: 2, Hash: "0xabcd"}
+	removed := &mongodb.MgoPairConfig{PairID: "eth", Version: 3, Removed: true}
+	tests := []struct {
+		prev     *mongodb.MgoPairConfig
+		version  uint64
+		prevHash string
+		ok       bool
+	}{
+		{nil, 1, "", true},
+		{nil, 2, "", false},
+		{nil, 1, "0xabcd", false},
+		{prev, 3, "0xabcd", true},
+		{prev, 2, "0xabcd", false},
+		{prev, 4, "0xabcd", false},
+		{prev, 3, "", false},
+		{prev, 3, "0x1234", false},
+		{removed, 4, "", true},
+		{removed, 4, "0xabcd", false},
+	}
+	for i, test := range tests {
+		err := checkPairConfigVersion(test.prev, test.version, test.prevHash)
+		if (err == nil) != test.ok {
+			t.Errorf("test %v: version %v prevHash '%v' check result mismatch, err %v", i, test.version, test.prevHash, err)
+		}
+	}
+}
@@ -40,7 +40,9 @@ func startReplaceSwapinJob() {
 		logWorker("replace", "stop replace swapin job as disabled")
 		return
 	}
+	lease := newJobLease("replace:swapin", true)
 	for {
+		lease.waitHeld()
 		res, err := findSwapinsToReplace()
 		if err != nil {
 			logWorkerError("replace", "find swapins error", err)
@@ -58,7 +60,9 @@ func startReplaceSwapoutJob() {
 		logWorker("replace", "stop replace swapout job as disabled")
 		return
 	}
+	lease := newJobLease("replace:swapout", true)
 	for {
+		lease.waitHeld()
 		res, err := findSwapoutsToReplace()
 		if err != nil {
 			logWorkerError("replace", "find swapouts error", err)
@@ -21,6 +21,7 @@ var (
 	errBuildTxFailed      = errors.New("build tx failed")
 	errSignTxFailed       = errors.New("sign tx failed")
 	errUpdateOldTxsFailed = errors.New("update old swaptxs failed")
+	errAddOutboxFailed    = errors.New("add swap outbox failed")
 )
 
 // ReplaceSwapin api
@@ -100,6 +101,16 @@ func replaceSwap(txid, pairID, bind, gasPriceStr string, isSwapin bool) (txHash
 		return "", fmt.Errorf("wrong value %v", res.Value)
 	}
 
+	var swapFee *big.Int
+	if tokens.HasFeePolicy(pairID, isSwapin) {
+		// keep the swap fee of the replaced tx
+		swapValue, errv := common.GetBigIntFromStr(res.SwapValue)
+		if errv != nil {
+			return "", fmt.Errorf("wrong swap value %v", res.SwapValue)
+		}
+		swapFee = new(big.Int).Sub(value, swapValue)
+	}
+
 	nonce := res.SwapNonce
 	args := &tokens.BuildTxArgs{
 		SwapInfo: tokens.SwapInfo{
@@ -117,6 +128,7 @@ func replaceSwap(txid, pairID, bind, gasPriceStr string, isSwapin bool) (txHash
 				GasPrice: gasPrice,
 				Nonce:    &nonce,
 			},
+			SwapFee: swapFee,
 		},
 	}
 	rawTx, err := bridge.BuildRawTransaction(args)
@@ -124,22 +136,28 @@ func replaceSwap(txid, pairID, bind, gasPriceStr string, isSwapin bool) (txHash
 		logWorkerError("replaceSwap", "build tx failed", err, "txid", txid, "bind", bind, "isSwapin", isSwapin)
 		return "", errBuildTxFailed
 	}
-	var signedTx interface{}
-	if tokenCfg.GetDcrmAddressPrivateKey() != nil {
-		signedTx, txHash, err = bridge.SignTransaction(rawTx, pairID)
-	} else {
-		signedTx, txHash, err = bridge.DcrmSignTransaction(rawTx, args.GetExtraArgs())
-	}
+	signedTx, txHash, err := tokens.SignRawTransaction(bridge, rawTx, args)
 	if err != nil {
 		logWorkerError("replaceSwap", "sign tx failed", err, "txid", txid, "bind", bind, "isSwapin", isSwapin)
 		return "", errSignTxFailed
 	}
 
+	matchTx := &MatchTx{
+		SwapTx:    txHash,
+		SwapValue: res.SwapValue,
+		SwapType:  swapType,
+		SwapNonce: nonce,
+	}
+	err = addSwapOutbox(bridge, signedTx, txid, pairID, bind, args.From, matchTx, true)
+	if err != nil {
+		logWorkerError("replaceSwap", "add swap outbox failed", err, "txid", txid, "bind", bind, "isSwapin", isSwapin)
+		return "", errAddOutboxFailed
+	}
 	err = replaceSwapResult(res, txHash, isSwapin)
 	if err != nil {
 		return "", errUpdateOldTxsFailed
 	}
-	err = sendSignedTransaction(bridge, signedTx, txid, pairID, bind, isSwapin, true)
+	err = sendSignedTransaction(bridge, signedTx, txHash, txid, pairID, bind, isSwapin, true)
 	return txHash, err
 }
 
@@ -147,22 +165,7 @@ func replaceSwapResult(swapResult *mongodb.MgoSwapResult, txHash string, isSwapi
 	txid := swapResult.TxID
 	pairID := swapResult.PairID
 	bind := swapResult.Bind
-	var oldSwapTxs []string
-	if len(swapResult.OldSwapTxs) > 0 {
-		var existsInOld bool
-		for _, oldSwapTx := range swapResult.OldSwapTxs {
-			if oldSwapTx == txHash {
-				existsInOld = true
-				break
-			}
-		}
-		if !existsInOld {
-			oldSwapTxs = swapResult.OldSwapTxs
-			oldSwapTxs = append(oldSwapTxs, txHash)
-		}
-	} else if swapResult.SwapTx != "" && txHash != swapResult.SwapTx {
-		oldSwapTxs = []string{swapResult.SwapTx, txHash}
-	}
+	oldSwapTxs := getOldSwapTxs(swapResult, txHash)
 	swapType := tokens.SwapType(swapResult.SwapType).String()
 	err = updateOldSwapTxs(txid, pairID, bind, oldSwapTxs, isSwapin)
 	if err != nil {
@@ -0,0 +1,324 @@
+package worker
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/log"
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+var (
+	restIntervalInStandby = 5 * time.Second
+
+	// unit of lease and claim ttl, leases are renewed every third of ttl
+	leaseTTLUnit = time.Second
+
+	// lease store, replaced in tests
+	acquireLease        = mongodb.AcquireLease
+	releaseLease        = mongodb.ReleaseLease
+	acquireSwapClaim    = mongodb.ClaimSwap
+	releaseSwapClaimDB  = mongodb.ReleaseSwapClaim
+	findLatestSwapNonce = mongodb.FindLatestSwapNonce
+
+	errSwapClaimedByOthers = errors.New("swap is claimed by other replica")
+	errSwapClaimLost       = errors.New("swap claim is lost")
+	errWaitNonceLease      = errors.New("wait nonce lease timeout")
+
+	// in process nonce locks of dcrm addresses
+	localNonceLocks     = make(map[string]*sync.Mutex)
+	localNonceLocksLock sync.Mutex
+)
+
+// jobLease is held by only one replica at the same time,
+// standby replicas keep trying and take over when it expired.
+// nil jobLease is always held (single server or active-active job).
+type jobLease struct {
+	name string
+	held int32
+	done chan struct{}
+}
+
+func getReplicaName() string {
+	return params.GetReplicaConfig().Name
+}
+
+func getLeaseTTL() int64 {
+	return params.GetReplicaConfig().LeaseTTL
+}
+
+func getClaimTTL() int64 {
+	return params.GetReplicaConfig().ClaimTTL
+}
+
+func getRenewInterval(ttl int64) time.Duration {
+	return time.Duration(ttl) * leaseTTLUnit / 3
+}
+
+// checkReplicaMode active-active replicas serialize swaps of the same dcrm
+// address by nonce lease, utxo chains have no nonce lease and reserve utxos
+// in process only, so replicas would spend the same utxos concurrently.
+func checkReplicaMode() {
+	if !params.IsReplicaEnabled() || params.GetReplicaConfig().Mode != params.ReplicaActiveActive {
+		return
+	}
+	for _, bridge := range []tokens.CrossChainBridge{tokens.SrcBridge, tokens.DstBridge} {
+		if _, ok := bridge.(tokens.NonceSetter); !ok {
+			log.Fatalf("replica mode '%v' is not supported by utxo chain '%v', use '%v' instead",
+				params.ReplicaActiveActive, bridge.GetChainConfig().BlockChain, params.ReplicaActiveStandby)
+		}
+	}
+}
+
+// newJobLease new job lease, singleton jobs always need lease,
+// other jobs need lease only in active-standby mode.
+func newJobLease(name string, isSingleton bool) *jobLease {
+	if !params.IsReplicaEnabled() {
+		return nil
+	}
+	if !isSingleton && params.GetReplicaConfig().Mode == params.ReplicaActiveActive {
+		return nil
+	}
+	lease := &jobLease{name: "job:" + name, done: make(chan struct{})}
+	go lease.keep()
+	return lease
+}
+
+func (l *jobLease) keep() {
+	owner := getReplicaName()
+	ttl := getLeaseTTL()
+	renewInterval := getRenewInterval(ttl)
+	for {
+		ok, err := acquireLease(l.name, owner, ttl)
+		if err != nil {
+			logWorkerError("replica", "acquire job lease failed", err, "lease", l.name)
+		}
+		var held int32
+		if ok {
+			held = 1
+		}
+		if atomic.SwapInt32(&l.held, held) != held {
+			logWorker("replica", "job lease changed", "lease", l.name, "owner", owner, "held", ok)
+		}
+		if !restInJobUntil(renewInterval, l.done) {
+			atomic.StoreInt32(&l.held, 0)
+			_ = releaseLease(l.name, owner)
+			logWorker("replica", "job lease released", "lease", l.name, "owner", owner)
+			return
+		}
+	}
+}
+
+// release stop keeping job lease and release it, so standby replicas can take over
+func (l *jobLease) release() {
+	if l == nil {
+		return
+	}
+	close(l.done)
+}
+
+func (l *jobLease) isHeld() bool {
+	return l == nil || atomic.LoadInt32(&l.held) == 1
+}
+
+// waitHeld block until job lease is held
+func (l *jobLease) waitHeld() {
+	for !l.isHeld() {
+		restInJob(restIntervalInStandby)
+	}
+}
+
+// waitHeldUntil block until job lease is held, return false if stopped
+func (l *jobLease) waitHeldUntil(stop <-chan struct{}) bool {
+	for !l.isHeld() {
+		if !restInJobUntil(restIntervalInStandby, stop) {
+			return false
+		}
+	}
+	return true
+}
+
+// claimSwap claim swap to process, and renew the claim if already claimed
+func claimSwap(isSwapin bool, txid, pairID, bind string) error {
+	if !params.IsReplicaEnabled() {
+		return nil
+	}
+	ok, err := acquireSwapClaim(isSwapin, txid, pairID, bind, getReplicaName(), getClaimTTL())
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errSwapClaimedByOthers
+	}
+	return nil
+}
+
+func releaseSwapClaim(isSwapin bool, txid, pairID, bind string) {
+	if !params.IsReplicaEnabled() {
+		return
+	}
+	err := releaseSwapClaimDB(isSwapin, txid, pairID, bind, getReplicaName())
+	if err != nil {
+		logWorkerError("replica", "release swap claim failed", err, "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin)
+	}
+}
+
+// swapClaim claim of swap renewed in background until released,
+// as processing swap may exceed claim ttl (eg. waiting nonce lease or dcrm signing).
+// nil swapClaim is never lost (single server).
+type swapClaim struct {
+	isSwapin bool
+	txid     string
+	pairID   string
+	bind     string
+	lost     int32
+	done     chan struct{}
+	stopped  chan struct{}
+}
+
+// keepSwapClaim claim swap and keep renewing the claim until released
+func keepSwapClaim(isSwapin bool, txid, pairID, bind string) (*swapClaim, error) {
+	if !params.IsReplicaEnabled() {
+		return nil, nil
+	}
+	err := claimSwap(isSwapin, txid, pairID, bind)
+	if err != nil {
+		return nil, err
+	}
+	c := &swapClaim{
+		isSwapin: isSwapin,
+		txid:     txid,
+		pairID:   pairID,
+		bind:     bind,
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}
+	go c.keep()
+	return c, nil
+}
+
+func (c *swapClaim) keep() {
+	defer close(c.stopped)
+	ticker := time.NewTicker(getRenewInterval(getClaimTTL()))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			err := claimSwap(c.isSwapin, c.txid, c.pairID, c.bind)
+			if err == errSwapClaimedByOthers {
+				atomic.StoreInt32(&c.lost, 1)
+				logWorkerError("replica", "swap claim is lost", err, "pairID", c.pairID, "txid", c.txid, "bind", c.bind, "isSwapin", c.isSwapin)
+				return
+			}
+			if err != nil {
+				logWorkerError("replica", "renew swap claim failed", err, "pairID", c.pairID, "txid", c.txid, "bind", c.bind, "isSwapin", c.isSwapin)
+			}
+		}
+	}
+}
+
+// isLost is claim taken over by other replica after it expired
+func (c *swapClaim) isLost() bool {
+	return c != nil && atomic.LoadInt32(&c.lost) == 1
+}
+
+// release stop renewing and release the claim
+func (c *swapClaim) release() {
+	if c == nil {
+		return
+	}
+	close(c.done)
+	<-c.stopped
+	if !c.isLost() {
+		releaseSwapClaim(c.isSwapin, c.txid, c.pairID, c.bind)
+	}
+}
+
+func getLocalNonceLock(name string) *sync.Mutex {
+	localNonceLocksLock.Lock()
+	defer localNonceLocksLock.Unlock()
+	lock, exist := localNonceLocks[name]
+	if !exist {
+		lock = new(sync.Mutex)
+		localNonceLocks[name] = lock
+	}
+	return lock
+}
+
+// lockSwapNonce serialize nonce allocation of dcrm address in process (eg.
+// swap and fee withdraw) and across replicas, it holds the nonce lock until
+// unlock is called, and syncs the latest swap nonce in database (increased
+// by other replicas) into nonce setter.
+func lockSwapNonce(nonceSetter tokens.NonceSetter, pairID, address string, isSwapin bool) (unlock func(), err error) {
+	name := "nonce:" + strings.ToLower(address) + ":" + getSwapType(isSwapin).String()
+	localLock := getLocalNonceLock(name)
+	localLock.Lock()
+	if !params.IsReplicaEnabled() {
+		return localLock.Unlock, nil
+	}
+	unlockLease, err := lockNonceLease(nonceSetter, name, pairID, address, isSwapin)
+	if err != nil {
+		localLock.Unlock()
+		return nil, err
+	}
+	return func() {
+		unlockLease()
+		localLock.Unlock()
+	}, nil
+}
+
+// lockNonceLease hold the nonce lease across replicas
+func lockNonceLease(nonceSetter tokens.NonceSetter, name, pairID, address string, isSwapin bool) (unlock func(), err error) {
+	owner := getReplicaName()
+	ttl := getLeaseTTL()
+	renewInterval := getRenewInterval(ttl)
+	deadline := time.Now().Add(time.Duration(getClaimTTL()) * leaseTTLUnit)
+	for {
+		ok, errf := acquireLease(name, owner, ttl)
+		if ok {
+			break
+		}
+		if errf != nil {
+			logWorkerError("replica", "acquire nonce lease failed", errf, "lease", name)
+		}
+		if time.Now().After(deadline) {
+			return nil, errWaitNonceLease
+		}
+		time.Sleep(renewInterval / 10)
+	}
+
+	if latest, errf := findLatestSwapNonce(address, isSwapin); errf == nil {
+		nonceSetter.AdjustNonce(pairID, latest.SwapNonce)
+	} else if errf != mongodb.ErrItemNotFound {
+		_ = releaseLease(name, owner)
+		return nil, errf
+	}
+
+	stop := make(chan struct{})
+	go func() {
+		ticker := time.NewTicker(renewInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				if ok, errf := acquireLease(name, owner, ttl); !ok {
+					logWorkerError("replica", "renew nonce lease failed", errf, "lease", name)
+				}
+			}
+		}
+	}()
+	unlock = func() {
+		close(stop)
+		_ = releaseLease(name, owner)
+	}
+	return unlock, nil
+}
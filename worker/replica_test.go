@@ -0,0 +1,259 @@
+package worker
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+const (
+	testReplica  = "replica-1"
+	testOther    = "replica-2"
+	testDcrmAddr = "0x1111111111111111111111111111111111111111"
+	testNonceKey = "nonce:" + testDcrmAddr + ":swapin"
+	testClaimKey = "swapin:txid:pairid:bind"
+)
+
+// fakeLeaseStore in memory leases with the same semantics as mongodb leases
+type fakeLeaseStore struct {
+	mu     sync.Mutex
+	leases map[string]*mongodb.MgoLease
+}
+
+func (s *fakeLeaseStore) acquire(name, owner string, ttl int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now().UnixNano()
+	if lease, exist := s.leases[name]; exist && lease.Owner != owner && lease.ExpireAt > now {
+		return false, nil
+	}
+	s.leases[name] = &mongodb.MgoLease{
+		Key:      name,
+		Owner:    owner,
+		ExpireAt: now + (time.Duration(ttl) * leaseTTLUnit).Nanoseconds(),
+	}
+	return true, nil
+}
+
+func (s *fakeLeaseStore) release(name, owner string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if lease, exist := s.leases[name]; exist && lease.Owner == owner {
+		delete(s.leases, name)
+	}
+	return nil
+}
+
+func (s *fakeLeaseStore) owner(name string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if lease, exist := s.leases[name]; exist {
+		return lease.Owner
+	}
+	return ""
+}
+
+// steal take over lease regardless of its owner
+func (s *fakeLeaseStore) steal(name, owner string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.leases[name] = &mongodb.MgoLease{Key: name, Owner: owner, ExpireAt: time.Now().Add(time.Hour).UnixNano()}
+}
+
+type fakeNonceSetter struct {
+	tokens.NonceSetter
+	adjusted uint64
+}
+
+func (s *fakeNonceSetter) AdjustNonce(pairID string, value uint64) uint64 {
+	s.adjusted = value
+	return value
+}
+
+func setupTestReplica(t *testing.T) *fakeLeaseStore {
+	store := &fakeLeaseStore{leases: make(map[string]*mongodb.MgoLease)}
+	oldConfig := params.GetConfig()
+	oldUnit := leaseTTLUnit
+	oldAcquire, oldRelease := acquireLease, releaseLease
+	oldClaim, oldReleaseClaim := acquireSwapClaim, releaseSwapClaimDB
+	oldFindNonce := findLatestSwapNonce
+	t.Cleanup(func() {
+		params.SetConfig(oldConfig)
+		leaseTTLUnit = oldUnit
+		acquireLease, releaseLease = oldAcquire, oldRelease
+		acquireSwapClaim, releaseSwapClaimDB = oldClaim, oldReleaseClaim
+		findLatestSwapNonce = oldFindNonce
+	})
+
+	params.SetConfig(&params.ServerConfig{
+		Replica: &params.ReplicaConfig{
+			Name:     testReplica,
+			Mode:     params.ReplicaActiveActive,
+			LeaseTTL: 3,
+			ClaimTTL: 10,
+		},
+	})
+	leaseTTLUnit = 10 * time.Millisecond
+	acquireLease = store.acquire
+	releaseLease = store.release
+	acquireSwapClaim = func(isSwapin bool, txid, pairID, bind, owner string, ttl int64) (bool, error) {
+		return store.acquire(testClaimKey, owner, ttl)
+	}
+	releaseSwapClaimDB = func(isSwapin bool, txid, pairID, bind, owner string) error {
+		return store.release(testClaimKey, owner)
+	}
+	findLatestSwapNonce = func(address string, isSwapin bool) (*mongodb.MgoLatestSwapNonce, error) {
+		return &mongodb.MgoLatestSwapNonce{Address: address, IsSwapin: isSwapin, SwapNonce: 42}, nil
+	}
+	return store
+}
+
+func TestSwapClaimRenewedInBackground(t *testing.T) {
+	store := setupTestReplica(t)
+
+	claim, err := keepSwapClaim(true, "txid", "pairid", "bind")
+	if err != nil {
+		t.Fatalf("claim swap failed: %v", err)
+	}
+	time.Sleep(3 * time.Duration(getClaimTTL()) * leaseTTLUnit)
+	if ok, _ := store.acquire(testClaimKey, testOther, getClaimTTL()); ok {
+		t.Fatal("other replica claimed swap after claim ttl, claim is not renewed")
+	}
+	if claim.isLost() {
+		t.Fatal("renewed claim is lost")
+	}
+	if err = claimSwap(true, "txid", "pairid", "bind"); err != nil {
+		t.Fatalf("renew claim by owner failed: %v", err)
+	}
+
+	claim.release()
+	if ok, _ := store.acquire(testClaimKey, testOther, getClaimTTL()); !ok {
+		t.Fatal("other replica can not claim released swap")
+	}
+}
+
+func TestSwapClaimLost(t *testing.T) {
+	store := setupTestReplica(t)
+
+	claim, err := keepSwapClaim(true, "txid", "pairid", "bind")
+	if err != nil {
+		t.Fatalf("claim swap failed: %v", err)
+	}
+	store.steal(testClaimKey, testOther)
+	time.Sleep(2 * getRenewInterval(getClaimTTL()))
+	if !claim.isLost() {
+		t.Fatal("claim taken over by other replica is not lost")
+	}
+	claim.release()
+	if store.owner(testClaimKey) != testOther {
+		t.Fatal("lost claim released the claim of other replica")
+	}
+
+	if _, err = keepSwapClaim(true, "txid", "pairid", "bind"); err != errSwapClaimedByOthers {
+		t.Fatalf("claim swap claimed by others, have err %v want %v", err, errSwapClaimedByOthers)
+	}
+}
+
+// claim must be kept when waiting nonce lease held by other replica,
+// so the swap is not claimed by others when we are building it
+func TestSwapClaimKeptWhenWaitingNonceLease(t *testing.T) {
+	store := setupTestReplica(t)
+
+	claim, err := keepSwapClaim(true, "txid", "pairid", "bind")
+	if err != nil {
+		t.Fatalf("claim swap failed: %v", err)
+	}
+	defer claim.release()
+
+	store.steal(testNonceKey, testOther) // renewed by other replica
+	waitLease := time.Duration(getClaimTTL()) * leaseTTLUnit * 6 / 10
+	go func() {
+		time.Sleep(waitLease)
+		_ = store.release(testNonceKey, testOther)
+	}()
+
+	start := time.Now()
+	nonceSetter := &fakeNonceSetter{}
+	unlock, err := lockSwapNonce(nonceSetter, "pairid", testDcrmAddr, true)
+	if err != nil {
+		t.Fatalf("lock swap nonce failed: %v", err)
+	}
+	if time.Since(start) < waitLease {
+		t.Fatal("nonce lease is acquired when held by other replica")
+	}
+	if nonceSetter.adjusted != 42 {
+		t.Fatalf("latest swap nonce is not synced, have %v want %v", nonceSetter.adjusted, 42)
+	}
+
+	// build and sign after waiting, total time exceeds claim ttl
+	time.Sleep(waitLease)
+	if ok, _ := store.acquire(testNonceKey, testOther, getLeaseTTL()); ok {
+		t.Fatal("other replica acquired nonce lease which is not unlocked")
+	}
+	if ok, _ := store.acquire(testClaimKey, testOther, getClaimTTL()); ok {
+		t.Fatal("other replica claimed swap when waiting nonce lease")
+	}
+	if claim.isLost() {
+		t.Fatal("claim is lost when waiting nonce lease")
+	}
+
+	unlock()
+	if ok, _ := store.acquire(testNonceKey, testOther, getLeaseTTL()); !ok {
+		t.Fatal("other replica can not acquire unlocked nonce lease")
+	}
+}
+
+func TestWaitNonceLeaseTimeout(t *testing.T) {
+	store := setupTestReplica(t)
+
+	store.steal(testNonceKey, testOther)
+	_, err := lockSwapNonce(&fakeNonceSetter{}, "pairid", testDcrmAddr, true)
+	if err != errWaitNonceLease {
+		t.Fatalf("lock swap nonce held by others, have err %v want %v", err, errWaitNonceLease)
+	}
+}
+
+// swap and fee withdraw of the same dcrm address are serialized in process
+func TestLockSwapNonceInProcess(t *testing.T) {
+	oldConfig := params.GetConfig()
+	t.Cleanup(func() { params.SetConfig(oldConfig) })
+	params.SetConfig(&params.ServerConfig{})
+
+	unlock, err := lockSwapNonce(&fakeNonceSetter{}, "pairid", testDcrmAddr, true)
+	if err != nil {
+		t.Fatalf("lock swap nonce failed: %v", err)
+	}
+
+	locked := make(chan func())
+	go func() {
+		unlock2, errf := lockSwapNonce(&fakeNonceSetter{}, "pairid2", testDcrmAddr, true)
+		if errf != nil {
+			t.Errorf("lock swap nonce failed: %v", errf)
+		}
+		locked <- unlock2
+	}()
+
+	// other swap type or address is not blocked
+	unlockOther, err := lockSwapNonce(&fakeNonceSetter{}, "pairid", testDcrmAddr, false)
+	if err != nil {
+		t.Fatalf("lock swap nonce failed: %v", err)
+	}
+	unlockOther()
+
+	select {
+	case <-locked:
+		t.Fatal("nonce lock of the same address is acquired before unlock")
+	case <-time.After(30 * time.Millisecond):
+	}
+	unlock()
+	select {
+	case unlock2 := <-locked:
+		unlock2()
+	case <-time.After(time.Second):
+		t.Fatal("nonce lock is not acquired after unlock")
+	}
+}
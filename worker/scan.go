@@ -13,8 +13,8 @@ func StartScanJob(isServer bool) {
 		if srcChainCfg.EnableScanPool {
 			go tokens.SrcBridge.StartPoolTransactionScanJob()
 		}
-		if btc.BridgeInstance != nil {
-			go btc.BridgeInstance.StartSwapHistoryScanJob()
+		if btcBridge := btc.GetSrcBridge(); btcBridge != nil {
+			go btcBridge.StartSwapHistoryScanJob()
 		}
 	}
 
@@ -23,7 +23,9 @@ func StartStableJob() {
 func startSwapinStableJob() {
 	swapinStableStarter.Do(func() {
 		logWorker("stable", "start update swapin stable job")
+		lease := newJobLease("stable:swapin", false)
 		for {
+			lease.waitHeld()
 			res, err := findSwapinResultsToStable()
 			if err != nil {
 				logWorkerError("stable", "find swapin results error", err)
@@ -46,7 +48,9 @@ func startSwapinStableJob() {
 func startSwapoutStableJob() {
 	swapoutStableStarter.Do(func() {
 		logWorker("stable", "start update swapout stable job")
+		lease := newJobLease("stable:swapout", false)
 		for {
+			lease.waitHeld()
 			res, err := findSwapoutResultsToStable()
 			if err != nil {
 				logWorkerError("stable", "find swapout results error", err)
@@ -131,9 +135,11 @@ func processSwapStable(swap *mongodb.MgoSwapResult, isSwapin bool) (err error) {
 				txFailed = true
 			}
 			if txFailed {
+				recordSwapNetworkFee(resBridge, swap, isSwapin)
 				return markSwapResultFailed(swap.TxID, swap.PairID, swap.Bind, isSwapin)
 			}
 		}
+		recordSwapNetworkFee(resBridge, swap, isSwapin)
 		return markSwapResultStable(swap.TxID, swap.PairID, swap.Bind, isSwapin)
 	}
 
@@ -164,3 +170,26 @@ func processUpdateSwapHeight(resBridge tokens.CrossChainBridge, swap *mongodb.Mg
 	}
 	return updateSwapResultHeight(swap, blockHeight, blockTime, swap.SwapTx != oldSwapTx)
 }
+
+// recordSwapNetworkFee record network fee paid by swap tx into fee ledger,
+// failed swap tx also pays network fee
+func recordSwapNetworkFee(resBridge tokens.CrossChainBridge, swap *mongodb.MgoSwapResult, isSwapin bool) {
+	feeGetter, ok := resBridge.(tokens.TxFeeGetter)
+	if !ok {
+		return
+	}
+	fee, err := feeGetter.GetTxFee(swap.SwapTx)
+	if err != nil {
+		logWorkerWarn("stable", "get swap tx fee failed", "swaptx", swap.SwapTx, "pairID", swap.PairID, "err", err)
+		return
+	}
+	_ = mongodb.AddFeeLedgerEntry(&mongodb.MgoFeeLedgerEntry{
+		Key:      mongodb.GetFeeLedgerKey(mongodb.FeeKindNetwork, isSwapin, swap.SwapTx),
+		PairID:   swap.PairID,
+		Kind:     mongodb.FeeKindNetwork,
+		IsSwapin: isSwapin,
+		TxID:     swap.TxID,
+		SwapTx:   swap.SwapTx,
+		Amount:   fee.String(),
+	})
+}
@@ -10,6 +10,7 @@ import (
 
 	"github.com/anyswap/CrossChain-Bridge/common"
 	"github.com/anyswap/CrossChain-Bridge/mongodb"
+	"github.com/anyswap/CrossChain-Bridge/params"
 	"github.com/anyswap/CrossChain-Bridge/tokens"
 )
 
@@ -22,6 +23,11 @@ var (
 	swapinTaskChanMap  = make(map[string]chan *tokens.BuildTxArgs)
 	swapoutTaskChanMap = make(map[string]chan *tokens.BuildTxArgs)
 
+	// stop channels of running swap jobs (key is pairID)
+	swapJobStops    = make(map[string]chan struct{})
+	swapJobsLock    sync.Mutex
+	swapJobsStarted bool
+
 	errAlreadySwapped = errors.New("already swapped")
 )
 
@@ -34,14 +40,23 @@ func StartSwapJob() {
 	if nonceSetter, ok := tokens.SrcBridge.(tokens.NonceSetter); ok {
 		nonceSetter.InitNonces(swapoutNonces)
 	}
+	swapJobsLock.Lock()
+	swapJobsStarted = true
+	swapJobsLock.Unlock()
 	for _, pairCfg := range tokens.GetTokenPairsConfig() {
 		AddSwapJob(pairCfg)
 	}
 }
 
-// AddSwapJob add swap job
+// AddSwapJob add swap job, do nothing if swap job of pair is running
 func AddSwapJob(pairCfg *tokens.TokenPairConfig) {
+	swapJobsLock.Lock()
+	defer swapJobsLock.Unlock()
+
 	pairID := strings.ToLower(pairCfg.PairID)
+	if _, exist := swapJobStops[pairID]; exist {
+		return
+	}
 	swapinDcrmAddr := strings.ToLower(pairCfg.DestToken.DcrmAddress)
 	if _, exist := swapinTaskChanMap[swapinDcrmAddr]; !exist {
 		swapinTaskChanMap[swapinDcrmAddr] = make(chan *tokens.BuildTxArgs, swapChanSize)
@@ -53,13 +68,38 @@ func AddSwapJob(pairCfg *tokens.TokenPairConfig) {
 		go processSwapTask(swapoutTaskChanMap[swapoutDcrmAddr])
 	}
 
-	go startSwapinSwapJob(pairID)
-	go startSwapoutSwapJob(pairID)
+	stop := make(chan struct{})
+	swapJobStops[pairID] = stop
+	go startSwapinSwapJob(pairID, stop)
+	go startSwapoutSwapJob(pairID, stop)
+}
+
+// RemoveSwapJob stop swap job of removed pair
+func RemoveSwapJob(pairID string) {
+	swapJobsLock.Lock()
+	defer swapJobsLock.Unlock()
+
+	pairID = strings.ToLower(pairID)
+	if stop, exist := swapJobStops[pairID]; exist {
+		close(stop)
+		delete(swapJobStops, pairID)
+	}
+}
+
+func isSwapJobStarted() bool {
+	swapJobsLock.Lock()
+	defer swapJobsLock.Unlock()
+	return swapJobsStarted
 }
 
-func startSwapinSwapJob(pairID string) {
-	logWorker("swap", "start swapin swap job")
+func startSwapinSwapJob(pairID string, stop <-chan struct{}) {
+	logWorker("swap", "start swapin swap job", "pairID", pairID)
+	lease := newJobLease("swap:swapin:"+pairID, false)
+	defer lease.release()
 	for {
+		if !lease.waitHeldUntil(stop) {
+			break
+		}
 		res, err := findSwapinsToSwap(pairID)
 		if err != nil {
 			logWorkerError("swapin", "find swapins error", err)
@@ -67,21 +107,30 @@ func startSwapinSwapJob(pairID string) {
 		if len(res) > 0 {
 			logWorker("swapin", "find swapins to swap", "count", len(res))
 		}
+		balances := make(vaultBalances)
 		for _, swap := range res {
-			err = processSwapinSwap(swap)
+			err = processSwapinSwap(swap, balances)
 			switch err {
-			case nil, errAlreadySwapped:
+			case nil, errAlreadySwapped, errSwapClaimedByOthers:
 			default:
 				logWorkerError("swapin", "process swapin swap error", err, "pairID", swap.PairID, "txid", swap.TxID, "bind", swap.Bind)
 			}
 		}
-		restInJob(restIntervalInDoSwapJob)
+		if !restInJobUntil(restIntervalInDoSwapJob, stop) {
+			break
+		}
 	}
+	logWorker("swap", "stop swapin swap job", "pairID", pairID)
 }
 
-func startSwapoutSwapJob(pairID string) {
-	logWorker("swapout", "start swapout swap job")
+func startSwapoutSwapJob(pairID string, stop <-chan struct{}) {
+	logWorker("swapout", "start swapout swap job", "pairID", pairID)
+	lease := newJobLease("swap:swapout:"+pairID, false)
+	defer lease.release()
 	for {
+		if !lease.waitHeldUntil(stop) {
+			break
+		}
 		res, err := findSwapoutsToSwap(pairID)
 		if err != nil {
 			logWorkerError("swapout", "find swapouts error", err)
@@ -89,16 +138,20 @@ func startSwapoutSwapJob(pairID string) {
 		if len(res) > 0 {
 			logWorker("swapout", "find swapouts to swap", "count", len(res))
 		}
+		balances := make(vaultBalances)
 		for _, swap := range res {
-			err = processSwapoutSwap(swap)
+			err = processSwapoutSwap(swap, balances)
 			switch err {
-			case nil, errAlreadySwapped:
+			case nil, errAlreadySwapped, errSwapClaimedByOthers:
 			default:
 				logWorkerError("swapout", "process swapout swap error", err, "pairID", swap.PairID, "txid", swap.TxID, "bind", swap.Bind)
 			}
 		}
-		restInJob(restIntervalInDoSwapJob)
+		if !restInJobUntil(restIntervalInDoSwapJob, stop) {
+			break
+		}
 	}
+	logWorker("swapout", "stop swapout swap job", "pairID", pairID)
 }
 
 func findSwapinsToSwap(pairID string) ([]*mongodb.MgoSwap, error) {
@@ -127,15 +180,15 @@ func isSwapInBlacklist(swap *mongodb.MgoSwapResult) (isBlacked bool, err error)
 	return isBlacked, nil
 }
 
-func processSwapinSwap(swap *mongodb.MgoSwap) (err error) {
-	return processSwap(swap, true)
+func processSwapinSwap(swap *mongodb.MgoSwap, balances vaultBalances) (err error) {
+	return processSwap(swap, true, balances)
 }
 
-func processSwapoutSwap(swap *mongodb.MgoSwap) (err error) {
-	return processSwap(swap, false)
+func processSwapoutSwap(swap *mongodb.MgoSwap, balances vaultBalances) (err error) {
+	return processSwap(swap, false, balances)
 }
 
-func processSwap(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
+func processSwap(swap *mongodb.MgoSwap, isSwapin bool, balances vaultBalances) (err error) {
 	pairID := swap.PairID
 	txid := swap.TxID
 	bind := swap.Bind
@@ -163,7 +216,7 @@ func processSwap(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
 	if isBlacked {
 		logWorkerTrace("swap", "address is in blacklist", "txid", txid, "bind", bind, "isSwapin", isSwapin)
 		err = tokens.ErrAddressIsInBlacklist
-		_ = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.SwapInBlacklist, now(), err.Error())
+		_ = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.SwapInBlacklist, now(), err.Error(), mongodb.ActorSwap)
 		return nil
 	}
 
@@ -189,8 +242,65 @@ func processSwap(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
 		From:        toTokenCfg.DcrmAddress,
 		OriginValue: value,
 	}
+	// snapshot swap fee priced by fee policy, oracles rebuild the same tx with it
+	if tokens.HasFeePolicy(pairID, isSwapin) {
+		args.Extra = &tokens.AllExtras{SwapFee: tokens.CalcSwapFee(pairID, value, isSwapin)}
+		if args.GetSwapValue().Sign() <= 0 {
+			logWorkerTrace("swap", "swap value is not enough for swap fee", "pairID", pairID, "txid", txid, "bind", bind, "value", value, "swapFee", args.Extra.SwapFee, "isSwapin", isSwapin)
+			return nil // retry in next round, as network fee may go down
+		}
+	}
+
+	err = checkVaultReserve(balances, toTokenCfg, args)
+	if err != nil {
+		return err
+	}
 
-	return dispatchSwapTask(args)
+	err = claimSwap(isSwapin, txid, pairID, bind)
+	if err != nil {
+		return err
+	}
+	err = dispatchSwapTask(args)
+	if err != nil {
+		releaseSwapClaim(isSwapin, txid, pairID, bind)
+	}
+	return err
+}
+
+// vaultBalances balances of lock/release vaults in a swap round,
+// fetched once per vault and reduced by swaps dispatched in the round.
+type vaultBalances map[string]*big.Int
+
+func getVaultBalanceKey(isSrc bool, token *tokens.TokenConfig) string {
+	return strings.ToLower(fmt.Sprintf("%v:%v:%v", isSrc, token.ContractAddress, token.DcrmAddress))
+}
+
+// checkVaultReserve refuse to swap if the erc20 vault of lock/release token
+// has not enough balance to release, the swap is retried in next round.
+func checkVaultReserve(balances vaultBalances, toTokenCfg *tokens.TokenConfig, args *tokens.BuildTxArgs) error {
+	pairID := args.PairID
+	isSwapin := args.SwapType == tokens.SwapinType
+	if !toTokenCfg.IsReleaseFromVault(!isSwapin) {
+		return nil
+	}
+	key := getVaultBalanceKey(!isSwapin, toTokenCfg)
+	balance, exist := balances[key]
+	if !exist {
+		resBridge := tokens.GetCrossChainBridge(!isSwapin)
+		vaultBalance, err := resBridge.GetTokenBalance("ERC20", toTokenCfg.ContractAddress, toTokenCfg.DcrmAddress)
+		if err != nil {
+			return err
+		}
+		balance = new(big.Int).Set(vaultBalance)
+		balances[key] = balance
+	}
+	amount := args.GetSwapValue()
+	if balance.Cmp(amount) < 0 {
+		logWorkerWarn("swap", "vault balance is not enough", "pairID", pairID, "vault", toTokenCfg.DcrmAddress, "balance", balance, "amount", amount, "isSwapin", isSwapin)
+		return tokens.ErrInsufficientVaultBalance
+	}
+	balance.Sub(balance, amount)
+	return nil
 }
 
 func preventReswap(res *mongodb.MgoSwapResult, isSwapin bool) (err error) {
@@ -215,7 +325,7 @@ func processNonEmptySwapResult(res *mongodb.MgoSwapResult, isSwapin bool) error
 	txid := res.TxID
 	pairID := res.PairID
 	bind := res.Bind
-	_ = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxProcessed, now(), "")
+	_ = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxProcessed, now(), "", mongodb.ActorSwap)
 	if res.Status != mongodb.MatchTxEmpty {
 		return errAlreadySwapped
 	}
@@ -242,7 +352,7 @@ func processHistory(res *mongodb.MgoSwapResult, isSwapin bool) error {
 	if _, err := resBridge.GetTransaction(history.matchTx); err == nil {
 		matchTx := &MatchTx{
 			SwapTx:    history.matchTx,
-			SwapValue: tokens.CalcSwappedValue(pairID, history.value, isSwapin).String(),
+			SwapValue: history.swapValue.String(),
 			SwapType:  swapType,
 			SwapNonce: history.nonce,
 		}
@@ -281,7 +391,7 @@ func processSwapTask(swapChan <-chan *tokens.BuildTxArgs) {
 		args := <-swapChan
 		err := doSwap(args)
 		switch err {
-		case nil, errAlreadySwapped:
+		case nil, errAlreadySwapped, errSwapClaimedByOthers:
 		default:
 			logWorkerError("doSwap", "process failed", err, "pairID", args.PairID, "txid", args.SwapID, "swapType", args.SwapType.String(), "value", args.OriginValue)
 		}
@@ -298,6 +408,14 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 	isSwapin := swapType == tokens.SwapinType
 	resBridge := tokens.GetCrossChainBridge(!isSwapin)
 
+	// renew the claim as it may be expired when waiting in task channel,
+	// and keep renewing it as waiting nonce lease and signing may take long
+	claim, err := keepSwapClaim(isSwapin, txid, pairID, bind)
+	if err != nil {
+		return err
+	}
+	defer claim.release()
+
 	res, err := mongodb.FindSwapResult(isSwapin, txid, pairID, bind)
 	if err != nil {
 		return err
@@ -307,6 +425,30 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 		return err
 	}
 
+	if nonceSetter, ok := resBridge.(tokens.NonceSetter); ok {
+		unlock, errf := lockSwapNonce(nonceSetter, pairID, args.From, isSwapin)
+		if errf != nil {
+			logWorkerError("doSwap", "lock swap nonce failed", errf, "txid", txid, "bind", bind, "isSwapin", isSwapin)
+			return errf
+		}
+		defer unlock()
+
+		// other replica may have swapped it when we are waiting nonce lease
+		if params.IsReplicaEnabled() {
+			res, err = mongodb.FindSwapResult(isSwapin, txid, pairID, bind)
+			if err != nil {
+				return err
+			}
+			err = preventReswap(res, isSwapin)
+			if err != nil {
+				return err
+			}
+		}
+	}
+	if claim.isLost() {
+		return errSwapClaimLost
+	}
+
 	logWorker("doSwap", "start to process", "pairID", pairID, "txid", txid, "bind", bind, "isSwapin", isSwapin, "value", originValue)
 
 	rawTx, err := resBridge.BuildRawTransaction(args)
@@ -315,81 +457,81 @@ func doSwap(args *tokens.BuildTxArgs) (err error) {
 		return err
 	}
 
-	var signedTx interface{}
-	var txHash string
-	tokenCfg := resBridge.GetTokenConfig(pairID)
-	if tokenCfg.GetDcrmAddressPrivateKey() != nil {
-		signedTx, txHash, err = resBridge.SignTransaction(rawTx, pairID)
-	} else {
-		signedTx, txHash, err = resBridge.DcrmSignTransaction(rawTx, args.GetExtraArgs())
-	}
+	signedTx, txHash, err := tokens.SignRawTransaction(resBridge, rawTx, args)
 	if err != nil {
 		logWorkerError("doSwap", "sign tx failed", err, "txid", txid, "bind", bind, "isSwapin", isSwapin)
+		releaseReservedUtxos(resBridge, args)
 		return err
 	}
+	if claim.isLost() {
+		logWorkerError("doSwap", "drop signed tx", errSwapClaimLost, "txid", txid, "bind", bind, "isSwapin", isSwapin, "swaptx", txHash)
+		releaseReservedUtxos(resBridge, args)
+		return errSwapClaimLost
+	}
 
 	swapTxNonce := args.GetTxNonce()
 
-	var oldSwapTxs []string
-	if len(res.OldSwapTxs) > 0 {
-		var existsInOld bool
-		for _, oldSwapTx := range res.OldSwapTxs {
-			if oldSwapTx == txHash {
-				existsInOld = true
-				break
-			}
-		}
-		if !existsInOld {
-			oldSwapTxs = res.OldSwapTxs
-			oldSwapTxs = append(oldSwapTxs, txHash)
-		}
-	} else if res.SwapTx != "" && txHash != res.SwapTx {
-		oldSwapTxs = []string{res.SwapTx, txHash}
-	}
-
-	// update database before sending transaction
-	addSwapHistory(txid, bind, originValue, txHash, swapTxNonce, isSwapin)
 	matchTx := &MatchTx{
 		SwapTx:     txHash,
-		OldSwapTxs: oldSwapTxs,
-		SwapValue:  tokens.CalcSwappedValue(pairID, originValue, isSwapin).String(),
+		OldSwapTxs: getOldSwapTxs(res, txHash),
+		SwapValue:  args.GetSwapValue().String(),
 		SwapType:   swapType,
 		SwapNonce:  swapTxNonce,
 	}
+
+	// persist signed tx in outbox, so it can be recovered if crashed before sending
+	err = addSwapOutbox(resBridge, signedTx, txid, pairID, bind, args.From, matchTx, false)
+	if err != nil {
+		logWorkerError("doSwap", "add swap outbox failed", err, "txid", txid, "bind", bind, "isSwapin", isSwapin)
+		releaseReservedUtxos(resBridge, args)
+		return err
+	}
+
+	// update database before sending transaction
+	addSwapHistory(txid, bind, originValue, args.GetSwapValue(), txHash, swapTxNonce, isSwapin)
 	err = updateSwapResult(txid, pairID, bind, matchTx)
 	if err != nil {
 		logWorkerError("doSwap", "update swap result failed", err, "txid", txid, "bind", bind, "isSwapin", isSwapin)
 		return err
 	}
 
-	err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxProcessed, now(), "")
+	err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxProcessed, now(), "", mongodb.ActorSwap)
 	if err != nil {
 		logWorkerError("doSwap", "update swap status failed", err, "txid", txid, "bind", bind, "isSwapin", isSwapin)
 		return err
 	}
 
-	return sendSignedTransaction(resBridge, signedTx, txid, pairID, bind, isSwapin, false)
+	return sendSignedTransaction(resBridge, signedTx, txHash, txid, pairID, bind, isSwapin, false)
+}
+
+// releaseReservedUtxos release utxos reserved by building swap tx which will not be sent
+func releaseReservedUtxos(bridge tokens.CrossChainBridge, args *tokens.BuildTxArgs) {
+	if reserver, ok := bridge.(tokens.UtxoReserver); ok {
+		reserver.ReleaseUtxos(args)
+	}
 }
 
 type swapInfo struct {
-	txid     string
-	bind     string
-	value    *big.Int
-	matchTx  string
-	nonce    uint64
-	isSwapin bool
+	txid      string
+	bind      string
+	value     *big.Int
+	swapValue *big.Int // signed value, fee may be priced by fee policy
+	matchTx   string
+	nonce     uint64
+	isSwapin  bool
 }
 
-func addSwapHistory(txid, bind string, value *big.Int, matchTx string, nonce uint64, isSwapin bool) {
+func addSwapHistory(txid, bind string, value, swapValue *big.Int, matchTx string, nonce uint64, isSwapin bool) {
 	// Create the new item as its own ring
 	item := ring.New(1)
 	item.Value = &swapInfo{
-		txid:     txid,
-		bind:     bind,
-		value:    value,
-		matchTx:  matchTx,
-		nonce:    nonce,
-		isSwapin: isSwapin,
+		txid:      txid,
+		bind:      bind,
+		value:     value,
+		swapValue: swapValue,
+		matchTx:   matchTx,
+		nonce:     nonce,
+		isSwapin:  isSwapin,
 	}
 
 	swapRingLock.Lock()
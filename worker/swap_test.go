@@ -0,0 +1,111 @@
+package worker
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/anyswap/CrossChain-Bridge/tokens"
+)
+
+// fakeVaultBridge bridge with token balance of vaults
+type fakeVaultBridge struct {
+	tokens.CrossChainBridge
+	balances map[string]*big.Int
+	queries  int
+}
+
+func (b *fakeVaultBridge) GetTokenBalance(tokenType, tokenAddress, accountAddress string) (*big.Int, error) {
+	b.queries++
+	balance, exist := b.balances[accountAddress]
+	if !exist {
+		return nil, tokens.ErrTxNotFound
+	}
+	return balance, nil
+}
+
+func newTestVaultToken(isLockRelease bool, dcrmAddress string) *tokens.TokenConfig {
+	decimals := uint8(0)
+	maxSwap, minSwap, bigValue := 1e6, 0.0, 1e6
+	feeRate, minFee, maxFee := 0.1, 0.0, 1e6
+	token := &tokens.TokenConfig{
+		ID:                "ERC20",
+		ContractAddress:   "0xtoken",
+		DcrmAddress:       dcrmAddress,
+		IsLockRelease:     isLockRelease,
+		Decimals:          &decimals,
+		MaximumSwap:       &maxSwap,
+		MinimumSwap:       &minSwap,
+		BigValueThreshold: &bigValue,
+		SwapFeeRate:       &feeRate,
+		MinimumSwapFee:    &minFee,
+		MaximumSwapFee:    &maxFee,
+	}
+	token.CalcAndStoreValue()
+	return token
+}
+
+func TestCheckVaultReserve(t *testing.T) {
+	oldPairsConfig := tokens.GetTokenPairsConfig()
+	oldDstBridge := tokens.DstBridge
+	defer func() {
+		tokens.SetTokenPairsConfig(oldPairsConfig, false)
+		tokens.DstBridge = oldDstBridge
+	}()
+
+	lockRelease := newTestVaultToken(true, "0xvault")
+	tokens.SetTokenPairsConfig(map[string]*tokens.TokenPairConfig{
+		"lock":  {PairID: "lock", SrcToken: newTestVaultToken(false, "0xsrc"), DestToken: lockRelease},
+		"mint":  {PairID: "mint", SrcToken: newTestVaultToken(false, "0xsrc"), DestToken: newTestVaultToken(false, "0xvault")},
+		"other": {PairID: "other", SrcToken: newTestVaultToken(false, "0xsrc"), DestToken: newTestVaultToken(true, "0xempty")},
+	}, false)
+	bridge := &fakeVaultBridge{balances: map[string]*big.Int{"0xvault": big.NewInt(1000)}}
+	tokens.DstBridge = bridge
+
+	newArgs := func(pairID string, value int64, swapFee *big.Int) *tokens.BuildTxArgs {
+		args := &tokens.BuildTxArgs{
+			SwapInfo:    tokens.SwapInfo{PairID: pairID, SwapType: tokens.SwapinType},
+			OriginValue: big.NewInt(value),
+		}
+		if swapFee != nil {
+			args.Extra = &tokens.AllExtras{SwapFee: swapFee}
+		}
+		return args
+	}
+
+	balances := make(vaultBalances)
+	// mint mode does not check vault
+	mintToken := tokens.GetTokenConfig("mint", false)
+	if err := checkVaultReserve(balances, mintToken, newArgs("mint", 5000, nil)); err != nil || bridge.queries != 0 {
+		t.Fatalf("check mint swap failed, err %v queries %v", err, bridge.queries)
+	}
+	// swap value after static fee 10% is 900
+	if err := checkVaultReserve(balances, lockRelease, newArgs("lock", 1000, nil)); err != nil {
+		t.Fatalf("check vault reserve failed, %v", err)
+	}
+	// swap value after snapshot fee 890 is 110, which exceeds the remaining balance 100
+	if err := checkVaultReserve(balances, lockRelease, newArgs("lock", 1000, big.NewInt(890))); err != tokens.ErrInsufficientVaultBalance {
+		t.Errorf("check vault reserve error mismatch, have %v want %v", err, tokens.ErrInsufficientVaultBalance)
+	}
+	if err := checkVaultReserve(balances, lockRelease, newArgs("lock", 1000, big.NewInt(900))); err != nil {
+		t.Errorf("check vault reserve with snapshot swap fee failed, %v", err)
+	}
+	if err := checkVaultReserve(balances, lockRelease, newArgs("lock", 1, nil)); err != tokens.ErrInsufficientVaultBalance {
+		t.Errorf("check vault reserve of used up vault error mismatch, have %v", err)
+	}
+	if bridge.queries != 1 {
+		t.Errorf("vault balance should be queried once in a round, have %v queries", bridge.queries)
+	}
+	if bridge.balances["0xvault"].Int64() != 1000 {
+		t.Errorf("vault balance of bridge should not be changed, have %v", bridge.balances["0xvault"])
+	}
+
+	otherToken := tokens.GetTokenConfig("other", false)
+	if err := checkVaultReserve(balances, otherToken, newArgs("other", 10, nil)); err == nil {
+		t.Error("check vault reserve should fail if get balance failed")
+	}
+
+	// balance is queried again in next round
+	if err := checkVaultReserve(make(vaultBalances), lockRelease, newArgs("lock", 1000, nil)); err != nil || bridge.queries != 3 {
+		t.Errorf("check vault reserve in next round failed, err %v queries %v", err, bridge.queries)
+	}
+}
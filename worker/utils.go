@@ -53,3 +53,15 @@ func getSepTimeInFind(dist int64) int64 {
 func restInJob(duration time.Duration) {
 	time.Sleep(duration)
 }
+
+// restInJobUntil rest in job, return false if stopped
+func restInJobUntil(duration time.Duration, stop <-chan struct{}) bool {
+	timer := time.NewTimer(duration)
+	defer timer.Stop()
+	select {
+	case <-stop:
+		return false
+	case <-timer.C:
+		return true
+	}
+}
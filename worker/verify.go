@@ -21,7 +21,9 @@ func StartVerifyJob() {
 func startSwapinVerifyJob() {
 	swapinVerifyStarter.Do(func() {
 		logWorker("verify", "start swapin verify job")
+		lease := newJobLease("verify:swapin", false)
 		for {
+			lease.waitHeld()
 			res, err := findSwapinsToVerify()
 			if err != nil {
 				logWorkerError("verify", "find swapins error", err)
@@ -45,7 +47,9 @@ func startSwapinVerifyJob() {
 func startSwapoutVerifyJob() {
 	swapoutVerifyStarter.Do(func() {
 		logWorker("verify", "start swapout verify job")
+		lease := newJobLease("verify:swapout", false)
 		for {
+			lease.waitHeld()
 			res, err := findSwapoutsToVerify()
 			if err != nil {
 				logWorkerError("verify", "find swapouts error", err)
@@ -114,7 +118,7 @@ func processSwapVerify(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
 	if swapInfo.Height != 0 &&
 		swapInfo.Height < *bridge.GetChainConfig().InitialHeight {
 		err = tokens.ErrTxBeforeInitialHeight
-		return mongodb.UpdateSwapinStatus(txid, pairID, bind, mongodb.TxVerifyFailed, now(), err.Error())
+		return mongodb.UpdateSwapinStatus(txid, pairID, bind, mongodb.TxVerifyFailed, now(), err.Error(), mongodb.ActorVerify)
 	}
 	isBlacked, errf := isInBlacklist(swapInfo)
 	if errf != nil {
@@ -122,7 +126,7 @@ func processSwapVerify(swap *mongodb.MgoSwap, isSwapin bool) (err error) {
 	}
 	if isBlacked {
 		err = tokens.ErrAddressIsInBlacklist
-		return mongodb.UpdateSwapinStatus(txid, pairID, bind, mongodb.SwapInBlacklist, now(), err.Error())
+		return mongodb.UpdateSwapinStatus(txid, pairID, bind, mongodb.SwapInBlacklist, now(), err.Error(), mongodb.ActorVerify)
 	}
 	return updateSwapStatus(pairID, txid, bind, swapInfo, isSwapin, err)
 }
@@ -139,30 +143,30 @@ func updateSwapStatus(pairID, txid, bind string, swapInfo *tokens.TxSwapInfo, is
 			status = mongodb.TxWithBigValue
 			resultStatus = mongodb.TxWithBigValue
 		}
-		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, status, now(), "")
+		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, status, now(), "", mongodb.ActorVerify)
 	case tokens.ErrTxWithWrongMemo:
 		resultStatus = mongodb.TxWithWrongMemo
-		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxWithWrongMemo, now(), err.Error())
+		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxWithWrongMemo, now(), err.Error(), mongodb.ActorVerify)
 	case tokens.ErrBindAddrIsContract:
 		resultStatus = mongodb.BindAddrIsContract
-		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.BindAddrIsContract, now(), err.Error())
+		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.BindAddrIsContract, now(), err.Error(), mongodb.ActorVerify)
 	case tokens.ErrTxWithWrongValue:
 		resultStatus = mongodb.TxWithWrongValue
-		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxWithWrongValue, now(), err.Error())
+		err = mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxWithWrongValue, now(), err.Error(), mongodb.ActorVerify)
 	case tokens.ErrTxSenderNotRegistered:
-		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxSenderNotRegistered, now(), err.Error())
+		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxSenderNotRegistered, now(), err.Error(), mongodb.ActorVerify)
 	case tokens.ErrTxWithWrongSender:
-		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxWithWrongSender, now(), err.Error())
+		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxWithWrongSender, now(), err.Error(), mongodb.ActorVerify)
 	case tokens.ErrTxIncompatible:
-		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxIncompatible, now(), err.Error())
+		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxIncompatible, now(), err.Error(), mongodb.ActorVerify)
 	case tokens.ErrTxWithWrongReceipt,
 		tokens.ErrBindAddressMismatch:
-		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxVerifyFailed, now(), err.Error())
+		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxVerifyFailed, now(), err.Error(), mongodb.ActorVerify)
 	case tokens.ErrRPCQueryError:
-		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.RPCQueryError, now(), err.Error())
+		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.RPCQueryError, now(), err.Error(), mongodb.ActorVerify)
 	default:
 		logWorkerWarn("verify", "maybe not considered tx verify error", "err", err)
-		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxVerifyFailed, now(), err.Error())
+		return mongodb.UpdateSwapStatus(isSwapin, txid, pairID, bind, mongodb.TxVerifyFailed, now(), err.Error(), mongodb.ActorVerify)
 	}
 
 	if err != nil {
@@ -3,7 +3,9 @@ package worker
 import (
 	"time"
 
+	"github.com/anyswap/CrossChain-Bridge/params"
 	"github.com/anyswap/CrossChain-Bridge/rpc/client"
+	"github.com/anyswap/CrossChain-Bridge/tokens"
 	"github.com/anyswap/CrossChain-Bridge/tokens/bridge"
 )
 
@@ -14,7 +16,13 @@ func StartWork(isServer bool) {
 	logWorker("worker", "start server worker")
 
 	client.InitHTTPClient()
+	if isServer && params.IsPairsConfigInDB() {
+		tokens.SetTokenPairsLoader(LoadTokenPairsFromDB)
+	}
 	bridge.InitCrossChainBridge(isServer)
+	if isServer {
+		checkReplicaMode()
+	}
 
 	go StartScanJob(isServer)
 	time.Sleep(interval)
@@ -26,12 +34,15 @@ func StartWork(isServer bool) {
 		go StartAcceptSignJob()
 		time.Sleep(interval)
 		go AddTokenPairDynamically()
+		go StartPairsConfigCheckJob()
 		return
 	}
 
 	go StartVerifyJob()
 	time.Sleep(interval)
 
+	StartOutboxJob() // recover outbox before swapping
+
 	go StartSwapJob()
 	time.Sleep(interval)
 